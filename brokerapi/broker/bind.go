@@ -11,6 +11,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/featureflags"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/varcontext"
 	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
 	"github.com/cloudfoundry/cloud-service-broker/utils/request"
@@ -25,11 +26,13 @@ var (
 
 // Bind creates an account with credentials to access an instance of a service.
 // It is bound to the `PUT /v2/service_instances/:instance_id/service_bindings/:binding_id` endpoint and can be called using the `cf bind-service` command.
-func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, _ bool) (domain.Binding, error) {
+// If the service has asynchronous bindings, the returned Binding will contain the operation ID for tracking its progress.
+func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, clientSupportsAsync bool) (domain.Binding, error) {
 	broker.Logger.Info("Binding", correlation.ID(ctx), lager.Data{
-		"instance_id": instanceID,
-		"binding_id":  bindingID,
-		"details":     details,
+		"instance_id":        instanceID,
+		"binding_id":         bindingID,
+		"accepts_incomplete": clientSupportsAsync,
+		"details":            details,
 	})
 
 	// check for existing binding
@@ -52,6 +55,10 @@ func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID str
 		return domain.Binding{}, fmt.Errorf("error retrieving service definition: %w", err)
 	}
 
+	if serviceDefinition.AsyncBindings && !clientSupportsAsync {
+		return domain.Binding{}, apiresponses.ErrAsyncRequired
+	}
+
 	err = serviceProvider.CheckUpgradeAvailable(generateTFInstanceID(instanceID))
 	if err != nil {
 		return domain.Binding{}, fmt.Errorf("failed to bind: %s", err.Error())
@@ -80,10 +87,14 @@ func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID str
 		return domain.Binding{}, fmt.Errorf("error generating bind variables: %w", err)
 	}
 
+	if serviceDefinition.AsyncBindings {
+		return broker.bindAsync(ctx, serviceDefinition, serviceProvider, instanceID, bindingID, parsedDetails, vars)
+	}
+
 	// create binding
 	credsDetails, err := serviceProvider.Bind(ctx, vars)
 	if err != nil {
-		return domain.Binding{}, fmt.Errorf("error performing bind: %w", err)
+		return domain.Binding{}, retryableError(fmt.Errorf("error performing bind: %w", err))
 	}
 
 	// save binding to database
@@ -134,6 +145,65 @@ func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID str
 	return *binding, nil
 }
 
+// bindAsync starts the creation of a binding and returns straight away. The credentials are
+// stored by LastBindingOperation once the operation has completed successfully.
+func (broker *ServiceBroker) bindAsync(ctx context.Context, serviceDefinition *broker.ServiceDefinition, serviceProvider broker.ServiceProvider, instanceID, bindingID string, parsedDetails paramparser.BindDetails, vars *varcontext.VarContext) (domain.Binding, error) {
+	// an asynchronous bind that is still running does not yet have any credentials stored
+	inProgress, err := broker.isBindingOperationInProgress(instanceID, bindingID)
+	switch {
+	case err != nil:
+		return domain.Binding{}, err
+	case inProgress:
+		return domain.Binding{IsAsync: true, OperationData: generateTFBindingID(instanceID, bindingID)}, nil
+	}
+
+	operationID, err := serviceProvider.BindAsync(ctx, vars)
+	if err != nil {
+		return domain.Binding{}, retryableError(fmt.Errorf("error performing bind: %w", err))
+	}
+
+	bindRequest := storage.BindRequestDetails{
+		ServiceInstanceGUID: instanceID,
+		ServiceBindingGUID:  bindingID,
+		RequestDetails:      parsedDetails.RequestParams,
+	}
+
+	if err := broker.store.StoreBindRequestDetails(bindRequest); err != nil {
+		return domain.Binding{}, fmt.Errorf("error saving bind request details to database: %s. Unbind operations will not be able to complete", err)
+	}
+
+	// The app GUID is only available on the bind request, so the permission is granted now
+	// and the credentials are put in the Credstore when the operation completes.
+	if broker.Credstore != nil {
+		credentialName := getCredentialName(broker.getServiceName(serviceDefinition), bindingID)
+
+		_, err = broker.Credstore.AddPermission(credentialName, "mtls-app:"+parsedDetails.AppGUID, []string{"read"})
+		if err != nil {
+			return domain.Binding{}, fmt.Errorf("bind failure: unable to add Credstore permissions to app: %w", err)
+		}
+	}
+
+	return domain.Binding{IsAsync: true, OperationData: operationID}, nil
+}
+
+func (broker *ServiceBroker) isBindingOperationInProgress(instanceID, bindingID string) (bool, error) {
+	deploymentID := generateTFBindingID(instanceID, bindingID)
+	exists, err := broker.store.ExistsTerraformDeployment(deploymentID)
+	switch {
+	case err != nil:
+		return false, fmt.Errorf("error checking for existing binding operation: %w", err)
+	case !exists:
+		return false, nil
+	}
+
+	deployment, err := broker.store.GetTerraformDeployment(deploymentID)
+	if err != nil {
+		return false, fmt.Errorf("error retrieving binding operation: %w", err)
+	}
+
+	return deployment.LastOperationState == tf.InProgress, nil
+}
+
 func validateBindParameters(params map[string]any, validUserInputFields []broker.BrokerVariable) error {
 	if len(params) == 0 {
 		return nil
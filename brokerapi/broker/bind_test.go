@@ -210,6 +210,83 @@ var _ = Describe("Bind", func() {
 
 	})
 
+	Describe("async bind", func() {
+		BeforeEach(func() {
+			brokerConfig.Registry["test-service"].AsyncBindings = true
+			fakeServiceProvider.BindAsyncReturns("tf:test-instance-id:test-binding-id", nil)
+		})
+
+		It("starts the bind and returns the operation data", func() {
+			response, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("validating response")
+			Expect(response).To(Equal(domain.Binding{
+				IsAsync:       true,
+				OperationData: "tf:test-instance-id:test-binding-id",
+			}))
+
+			By("validating provider async bind has been called")
+			Expect(fakeServiceProvider.BindAsyncCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.BindCallCount()).To(BeZero())
+			_, actualVars := fakeServiceProvider.BindAsyncArgsForCall(0)
+			Expect(actualVars.GetString("bind_field_1")).To(Equal("bind_value_1"))
+
+			By("validating the bind request details are stored, but not the credentials")
+			Expect(fakeStorage.StoreBindRequestDetailsCallCount()).To(Equal(1))
+			Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(BeZero())
+
+			By("validating the app is given permission without storing credentials in the credstore yet")
+			Expect(fakeCredStore.AddPermissionCallCount()).To(Equal(1))
+			actualPath, actualActor, _ := fakeCredStore.AddPermissionArgsForCall(0)
+			Expect(actualPath).To(Equal("/c/csb/test-service/test-binding-id/secrets-and-services"))
+			Expect(actualActor).To(Equal("mtls-app:test-app-guid"))
+			Expect(fakeCredStore.PutCallCount()).To(BeZero())
+		})
+
+		When("the client does not support async operations", func() {
+			It("returns an error", func() {
+				_, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, false)
+
+				Expect(err).To(MatchError(apiresponses.ErrAsyncRequired))
+				Expect(fakeServiceProvider.BindAsyncCallCount()).To(BeZero())
+			})
+		})
+
+		When("a bind operation is already in progress", func() {
+			BeforeEach(func() {
+				fakeStorage.ExistsTerraformDeploymentReturns(true, nil)
+				fakeStorage.GetTerraformDeploymentReturns(storage.TerraformDeployment{
+					LastOperationType:  models.BindOperationType,
+					LastOperationState: "in progress",
+				}, nil)
+			})
+
+			It("returns the operation data without starting another bind", func() {
+				response, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(response).To(Equal(domain.Binding{
+					IsAsync:       true,
+					OperationData: "tf:test-instance-id:test-binding-id",
+				}))
+				Expect(fakeServiceProvider.BindAsyncCallCount()).To(BeZero())
+			})
+		})
+
+		When("provider async bind fails", func() {
+			BeforeEach(func() {
+				fakeServiceProvider.BindAsyncReturns("", fmt.Errorf("bind-error"))
+			})
+
+			It("should error", func() {
+				_, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+
+				Expect(err).To(MatchError(`error performing bind: bind-error`))
+			})
+		})
+	})
+
 	Describe("unsuccessful bind", func() {
 		When("error reading binding credentials", func() {
 			BeforeEach(func() {
@@ -322,6 +399,18 @@ var _ = Describe("Bind", func() {
 			})
 		})
 
+		When("another operation has locked the binding", func() {
+			BeforeEach(func() {
+				fakeServiceProvider.BindReturns(nil, fmt.Errorf("error marking job started: %w", storage.ErrLeaseHeld))
+			})
+
+			It("should return HTTP 422 as per OSBAPI spec", func() {
+				_, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, false)
+
+				Expect(err).To(MatchError(apiresponses.ErrConcurrentInstanceAccess))
+			})
+		})
+
 		When("fails to store service binding credentials", func() {
 			const saveError = "credential-save-error"
 
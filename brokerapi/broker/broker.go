@@ -16,15 +16,20 @@
 package broker
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
 
 	"code.cloudfoundry.org/lager/v3"
+	"github.com/pivotal-cf/brokerapi/v9/domain/apiresponses"
 
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/credstore"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/featureflags"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 )
 
 const credhubClientIdentifier = "csb"
@@ -51,6 +56,25 @@ func New(cfg *BrokerConfig, store Storage, logger lager.Logger) (*ServiceBroker,
 	}, nil
 }
 
+// errQueueFull tells the platform to retry the request once Terraform is less busy
+var errQueueFull = apiresponses.NewFailureResponseBuilder(executor.ErrQueueFull, http.StatusTooManyRequests, "terraform-queue-full").
+	WithErrorKey("TerraformQueueFull").
+	Build()
+
+// retryableError reports that an operation could not start, but can be retried, because
+// another operation, possibly on another broker instance, has locked the deployment, or
+// because too many Terraform operations are queued
+func retryableError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrLeaseHeld):
+		return apiresponses.ErrConcurrentInstanceAccess
+	case errors.Is(err, executor.ErrQueueFull):
+		return errQueueFull
+	default:
+		return err
+	}
+}
+
 func validateProvisionParameters(params map[string]any, validUserInputFields []broker.BrokerVariable, validImportFields []broker.ImportVariable, plan *broker.ServicePlan) error {
 	if len(params) == 0 {
 		return nil
@@ -108,6 +132,30 @@ func validateDefinedParams(params map[string]any, validUserInputFields []broker.
 	return fmt.Errorf("additional properties are not allowed: %s", strings.Join(invalidParams, ", "))
 }
 
+// withoutTransientParameters returns the request parameters to store with a service instance,
+// leaving out the parameters that only apply to the request in which they are set
+func withoutTransientParameters(params map[string]any, transient []string) map[string]any {
+	var result map[string]any
+	for _, name := range transient {
+		if _, ok := params[name]; !ok {
+			continue
+		}
+
+		if result == nil {
+			result = make(map[string]any, len(params))
+			for k, v := range params {
+				result[k] = v
+			}
+		}
+		delete(result, name)
+	}
+
+	if result == nil {
+		return params
+	}
+	return result
+}
+
 func generateTFInstanceID(instanceID string) string {
 	return "tf:" + instanceID + ":"
 }
@@ -9,6 +9,18 @@ import (
 )
 
 type FakeStorage struct {
+	AcquireTerraformDeploymentLeaseStub        func(string, string) error
+	acquireTerraformDeploymentLeaseMutex       sync.RWMutex
+	acquireTerraformDeploymentLeaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	acquireTerraformDeploymentLeaseReturns struct {
+		result1 error
+	}
+	acquireTerraformDeploymentLeaseReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CreateServiceBindingCredentialsStub        func(storage.ServiceBindingCredentials) error
 	createServiceBindingCredentialsMutex       sync.RWMutex
 	createServiceBindingCredentialsArgsForCall []struct {
@@ -106,6 +118,20 @@ type FakeStorage struct {
 		result1 bool
 		result2 error
 	}
+	FinishOperationLogStub        func(string, string, string, string) error
+	finishOperationLogMutex       sync.RWMutex
+	finishOperationLogArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	finishOperationLogReturns struct {
+		result1 error
+	}
+	finishOperationLogReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetBindRequestDetailsStub        func(string, string) (storage.JSONObject, error)
 	getBindRequestDetailsMutex       sync.RWMutex
 	getBindRequestDetailsArgsForCall []struct {
@@ -186,6 +212,29 @@ type FakeStorage struct {
 		result1 storage.TerraformDeployment
 		result2 error
 	}
+	ReleaseTerraformDeploymentLeaseStub        func(string, string) error
+	releaseTerraformDeploymentLeaseMutex       sync.RWMutex
+	releaseTerraformDeploymentLeaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	releaseTerraformDeploymentLeaseReturns struct {
+		result1 error
+	}
+	releaseTerraformDeploymentLeaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StartOperationLogStub        func(storage.OperationLog) error
+	startOperationLogMutex       sync.RWMutex
+	startOperationLogArgsForCall []struct {
+		arg1 storage.OperationLog
+	}
+	startOperationLogReturns struct {
+		result1 error
+	}
+	startOperationLogReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StoreBindRequestDetailsStub        func(storage.BindRequestDetails) error
 	storeBindRequestDetailsMutex       sync.RWMutex
 	storeBindRequestDetailsArgsForCall []struct {
@@ -231,10 +280,83 @@ type FakeStorage struct {
 	storeTerraformDeploymentReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StoreTerraformLogStub        func(storage.TerraformLog) error
+	storeTerraformLogMutex       sync.RWMutex
+	storeTerraformLogArgsForCall []struct {
+		arg1 storage.TerraformLog
+	}
+	storeTerraformLogReturns struct {
+		result1 error
+	}
+	storeTerraformLogReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeStorage) AcquireTerraformDeploymentLease(arg1 string, arg2 string) error {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	ret, specificReturn := fake.acquireTerraformDeploymentLeaseReturnsOnCall[len(fake.acquireTerraformDeploymentLeaseArgsForCall)]
+	fake.acquireTerraformDeploymentLeaseArgsForCall = append(fake.acquireTerraformDeploymentLeaseArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AcquireTerraformDeploymentLeaseStub
+	fakeReturns := fake.acquireTerraformDeploymentLeaseReturns
+	fake.recordInvocation("AcquireTerraformDeploymentLease", []interface{}{arg1, arg2})
+	fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorage) AcquireTerraformDeploymentLeaseCallCount() int {
+	fake.acquireTerraformDeploymentLeaseMutex.RLock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.RUnlock()
+	return len(fake.acquireTerraformDeploymentLeaseArgsForCall)
+}
+
+func (fake *FakeStorage) AcquireTerraformDeploymentLeaseCalls(stub func(string, string) error) {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	fake.AcquireTerraformDeploymentLeaseStub = stub
+}
+
+func (fake *FakeStorage) AcquireTerraformDeploymentLeaseArgsForCall(i int) (string, string) {
+	fake.acquireTerraformDeploymentLeaseMutex.RLock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.RUnlock()
+	argsForCall := fake.acquireTerraformDeploymentLeaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorage) AcquireTerraformDeploymentLeaseReturns(result1 error) {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	fake.AcquireTerraformDeploymentLeaseStub = nil
+	fake.acquireTerraformDeploymentLeaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) AcquireTerraformDeploymentLeaseReturnsOnCall(i int, result1 error) {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	fake.AcquireTerraformDeploymentLeaseStub = nil
+	if fake.acquireTerraformDeploymentLeaseReturnsOnCall == nil {
+		fake.acquireTerraformDeploymentLeaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.acquireTerraformDeploymentLeaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStorage) CreateServiceBindingCredentials(arg1 storage.ServiceBindingCredentials) error {
 	fake.createServiceBindingCredentialsMutex.Lock()
 	ret, specificReturn := fake.createServiceBindingCredentialsReturnsOnCall[len(fake.createServiceBindingCredentialsArgsForCall)]
@@ -735,6 +857,70 @@ func (fake *FakeStorage) ExistsTerraformDeploymentReturnsOnCall(i int, result1 b
 	}{result1, result2}
 }
 
+func (fake *FakeStorage) FinishOperationLog(arg1 string, arg2 string, arg3 string, arg4 string) error {
+	fake.finishOperationLogMutex.Lock()
+	ret, specificReturn := fake.finishOperationLogReturnsOnCall[len(fake.finishOperationLogArgsForCall)]
+	fake.finishOperationLogArgsForCall = append(fake.finishOperationLogArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.FinishOperationLogStub
+	fakeReturns := fake.finishOperationLogReturns
+	fake.recordInvocation("FinishOperationLog", []interface{}{arg1, arg2, arg3, arg4})
+	fake.finishOperationLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorage) FinishOperationLogCallCount() int {
+	fake.finishOperationLogMutex.RLock()
+	defer fake.finishOperationLogMutex.RUnlock()
+	return len(fake.finishOperationLogArgsForCall)
+}
+
+func (fake *FakeStorage) FinishOperationLogCalls(stub func(string, string, string, string) error) {
+	fake.finishOperationLogMutex.Lock()
+	defer fake.finishOperationLogMutex.Unlock()
+	fake.FinishOperationLogStub = stub
+}
+
+func (fake *FakeStorage) FinishOperationLogArgsForCall(i int) (string, string, string, string) {
+	fake.finishOperationLogMutex.RLock()
+	defer fake.finishOperationLogMutex.RUnlock()
+	argsForCall := fake.finishOperationLogArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStorage) FinishOperationLogReturns(result1 error) {
+	fake.finishOperationLogMutex.Lock()
+	defer fake.finishOperationLogMutex.Unlock()
+	fake.FinishOperationLogStub = nil
+	fake.finishOperationLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) FinishOperationLogReturnsOnCall(i int, result1 error) {
+	fake.finishOperationLogMutex.Lock()
+	defer fake.finishOperationLogMutex.Unlock()
+	fake.FinishOperationLogStub = nil
+	if fake.finishOperationLogReturnsOnCall == nil {
+		fake.finishOperationLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.finishOperationLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStorage) GetBindRequestDetails(arg1 string, arg2 string) (storage.JSONObject, error) {
 	fake.getBindRequestDetailsMutex.Lock()
 	ret, specificReturn := fake.getBindRequestDetailsReturnsOnCall[len(fake.getBindRequestDetailsArgsForCall)]
@@ -1121,6 +1307,129 @@ func (fake *FakeStorage) GetTerraformDeploymentReturnsOnCall(i int, result1 stor
 	}{result1, result2}
 }
 
+func (fake *FakeStorage) ReleaseTerraformDeploymentLease(arg1 string, arg2 string) error {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	ret, specificReturn := fake.releaseTerraformDeploymentLeaseReturnsOnCall[len(fake.releaseTerraformDeploymentLeaseArgsForCall)]
+	fake.releaseTerraformDeploymentLeaseArgsForCall = append(fake.releaseTerraformDeploymentLeaseArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ReleaseTerraformDeploymentLeaseStub
+	fakeReturns := fake.releaseTerraformDeploymentLeaseReturns
+	fake.recordInvocation("ReleaseTerraformDeploymentLease", []interface{}{arg1, arg2})
+	fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorage) ReleaseTerraformDeploymentLeaseCallCount() int {
+	fake.releaseTerraformDeploymentLeaseMutex.RLock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.RUnlock()
+	return len(fake.releaseTerraformDeploymentLeaseArgsForCall)
+}
+
+func (fake *FakeStorage) ReleaseTerraformDeploymentLeaseCalls(stub func(string, string) error) {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	fake.ReleaseTerraformDeploymentLeaseStub = stub
+}
+
+func (fake *FakeStorage) ReleaseTerraformDeploymentLeaseArgsForCall(i int) (string, string) {
+	fake.releaseTerraformDeploymentLeaseMutex.RLock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.RUnlock()
+	argsForCall := fake.releaseTerraformDeploymentLeaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorage) ReleaseTerraformDeploymentLeaseReturns(result1 error) {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	fake.ReleaseTerraformDeploymentLeaseStub = nil
+	fake.releaseTerraformDeploymentLeaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) ReleaseTerraformDeploymentLeaseReturnsOnCall(i int, result1 error) {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	fake.ReleaseTerraformDeploymentLeaseStub = nil
+	if fake.releaseTerraformDeploymentLeaseReturnsOnCall == nil {
+		fake.releaseTerraformDeploymentLeaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseTerraformDeploymentLeaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) StartOperationLog(arg1 storage.OperationLog) error {
+	fake.startOperationLogMutex.Lock()
+	ret, specificReturn := fake.startOperationLogReturnsOnCall[len(fake.startOperationLogArgsForCall)]
+	fake.startOperationLogArgsForCall = append(fake.startOperationLogArgsForCall, struct {
+		arg1 storage.OperationLog
+	}{arg1})
+	stub := fake.StartOperationLogStub
+	fakeReturns := fake.startOperationLogReturns
+	fake.recordInvocation("StartOperationLog", []interface{}{arg1})
+	fake.startOperationLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorage) StartOperationLogCallCount() int {
+	fake.startOperationLogMutex.RLock()
+	defer fake.startOperationLogMutex.RUnlock()
+	return len(fake.startOperationLogArgsForCall)
+}
+
+func (fake *FakeStorage) StartOperationLogCalls(stub func(storage.OperationLog) error) {
+	fake.startOperationLogMutex.Lock()
+	defer fake.startOperationLogMutex.Unlock()
+	fake.StartOperationLogStub = stub
+}
+
+func (fake *FakeStorage) StartOperationLogArgsForCall(i int) storage.OperationLog {
+	fake.startOperationLogMutex.RLock()
+	defer fake.startOperationLogMutex.RUnlock()
+	argsForCall := fake.startOperationLogArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStorage) StartOperationLogReturns(result1 error) {
+	fake.startOperationLogMutex.Lock()
+	defer fake.startOperationLogMutex.Unlock()
+	fake.StartOperationLogStub = nil
+	fake.startOperationLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) StartOperationLogReturnsOnCall(i int, result1 error) {
+	fake.startOperationLogMutex.Lock()
+	defer fake.startOperationLogMutex.Unlock()
+	fake.StartOperationLogStub = nil
+	if fake.startOperationLogReturnsOnCall == nil {
+		fake.startOperationLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.startOperationLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStorage) StoreBindRequestDetails(arg1 storage.BindRequestDetails) error {
 	fake.storeBindRequestDetailsMutex.Lock()
 	ret, specificReturn := fake.storeBindRequestDetailsReturnsOnCall[len(fake.storeBindRequestDetailsArgsForCall)]
@@ -1366,9 +1675,72 @@ func (fake *FakeStorage) StoreTerraformDeploymentReturnsOnCall(i int, result1 er
 	}{result1}
 }
 
+func (fake *FakeStorage) StoreTerraformLog(arg1 storage.TerraformLog) error {
+	fake.storeTerraformLogMutex.Lock()
+	ret, specificReturn := fake.storeTerraformLogReturnsOnCall[len(fake.storeTerraformLogArgsForCall)]
+	fake.storeTerraformLogArgsForCall = append(fake.storeTerraformLogArgsForCall, struct {
+		arg1 storage.TerraformLog
+	}{arg1})
+	stub := fake.StoreTerraformLogStub
+	fakeReturns := fake.storeTerraformLogReturns
+	fake.recordInvocation("StoreTerraformLog", []interface{}{arg1})
+	fake.storeTerraformLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorage) StoreTerraformLogCallCount() int {
+	fake.storeTerraformLogMutex.RLock()
+	defer fake.storeTerraformLogMutex.RUnlock()
+	return len(fake.storeTerraformLogArgsForCall)
+}
+
+func (fake *FakeStorage) StoreTerraformLogCalls(stub func(storage.TerraformLog) error) {
+	fake.storeTerraformLogMutex.Lock()
+	defer fake.storeTerraformLogMutex.Unlock()
+	fake.StoreTerraformLogStub = stub
+}
+
+func (fake *FakeStorage) StoreTerraformLogArgsForCall(i int) storage.TerraformLog {
+	fake.storeTerraformLogMutex.RLock()
+	defer fake.storeTerraformLogMutex.RUnlock()
+	argsForCall := fake.storeTerraformLogArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStorage) StoreTerraformLogReturns(result1 error) {
+	fake.storeTerraformLogMutex.Lock()
+	defer fake.storeTerraformLogMutex.Unlock()
+	fake.StoreTerraformLogStub = nil
+	fake.storeTerraformLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) StoreTerraformLogReturnsOnCall(i int, result1 error) {
+	fake.storeTerraformLogMutex.Lock()
+	defer fake.storeTerraformLogMutex.Unlock()
+	fake.StoreTerraformLogStub = nil
+	if fake.storeTerraformLogReturnsOnCall == nil {
+		fake.storeTerraformLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeTerraformLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStorage) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.acquireTerraformDeploymentLeaseMutex.RLock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.RUnlock()
 	fake.createServiceBindingCredentialsMutex.RLock()
 	defer fake.createServiceBindingCredentialsMutex.RUnlock()
 	fake.deleteBindRequestDetailsMutex.RLock()
@@ -1385,6 +1757,8 @@ func (fake *FakeStorage) Invocations() map[string][][]interface{} {
 	defer fake.existsServiceInstanceDetailsMutex.RUnlock()
 	fake.existsTerraformDeploymentMutex.RLock()
 	defer fake.existsTerraformDeploymentMutex.RUnlock()
+	fake.finishOperationLogMutex.RLock()
+	defer fake.finishOperationLogMutex.RUnlock()
 	fake.getBindRequestDetailsMutex.RLock()
 	defer fake.getBindRequestDetailsMutex.RUnlock()
 	fake.getProvisionRequestDetailsMutex.RLock()
@@ -1397,6 +1771,10 @@ func (fake *FakeStorage) Invocations() map[string][][]interface{} {
 	defer fake.getServiceInstanceDetailsMutex.RUnlock()
 	fake.getTerraformDeploymentMutex.RLock()
 	defer fake.getTerraformDeploymentMutex.RUnlock()
+	fake.releaseTerraformDeploymentLeaseMutex.RLock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.RUnlock()
+	fake.startOperationLogMutex.RLock()
+	defer fake.startOperationLogMutex.RUnlock()
 	fake.storeBindRequestDetailsMutex.RLock()
 	defer fake.storeBindRequestDetailsMutex.RUnlock()
 	fake.storeProvisionRequestDetailsMutex.RLock()
@@ -1405,6 +1783,8 @@ func (fake *FakeStorage) Invocations() map[string][][]interface{} {
 	defer fake.storeServiceInstanceDetailsMutex.RUnlock()
 	fake.storeTerraformDeploymentMutex.RLock()
 	defer fake.storeTerraformDeploymentMutex.RUnlock()
+	fake.storeTerraformLogMutex.RLock()
+	defer fake.storeTerraformLogMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
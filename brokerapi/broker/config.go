@@ -23,6 +23,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/pkg/brokerpak"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/config"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/credstore"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
 )
 
 type BrokerConfig struct {
@@ -36,6 +37,10 @@ func NewBrokerConfigFromEnv(logger lager.Logger) (*BrokerConfig, error) {
 		return nil, fmt.Errorf("error loading brokerpaks: %v", err)
 	}
 
+	for _, svc := range registry {
+		redaction.AddSensitiveFields(svc.SensitiveFields()...)
+	}
+
 	envConfig, err := config.Parse()
 	if err != nil {
 		return nil, fmt.Errorf("failed loading config: %v", err)
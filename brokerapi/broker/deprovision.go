@@ -83,7 +83,7 @@ func (broker *ServiceBroker) Deprovision(ctx context.Context, instanceID string,
 
 	operationID, err := serviceProvider.Deprovision(ctx, instance.GUID, vars)
 	if err != nil {
-		return domain.DeprovisionServiceSpec{}, err
+		return domain.DeprovisionServiceSpec{}, retryableError(err)
 	}
 
 	if operationID == nil {
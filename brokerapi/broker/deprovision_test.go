@@ -211,6 +211,17 @@ var _ = Describe("Deprovision", func() {
 		})
 	})
 
+	When("another operation has locked the instance", func() {
+		BeforeEach(func() {
+			fakeServiceProvider.DeprovisionReturns(nil, fmt.Errorf("%w: tf:instance:", storage.ErrLeaseHeld))
+		})
+
+		It("should return HTTP 422 as per OSBAPI spec", func() {
+			_, err := serviceBroker.Deprovision(context.TODO(), instanceToDeleteID, deprovisionDetails, true)
+			Expect(err).To(MatchError(apiresponses.ErrConcurrentInstanceAccess))
+		})
+	})
+
 	When("instance does not exists", func() {
 		BeforeEach(func() {
 			fakeStorage.ExistsServiceInstanceDetailsReturns(false, nil)
@@ -2,8 +2,7 @@ package broker
 
 import (
 	"context"
-	"errors"
-	"net/http"
+	"fmt"
 
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/pivotal-cf/brokerapi/v9/domain"
@@ -15,15 +14,58 @@ import (
 // GetBinding fetches an existing service binding.
 // GET /v2/service_instances/{instance_id}/service_bindings/{binding_id}
 //
-// NOTE: This functionality is not implemented.
+// The credentials are rebuilt from the stored binding credentials and instance outputs,
+// so no new credentials are created. When a Credstore is configured, only the CredHub
+// reference is returned, as is the case when the binding is created. Sensitive parameters
+// are write-only, so they are not returned.
 func (broker *ServiceBroker) GetBinding(ctx context.Context, instanceID, bindingID string, _ domain.FetchBindingDetails) (domain.GetBindingSpec, error) {
 	broker.Logger.Info("GetBinding", correlation.ID(ctx), lager.Data{
 		"instance_id": instanceID,
 		"binding_id":  bindingID,
 	})
 
-	return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(
-		errors.New("the service_bindings endpoint is unsupported"),
-		http.StatusBadRequest,
-		"unsupported")
+	// check whether binding exists
+	exists, err := broker.store.ExistsServiceBindingCredentials(bindingID, instanceID)
+	switch {
+	case err != nil:
+		return domain.GetBindingSpec{}, fmt.Errorf("error locating service binding: %w", err)
+	case !exists:
+		return domain.GetBindingSpec{}, apiresponses.ErrBindingNotFound
+	}
+
+	instanceRecord, err := broker.store.GetServiceInstanceDetails(instanceID)
+	if err != nil {
+		return domain.GetBindingSpec{}, fmt.Errorf("error retrieving service instance details: %w", err)
+	}
+
+	serviceDefinition, err := broker.registry.GetServiceByID(instanceRecord.ServiceGUID)
+	if err != nil {
+		return domain.GetBindingSpec{}, fmt.Errorf("error retrieving service definition: %w", err)
+	}
+
+	bindingRecord, err := broker.store.GetServiceBindingCredentials(bindingID, instanceID)
+	if err != nil {
+		return domain.GetBindingSpec{}, fmt.Errorf("error retrieving binding credentials: %w", err)
+	}
+
+	params, err := broker.store.GetBindRequestDetails(bindingID, instanceID)
+	if err != nil {
+		return domain.GetBindingSpec{}, fmt.Errorf("error retrieving bind request details for %q: %w", bindingID, err)
+	}
+
+	binding, err := buildInstanceCredentials(bindingRecord.Credentials, instanceRecord.Outputs)
+	if err != nil {
+		return domain.GetBindingSpec{}, fmt.Errorf("error building credentials: %w", err)
+	}
+
+	if broker.Credstore != nil {
+		binding.Credentials = map[string]any{
+			"credhub-ref": getCredentialName(broker.getServiceName(serviceDefinition), bindingID),
+		}
+	}
+
+	return domain.GetBindingSpec{
+		Credentials: binding.Credentials,
+		Parameters:  withoutSensitiveParameters(params, serviceDefinition.BindInputVariables),
+	}, nil
 }
@@ -1,23 +1,153 @@
 package broker_test
 
 import (
+	"errors"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi/v9/domain"
+	"github.com/pivotal-cf/brokerapi/v9/domain/apiresponses"
 	"golang.org/x/net/context"
 
 	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker"
 	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	pkgBroker "github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/credstore/credstorefakes"
 	"github.com/cloudfoundry/cloud-service-broker/utils"
 )
 
 var _ = Describe("GetBinding", func() {
-	It("is not implemented", func() {
-		serviceBroker, err := broker.New(&broker.BrokerConfig{}, &brokerfakes.FakeStorage{}, utils.NewLogger("brokers-test"))
+	const (
+		offeringID = "test-service-id"
+		planID     = "test-plan-id"
+		instanceID = "test-instance-id"
+		bindingID  = "test-binding-id"
+	)
+
+	var (
+		serviceBroker *broker.ServiceBroker
+		brokerConfig  *broker.BrokerConfig
+		fakeStorage   *brokerfakes.FakeStorage
+	)
+
+	BeforeEach(func() {
+		fakeStorage = &brokerfakes.FakeStorage{}
+		fakeStorage.ExistsServiceBindingCredentialsReturns(true, nil)
+		fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+			GUID:        instanceID,
+			ServiceGUID: offeringID,
+			PlanGUID:    planID,
+			Outputs:     storage.JSONObject{"hostname": "fake-host", "username": "instance-user"},
+		}, nil)
+		fakeStorage.GetServiceBindingCredentialsReturns(storage.ServiceBindingCredentials{
+			ServiceGUID:         offeringID,
+			ServiceInstanceGUID: instanceID,
+			BindingGUID:         bindingID,
+			Credentials:         storage.JSONObject{"username": "binding-user", "password": "fake-password"},
+		}, nil)
+		fakeStorage.GetBindRequestDetailsReturns(storage.JSONObject{"bind_field_1": "bind_value_1"}, nil)
+
+		brokerConfig = &broker.BrokerConfig{
+			Registry: pkgBroker.BrokerRegistry{
+				"test-service": &pkgBroker.ServiceDefinition{
+					ID:                 offeringID,
+					Name:               "test-service",
+					Bindable:           true,
+					BindInputVariables: []pkgBroker.BrokerVariable{{FieldName: "bind_field_1"}, {FieldName: "bind_secret", Sensitive: true}},
+				},
+			},
+		}
+
+		var err error
+		serviceBroker, err = broker.New(brokerConfig, fakeStorage, utils.NewLogger("brokers-test"))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("returns the binding credentials merged with the instance outputs", func() {
+		spec, err := serviceBroker.GetBinding(context.TODO(), instanceID, bindingID, domain.FetchBindingDetails{})
 		Expect(err).ToNot(HaveOccurred())
 
-		_, err = serviceBroker.GetBinding(context.TODO(), "instance-id", "binding-id", domain.FetchBindingDetails{})
+		Expect(spec).To(Equal(domain.GetBindingSpec{
+			Credentials: map[string]any{
+				"hostname": "fake-host",
+				"username": "binding-user",
+				"password": "fake-password",
+			},
+			Parameters: storage.JSONObject{"bind_field_1": "bind_value_1"},
+		}))
+
+		By("reading the records for the right binding")
+		actualBindingID, actualInstanceID := fakeStorage.GetServiceBindingCredentialsArgsForCall(0)
+		Expect(actualBindingID).To(Equal(bindingID))
+		Expect(actualInstanceID).To(Equal(instanceID))
+	})
+
+	When("a credstore is configured", func() {
+		var fakeCredStore *credstorefakes.FakeCredStore
+
+		BeforeEach(func() {
+			fakeCredStore = &credstorefakes.FakeCredStore{}
+			serviceBroker.Credstore = fakeCredStore
+		})
+
+		It("returns a CredHub reference without storing new credentials", func() {
+			spec, err := serviceBroker.GetBinding(context.TODO(), instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(spec.Credentials).To(Equal(map[string]any{
+				"credhub-ref": "/c/csb/test-service/test-binding-id/secrets-and-services",
+			}))
+			Expect(fakeCredStore.PutCallCount()).To(BeZero())
+		})
+	})
+
+	When("the binding has sensitive parameters", func() {
+		BeforeEach(func() {
+			fakeStorage.GetBindRequestDetailsReturns(storage.JSONObject{"bind_field_1": "bind_value_1", "bind_secret": "fake-secret"}, nil)
+		})
+
+		It("does not return them", func() {
+			spec, err := serviceBroker.GetBinding(context.TODO(), instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(spec.Parameters).To(Equal(storage.JSONObject{"bind_field_1": "bind_value_1"}))
+		})
+	})
+
+	When("the binding does not exist", func() {
+		BeforeEach(func() {
+			fakeStorage.ExistsServiceBindingCredentialsReturns(false, nil)
+		})
+
+		It("returns a not found error", func() {
+			_, err := serviceBroker.GetBinding(context.TODO(), instanceID, bindingID, domain.FetchBindingDetails{})
+
+			Expect(err).To(MatchError(apiresponses.ErrBindingNotFound))
+		})
+	})
+
+	When("checking for the binding fails", func() {
+		BeforeEach(func() {
+			fakeStorage.ExistsServiceBindingCredentialsReturns(false, errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			_, err := serviceBroker.GetBinding(context.TODO(), instanceID, bindingID, domain.FetchBindingDetails{})
+
+			Expect(err).To(MatchError("error locating service binding: boom"))
+		})
+	})
+
+	When("reading the binding credentials fails", func() {
+		BeforeEach(func() {
+			fakeStorage.GetServiceBindingCredentialsReturns(storage.ServiceBindingCredentials{}, errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			_, err := serviceBroker.GetBinding(context.TODO(), instanceID, bindingID, domain.FetchBindingDetails{})
 
-		Expect(err).To(MatchError("the service_bindings endpoint is unsupported"))
+			Expect(err).To(MatchError("error retrieving binding credentials: boom"))
+		})
 	})
 })
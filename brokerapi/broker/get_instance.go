@@ -2,27 +2,96 @@ package broker
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"net/http"
 
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/pivotal-cf/brokerapi/v9/domain"
 	"github.com/pivotal-cf/brokerapi/v9/domain/apiresponses"
 
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
 )
 
 // GetInstance fetches information about a service instance
 // GET /v2/service_instances/{instance_id}
 //
-// NOTE: This functionality is not implemented.
+// The stored instance details are considered the source of truth, so the service_id and
+// plan_id are read from the database rather than from the request. Sensitive parameters
+// are write-only, so they are not returned.
 func (broker *ServiceBroker) GetInstance(ctx context.Context, instanceID string, _ domain.FetchInstanceDetails) (domain.GetInstanceDetailsSpec, error) {
 	broker.Logger.Info("GetInstance", correlation.ID(ctx), lager.Data{
 		"instance_id": instanceID,
 	})
 
-	return domain.GetInstanceDetailsSpec{}, apiresponses.NewFailureResponse(
-		errors.New("the service_instances endpoint is unsupported"),
-		http.StatusBadRequest,
-		"unsupported")
+	// check whether instance exists
+	exists, err := broker.store.ExistsServiceInstanceDetails(instanceID)
+	switch {
+	case err != nil:
+		return domain.GetInstanceDetailsSpec{}, fmt.Errorf("database error checking for existing instance: %w", err)
+	case !exists:
+		return domain.GetInstanceDetailsSpec{}, apiresponses.ErrInstanceDoesNotExist
+	}
+
+	instanceRecord, err := broker.store.GetServiceInstanceDetails(instanceID)
+	if err != nil {
+		return domain.GetInstanceDetailsSpec{}, fmt.Errorf("error retrieving service instance details: %w", err)
+	}
+
+	serviceDefinition, serviceProvider, err := broker.getDefinitionAndProvider(instanceRecord.ServiceGUID)
+	if err != nil {
+		return domain.GetInstanceDetailsSpec{}, fmt.Errorf("error retrieving service definition: %w", err)
+	}
+
+	// From the OSB spec: an instance that is still being provisioned must be reported as
+	// not found, and an instance that is being updated must be reported as a concurrency error.
+	// The operation type is only cleared when an operation succeeds, so a provision that has
+	// finished is checked for failure.
+	switch instanceRecord.OperationType {
+	case models.ProvisionOperationType:
+		done, _, err := serviceProvider.PollInstance(ctx, instanceRecord.GUID)
+		switch {
+		case err != nil:
+			return domain.GetInstanceDetailsSpec{}, apiresponses.NewFailureResponse(
+				fmt.Errorf("provision of the service instance failed: %w", err),
+				http.StatusUnprocessableEntity,
+				"get-instance-provision-failed",
+			)
+		case !done:
+			return domain.GetInstanceDetailsSpec{}, apiresponses.ErrInstanceDoesNotExist
+		}
+	case models.UpdateOperationType, models.UpgradeOperationType:
+		return domain.GetInstanceDetailsSpec{}, apiresponses.ErrConcurrentInstanceAccess
+	}
+
+	params, err := broker.store.GetProvisionRequestDetails(instanceID)
+	if err != nil {
+		return domain.GetInstanceDetailsSpec{}, fmt.Errorf("error retrieving provision request details for %q: %w", instanceID, err)
+	}
+
+	return domain.GetInstanceDetailsSpec{
+		ServiceID:    instanceRecord.ServiceGUID,
+		PlanID:       instanceRecord.PlanGUID,
+		DashboardURL: instanceRecord.URL,
+		Parameters:   withoutSensitiveParameters(params, serviceDefinition.ProvisionInputVariables),
+	}, nil
+}
+
+// withoutSensitiveParameters returns the request parameters without the values of the variables
+// that are marked as sensitive
+func withoutSensitiveParameters(params storage.JSONObject, variables []broker.BrokerVariable) storage.JSONObject {
+	result := make(storage.JSONObject, len(params))
+	for k, v := range params {
+		result[k] = v
+	}
+
+	for _, v := range variables {
+		if v.Sensitive {
+			delete(result, v.FieldName)
+		}
+	}
+
+	return result
 }
@@ -1,24 +1,192 @@
 package broker_test
 
 import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/lager/v3"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi/v9/domain"
+	"github.com/pivotal-cf/brokerapi/v9/domain/apiresponses"
 	"golang.org/x/net/context"
 
+	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker"
 	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	pkgBroker "github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+	pkgBrokerFakes "github.com/cloudfoundry/cloud-service-broker/pkg/broker/brokerfakes"
 	"github.com/cloudfoundry/cloud-service-broker/utils"
-
-	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker"
 )
 
 var _ = Describe("GetInstance", func() {
-	It("is not implemented", func() {
-		serviceBroker, err := broker.New(&broker.BrokerConfig{}, &brokerfakes.FakeStorage{}, utils.NewLogger("brokers-test"))
+	const (
+		instanceID = "test-instance-id"
+		offeringID = "test-service-id"
+		planID     = "test-plan-id"
+	)
+
+	var (
+		serviceBroker       *broker.ServiceBroker
+		fakeStorage         *brokerfakes.FakeStorage
+		fakeServiceProvider *pkgBrokerFakes.FakeServiceProvider
+	)
+
+	BeforeEach(func() {
+		fakeServiceProvider = &pkgBrokerFakes.FakeServiceProvider{}
+		providerBuilder := func(logger lager.Logger, store pkgBroker.ServiceProviderStorage) pkgBroker.ServiceProvider {
+			return fakeServiceProvider
+		}
+
+		fakeStorage = &brokerfakes.FakeStorage{}
+		fakeStorage.ExistsServiceInstanceDetailsReturns(true, nil)
+		fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+			GUID:        instanceID,
+			ServiceGUID: offeringID,
+			PlanGUID:    planID,
+			URL:         "https://dashboard.example.com",
+		}, nil)
+		fakeStorage.GetProvisionRequestDetailsReturns(storage.JSONObject{"foo": "bar"}, nil)
+
+		brokerConfig := &broker.BrokerConfig{
+			Registry: pkgBroker.BrokerRegistry{
+				"test-service": &pkgBroker.ServiceDefinition{
+					ID:              offeringID,
+					Name:            "test-service",
+					ProviderBuilder: providerBuilder,
+					ProvisionInputVariables: []pkgBroker.BrokerVariable{
+						{FieldName: "foo"},
+						{FieldName: "admin_password", Sensitive: true},
+					},
+				},
+			},
+		}
+
+		var err error
+		serviceBroker, err = broker.New(brokerConfig, fakeStorage, utils.NewLogger("brokers-test"))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("returns the stored instance details and parameters", func() {
+		spec, err := serviceBroker.GetInstance(context.TODO(), instanceID, domain.FetchInstanceDetails{})
 		Expect(err).ToNot(HaveOccurred())
 
-		_, err = serviceBroker.GetInstance(context.TODO(), "instance-id", domain.FetchInstanceDetails{})
+		Expect(spec).To(Equal(domain.GetInstanceDetailsSpec{
+			ServiceID:    offeringID,
+			PlanID:       planID,
+			DashboardURL: "https://dashboard.example.com",
+			Parameters:   storage.JSONObject{"foo": "bar"},
+		}))
+
+		By("reading the records for the right instance")
+		Expect(fakeStorage.GetServiceInstanceDetailsArgsForCall(0)).To(Equal(instanceID))
+		Expect(fakeStorage.GetProvisionRequestDetailsArgsForCall(0)).To(Equal(instanceID))
+	})
+
+	When("the instance has sensitive parameters", func() {
+		BeforeEach(func() {
+			fakeStorage.GetProvisionRequestDetailsReturns(storage.JSONObject{"foo": "bar", "admin_password": "fake-password"}, nil)
+		})
+
+		It("does not return them", func() {
+			spec, err := serviceBroker.GetInstance(context.TODO(), instanceID, domain.FetchInstanceDetails{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(spec.Parameters).To(Equal(storage.JSONObject{"foo": "bar"}))
+		})
+	})
+
+	When("the instance does not exist", func() {
+		BeforeEach(func() {
+			fakeStorage.ExistsServiceInstanceDetailsReturns(false, nil)
+		})
+
+		It("returns a not found error", func() {
+			_, err := serviceBroker.GetInstance(context.TODO(), instanceID, domain.FetchInstanceDetails{})
+
+			Expect(err).To(MatchError(apiresponses.ErrInstanceDoesNotExist))
+		})
+	})
+
+	When("the instance is being provisioned", func() {
+		BeforeEach(func() {
+			fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+				GUID:          instanceID,
+				ServiceGUID:   offeringID,
+				PlanGUID:      planID,
+				OperationType: models.ProvisionOperationType,
+			}, nil)
+		})
+
+		It("returns a not found error while the provision is in progress", func() {
+			fakeServiceProvider.PollInstanceReturns(false, "in progress", nil)
+
+			_, err := serviceBroker.GetInstance(context.TODO(), instanceID, domain.FetchInstanceDetails{})
+
+			Expect(err).To(MatchError(apiresponses.ErrInstanceDoesNotExist))
+			_, polledID := fakeServiceProvider.PollInstanceArgsForCall(0)
+			Expect(polledID).To(Equal(instanceID))
+		})
+
+		It("returns an unprocessable entity error when the provision failed", func() {
+			fakeServiceProvider.PollInstanceReturns(true, "", errors.New("boom"))
+
+			_, err := serviceBroker.GetInstance(context.TODO(), instanceID, domain.FetchInstanceDetails{})
+
+			Expect(err).To(MatchError("provision of the service instance failed: boom"))
+			var failure *apiresponses.FailureResponse
+			Expect(errors.As(err, &failure)).To(BeTrue())
+			Expect(failure.ValidatedStatusCode(nil)).To(Equal(http.StatusUnprocessableEntity))
+		})
+
+		It("returns the instance when the provision succeeded", func() {
+			fakeServiceProvider.PollInstanceReturns(true, "", nil)
+
+			spec, err := serviceBroker.GetInstance(context.TODO(), instanceID, domain.FetchInstanceDetails{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.ServiceID).To(Equal(offeringID))
+		})
+	})
+
+	When("the instance is being updated", func() {
+		BeforeEach(func() {
+			fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+				GUID:          instanceID,
+				ServiceGUID:   offeringID,
+				OperationType: models.UpdateOperationType,
+			}, nil)
+		})
+
+		It("returns a concurrency error", func() {
+			_, err := serviceBroker.GetInstance(context.TODO(), instanceID, domain.FetchInstanceDetails{})
+
+			Expect(err).To(MatchError(apiresponses.ErrConcurrentInstanceAccess))
+		})
+	})
+
+	When("checking for the instance fails", func() {
+		BeforeEach(func() {
+			fakeStorage.ExistsServiceInstanceDetailsReturns(false, errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			_, err := serviceBroker.GetInstance(context.TODO(), instanceID, domain.FetchInstanceDetails{})
+
+			Expect(err).To(MatchError("database error checking for existing instance: boom"))
+		})
+	})
+
+	When("reading the provision request details fails", func() {
+		BeforeEach(func() {
+			fakeStorage.GetProvisionRequestDetailsReturns(nil, errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			_, err := serviceBroker.GetInstance(context.TODO(), instanceID, domain.FetchInstanceDetails{})
 
-		Expect(err).To(MatchError("the service_instances endpoint is unsupported"))
+			Expect(err).To(MatchError(`error retrieving provision request details for "test-instance-id": boom`))
+		})
 	})
 })
@@ -2,18 +2,22 @@ package broker
 
 import (
 	"context"
+	"fmt"
 
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/pivotal-cf/brokerapi/v9/domain"
 	"github.com/pivotal-cf/brokerapi/v9/domain/apiresponses"
 
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
 )
 
 // LastBindingOperation fetches last operation state for a service binding.
 // GET /v2/service_instances/{instance_id}/service_bindings/{binding_id}/last_operation
 //
-// NOTE: This functionality is not implemented.
+// It is only supported for services with asynchronous bindings.
 func (broker *ServiceBroker) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details domain.PollDetails) (domain.LastOperation, error) {
 	broker.Logger.Info("LastBindingOperation", correlation.ID(ctx), lager.Data{
 		"instance_id":    instanceID,
@@ -23,5 +27,126 @@ func (broker *ServiceBroker) LastBindingOperation(ctx context.Context, instanceI
 		"operation_data": details.OperationData,
 	})
 
-	return domain.LastOperation{}, apiresponses.ErrAsyncRequired
+	exists, err := broker.store.ExistsServiceInstanceDetails(instanceID)
+	switch {
+	case err != nil:
+		return domain.LastOperation{}, fmt.Errorf("database error checking for existing instance: %w", err)
+	case !exists:
+		return domain.LastOperation{}, apiresponses.ErrInstanceDoesNotExist
+	}
+
+	instance, err := broker.store.GetServiceInstanceDetails(instanceID)
+	if err != nil {
+		return domain.LastOperation{}, fmt.Errorf("error retrieving service instance details: %w", err)
+	}
+
+	serviceDefinition, serviceProvider, err := broker.getDefinitionAndProvider(instance.ServiceGUID)
+	if err != nil {
+		return domain.LastOperation{}, err
+	}
+
+	if !serviceDefinition.AsyncBindings {
+		return domain.LastOperation{}, apiresponses.ErrAsyncRequired
+	}
+
+	deploymentID := generateTFBindingID(instanceID, bindingID)
+	exists, err = broker.store.ExistsTerraformDeployment(deploymentID)
+	switch {
+	case err != nil:
+		return domain.LastOperation{}, fmt.Errorf("error locating binding operation: %w", err)
+	case !exists:
+		return domain.LastOperation{}, apiresponses.ErrBindingDoesNotExist
+	}
+
+	deployment, err := broker.store.GetTerraformDeployment(deploymentID)
+	if err != nil {
+		return domain.LastOperation{}, fmt.Errorf("error retrieving binding operation: %w", err)
+	}
+
+	done, message, err := serviceProvider.PollBinding(ctx, instanceID, bindingID)
+	if err != nil {
+		cleanupErr := broker.updateBindingStateOnOperationFailure(serviceDefinition, deployment.LastOperationType, instanceID, bindingID)
+		return domain.LastOperation{State: domain.Failed, Description: err.Error()}, cleanupErr
+	}
+
+	if !done {
+		return domain.LastOperation{State: domain.InProgress, Description: message}, nil
+	}
+
+	updateErr := broker.updateBindingStateOnOperationCompletion(ctx, serviceDefinition, serviceProvider, deployment.LastOperationType, instance, bindingID)
+
+	return domain.LastOperation{State: domain.Succeeded, Description: message}, updateErr
+}
+
+// updateBindingStateOnOperationCompletion stores the credentials of a new binding, or removes
+// a deleted binding, once an asynchronous binding operation finishes successfully.
+// The platform may poll more than once, so it is safe to call repeatedly.
+func (broker *ServiceBroker) updateBindingStateOnOperationCompletion(ctx context.Context, serviceDefinition *broker.ServiceDefinition, serviceProvider broker.ServiceProvider, lastOperationType string, instance storage.ServiceInstanceDetails, bindingID string) error {
+	exists, err := broker.store.ExistsServiceBindingCredentials(bindingID, instance.GUID)
+	if err != nil {
+		return fmt.Errorf("error checking for existing binding: %w", err)
+	}
+
+	switch lastOperationType {
+	case models.BindOperationType:
+		if exists {
+			return nil
+		}
+
+		outputs, err := serviceProvider.GetBindingOutputs(ctx, instance.GUID, bindingID)
+		if err != nil {
+			return fmt.Errorf("error getting binding outputs: %w", err)
+		}
+
+		newCreds := storage.ServiceBindingCredentials{
+			ServiceInstanceGUID: instance.GUID,
+			BindingGUID:         bindingID,
+			ServiceGUID:         instance.ServiceGUID,
+			Credentials:         outputs,
+		}
+		if err := broker.store.CreateServiceBindingCredentials(newCreds); err != nil {
+			return fmt.Errorf("error saving credentials to database: %w. WARNING: these credentials cannot be unbound through cf. Please contact your operator for cleanup", err)
+		}
+
+		if broker.Credstore != nil {
+			binding, err := buildInstanceCredentials(newCreds.Credentials, instance.Outputs)
+			if err != nil {
+				return fmt.Errorf("error building credentials: %w", err)
+			}
+
+			credentialName := getCredentialName(broker.getServiceName(serviceDefinition), bindingID)
+			if _, err := broker.Credstore.Put(credentialName, binding.Credentials); err != nil {
+				return fmt.Errorf("bind failure: unable to put credentials in Credstore: %w", err)
+			}
+		}
+	case models.UnbindOperationType:
+		if !exists {
+			return nil
+		}
+
+		return broker.removeBinding(serviceDefinition, instance.GUID, bindingID)
+	}
+
+	return nil
+}
+
+// updateBindingStateOnOperationFailure removes the records stored when a binding was requested,
+// so that a failed asynchronous bind can be requested again.
+func (broker *ServiceBroker) updateBindingStateOnOperationFailure(serviceDefinition *broker.ServiceDefinition, lastOperationType, instanceID, bindingID string) error {
+	if lastOperationType != models.BindOperationType {
+		return nil
+	}
+
+	if broker.Credstore != nil {
+		credentialName := getCredentialName(broker.getServiceName(serviceDefinition), bindingID)
+		if err := broker.Credstore.DeletePermission(credentialName); err != nil {
+			broker.Logger.Error(fmt.Sprintf("fail to delete permissions on the key %s", credentialName), err)
+		}
+	}
+
+	if err := broker.store.DeleteBindRequestDetails(bindingID, instanceID); err != nil {
+		return fmt.Errorf("error deleting bind request details from database: %w", err)
+	}
+
+	return nil
 }
@@ -1,24 +1,232 @@
 package broker_test
 
 import (
+	"errors"
+
+	"code.cloudfoundry.org/lager/v3"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi/v9/domain"
+	"github.com/pivotal-cf/brokerapi/v9/domain/apiresponses"
 	"golang.org/x/net/context"
 
+	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker"
 	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	pkgBroker "github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+	pkgBrokerFakes "github.com/cloudfoundry/cloud-service-broker/pkg/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/credstore/credstorefakes"
 	"github.com/cloudfoundry/cloud-service-broker/utils"
-
-	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker"
 )
 
 var _ = Describe("LastBindingOperation", func() {
-	It("is not implemented for async bindings", func() {
-		serviceBroker, err := broker.New(&broker.BrokerConfig{}, &brokerfakes.FakeStorage{}, utils.NewLogger("brokers-test"))
+	const (
+		offeringID = "test-service-id"
+		planID     = "test-plan-id"
+		instanceID = "test-instance-id"
+		bindingID  = "test-binding-id"
+	)
+
+	var (
+		serviceBroker *broker.ServiceBroker
+		brokerConfig  *broker.BrokerConfig
+
+		fakeStorage         *brokerfakes.FakeStorage
+		fakeServiceProvider *pkgBrokerFakes.FakeServiceProvider
+		fakeCredStore       *credstorefakes.FakeCredStore
+	)
+
+	BeforeEach(func() {
+		fakeServiceProvider = &pkgBrokerFakes.FakeServiceProvider{}
+		fakeServiceProvider.GetBindingOutputsReturns(storage.JSONObject{"username": "binding-user"}, nil)
+
+		fakeStorage = &brokerfakes.FakeStorage{}
+		fakeStorage.ExistsServiceInstanceDetailsReturns(true, nil)
+		fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+			GUID:        instanceID,
+			ServiceGUID: offeringID,
+			PlanGUID:    planID,
+			Outputs:     storage.JSONObject{"hostname": "fake-host"},
+		}, nil)
+		fakeStorage.ExistsTerraformDeploymentReturns(true, nil)
+		fakeStorage.GetTerraformDeploymentReturns(storage.TerraformDeployment{
+			ID:                 "tf:test-instance-id:test-binding-id",
+			LastOperationType:  models.BindOperationType,
+			LastOperationState: "in progress",
+		}, nil)
+
+		fakeCredStore = &credstorefakes.FakeCredStore{}
+
+		providerBuilder := func(logger lager.Logger, store pkgBroker.ServiceProviderStorage) pkgBroker.ServiceProvider {
+			return fakeServiceProvider
+		}
+
+		brokerConfig = &broker.BrokerConfig{
+			Registry: pkgBroker.BrokerRegistry{
+				"test-service": &pkgBroker.ServiceDefinition{
+					ID:              offeringID,
+					Name:            "test-service",
+					Bindable:        true,
+					AsyncBindings:   true,
+					ProviderBuilder: providerBuilder,
+				},
+			},
+			Credstore: fakeCredStore,
+		}
+
+		var err error
+		serviceBroker, err = broker.New(brokerConfig, fakeStorage, utils.NewLogger("brokers-test"))
 		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("returns in progress while the operation is running", func() {
+		fakeServiceProvider.PollBindingReturns(false, "bind in progress", nil)
+
+		response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(response).To(Equal(domain.LastOperation{State: domain.InProgress, Description: "bind in progress"}))
+		_, actualInstanceID, actualBindingID := fakeServiceProvider.PollBindingArgsForCall(0)
+		Expect(actualInstanceID).To(Equal(instanceID))
+		Expect(actualBindingID).To(Equal(bindingID))
+		Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(BeZero())
+	})
+
+	When("a bind operation succeeds", func() {
+		BeforeEach(func() {
+			fakeServiceProvider.PollBindingReturns(true, "bind succeeded", nil)
+		})
+
+		It("stores the binding credentials", func() {
+			response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(response).To(Equal(domain.LastOperation{State: domain.Succeeded, Description: "bind succeeded"}))
+
+			By("validating the credentials are stored in the database")
+			Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(Equal(1))
+			Expect(fakeStorage.CreateServiceBindingCredentialsArgsForCall(0)).To(Equal(storage.ServiceBindingCredentials{
+				ServiceGUID:         offeringID,
+				ServiceInstanceGUID: instanceID,
+				BindingGUID:         bindingID,
+				Credentials:         storage.JSONObject{"username": "binding-user"},
+			}))
+
+			By("validating the merged credentials are put in the credstore")
+			Expect(fakeCredStore.PutCallCount()).To(Equal(1))
+			actualKey, actualCredentials := fakeCredStore.PutArgsForCall(0)
+			Expect(actualKey).To(Equal("/c/csb/test-service/test-binding-id/secrets-and-services"))
+			Expect(actualCredentials).To(Equal(map[string]any{"hostname": "fake-host", "username": "binding-user"}))
+		})
+
+		It("does not store the credentials again when polled again", func() {
+			fakeStorage.ExistsServiceBindingCredentialsReturns(true, nil)
+
+			_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(BeZero())
+			Expect(fakeCredStore.PutCallCount()).To(BeZero())
+		})
+
+		It("returns an error when the outputs cannot be read", func() {
+			fakeServiceProvider.GetBindingOutputsReturns(nil, errors.New("boom"))
+
+			_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+
+			Expect(err).To(MatchError("error getting binding outputs: boom"))
+		})
+	})
+
+	When("a bind operation fails", func() {
+		BeforeEach(func() {
+			fakeServiceProvider.PollBindingReturns(true, "bind failed: boom", errors.New("bind failed: boom"))
+		})
+
+		It("returns failed and removes the bind request details", func() {
+			response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(response).To(Equal(domain.LastOperation{State: domain.Failed, Description: "bind failed: boom"}))
+			Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(BeZero())
+			Expect(fakeStorage.DeleteBindRequestDetailsCallCount()).To(Equal(1))
+			Expect(fakeCredStore.DeletePermissionCallCount()).To(Equal(1))
+		})
+	})
+
+	When("an unbind operation succeeds", func() {
+		BeforeEach(func() {
+			fakeStorage.GetTerraformDeploymentReturns(storage.TerraformDeployment{
+				LastOperationType:  models.UnbindOperationType,
+				LastOperationState: "succeeded",
+			}, nil)
+			fakeStorage.ExistsServiceBindingCredentialsReturns(true, nil)
+			fakeServiceProvider.PollBindingReturns(true, "unbind succeeded", nil)
+		})
+
+		It("removes the binding", func() {
+			response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(response).To(Equal(domain.LastOperation{State: domain.Succeeded, Description: "unbind succeeded"}))
+			Expect(fakeCredStore.DeleteCallCount()).To(Equal(1))
+			Expect(fakeStorage.DeleteServiceBindingCredentialsCallCount()).To(Equal(1))
+			Expect(fakeStorage.DeleteBindRequestDetailsCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the service does not have async bindings", func() {
+		BeforeEach(func() {
+			brokerConfig.Registry["test-service"].AsyncBindings = false
+		})
+
+		It("returns an error", func() {
+			_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+
+			Expect(err).To(MatchError("This service plan requires client support for asynchronous service operations."))
+		})
+	})
+
+	When("there is no binding operation", func() {
+		BeforeEach(func() {
+			fakeStorage.ExistsTerraformDeploymentReturns(false, nil)
+		})
+
+		It("returns an error", func() {
+			_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+
+			Expect(err).To(MatchError(apiresponses.ErrBindingDoesNotExist))
+		})
+	})
+
+	When("the instance does not exist", func() {
+		BeforeEach(func() {
+			fakeStorage.ExistsServiceInstanceDetailsReturns(false, nil)
+		})
+
+		It("returns a not found error", func() {
+			_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+
+			Expect(err).To(MatchError(apiresponses.ErrInstanceDoesNotExist))
+		})
+	})
+
+	When("reading the instance fails", func() {
+		It("returns the error when checking for the instance fails", func() {
+			fakeStorage.ExistsServiceInstanceDetailsReturns(false, errors.New("boom"))
+
+			_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+
+			Expect(err).To(MatchError("database error checking for existing instance: boom"))
+		})
+
+		It("returns the error when retrieving the instance fails", func() {
+			fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{}, errors.New("boom"))
 
-		_, err = serviceBroker.LastBindingOperation(context.TODO(), "instance-id", "binding-id", domain.PollDetails{})
+			_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
 
-		Expect(err).To(MatchError("This service plan requires client support for asynchronous service operations."))
+			Expect(err).To(MatchError("error retrieving service instance details: boom"))
+		})
 	})
 })
@@ -0,0 +1,68 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"code.cloudfoundry.org/lager/v3"
+	"github.com/pivotal-cf/brokerapi/v9/domain"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
+)
+
+// PlanUpdate reports the changes that updating a service instance to a plan and parameters would
+// make to its resources. The update goes through the same validation and variable resolution as an
+// update request, but it is only planned: neither the service instance nor its resources are changed.
+// When the plan ID is empty, the current plan of the service instance is used.
+func (broker *ServiceBroker) PlanUpdate(ctx context.Context, instanceID, planID string, params json.RawMessage) ([]storage.TerraformResourceChange, error) {
+	broker.Logger.Info("PlanUpdate", correlation.ID(ctx), lager.Data{
+		"instance_id": instanceID,
+		"plan_id":     planID,
+	})
+
+	instance, err := broker.store.GetServiceInstanceDetails(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("database error getting existing instance: %s", err)
+	}
+
+	serviceDefinition, err := broker.registry.GetServiceByID(instance.ServiceGUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if planID == "" {
+		planID = instance.PlanGUID
+	}
+	plan, err := serviceDefinition.GetPlanByID(planID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The maintenance info is not changed, so the request is always decided to be an update rather than an upgrade
+	details := domain.UpdateDetails{
+		ServiceID:       instance.ServiceGUID,
+		PlanID:          planID,
+		RawParameters:   params,
+		MaintenanceInfo: plan.MaintenanceInfo,
+		PreviousValues: domain.PreviousValues{
+			PlanID:          instance.PlanGUID,
+			ServiceID:       instance.ServiceGUID,
+			OrgID:           instance.OrganizationGUID,
+			SpaceID:         instance.SpaceGUID,
+			MaintenanceInfo: plan.MaintenanceInfo,
+		},
+	}
+
+	req, err := broker.prepareUpdate(ctx, instanceID, details)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.serviceProvider.CheckUpgradeAvailable(generateTFInstanceID(instance.GUID)); err != nil {
+		return nil, fmt.Errorf("terraform version check failed: %s", err.Error())
+	}
+
+	return req.serviceProvider.PlanUpdate(ctx, req.vars)
+}
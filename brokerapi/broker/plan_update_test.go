@@ -0,0 +1,148 @@
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/lager/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi/v9/domain"
+
+	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker"
+	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	pkgBroker "github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+	pkgBrokerFakes "github.com/cloudfoundry/cloud-service-broker/pkg/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+)
+
+var _ = Describe("PlanUpdate", func() {
+	const (
+		originalPlanID = "test-plan-id"
+		newPlanID      = "new-test-plan-id"
+		offeringID     = "test-service-id"
+		instanceID     = "test-instance-id"
+	)
+
+	var (
+		serviceBroker       *broker.ServiceBroker
+		fakeStorage         *brokerfakes.FakeStorage
+		fakeServiceProvider *pkgBrokerFakes.FakeServiceProvider
+	)
+
+	BeforeEach(func() {
+		fakeServiceProvider = &pkgBrokerFakes.FakeServiceProvider{}
+		fakeServiceProvider.PlanUpdateReturns([]storage.TerraformResourceChange{{Address: "random_password.password", Action: "replace"}}, nil)
+
+		brokerConfig := &broker.BrokerConfig{
+			Registry: pkgBroker.BrokerRegistry{
+				"test-service": &pkgBroker.ServiceDefinition{
+					ID:   offeringID,
+					Name: "test-service",
+					Plans: []pkgBroker.ServicePlan{
+						{
+							ServicePlan: domain.ServicePlan{
+								ID:              originalPlanID,
+								Name:            "test-plan",
+								MaintenanceInfo: &domain.MaintenanceInfo{Version: "2.0.0"},
+							},
+							ServiceProperties: map[string]any{"plan-defined-key": "original-plan-value"},
+						},
+						{
+							ServicePlan: domain.ServicePlan{
+								ID:              newPlanID,
+								Name:            "new-test-plan",
+								MaintenanceInfo: &domain.MaintenanceInfo{Version: "2.0.0"},
+							},
+							ServiceProperties: map[string]any{"plan-defined-key": "new-plan-value"},
+						},
+					},
+					ProvisionInputVariables: []pkgBroker.BrokerVariable{
+						{FieldName: "foo", Type: "string", Details: "fake field name"},
+						{FieldName: "bar", Type: "string", Details: "other fake field name"},
+					},
+					ProviderBuilder: func(logger lager.Logger, store pkgBroker.ServiceProviderStorage) pkgBroker.ServiceProvider {
+						return fakeServiceProvider
+					},
+				},
+			},
+		}
+
+		fakeStorage = &brokerfakes.FakeStorage{}
+		fakeStorage.ExistsServiceInstanceDetailsReturns(true, nil)
+		fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+			GUID:        instanceID,
+			ServiceGUID: offeringID,
+			PlanGUID:    originalPlanID,
+		}, nil)
+		fakeStorage.GetProvisionRequestDetailsReturns(map[string]any{"foo": "provisioned"}, nil)
+
+		var err error
+		serviceBroker, err = broker.New(brokerConfig, fakeStorage, utils.NewLogger("brokers-test"))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("plans the update with the merged parameters, without changing the service instance", func() {
+		changes, err := serviceBroker.PlanUpdate(context.TODO(), instanceID, newPlanID, json.RawMessage(`{"bar":"updated"}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(changes).To(Equal([]storage.TerraformResourceChange{{Address: "random_password.password", Action: "replace"}}))
+		Expect(fakeServiceProvider.CheckUpgradeAvailableArgsForCall(0)).To(Equal("tf:test-instance-id:"))
+
+		Expect(fakeServiceProvider.PlanUpdateCallCount()).To(Equal(1))
+		_, vars := fakeServiceProvider.PlanUpdateArgsForCall(0)
+		Expect(vars.GetString("foo")).To(Equal("provisioned"))
+		Expect(vars.GetString("bar")).To(Equal("updated"))
+		Expect(vars.GetString("plan-defined-key")).To(Equal("new-plan-value"))
+
+		Expect(fakeServiceProvider.UpdateCallCount()).To(BeZero())
+		Expect(fakeStorage.StoreServiceInstanceDetailsCallCount()).To(BeZero())
+		Expect(fakeStorage.StoreProvisionRequestDetailsCallCount()).To(BeZero())
+	})
+
+	It("uses the current plan when no plan is specified", func() {
+		_, err := serviceBroker.PlanUpdate(context.TODO(), instanceID, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, vars := fakeServiceProvider.PlanUpdateArgsForCall(0)
+		Expect(vars.GetString("plan-defined-key")).To(Equal("original-plan-value"))
+	})
+
+	It("validates the parameters in the same way as an update", func() {
+		_, err := serviceBroker.PlanUpdate(context.TODO(), instanceID, "", json.RawMessage(`{"unknown":"value"}`))
+		Expect(err).To(MatchError(ContainSubstring("additional properties are not allowed: unknown")))
+		Expect(fakeServiceProvider.PlanUpdateCallCount()).To(BeZero())
+	})
+
+	When("the plan does not exist", func() {
+		It("returns an error", func() {
+			_, err := serviceBroker.PlanUpdate(context.TODO(), instanceID, "non-existent-plan", nil)
+			Expect(err).To(MatchError(`plan ID "non-existent-plan" could not be found`))
+		})
+	})
+
+	When("the instance must be upgraded before it can be updated", func() {
+		BeforeEach(func() {
+			fakeServiceProvider.CheckUpgradeAvailableReturns(errors.New("upgrade required"))
+		})
+
+		It("returns an error", func() {
+			_, err := serviceBroker.PlanUpdate(context.TODO(), instanceID, "", nil)
+			Expect(err).To(MatchError("terraform version check failed: upgrade required"))
+			Expect(fakeServiceProvider.PlanUpdateCallCount()).To(BeZero())
+		})
+	})
+
+	When("planning fails", func() {
+		BeforeEach(func() {
+			fakeServiceProvider.PlanUpdateReturns(nil, errors.New("plan failed"))
+		})
+
+		It("returns the error", func() {
+			_, err := serviceBroker.PlanUpdate(context.TODO(), instanceID, "", nil)
+			Expect(err).To(MatchError("plan failed"))
+		})
+	})
+})
@@ -84,7 +84,7 @@ func (broker *ServiceBroker) Provision(ctx context.Context, instanceID string, d
 	// get instance details
 	instanceDetails, err := serviceProvider.Provision(ctx, vars)
 	if err != nil {
-		return domain.ProvisionedServiceSpec{}, err
+		return domain.ProvisionedServiceSpec{}, retryableError(err)
 	}
 
 	// save instance details
@@ -99,7 +99,7 @@ func (broker *ServiceBroker) Provision(ctx context.Context, instanceID string, d
 	}
 
 	// save provision request details
-	if err := broker.store.StoreProvisionRequestDetails(instanceID, parsedDetails.RequestParams); err != nil {
+	if err := broker.store.StoreProvisionRequestDetails(instanceID, withoutTransientParameters(parsedDetails.RequestParams, serviceDefinition.TransientParameters)); err != nil {
 		return domain.ProvisionedServiceSpec{}, fmt.Errorf("error saving provision request details to database: %s. Services relying on async provisioning will not be able to complete provisioning", err)
 	}
 
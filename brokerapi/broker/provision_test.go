@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/cloudfoundry/cloud-service-broker/brokerapi/broker"
@@ -14,6 +15,7 @@ import (
 	pkgBroker "github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	pkgBrokerFakes "github.com/cloudfoundry/cloud-service-broker/pkg/broker/brokerfakes"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/featureflags"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/varcontext"
 	"github.com/cloudfoundry/cloud-service-broker/utils"
 	. "github.com/onsi/ginkgo/v2"
@@ -85,7 +87,13 @@ var _ = Describe("Provision", func() {
 							Type:      "string",
 							Details:   "yet another fake field name",
 						},
+						{
+							FieldName: "confirm",
+							Type:      "boolean",
+							Details:   "fake transient field",
+						},
 					},
+					TransientParameters: []string{"confirm"},
 					ImportInputVariables: []pkgBroker.ImportVariable{
 						{
 							Name:       "import_field_1",
@@ -177,6 +185,22 @@ var _ = Describe("Provision", func() {
 			Expect(actualParams).To(Equal(expectedParams))
 		})
 
+		It("should not store transient parameters", func() {
+			provisionDetails = domain.ProvisionDetails{
+				ServiceID:     offeringID,
+				PlanID:        planID,
+				RawParameters: json.RawMessage(`{"foo":"something", "confirm":true}`),
+			}
+
+			_, err := serviceBroker.Provision(context.TODO(), newInstanceID, provisionDetails, true)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, actualVars := fakeServiceProvider.ProvisionArgsForCall(0)
+			Expect(actualVars.GetBool("confirm")).To(BeTrue())
+			_, actualParams := fakeStorage.StoreProvisionRequestDetailsArgsForCall(0)
+			Expect(actualParams).To(Equal(storage.JSONObject{"foo": "something"}))
+		})
+
 		Describe("provision variables", func() {
 			It("passes plan provided service properties", func() {
 				_, err := serviceBroker.Provision(context.TODO(), newInstanceID, provisionDetails, true)
@@ -263,6 +287,35 @@ var _ = Describe("Provision", func() {
 		})
 	})
 
+	When("another operation has locked the instance", func() {
+		BeforeEach(func() {
+			fakeServiceProvider.ProvisionReturns(storage.ServiceInstanceDetails{}, fmt.Errorf("error marking job started: %w", storage.ErrLeaseHeld))
+		})
+
+		It("should return HTTP 422 as per OSBAPI spec", func() {
+			_, err := serviceBroker.Provision(context.TODO(), "new-instance", provisionDetails, true)
+			Expect(err).To(MatchError(apiresponses.ErrConcurrentInstanceAccess))
+		})
+	})
+
+	When("too many terraform operations are queued", func() {
+		BeforeEach(func() {
+			fakeServiceProvider.ProvisionReturns(storage.ServiceInstanceDetails{}, executor.ErrQueueFull)
+		})
+
+		It("should return HTTP 429 so that the platform retries", func() {
+			_, err := serviceBroker.Provision(context.TODO(), "new-instance", provisionDetails, true)
+
+			var failure *apiresponses.FailureResponse
+			Expect(errors.As(err, &failure)).To(BeTrue())
+			Expect(failure.ValidatedStatusCode(nil)).To(Equal(http.StatusTooManyRequests))
+			Expect(failure.ErrorResponse()).To(Equal(apiresponses.ErrorResponse{
+				Error:       "TerraformQueueFull",
+				Description: executor.ErrQueueFull.Error(),
+			}))
+		})
+	})
+
 	When("instance already exists", func() {
 		BeforeEach(func() {
 			fakeStorage.ExistsServiceInstanceDetailsReturns(true, nil)
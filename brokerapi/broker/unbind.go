@@ -9,17 +9,20 @@ import (
 	"github.com/pivotal-cf/brokerapi/v9/domain/apiresponses"
 
 	"github.com/cloudfoundry/cloud-service-broker/internal/paramparser"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
 	"github.com/cloudfoundry/cloud-service-broker/utils/request"
 )
 
 // Unbind destroys an account and credentials with access to an instance of a service.
 // It is bound to the `DELETE /v2/service_instances/:instance_id/service_bindings/:binding_id` endpoint and can be called using the `cf unbind-service` command.
-func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID string, details domain.UnbindDetails, _ bool) (domain.UnbindSpec, error) {
+// If the service has asynchronous bindings, the returned UnbindSpec will contain the operation ID for tracking its progress.
+func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID string, details domain.UnbindDetails, clientSupportsAsync bool) (domain.UnbindSpec, error) {
 	broker.Logger.Info("Unbinding", correlation.ID(ctx), lager.Data{
-		"instance_id": instanceID,
-		"binding_id":  bindingID,
-		"details":     details,
+		"instance_id":        instanceID,
+		"binding_id":         bindingID,
+		"accepts_incomplete": clientSupportsAsync,
+		"details":            details,
 	})
 
 	// verify the service exists and the plan exists
@@ -28,6 +31,10 @@ func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID s
 		return domain.UnbindSpec{}, err
 	}
 
+	if serviceDefinition.AsyncBindings && !clientSupportsAsync {
+		return domain.UnbindSpec{}, apiresponses.ErrAsyncRequired
+	}
+
 	// validate existence of binding
 	exists, err := broker.store.ExistsServiceBindingCredentials(bindingID, instanceID)
 	switch {
@@ -70,33 +77,53 @@ func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID s
 		return domain.UnbindSpec{}, err
 	}
 
+	if serviceDefinition.AsyncBindings {
+		operationID, err := serviceProvider.UnbindAsync(ctx, instanceID, bindingID, vars)
+		if err != nil {
+			return domain.UnbindSpec{}, retryableError(err)
+		}
+
+		// the binding is removed from the database by LastBindingOperation once the operation has completed
+		return domain.UnbindSpec{IsAsync: true, OperationData: operationID}, nil
+	}
+
 	// remove binding from service provider
 	if err := serviceProvider.Unbind(ctx, instanceID, bindingID, vars); err != nil {
+		return domain.UnbindSpec{}, retryableError(err)
+	}
+
+	if err := broker.removeBinding(serviceDefinition, instanceID, bindingID); err != nil {
 		return domain.UnbindSpec{}, err
 	}
 
+	return domain.UnbindSpec{}, nil
+}
+
+// removeBinding deletes the credentials and request details of a binding that has been
+// destroyed by the service provider.
+func (broker *ServiceBroker) removeBinding(serviceDefinition *broker.ServiceDefinition, instanceID, bindingID string) error {
 	if broker.Credstore != nil {
 
 		credentialName := getCredentialName(broker.getServiceName(serviceDefinition), bindingID)
 
-		err = broker.Credstore.DeletePermission(credentialName)
+		err := broker.Credstore.DeletePermission(credentialName)
 		if err != nil {
 			broker.Logger.Error(fmt.Sprintf("fail to delete permissions on the key %s", credentialName), err)
 		}
 
-		err := broker.Credstore.Delete(credentialName)
+		err = broker.Credstore.Delete(credentialName)
 		if err != nil {
-			return domain.UnbindSpec{}, err
+			return err
 		}
 	}
 
 	// remove binding from database
 	if err := broker.store.DeleteServiceBindingCredentials(bindingID, instanceID); err != nil {
-		return domain.UnbindSpec{}, fmt.Errorf("error soft-deleting credentials from database: %s. WARNING: these credentials will remain visible in cf. Contact your operator for cleanup", err)
+		return fmt.Errorf("error soft-deleting credentials from database: %s. WARNING: these credentials will remain visible in cf. Contact your operator for cleanup", err)
 	}
 	if err := broker.store.DeleteBindRequestDetails(bindingID, instanceID); err != nil {
-		return domain.UnbindSpec{}, fmt.Errorf("error soft-deleting bind request details from database: %s", err)
+		return fmt.Errorf("error soft-deleting bind request details from database: %s", err)
 	}
 
-	return domain.UnbindSpec{}, nil
+	return nil
 }
@@ -209,6 +209,57 @@ var _ = Describe("Unbind", func() {
 		})
 	})
 
+	Describe("async unbind", func() {
+		BeforeEach(func() {
+			brokerConfig.Registry["test-service"].AsyncBindings = true
+			fakeServiceProvider.UnbindAsyncReturns("tf:test-instance-id:test-binding-id", nil)
+		})
+
+		It("starts the unbind and leaves the binding in the database", func() {
+			response, err := serviceBroker.Unbind(context.TODO(), instanceID, bindingID, unbindDetails, true)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("validating response")
+			Expect(response).To(Equal(domain.UnbindSpec{
+				IsAsync:       true,
+				OperationData: "tf:test-instance-id:test-binding-id",
+			}))
+
+			By("validating provider async unbind has been called")
+			Expect(fakeServiceProvider.UnbindAsyncCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.UnbindCallCount()).To(BeZero())
+			_, actualInstanceID, actualBindingID, _ := fakeServiceProvider.UnbindAsyncArgsForCall(0)
+			Expect(actualInstanceID).To(Equal(instanceID))
+			Expect(actualBindingID).To(Equal(bindingID))
+
+			By("validating the binding is not yet removed")
+			Expect(fakeCredStore.DeleteCallCount()).To(BeZero())
+			Expect(fakeStorage.DeleteServiceBindingCredentialsCallCount()).To(BeZero())
+			Expect(fakeStorage.DeleteBindRequestDetailsCallCount()).To(BeZero())
+		})
+
+		When("the client does not support async operations", func() {
+			It("returns an error", func() {
+				_, err := serviceBroker.Unbind(context.TODO(), instanceID, bindingID, unbindDetails, false)
+
+				Expect(err).To(MatchError(apiresponses.ErrAsyncRequired))
+				Expect(fakeServiceProvider.UnbindAsyncCallCount()).To(BeZero())
+			})
+		})
+
+		When("provider async unbind fails", func() {
+			BeforeEach(func() {
+				fakeServiceProvider.UnbindAsyncReturns("", fmt.Errorf("unbind-error"))
+			})
+
+			It("should error", func() {
+				_, err := serviceBroker.Unbind(context.TODO(), instanceID, bindingID, unbindDetails, true)
+
+				Expect(err).To(MatchError("unbind-error"))
+			})
+		})
+	})
+
 	Describe("unsuccessful unbind", func() {
 		When("service offering does not exists", func() {
 			const nonExistentService = "non-existent-service"
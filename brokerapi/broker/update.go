@@ -32,92 +32,126 @@ func (broker *ServiceBroker) Update(ctx context.Context, instanceID string, deta
 		"details":            details,
 	})
 
+	// verify async provisioning is allowed if it is required
+	if !asyncAllowed {
+		return domain.UpdateServiceSpec{}, apiresponses.ErrAsyncRequired
+	}
+
+	req, err := broker.prepareUpdate(ctx, instanceID, details)
+	if err != nil {
+		return domain.UpdateServiceSpec{}, err
+	}
+
+	switch req.operation {
+	case decider.Upgrade:
+		return broker.doUpgrade(ctx, req.serviceDefinition, req.serviceProvider, req.instance, req.vars, req.plan)
+	default:
+		return broker.doUpdate(ctx, req.serviceProvider, req.instance, req.vars, req.parsedDetails, req.mergedDetails)
+	}
+}
+
+// updateRequest is a validated update request, with the variables that are passed to the service provider
+type updateRequest struct {
+	instance          storage.ServiceInstanceDetails
+	serviceDefinition *broker.ServiceDefinition
+	serviceProvider   broker.ServiceProvider
+	plan              *broker.ServicePlan
+	parsedDetails     paramparser.UpdateDetails
+	operation         decider.Operation
+	vars              *varcontext.VarContext
+	mergedDetails     map[string]any
+}
+
+// prepareUpdate validates an update request, and merges its parameters with those of previous
+// provision and update requests. It does not modify the service instance.
+func (broker *ServiceBroker) prepareUpdate(ctx context.Context, instanceID string, details domain.UpdateDetails) (updateRequest, error) {
 	// make sure that instance actually exists
 	exists, err := broker.store.ExistsServiceInstanceDetails(instanceID)
 	switch {
 	case err != nil:
-		return domain.UpdateServiceSpec{}, fmt.Errorf("database error checking for existing instance: %s", err)
+		return updateRequest{}, fmt.Errorf("database error checking for existing instance: %s", err)
 	case !exists:
-		return domain.UpdateServiceSpec{}, apiresponses.ErrInstanceDoesNotExist
+		return updateRequest{}, apiresponses.ErrInstanceDoesNotExist
 	}
 
 	instance, err := broker.store.GetServiceInstanceDetails(instanceID)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, fmt.Errorf("database error getting existing instance: %s", err)
+		return updateRequest{}, fmt.Errorf("database error getting existing instance: %s", err)
 	}
 
 	serviceDefinition, serviceProvider, err := broker.getDefinitionAndProvider(instance.ServiceGUID)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, err
+		return updateRequest{}, err
 	}
 
 	parsedDetails, err := paramparser.ParseUpdateDetails(details)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, ErrInvalidUserInput
+		return updateRequest{}, ErrInvalidUserInput
 	}
 
 	// verify the service exists and the plan exists
 	plan, err := serviceDefinition.GetPlanByID(parsedDetails.PlanID)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, err
+		return updateRequest{}, err
 	}
 	maintenanceInfoVersion, err := readMaintenanceInfoVersion(plan)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, err
-	}
-
-	// verify async provisioning is allowed if it is required
-	if !asyncAllowed {
-		return domain.UpdateServiceSpec{}, apiresponses.ErrAsyncRequired
+		return updateRequest{}, err
 	}
 
 	// Give the user a better error message if they give us a bad request
 	if err := validateProvisionParameters(parsedDetails.RequestParams, serviceDefinition.ProvisionInputVariables, nil, plan); err != nil {
-		return domain.UpdateServiceSpec{}, err
+		return updateRequest{}, err
 	}
 	if !serviceDefinition.AllowedUpdate(parsedDetails.RequestParams) {
-		return domain.UpdateServiceSpec{}, ErrNonUpdatableParameter
+		return updateRequest{}, ErrNonUpdatableParameter
 	}
 
 	provisionDetails, err := broker.store.GetProvisionRequestDetails(instanceID)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, fmt.Errorf("error retrieving provision request details for %q: %w", instanceID, err)
+		return updateRequest{}, fmt.Errorf("error retrieving provision request details for %q: %w", instanceID, err)
 	}
 
 	initialProperties, err := mergeJSON(provisionDetails, parsedDetails.RequestParams, plan.GetServiceProperties())
 	if err != nil {
-		return domain.UpdateServiceSpec{}, err
+		return updateRequest{}, err
 	}
 	importedParams, err := serviceProvider.GetImportedProperties(ctx, instance.GUID, serviceDefinition.ProvisionInputVariables, initialProperties)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, fmt.Errorf("error retrieving expected parameters for %q: %w", instanceID, err)
+		return updateRequest{}, fmt.Errorf("error retrieving expected parameters for %q: %w", instanceID, err)
 	}
 
 	mergedDetails, err := mergeJSON(provisionDetails, importedParams, parsedDetails.RequestParams)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, fmt.Errorf("error merging update and provision details: %w", err)
+		return updateRequest{}, fmt.Errorf("error merging update and provision details: %w", err)
 	}
 
 	vars, err := serviceDefinition.UpdateVariables(instanceID, parsedDetails, mergedDetails, *plan, request.DecodeOriginatingIdentityHeader(ctx))
 	if err != nil {
-		return domain.UpdateServiceSpec{}, err
+		return updateRequest{}, err
 	}
 
 	operation, err := decider.DecideOperation(maintenanceInfoVersion, parsedDetails)
-	switch {
-	case err != nil:
-		return domain.UpdateServiceSpec{}, fmt.Errorf("error deciding update path: %w", err)
-	case operation == decider.Upgrade:
-		return broker.doUpgrade(ctx, serviceDefinition, serviceProvider, instance, vars, plan)
-	default:
-		return broker.doUpdate(ctx, serviceProvider, instance, vars, parsedDetails, mergedDetails)
+	if err != nil {
+		return updateRequest{}, fmt.Errorf("error deciding update path: %w", err)
 	}
+
+	return updateRequest{
+		instance:          instance,
+		serviceDefinition: serviceDefinition,
+		serviceProvider:   serviceProvider,
+		plan:              plan,
+		parsedDetails:     parsedDetails,
+		operation:         operation,
+		vars:              vars,
+		mergedDetails:     withoutTransientParameters(mergedDetails, serviceDefinition.TransientParameters),
+	}, nil
 }
 
 func (broker *ServiceBroker) doUpgrade(ctx context.Context, serviceDefinition *broker.ServiceDefinition, serviceProvider broker.ServiceProvider, instance storage.ServiceInstanceDetails, instanceVars *varcontext.VarContext, plan *broker.ServicePlan) (domain.UpdateServiceSpec, error) {
 	instanceUpgradeFinished, err := serviceProvider.UpgradeInstance(ctx, instanceVars)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, err
+		return domain.UpdateServiceSpec{}, retryableError(err)
 	}
 
 	go func() {
@@ -175,7 +209,7 @@ func (broker *ServiceBroker) doUpdate(ctx context.Context, serviceProvider broke
 
 	instanceDetails, err := serviceProvider.Update(ctx, vars)
 	if err != nil {
-		return domain.UpdateServiceSpec{}, err
+		return domain.UpdateServiceSpec{}, retryableError(err)
 	}
 
 	// save instance plan change
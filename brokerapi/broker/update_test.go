@@ -107,7 +107,13 @@ var _ = Describe("Update", func() {
 							Details:        "fake field name",
 							ProhibitUpdate: true,
 						},
+						{
+							FieldName: "confirm",
+							Type:      "boolean",
+							Details:   "fake transient field",
+						},
 					},
+					TransientParameters: []string{"confirm"},
 					ProvisionComputedVariables: []varcontext.DefaultVariable{
 						{Name: "labels", Default: "${json.marshal(request.default_labels)}", Overwrite: true},
 						{Name: "copyOriginatingIdentity", Default: "${json.marshal(request.x_broker_api_originating_identity)}", Overwrite: true},
@@ -511,6 +517,27 @@ var _ = Describe("Update", func() {
 			})
 		})
 
+		Describe("passing transient variables on update", func() {
+			BeforeEach(func() {
+				fakeStorage.GetProvisionRequestDetailsReturns(map[string]any{"foo": "bar"}, nil)
+			})
+
+			It("passes them to the provider, but does not store them", func() {
+				updateDetails.RawParameters = json.RawMessage(`{"foo":"quz","confirm":true}`)
+
+				_, err := serviceBroker.Update(context.TODO(), instanceID, updateDetails, true)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeServiceProvider.UpdateCallCount()).To(Equal(1))
+				_, actualVars := fakeServiceProvider.UpdateArgsForCall(0)
+				Expect(actualVars.GetBool("confirm")).To(BeTrue())
+
+				Expect(fakeStorage.StoreProvisionRequestDetailsCallCount()).To(Equal(1))
+				_, actualRequestVars := fakeStorage.StoreProvisionRequestDetailsArgsForCall(0)
+				Expect(actualRequestVars).To(Equal(storage.JSONObject{"foo": "quz"}))
+			})
+		})
+
 		Describe("passing variables on provision, import and update", func() {
 			BeforeEach(func() {
 				fakeStorage.GetProvisionRequestDetailsReturns(map[string]any{"foo": "bar", "baz": "quz"}, nil)
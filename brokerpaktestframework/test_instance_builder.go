@@ -97,8 +97,19 @@ func writeManifest(brokerPackDir string, build string, workingDir string) (err e
 	}
 
 	parsedManifest.Platforms = []platform.Platform{{Os: runtime.GOOS, Arch: runtime.GOARCH}}
-	for i := range parsedManifest.TerraformVersions {
+	for i, v := range parsedManifest.TerraformVersions {
 		parsedManifest.TerraformVersions[i].URLTemplate = build
+
+		// The binary is extracted with the name it is fetched with, so OpenTofu needs a mock named "tofu"
+		if v.IsOpenTofu() {
+			tofuBuild := path.Join(path.Dir(build), "tofu")
+			if _, statErr := os.Stat(tofuBuild); statErr != nil {
+				if err = os.Link(build, tofuBuild); err != nil {
+					return
+				}
+			}
+			parsedManifest.TerraformVersions[i].URLTemplate = tofuBuild
+		}
 	}
 	parsedManifest.TerraformProviders = nil
 	outputFile, err := os.Create(path.Join(workingDir, "manifest.yml"))
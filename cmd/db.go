@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice"
+	"github.com/cloudfoundry/cloud-service-broker/internal/dbarchive"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+)
+
+const archivePassphraseProp = "db.archive.passphrase"
+
+func init() {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Export and import the broker database",
+		Long: `Lets you move the state of the broker from one database to another.
+
+The export command writes every record from the database configured for the broker to an archive,
+and the import command restores the records from an archive into the database configured for the
+broker. Records are decrypted on export, and encrypted with the primary encryption password of the
+importing broker on import, so the two databases can be configured with different encryption passwords.
+
+The archive contains credentials, so it should be encrypted by setting a passphrase with the
+DB_ARCHIVE_PASSPHRASE environment variable. The same passphrase must be set when importing.
+
+For example, to move from a SQLite database to MySQL:
+
+  DB_TYPE=sqlite3 DB_PATH=broker.db cloud-service-broker db export broker.archive
+  DB_TYPE=mysql DB_HOST=... cloud-service-broker db import broker.archive
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	dbCmd.AddCommand(&cobra.Command{
+		Use:   "export <archive file>",
+		Short: "export the broker database to an archive",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			exportDB(args[0])
+		},
+	})
+
+	dbCmd.AddCommand(&cobra.Command{
+		Use:   "import <archive file>",
+		Short: "import an archive into an empty broker database",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			importDB(args[0])
+		},
+	})
+
+	rootCmd.AddCommand(dbCmd)
+
+	_ = viper.BindEnv(archivePassphraseProp, "DB_ARCHIVE_PASSPHRASE")
+}
+
+func exportDB(archivePath string) {
+	logger := utils.NewLogger("db-export")
+	db := dbservice.New(logger)
+	encryptor := setupDBEncryption(db, logger)
+	store := newStorage(db, encryptor, logger)
+
+	records, err := store.ExportAllRecords()
+	if err != nil {
+		log.Fatalf("error exporting records: %s", err)
+	}
+
+	passphrase := viper.GetString(archivePassphraseProp)
+	if passphrase == "" {
+		log.Printf("WARNING: no archive passphrase set, the archive will not be encrypted")
+	}
+
+	fd, err := os.OpenFile(archivePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		log.Fatalf("error creating archive: %s", err)
+	}
+
+	if err := dbarchive.Write(fd, records, passphrase); err != nil {
+		log.Fatalf("error writing archive: %s", err)
+	}
+	if err := fd.Close(); err != nil {
+		log.Fatalf("error closing archive: %s", err)
+	}
+
+	log.Printf("exported %d service instances and %d service bindings to %s", len(records.ServiceInstanceDetails), len(records.ServiceBindingCredentials), archivePath)
+}
+
+func importDB(archivePath string) {
+	logger := utils.NewLogger("db-import")
+	db := dbservice.New(logger)
+	encryptor := setupDBEncryption(db, logger)
+	store := newStorage(db, encryptor, logger)
+
+	fd, err := os.Open(archivePath)
+	if err != nil {
+		log.Fatalf("error opening archive: %s", err)
+	}
+	defer fd.Close()
+
+	records, err := dbarchive.Read(fd, viper.GetString(archivePassphraseProp))
+	if err != nil {
+		log.Fatalf("error reading archive: %s", err)
+	}
+
+	if err := store.ImportAllRecords(records); err != nil {
+		log.Fatalf("error importing records: %s", err)
+	}
+
+	log.Printf("imported %d service instances and %d service bindings from %s", len(records.ServiceInstanceDetails), len(records.ServiceBindingCredentials), archivePath)
+}
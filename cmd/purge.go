@@ -7,7 +7,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/cloudfoundry/cloud-service-broker/dbservice"
-	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/utils"
 )
 
@@ -46,7 +45,7 @@ func purge(serviceInstanceGUID string) {
 	logger := utils.NewLogger("purge")
 	db := dbservice.New(logger)
 	encryptor := setupDBEncryption(db, logger)
-	store := storage.New(db, encryptor)
+	store := newStorage(db, encryptor, logger)
 
 	bindings, err := store.GetServiceBindingIDsForServiceInstance(serviceInstanceGUID)
 	if err != nil {
@@ -20,20 +20,29 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/lager/v3"
 	osbapiBroker "github.com/cloudfoundry/cloud-service-broker/brokerapi/broker"
 	"github.com/cloudfoundry/cloud-service-broker/dbservice"
 	"github.com/cloudfoundry/cloud-service-broker/internal/displaycatalog"
+	"github.com/cloudfoundry/cloud-service-broker/internal/drift"
 	"github.com/cloudfoundry/cloud-service-broker/internal/encryption"
 	"github.com/cloudfoundry/cloud-service-broker/internal/infohandler"
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics"
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics/storecollector"
+	"github.com/cloudfoundry/cloud-service-broker/internal/operationshandler"
+	"github.com/cloudfoundry/cloud-service-broker/internal/reconciler"
 	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	pakBroker "github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/brokerpak"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/server"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/toggles"
 	"github.com/cloudfoundry/cloud-service-broker/utils"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
+	"github.com/pborman/uuid"
 	"github.com/pivotal-cf/brokerapi/v9"
+	"github.com/pivotal-cf/brokerapi/v9/auth"
 	"github.com/pivotal-cf/brokerapi/v9/domain"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -47,6 +56,10 @@ const (
 	apiHostProp         = "api.host"
 	encryptionPasswords = "db.encryption.passwords"
 	encryptionEnabled   = "db.encryption.enabled"
+	driftCheckInterval  = "drift.check.interval"
+	metricsPortProp     = "api.metrics_port"
+	operationStaleAfter = "terraform.operation_stale_after"
+	logRedactKeys       = "log.redact_key_patterns"
 )
 
 var cfCompatibilityToggle = toggles.Features.Toggle("enable-cf-sharing", false, `Set all services to have the Sharable flag so they can be shared
@@ -77,13 +90,33 @@ func init() {
 	_ = viper.BindEnv(apiHostProp, "CSB_LISTENER_HOST")
 	_ = viper.BindEnv(encryptionPasswords, "ENCRYPTION_PASSWORDS")
 	_ = viper.BindEnv(encryptionEnabled, "ENCRYPTION_ENABLED")
+	_ = viper.BindEnv(driftCheckInterval, "DRIFT_CHECK_INTERVAL")
+	_ = viper.BindEnv(metricsPortProp, "METRICS_PORT")
+	viper.SetDefault(operationStaleAfter, 5*time.Minute)
+	_ = viper.BindEnv(operationStaleAfter, "TERRAFORM_OPERATION_STALE_AFTER")
+	viper.SetDefault(logRedactKeys, redaction.DefaultKeyPatterns)
+	_ = viper.BindEnv(logRedactKeys, "LOG_REDACT_KEY_PATTERNS")
 }
 
 func serve() {
 	logger := utils.NewLogger("cloud-service-broker")
+	if err := redaction.SetKeyPatterns(viper.GetStringSlice(logRedactKeys)); err != nil {
+		logger.Fatal("Error configuring log redaction", err)
+	}
 	logger.Info("starting", lager.Data{"version": utils.Version})
 	db := dbservice.New(logger)
 	encryptor := setupDBEncryption(db, logger)
+	store := newStorage(db, encryptor, logger).WithOperationOwner(uuid.New())
+
+	if staleAfter := viper.GetDuration(operationStaleAfter); staleAfter > 0 {
+		// Deployments are locked while an operation runs so that broker instances sharing
+		// the database do not run Terraform on the same deployment. A lock is renewed by the
+		// heartbeat, so it expires when the operation becomes stale.
+		store = store.WithLeaseDuration(staleAfter)
+		r := reconciler.New(store, staleAfter, logger)
+		reconcileOperations(r, logger)
+		go scheduleReconciliation(r, staleAfter, logger)
+	}
 
 	// init broker
 	cfg, err := osbapiBroker.NewBrokerConfigFromEnv(logger)
@@ -91,7 +124,7 @@ func serve() {
 		logger.Fatal("Error initializing service broker config", err)
 	}
 	var serviceBroker domain.ServiceBroker
-	serviceBroker, err = osbapiBroker.New(cfg, storage.New(db, encryptor), logger)
+	serviceBroker, err = osbapiBroker.New(cfg, store, logger)
 	if err != nil {
 		logger.Fatal("Error initializing service broker", err)
 	}
@@ -101,6 +134,8 @@ func serve() {
 		Password: viper.GetString(apiPasswordProp),
 	}
 
+	serviceBroker = metrics.NewBrokerWrapper(serviceBroker)
+
 	if cfCompatibilityToggle.IsActive() {
 		logger.Info("Enabling Cloud Foundry service sharing")
 		serviceBroker = server.NewCfSharingWrapper(serviceBroker)
@@ -118,11 +153,31 @@ func serve() {
 
 	brokerAPI := brokerapi.New(serviceBroker, logger, credentials)
 
+	if interval := viper.GetDuration(driftCheckInterval); interval > 0 {
+		logger.Info("scheduling-drift-checks", lager.Data{"interval": interval.String()})
+		go scheduleDriftChecks(drift.NewChecker(cfg.Registry, store, logger), interval, logger)
+	}
+
 	sqldb, err := db.DB()
 	if err != nil {
 		logger.Error("failed to get database connection", err)
 	}
-	startServer(cfg.Registry, sqldb, brokerAPI)
+	if err := metrics.Register(storecollector.New(store, logger)); err != nil {
+		logger.Error("failed to register metrics collector", err)
+	}
+
+	authWrapper := auth.NewWrapper(credentials.Username, credentials.Password)
+	adminHandlers := map[string]http.Handler{
+		operationshandler.Path: authWrapper.WrapFunc(operationshandler.New(store)),
+	}
+
+	if port := viper.GetString(metricsPortProp); port != "" {
+		go serveMetrics(port, logger)
+	} else {
+		adminHandlers["/metrics"] = authWrapper.Wrap(metrics.Handler())
+	}
+
+	startServer(cfg.Registry, sqldb, brokerAPI, adminHandlers)
 }
 
 func serveDocs() {
@@ -133,7 +188,7 @@ func serveDocs() {
 		logger.Error("loading brokerpaks", err)
 	}
 
-	startServer(registry, nil, nil)
+	startServer(registry, nil, nil, nil)
 }
 
 func setupDBEncryption(db *gorm.DB, logger lager.Logger) storage.Encryptor {
@@ -143,12 +198,12 @@ func setupDBEncryption(db *gorm.DB, logger lager.Logger) storage.Encryptor {
 	}
 
 	if config.Changed {
-		if err := storage.New(db, config.RotationEncryptor).CheckAllRecords(); err != nil {
+		if err := newStorage(db, config.RotationEncryptor, logger).CheckAllRecords(); err != nil {
 			logger.Fatal("refusing to encrypt the database as some fields cannot be successfully read", err)
 		}
 
 		logger.Info("rotating-database-encryption", lager.Data{"previous-primary": labelName(config.StoredPrimaryLabel), "new-primary": labelName(config.ConfiguredPrimaryLabel)})
-		if err := storage.New(db, config.RotationEncryptor).UpdateAllRecords(); err != nil {
+		if err := newStorage(db, config.RotationEncryptor, logger).UpdateAllRecords(); err != nil {
 			logger.Fatal("Error rotating database encryption", err)
 		}
 		if err := encryption.UpdatePasswordMetadata(db, config.ConfiguredPrimaryLabel); err != nil {
@@ -156,7 +211,7 @@ func setupDBEncryption(db *gorm.DB, logger lager.Logger) storage.Encryptor {
 		}
 	}
 
-	err = storage.New(db, config.Encryptor).CheckAllRecords()
+	err = newStorage(db, config.Encryptor, logger).CheckAllRecords()
 	switch {
 	case err != nil:
 		// This error denotes that there was a problem reading at least one database field.
@@ -177,7 +232,7 @@ func setupDBEncryption(db *gorm.DB, logger lager.Logger) storage.Encryptor {
 	return config.Encryptor
 }
 
-func startServer(registry pakBroker.BrokerRegistry, db *sql.DB, brokerapi http.Handler) {
+func startServer(registry pakBroker.BrokerRegistry, db *sql.DB, brokerapi http.Handler, adminHandlers map[string]http.Handler) {
 	logger := utils.NewLogger("cloud-service-broker")
 
 	docsHandler := server.DocsHandler(registry)
@@ -187,6 +242,9 @@ func startServer(registry pakBroker.BrokerRegistry, db *sql.DB, brokerapi http.H
 	router.HandleFunc("/examples", server.NewExampleHandler(registry))
 	server.AddHealthHandler(router, db)
 	router.HandleFunc("/info", infohandler.NewDefault())
+	for path, handler := range adminHandlers {
+		router.Handle(path, handler)
+	}
 
 	router.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
 		switch {
@@ -205,6 +263,71 @@ func startServer(registry pakBroker.BrokerRegistry, db *sql.DB, brokerapi http.H
 	_ = http.ListenAndServe(fmt.Sprintf("%s:%s", host, port), router)
 }
 
+// serveMetrics serves the metrics on a separate port without authentication,
+// so that they can be scraped from a network that does not reach the broker API
+func serveMetrics(port string, logger lager.Logger) {
+	router := http.NewServeMux()
+	router.Handle("/metrics", metrics.Handler())
+
+	host := viper.GetString(apiHostProp)
+	logger.Info("serving-metrics", lager.Data{"port": port})
+	if err := http.ListenAndServe(fmt.Sprintf("%s:%s", host, port), router); err != nil {
+		logger.Error("metrics-server-failed", err)
+	}
+}
+
+// scheduleReconciliation records heartbeats for the operations run by this broker process
+// several times within the stale duration, and marks operations as failed when they have
+// been left in progress by a broker process that has stopped
+func scheduleReconciliation(r *reconciler.Reconciler, staleAfter time.Duration, logger lager.Logger) {
+	heartbeat := time.NewTicker(staleAfter / 5)
+	defer heartbeat.Stop()
+	reconcile := time.NewTicker(staleAfter)
+	defer reconcile.Stop()
+
+	for {
+		select {
+		case <-heartbeat.C:
+			if err := r.Heartbeat(); err != nil {
+				logger.Error("operation-heartbeat-failed", err)
+			}
+		case <-reconcile.C:
+			reconcileOperations(r, logger)
+		}
+	}
+}
+
+func reconcileOperations(r *reconciler.Reconciler, logger lager.Logger) {
+	failed, err := r.Reconcile()
+	if err != nil {
+		logger.Error("reconciliation-failed", err)
+	}
+	if len(failed) > 0 {
+		logger.Info("reconciliation-complete", lager.Data{"failed": failed})
+	}
+}
+
+func scheduleDriftChecks(checker *drift.Checker, interval time.Duration, logger lager.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		results, err := checker.CheckAll(context.Background())
+		if err != nil {
+			logger.Error("drift-check-failed", err)
+			continue
+		}
+
+		var drifted []string
+		for _, result := range results {
+			if result.Drifted {
+				drifted = append(drifted, result.DeploymentID)
+			}
+		}
+		logger.Info("drift-check-complete", lager.Data{"checked": len(results), "drifted": drifted})
+	}
+}
+
 func labelName(label string) string {
 	switch label {
 	case "":
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/lager/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/statestore"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+const (
+	stateStoreTypeProp              = "terraform.state_store.type"
+	stateStorePathProp              = "terraform.state_store.path"
+	stateStoreS3EndpointProp        = "terraform.state_store.s3.endpoint"
+	stateStoreS3RegionProp          = "terraform.state_store.s3.region"
+	stateStoreS3BucketProp          = "terraform.state_store.s3.bucket"
+	stateStoreS3PrefixProp          = "terraform.state_store.s3.prefix"
+	stateStoreS3AccessKeyIDProp     = "terraform.state_store.s3.access_key_id"
+	stateStoreS3SecretAccessKeyProp = "terraform.state_store.s3.secret_access_key"
+	stateHistoryLimitProp           = "terraform.state_history.limit"
+	terraformLogsRetentionProp      = "terraform.logs.retention"
+	terraformLogsMaxSizeProp        = "terraform.logs.max_size"
+)
+
+func init() {
+	viper.SetDefault(stateStoreTypeProp, statestore.TypeDatabase)
+	_ = viper.BindEnv(stateStoreTypeProp, "TERRAFORM_STATE_STORE")
+	_ = viper.BindEnv(stateStorePathProp, "TERRAFORM_STATE_STORE_PATH")
+	_ = viper.BindEnv(stateStoreS3EndpointProp, "TERRAFORM_STATE_STORE_S3_ENDPOINT")
+	_ = viper.BindEnv(stateStoreS3RegionProp, "TERRAFORM_STATE_STORE_S3_REGION")
+	_ = viper.BindEnv(stateStoreS3BucketProp, "TERRAFORM_STATE_STORE_S3_BUCKET")
+	_ = viper.BindEnv(stateStoreS3PrefixProp, "TERRAFORM_STATE_STORE_S3_PREFIX")
+	_ = viper.BindEnv(stateStoreS3AccessKeyIDProp, "TERRAFORM_STATE_STORE_S3_ACCESS_KEY_ID")
+	_ = viper.BindEnv(stateStoreS3SecretAccessKeyProp, "TERRAFORM_STATE_STORE_S3_SECRET_ACCESS_KEY")
+
+	viper.SetDefault(stateHistoryLimitProp, 5)
+	_ = viper.BindEnv(stateHistoryLimitProp, "TERRAFORM_STATE_HISTORY_LIMIT")
+
+	viper.SetDefault(terraformLogsRetentionProp, 7*24*time.Hour)
+	_ = viper.BindEnv(terraformLogsRetentionProp, "TERRAFORM_LOGS_RETENTION")
+	viper.SetDefault(terraformLogsMaxSizeProp, 1024*1024)
+	_ = viper.BindEnv(terraformLogsMaxSizeProp, "TERRAFORM_LOGS_MAX_SIZE")
+}
+
+// newStorage creates a Storage that uses the configured Terraform state store, state history and logs
+func newStorage(db *gorm.DB, encryptor storage.Encryptor, logger lager.Logger) *storage.Storage {
+	stateStore, err := statestore.New(statestore.Config{
+		Type: viper.GetString(stateStoreTypeProp),
+		Path: viper.GetString(stateStorePathProp),
+		S3: statestore.S3Config{
+			Endpoint:        viper.GetString(stateStoreS3EndpointProp),
+			Region:          viper.GetString(stateStoreS3RegionProp),
+			Bucket:          viper.GetString(stateStoreS3BucketProp),
+			Prefix:          viper.GetString(stateStoreS3PrefixProp),
+			AccessKeyID:     viper.GetString(stateStoreS3AccessKeyIDProp),
+			SecretAccessKey: viper.GetString(stateStoreS3SecretAccessKeyProp),
+		},
+	})
+	if err != nil {
+		logger.Fatal("Error configuring Terraform state store", err)
+	}
+
+	return storage.New(db, encryptor).
+		WithStateStore(stateStore).
+		WithStateHistoryLimit(viper.GetInt(stateHistoryLimitProp)).
+		WithTerraformLogs(viper.GetDuration(terraformLogsRetentionProp), viper.GetInt(terraformLogsMaxSizeProp))
+}
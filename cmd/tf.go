@@ -16,15 +16,21 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/pborman/uuid"
 	"github.com/spf13/cobra"
 
+	osbapiBroker "github.com/cloudfoundry/cloud-service-broker/brokerapi/broker"
 	"github.com/cloudfoundry/cloud-service-broker/dbservice"
+	"github.com/cloudfoundry/cloud-service-broker/internal/drift"
 	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
@@ -46,7 +52,7 @@ func init() {
 			logger := utils.NewLogger("tf")
 			db := dbservice.New(logger)
 			encryptor := setupDBEncryption(db, logger)
-			store = storage.New(db, encryptor)
+			store = newStorage(db, encryptor, logger)
 			terraformProvider = tf.NewTerraformProvider(
 				executor.TFBinariesContext{},
 				invoker.NewTerraformInvokerFactory(executor.NewExecutorFactory("", nil, nil), "", map[string]string{}),
@@ -125,4 +131,388 @@ func init() {
 			_ = w.Flush()
 		},
 	})
+
+	tfCmd.AddCommand(&cobra.Command{
+		Use:   "history <deployment ID>",
+		Short: "show the previous workspaces of a Terraform deployment",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			results, err := store.GetTerraformDeploymentHistory(args[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.StripEscape)
+			_, _ = fmt.Fprintln(w, "Revision\tTaken\tLast Operation\tState\tVersion")
+
+			for _, result := range results {
+				stateVersion := ""
+				if v, err := result.Workspace.StateTFVersion(); err == nil {
+					stateVersion = v.String()
+				}
+
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", result.Revision, result.CreatedAt.Format(time.RFC822), result.OperationType, result.OperationState, stateVersion)
+			}
+			_ = w.Flush()
+		},
+	})
+
+	tfCmd.AddCommand(&cobra.Command{
+		Use:   "restore <deployment ID> <revision>",
+		Short: "restore a previous workspace of a Terraform deployment",
+		Long: `Replaces the workspace of a Terraform deployment with a previous workspace from its history.
+The current workspace is added to the history, so it can be restored again. The operation that
+failed can then be re-run, for example by updating the service instance.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			revision, err := strconv.Atoi(args[1])
+			if err != nil {
+				log.Fatalf("invalid revision %q: %s", args[1], err)
+			}
+
+			if err := restoreTerraformDeployment(store, args[0], revision); err != nil {
+				log.Fatal(err)
+			}
+
+			log.Printf("restored revision %d of %q", revision, args[0])
+		},
+	})
+
+	tfCmd.AddCommand(&cobra.Command{
+		Use:   "cancel <deployment ID>",
+		Short: "cancel the operation in progress on a Terraform deployment",
+		Long: `Asks the broker that runs the operation in progress on a Terraform deployment to stop
+Terraform. The broker interrupts Terraform when it next records a heartbeat for its operations,
+and the operation then fails, so that it can be retried. Heartbeats are only recorded when
+TERRAFORM_OPERATION_STALE_AFTER is not 0.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			deployment, err := store.GetTerraformDeployment(args[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+			if deployment.LastOperationState != tf.InProgress {
+				log.Fatalf("no operation is in progress on %q: the last %s operation has %s", args[0], deployment.LastOperationType, deployment.LastOperationState)
+			}
+
+			if err := store.RequestTerraformDeploymentCancellation(args[0]); err != nil {
+				log.Fatal(err)
+			}
+
+			log.Printf("requested cancellation of the %s operation on %q", deployment.LastOperationType, args[0])
+		},
+	})
+
+	markFailedCmd := &cobra.Command{
+		Use:   "mark-failed <deployment ID>",
+		Short: "mark the operation in progress on a Terraform deployment as failed",
+		Long: `Marks the operation in progress on a Terraform deployment as failed, so that the platform
+stops polling it and it can be retried. Use this when the operation will never finish, for
+example because the broker that ran it has gone away. In case the broker is still running
+Terraform, it is also asked to stop, as with "tf cancel".`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			message, err := cmd.Flags().GetString("message")
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if err := tf.NewDeploymentManager(store).MarkOperationFailed(args[0], message); err != nil {
+				log.Fatal(err)
+			}
+
+			// Changing the operation state drops any earlier cancellation request,
+			// so the request is made after the operation has been marked failed
+			if err := store.RequestTerraformDeploymentCancellation(args[0]); err != nil {
+				log.Fatal(err)
+			}
+
+			log.Printf("marked the operation on %q as failed", args[0])
+		},
+	}
+	markFailedCmd.Flags().StringP("message", "m", "", "the reason for the failure, which is added to the last operation message")
+	tfCmd.AddCommand(markFailedCmd)
+
+	operationsCmd := &cobra.Command{
+		Use:   "operations <service instance ID>",
+		Short: "show the operations on a service instance and its bindings",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			results, err := store.GetOperationLogs(args[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+				data, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					log.Fatal(err)
+				}
+				fmt.Println(string(data))
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.StripEscape)
+			_, _ = fmt.Fprintln(w, "ID\tOperation\tState\tStarted\tFinished\tCorrelation ID\tMessage")
+
+			for _, result := range results {
+				finished := ""
+				if result.FinishedAt != nil {
+					finished = result.FinishedAt.Format(time.RFC822)
+				}
+
+				_, _ = fmt.Fprintf(w, "%q\t%s\t%s\t%s\t%s\t%s\t%q\n", result.DeploymentID, result.OperationType, result.State, result.StartedAt.Format(time.RFC822), finished, result.CorrelationID, result.Message)
+			}
+			_ = w.Flush()
+		},
+	}
+	operationsCmd.Flags().Bool("json", false, "print the operations as JSON, including the originating identity and Terraform error output")
+	tfCmd.AddCommand(operationsCmd)
+
+	logsCmd := &cobra.Command{
+		Use:   "logs <deployment ID>",
+		Short: "show the output of the Terraform commands that ran for a deployment",
+		Long: `Shows the output of the Terraform commands that ran for the operations on a deployment, oldest first.
+Secrets in the output are redacted, and the output is kept for the retention period configured
+with TERRAFORM_LOGS_RETENTION.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			results, err := store.GetTerraformLogs(args[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+				data, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					log.Fatal(err)
+				}
+				fmt.Println(string(data))
+				return
+			}
+
+			withTFLog, _ := cmd.Flags().GetBool("tf-log")
+			for _, result := range results {
+				printTerraformLog(result, withTFLog)
+			}
+		},
+	}
+	logsCmd.Flags().Bool("json", false, "print the logs as JSON")
+	logsCmd.Flags().Bool("tf-log", false, "include the TF_LOG output, when it was recorded")
+	tfCmd.AddCommand(logsCmd)
+
+	planCmd := &cobra.Command{
+		Use:   "plan <service instance ID>",
+		Short: "show the changes that an update of a service instance would make",
+		Long: `Runs "terraform plan" with the plan and parameters of an update of a service instance, and shows
+the resources that the update would add, change or destroy. The parameters are merged with those of
+previous provision and update requests, as they would be for an update. The update is not applied, and
+the service instance is not modified.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			params, err := cmd.Flags().GetString("params")
+			if err != nil {
+				log.Fatal(err)
+			}
+			planID, err := cmd.Flags().GetString("plan-id")
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			logger := utils.NewLogger("tf-plan")
+			cfg, err := osbapiBroker.NewBrokerConfigFromEnv(logger)
+			if err != nil {
+				log.Fatal(err)
+			}
+			serviceBroker, err := osbapiBroker.New(cfg, store, logger)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var rawParams json.RawMessage
+			if params != "" {
+				rawParams = json.RawMessage(params)
+			}
+
+			changes, err := serviceBroker.PlanUpdate(context.Background(), args[0], planID, rawParams)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			printPlan(changes)
+		},
+	}
+	planCmd.Flags().StringP("params", "p", "", "the parameters of the update, as a JSON object")
+	planCmd.Flags().String("plan-id", "", "the ID of the plan to update to, defaults to the current plan")
+	tfCmd.AddCommand(planCmd)
+
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "detect changes made to resources outside of the broker",
+		Long: `Runs "terraform plan" for Terraform deployments and records whether the plan is empty.
+A non-empty plan means that the resources have drifted from the Terraform state.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+	tfCmd.AddCommand(driftCmd)
+
+	driftCmd.AddCommand(&cobra.Command{
+		Use:   "check [deployment IDs]",
+		Short: "check Terraform deployments for drift, or all deployments when none are specified",
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := utils.NewLogger("tf-drift")
+			cfg, err := osbapiBroker.NewBrokerConfigFromEnv(logger)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			checker := drift.NewChecker(cfg.Registry, store, logger)
+			var results []storage.TerraformDrift
+			switch len(args) {
+			case 0:
+				results, err = checker.CheckAll(context.Background())
+			default:
+				results, err = checker.Check(context.Background(), args...)
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			printDrift(results)
+		},
+	})
+
+	driftListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "show the results of the last drift check for each Terraform deployment",
+		Run: func(cmd *cobra.Command, args []string) {
+			results, err := store.GetAllTerraformDrifts()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			onlyDrifted, err := cmd.Flags().GetBool("drifted")
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if onlyDrifted {
+				var drifted []storage.TerraformDrift
+				for _, result := range results {
+					if result.Drifted {
+						drifted = append(drifted, result)
+					}
+				}
+				results = drifted
+			}
+
+			printDrift(results)
+		},
+	}
+	driftListCmd.Flags().BoolP("drifted", "d", false, "only show deployments that have drifted")
+	driftCmd.AddCommand(driftListCmd)
+}
+
+// restoreTerraformDeployment replaces the workspace of a deployment with a snapshot. The deployment
+// is locked while it does so, so that an operation cannot start until the workspace is replaced.
+func restoreTerraformDeployment(store *storage.Storage, deploymentID string, revision int) error {
+	leaseHolder := uuid.New()
+	if err := store.AcquireTerraformDeploymentLease(deploymentID, leaseHolder); err != nil {
+		return err
+	}
+	defer func() { _ = store.ReleaseTerraformDeploymentLease(deploymentID, leaseHolder) }()
+
+	deployment, err := store.GetTerraformDeployment(deploymentID)
+	if err != nil {
+		return err
+	}
+	if deployment.LastOperationState == tf.InProgress {
+		return fmt.Errorf("cannot restore %q while a %s operation is in progress", deploymentID, deployment.LastOperationType)
+	}
+
+	snapshot, err := store.GetTerraformDeploymentSnapshot(deploymentID, revision)
+	if err != nil {
+		return err
+	}
+
+	deployment.Workspace = snapshot.Workspace
+	deployment.LastOperationMessage = fmt.Sprintf("workspace restored from revision %d", revision)
+	return store.StoreTerraformDeployment(deployment)
+}
+
+func printPlan(changes []storage.TerraformResourceChange) {
+	var add, change, destroy int
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.StripEscape)
+	_, _ = fmt.Fprintln(w, "Address\tAction")
+
+	for _, c := range changes {
+		switch c.Action {
+		case "create":
+			add++
+		case "update":
+			change++
+		case "delete":
+			destroy++
+		case "replace":
+			add++
+			destroy++
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", c.Address, c.Action)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\nPlan: %d to add, %d to change, %d to destroy.\n", add, change, destroy)
+}
+
+func printDrift(results []storage.TerraformDrift) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.StripEscape)
+	_, _ = fmt.Fprintln(w, "ID\tDrifted\tLast Checked\tChanges\tError")
+
+	for _, result := range results {
+		var changes []string
+		for _, change := range result.ResourceChanges {
+			switch len(change.Attributes) {
+			case 0:
+				changes = append(changes, fmt.Sprintf("%s (%s)", change.Address, change.Action))
+			default:
+				changes = append(changes, fmt.Sprintf("%s (%s: %s)", change.Address, change.Action, strings.Join(change.Attributes, ", ")))
+			}
+		}
+
+		lastChecked := ""
+		if !result.CheckedAt.IsZero() {
+			lastChecked = result.CheckedAt.Format(time.RFC822)
+		}
+
+		_, _ = fmt.Fprintf(w, "%q\t%t\t%s\t%s\t%q\n", result.DeploymentID, result.Drifted, lastChecked, strings.Join(changes, "; "), result.CheckError)
+	}
+	_ = w.Flush()
+}
+
+func printTerraformLog(l storage.TerraformLog, withTFLog bool) {
+	outcome := "succeeded"
+	if l.Error != "" {
+		outcome = fmt.Sprintf("failed: %s", l.Error)
+	}
+	fmt.Printf("==> terraform %s at %s, took %s, %s\n", l.Command, l.StartedAt.Format(time.RFC822), l.FinishedAt.Sub(l.StartedAt).Truncate(time.Second), outcome)
+
+	printTerraformLogSection("stdout", l.Stdout)
+	printTerraformLogSection("stderr", l.Stderr)
+	if withTFLog {
+		printTerraformLogSection("TF_LOG", l.TFLog)
+	}
+	fmt.Println()
+}
+
+func printTerraformLogSection(name, output string) {
+	if output == "" {
+		return
+	}
+
+	fmt.Printf("--- %s ---\n%s", name, output)
+	if !strings.HasSuffix(output, "\n") {
+		fmt.Println()
+	}
 }
@@ -23,7 +23,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
 )
 
-const numMigrations = 16
+const numMigrations = 26
 
 // RunMigrations runs schema migrations on the provided service broker database to get it up to date
 func RunMigrations(db *gorm.DB) error {
@@ -128,6 +128,59 @@ func RunMigrations(db *gorm.DB) error {
 		return autoMigrateTables(db, &models.BindRequestDetailsV1{})
 	}
 
+	migrations[16] = func() error {
+		return autoMigrateTables(db, &models.TerraformDriftV1{})
+	}
+
+	migrations[17] = func() error {
+		return autoMigrateTables(db, &models.TerraformDeploymentV4{})
+	}
+
+	migrations[18] = func() error {
+		return autoMigrateTables(db, &models.TerraformDeploymentSnapshotV1{})
+	}
+
+	migrations[19] = func() error {
+		return autoMigrateTables(db, &models.OperationLogV1{})
+	}
+
+	migrations[20] = func() error {
+		return autoMigrateTables(db, &models.TerraformDeploymentV5{})
+	}
+
+	migrations[21] = func() error {
+		return autoMigrateTables(db, &models.TerraformDeploymentLeaseV1{})
+	}
+
+	migrations[22] = func() error {
+		return autoMigrateTables(db, &models.TerraformDeploymentV6{})
+	}
+
+	migrations[23] = func() error {
+		return autoMigrateTables(db, &models.TerraformLogV1{})
+	}
+
+	migrations[24] = func() error {
+		// The logs are only kept for a retention period, so rather than converting the plaintext
+		// output to encrypted columns, the table is recreated and the plaintext output is discarded
+		if err := db.Migrator().DropTable(&models.TerraformLogV1{}); err != nil {
+			return err
+		}
+		return autoMigrateTables(db, &models.TerraformLogV2{})
+	}
+
+	migrations[25] = func() error {
+		// The plaintext error output and originating identities of the recorded operations are
+		// discarded rather than encrypted, because the encryption is not configured here
+		if err := db.Migrator().DropColumn(&models.OperationLogV1{}, "stderr"); err != nil {
+			return err
+		}
+		if err := db.Model(&models.OperationLogV1{}).Where("originating_identity IS NOT NULL").Update("originating_identity", nil).Error; err != nil {
+			return err
+		}
+		return autoMigrateTables(db, &models.OperationLogV2{})
+	}
+
 	var lastMigrationNumber = -1
 
 	// if we've run any migrations before, we should have a migrations table, so find the last one we ran
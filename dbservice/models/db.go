@@ -54,8 +54,23 @@ type CloudOperation CloudOperationV1
 
 // TerraformDeployment holds Terraform state and plan information for resources
 // that use that execution system.
-type TerraformDeployment TerraformDeploymentV3
+type TerraformDeployment TerraformDeploymentV6
 
 // PasswordMetadata contains information about the passwords, but never the
 // passwords themselves
 type PasswordMetadata PasswordMetadataV1
+
+// TerraformDrift holds the result of the last drift check for a Terraform deployment
+type TerraformDrift TerraformDriftV1
+
+// TerraformDeploymentSnapshot holds a previous version of the workspace of a Terraform deployment
+type TerraformDeploymentSnapshot TerraformDeploymentSnapshotV1
+
+// OperationLog records an operation on a Terraform deployment
+type OperationLog OperationLogV2
+
+// TerraformDeploymentLease locks a Terraform deployment while an operation runs on it
+type TerraformDeploymentLease TerraformDeploymentLeaseV1
+
+// TerraformLog holds the output of a Terraform command that ran for an operation on a Terraform deployment
+type TerraformLog TerraformLogV2
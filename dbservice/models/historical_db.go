@@ -382,6 +382,83 @@ func (TerraformDeploymentV3) TableName() string {
 	return "terraform_deployments"
 }
 
+// TerraformDeploymentV4 adds a reference to Terraform state that is held in a state store
+// rather than in the workspace
+type TerraformDeploymentV4 struct {
+	ID        string `gorm:"primary_key;type:varchar(1024)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+
+	// Workspace contains a JSON serialized version of the Terraform workspace.
+	Workspace []byte `gorm:"type:mediumblob"`
+
+	// LastOperationType describes the last operation being performed on the resource.
+	LastOperationType string
+
+	// LastOperationState holds one of the following strings "in progress", "succeeded", "failed".
+	// These mirror the OSB API.
+	LastOperationState string
+
+	// LastOperationMessage is a description that can be passed back to the user.
+	LastOperationMessage string `gorm:"type:text"`
+
+	// StateRef is the key of the Terraform state in the state store. When empty,
+	// the state is held in the workspace.
+	StateRef string `gorm:"type:varchar(1024)"`
+
+	// StateChecksum is the SHA-256 checksum of the Terraform state held in the state store.
+	StateChecksum string `gorm:"type:varchar(64)"`
+}
+
+// TableName returns a consistent table name for
+// gorm so multiple structs from different versions of the database all operate
+// on the same table.
+func (TerraformDeploymentV4) TableName() string {
+	return "terraform_deployments"
+}
+
+// TerraformDeploymentV5 adds the broker process that owns the last operation
+type TerraformDeploymentV5 struct {
+	ID        string `gorm:"primary_key;type:varchar(1024)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+
+	// Workspace contains a JSON serialized version of the Terraform workspace.
+	Workspace []byte `gorm:"type:mediumblob"`
+
+	// LastOperationType describes the last operation being performed on the resource.
+	LastOperationType string
+
+	// LastOperationState holds one of the following strings "in progress", "succeeded", "failed".
+	// These mirror the OSB API.
+	LastOperationState string
+
+	// LastOperationMessage is a description that can be passed back to the user.
+	LastOperationMessage string `gorm:"type:text"`
+
+	// StateRef is the key of the Terraform state in the state store. When empty,
+	// the state is held in the workspace.
+	StateRef string `gorm:"type:varchar(1024)"`
+
+	// StateChecksum is the SHA-256 checksum of the Terraform state held in the state store.
+	StateChecksum string `gorm:"type:varchar(64)"`
+
+	// OperationOwner identifies the broker process that last stored the deployment.
+	// While an operation is in progress, the owner refreshes OperationHeartbeatAt so
+	// that other broker processes can tell whether the operation is still running.
+	OperationOwner       string `gorm:"type:varchar(255)"`
+	OperationHeartbeatAt *time.Time
+}
+
+// TableName returns a consistent table name for
+// gorm so multiple structs from different versions of the database all operate
+// on the same table.
+func (TerraformDeploymentV5) TableName() string {
+	return "terraform_deployments"
+}
+
 // PasswordMetadataV1 contains information about the passwords, but never the
 // passwords themselves
 type PasswordMetadataV1 struct {
@@ -398,3 +475,239 @@ type PasswordMetadataV1 struct {
 func (PasswordMetadataV1) TableName() string {
 	return "password_metadata"
 }
+
+// TerraformDriftV1 holds the result of the last drift check for a Terraform deployment
+type TerraformDriftV1 struct {
+	// ID is the ID of the Terraform deployment that was checked
+	ID        string `gorm:"primary_key;type:varchar(1024)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Drifted is true when "terraform plan" is not empty
+	Drifted bool
+
+	// ResourceChanges contains a JSON serialized list of the resources that would be changed.
+	// It only holds resource addresses and attribute names, never values.
+	ResourceChanges []byte `gorm:"type:blob"`
+
+	// CheckError holds the error when the drift check could not be run.
+	CheckError string `gorm:"type:text"`
+}
+
+// TableName returns a consistent table name for
+// gorm so multiple structs from different versions of the database all operate
+// on the same table.
+func (TerraformDriftV1) TableName() string {
+	return "terraform_drifts"
+}
+
+// TerraformDeploymentSnapshotV1 holds a previous version of the workspace of a Terraform deployment
+type TerraformDeploymentSnapshotV1 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	// DeploymentID is the ID of the Terraform deployment
+	DeploymentID string `gorm:"index;type:varchar(1024);not null"`
+
+	// Revision increases with each snapshot of a Terraform deployment
+	Revision int `gorm:"not null"`
+
+	// OperationType and OperationState describe the last operation on the
+	// Terraform deployment when the snapshot was taken.
+	OperationType  string
+	OperationState string
+
+	// Workspace contains a JSON serialized version of the Terraform workspace.
+	Workspace []byte `gorm:"type:mediumblob"`
+
+	// StateRef and StateChecksum refer to the Terraform state when it is held in a state store.
+	StateRef      string `gorm:"type:varchar(1024)"`
+	StateChecksum string `gorm:"type:varchar(64)"`
+}
+
+func (TerraformDeploymentSnapshotV1) TableName() string {
+	return "terraform_deployment_snapshots"
+}
+
+// OperationLogV1 records an operation on a Terraform deployment
+type OperationLogV1 struct {
+	ID uint `gorm:"primarykey"`
+
+	// DeploymentID is the ID of the Terraform deployment
+	DeploymentID string `gorm:"index;type:varchar(1024);not null"`
+
+	OperationType string
+	StartedAt     time.Time
+	FinishedAt    *time.Time
+
+	// State is "in progress", "succeeded" or "failed", and Message is the
+	// message that was reported to the platform when the operation finished.
+	State   string
+	Message string `gorm:"type:text"`
+
+	// CorrelationID and RequestID identify the request that started the operation.
+	CorrelationID string
+	RequestID     string
+
+	// OriginatingIdentity contains the JSON serialized X-Broker-API-Originating-Identity
+	// header of the request that started the operation.
+	OriginatingIdentity []byte `gorm:"type:blob"`
+
+	// Stderr holds the truncated Terraform error output when the operation failed.
+	Stderr string `gorm:"type:text"`
+}
+
+func (OperationLogV1) TableName() string {
+	return "operation_logs"
+}
+
+// TerraformDeploymentLeaseV1 locks a Terraform deployment while an operation runs on it,
+// so that broker processes sharing the database do not run Terraform on it at the same time
+type TerraformDeploymentLeaseV1 struct {
+	// ID is the ID of the Terraform deployment
+	ID string `gorm:"primary_key;type:varchar(1024)"`
+
+	// Holder identifies the operation that holds the lease, and Owner
+	// identifies the broker process that runs the operation.
+	Holder string `gorm:"type:varchar(255);not null"`
+	Owner  string `gorm:"type:varchar(255)"`
+
+	// ExpiresAt is when the lease can be taken by another operation,
+	// unless the owner renews it first.
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+func (TerraformDeploymentLeaseV1) TableName() string {
+	return "terraform_deployment_leases"
+}
+
+// TerraformDeploymentV6 adds the time at which an operator asked for the last operation to be cancelled
+type TerraformDeploymentV6 struct {
+	ID        string `gorm:"primary_key;type:varchar(1024)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+
+	// Workspace contains a JSON serialized version of the Terraform workspace.
+	Workspace []byte `gorm:"type:mediumblob"`
+
+	// LastOperationType describes the last operation being performed on the resource.
+	LastOperationType string
+
+	// LastOperationState holds one of the following strings "in progress", "succeeded", "failed".
+	// These mirror the OSB API.
+	LastOperationState string
+
+	// LastOperationMessage is a description that can be passed back to the user.
+	LastOperationMessage string `gorm:"type:text"`
+
+	// StateRef is the key of the Terraform state in the state store. When empty,
+	// the state is held in the workspace.
+	StateRef string `gorm:"type:varchar(1024)"`
+
+	// StateChecksum is the SHA-256 checksum of the Terraform state held in the state store.
+	StateChecksum string `gorm:"type:varchar(64)"`
+
+	// OperationOwner identifies the broker process that last stored the deployment.
+	// While an operation is in progress, the owner refreshes OperationHeartbeatAt so
+	// that other broker processes can tell whether the operation is still running.
+	OperationOwner       string `gorm:"type:varchar(255)"`
+	OperationHeartbeatAt *time.Time
+
+	// CancelRequestedAt is set when an operator asks for the operation in progress to be
+	// cancelled. The owner of the operation stops Terraform when it next records a heartbeat.
+	CancelRequestedAt *time.Time
+}
+
+// TableName returns a consistent table name for
+// gorm so multiple structs from different versions of the database all operate
+// on the same table.
+func (TerraformDeploymentV6) TableName() string {
+	return "terraform_deployments"
+}
+
+// TerraformLogV1 holds the output of a Terraform command that ran for an operation on a Terraform deployment
+type TerraformLogV1 struct {
+	ID uint `gorm:"primarykey"`
+
+	// DeploymentID is the ID of the Terraform deployment
+	DeploymentID string `gorm:"index;type:varchar(1024);not null"`
+
+	// Command is the Terraform command, for example "apply"
+	Command    string    `gorm:"type:varchar(255)"`
+	StartedAt  time.Time `gorm:"index"`
+	FinishedAt time.Time
+
+	// Error describes why the command failed, and is empty when it succeeded
+	Error string `gorm:"type:text"`
+
+	// Stdout, Stderr and TFLog hold the redacted and truncated output of the command,
+	// and the TF_LOG output when it was enabled.
+	Stdout string `gorm:"type:mediumtext"`
+	Stderr string `gorm:"type:mediumtext"`
+	TFLog  string `gorm:"type:mediumtext"`
+}
+
+func (TerraformLogV1) TableName() string {
+	return "terraform_logs"
+}
+
+// TerraformLogV2 encrypts the output of Terraform commands, which can contain the values of
+// sensitive attributes, for example in the JSON of a plan or in the TF_LOG output
+type TerraformLogV2 struct {
+	ID uint `gorm:"primarykey"`
+
+	// DeploymentID is the ID of the Terraform deployment
+	DeploymentID string `gorm:"index;type:varchar(1024);not null"`
+
+	// Command is the Terraform command, for example "apply"
+	Command    string    `gorm:"type:varchar(255)"`
+	StartedAt  time.Time `gorm:"index"`
+	FinishedAt time.Time
+
+	// Error describes why the command failed, and is empty when it succeeded
+	Error string `gorm:"type:text"`
+
+	// Stdout, Stderr and TFLog hold the encrypted, redacted and truncated output of the
+	// command, and the TF_LOG output when it was enabled.
+	Stdout []byte `gorm:"type:mediumblob"`
+	Stderr []byte `gorm:"type:mediumblob"`
+	TFLog  []byte `gorm:"type:mediumblob"`
+}
+
+func (TerraformLogV2) TableName() string {
+	return "terraform_logs"
+}
+
+// OperationLogV2 encrypts the Terraform error output, which can contain the values of sensitive
+// attributes. The originating identity, which is also encrypted, keeps its column.
+type OperationLogV2 struct {
+	ID uint `gorm:"primarykey"`
+
+	// DeploymentID is the ID of the Terraform deployment
+	DeploymentID string `gorm:"index;type:varchar(1024);not null"`
+
+	OperationType string
+	StartedAt     time.Time
+	FinishedAt    *time.Time
+
+	// State is "in progress", "succeeded" or "failed", and Message is the
+	// message that was reported to the platform when the operation finished.
+	State   string
+	Message string `gorm:"type:text"`
+
+	// CorrelationID and RequestID identify the request that started the operation.
+	CorrelationID string
+	RequestID     string
+
+	// OriginatingIdentity contains the encrypted JSON serialized X-Broker-API-Originating-Identity
+	// header of the request that started the operation.
+	OriginatingIdentity []byte `gorm:"type:blob"`
+
+	// Stderr holds the encrypted and truncated Terraform error output when the operation failed.
+	Stderr []byte `gorm:"type:blob"`
+}
+
+func (OperationLogV2) TableName() string {
+	return "operation_logs"
+}
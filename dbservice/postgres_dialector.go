@@ -0,0 +1,63 @@
+// Copyright 2023 the Service Broker Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbservice
+
+import (
+	"regexp"
+	"strings"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// postgresDialector is a PostgreSQL dialector that understands the MySQL column types
+// used in the model definitions. The models, and therefore the migrations, have to keep
+// those types so that existing MySQL databases are not altered, so the types are
+// translated to their PostgreSQL equivalents here instead.
+type postgresDialector struct {
+	postgres.Dialector
+}
+
+var mysqlIntType = regexp.MustCompile(`^int\(\d+\)$`)
+
+func newPostgresDialector(conn gorm.ConnPool) gorm.Dialector {
+	return postgresDialector{Dialector: postgres.Dialector{Config: &postgres.Config{Conn: conn}}}
+}
+
+func (d postgresDialector) DataTypeOf(field *schema.Field) string {
+	dataType := d.Dialector.DataTypeOf(field)
+
+	switch t := strings.ToLower(dataType); {
+	case t == "tinyblob", t == "blob", t == "mediumblob", t == "longblob":
+		return "bytea"
+	case t == "tinytext", t == "mediumtext", t == "longtext":
+		return "text"
+	case mysqlIntType.MatchString(t):
+		return "integer"
+	default:
+		return dataType
+	}
+}
+
+// Migrator must be overridden so that the migrator uses DataTypeOf() from this dialector
+func (d postgresDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return postgres.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 the Service Broker Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbservice
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+)
+
+func TestPostgresDialector_DataTypeOf(t *testing.T) {
+	cases := map[string]struct {
+		Model    any
+		Field    string
+		Expected string
+	}{
+		"blob": {
+			Model:    &models.ServiceBindingCredentialsV2{},
+			Field:    "OtherDetails",
+			Expected: "bytea",
+		},
+		"mediumblob": {
+			Model:    &models.TerraformDeploymentV3{},
+			Field:    "Workspace",
+			Expected: "bytea",
+		},
+		"mediumtext": {
+			Model:    &models.TerraformDeploymentV2{},
+			Field:    "Workspace",
+			Expected: "text",
+		},
+		"int with display width": {
+			Model:    &models.MigrationV1{},
+			Field:    "MigrationID",
+			Expected: "integer",
+		},
+		"text": {
+			Model:    &models.TerraformDeploymentV3{},
+			Field:    "LastOperationMessage",
+			Expected: "text",
+		},
+		"varchar": {
+			Model:    &models.TerraformDeploymentV3{},
+			Field:    "ID",
+			Expected: "varchar(1024)",
+		},
+	}
+
+	dialector := newPostgresDialector(nil).(postgresDialector)
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			s, err := schema.Parse(tc.Model, &sync.Map{}, schema.NamingStrategy{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual := dialector.DataTypeOf(s.LookUpField(tc.Field)); actual != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestPostgresDialector_AllModels(t *testing.T) {
+	allModels := []any{
+		&models.ServiceBindingCredentialsV1{},
+		&models.ServiceBindingCredentialsV2{},
+		&models.ServiceInstanceDetailsV1{},
+		&models.ServiceInstanceDetailsV2{},
+		&models.ServiceInstanceDetailsV3{},
+		&models.ProvisionRequestDetailsV1{},
+		&models.ProvisionRequestDetailsV2{},
+		&models.ProvisionRequestDetailsV3{},
+		&models.BindRequestDetailsV1{},
+		&models.MigrationV1{},
+		&models.CloudOperationV1{},
+		&models.PlanDetailsV1{},
+		&models.TerraformDeploymentV1{},
+		&models.TerraformDeploymentV2{},
+		&models.TerraformDeploymentV3{},
+		&models.TerraformDeploymentV4{},
+		&models.TerraformDeploymentV5{},
+		&models.PasswordMetadataV1{},
+		&models.TerraformDriftV1{},
+		&models.TerraformDeploymentSnapshotV1{},
+		&models.OperationLogV1{},
+		&models.TerraformDeploymentLeaseV1{},
+		&models.TerraformDeploymentV6{},
+		&models.TerraformLogV1{},
+		&models.TerraformLogV2{},
+		&models.OperationLogV2{},
+	}
+	postgresType := regexp.MustCompile(`^(boolean|smallint|integer|bigint|bigserial|decimal|text|varchar\(\d+\)|timestamptz|bytea)$`)
+
+	dialector := newPostgresDialector(nil).(postgresDialector)
+	for _, model := range allModels {
+		s, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, field := range s.Fields {
+			if field.DBName == "" {
+				continue
+			}
+
+			if actual := dialector.DataTypeOf(field); !postgresType.MatchString(actual) {
+				t.Errorf("%s: unexpected PostgreSQL type %q", fmt.Sprintf("%s.%s", s.Name, field.Name), actual)
+			}
+		}
+	}
+}
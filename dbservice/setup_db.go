@@ -19,6 +19,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 
@@ -26,6 +27,8 @@ import (
 
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/spf13/viper"
 	gormmysql "gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -45,8 +48,12 @@ const (
 	dbTypeProp          = "db.type"
 	dbPathProp          = "db.path"
 
-	DBTypeMySQL   = "mysql"
-	DBTypeSQLite3 = "sqlite3"
+	DBTypeMySQL    = "mysql"
+	DBTypeSQLite3  = "sqlite3"
+	DBTypePostgres = "postgres"
+
+	defaultMySQLPort    = "3306"
+	defaultPostgresPort = "5432"
 )
 
 func init() {
@@ -63,7 +70,6 @@ func init() {
 	_ = viper.BindEnv(dbPassProp, "DB_PASSWORD")
 
 	_ = viper.BindEnv(dbPortProp, "DB_PORT")
-	viper.SetDefault(dbPortProp, "3306")
 	_ = viper.BindEnv(dbNameProp, "DB_NAME")
 	viper.SetDefault(dbNameProp, "servicebroker")
 
@@ -84,10 +90,12 @@ func SetupDB(logger lager.Logger) *gorm.DB {
 	}
 	switch dbType {
 	default:
-		logger.Error("Database Setup", fmt.Errorf("invalid database type %q, valid types are: sqlite3 and mysql", dbType))
+		logger.Error("Database Setup", fmt.Errorf("invalid database type %q, valid types are: sqlite3, mysql and postgres", dbType))
 		os.Exit(1)
 	case DBTypeMySQL:
 		db, err = setupMySQLDB(logger)
+	case DBTypePostgres:
+		db, err = setupPostgresDB(logger)
 	case DBTypeSQLite3:
 		db, err = setupSqlite3Db(logger)
 	}
@@ -123,7 +131,7 @@ func setupMySQLDB(logger lager.Logger) (*gorm.DB, error) {
 		return nil, errors.New("DB_HOST, DB_USERNAME and DB_PASSWORD are required environment variables")
 	}
 
-	dbPort := viper.GetString(dbPortProp)
+	dbPort := dbPortOrDefault(defaultMySQLPort)
 	dbName := viper.GetString(dbNameProp)
 
 	tlsStr, err := generateTLSStringFromEnv()
@@ -146,6 +154,119 @@ func setupMySQLDB(logger lager.Logger) (*gorm.DB, error) {
 	}), &gorm.Config{})
 }
 
+func setupPostgresDB(logger lager.Logger) (*gorm.DB, error) {
+	dbHost := viper.GetString(dbHostProp)
+	dbUsername := viper.GetString(dbUserProp)
+	dbPassword := viper.GetString(dbPassProp)
+
+	if dbPassword == "" || dbHost == "" || dbUsername == "" {
+		return nil, errors.New("DB_HOST, DB_USERNAME and DB_PASSWORD are required environment variables")
+	}
+
+	dbPort := dbPortOrDefault(defaultPostgresPort)
+	dbName := viper.GetString(dbNameProp)
+
+	config, sslMode, err := generatePostgresConfigFromEnv(dbHost, dbPort, dbUsername, dbPassword, dbName)
+	if err != nil {
+		return nil, fmt.Errorf("error generating PostgreSQL config from env: %s", err)
+	}
+
+	logger.Info("Connecting to PostgreSQL Database", lager.Data{
+		"host":     dbHost,
+		"port":     dbPort,
+		"name":     dbName,
+		"username": dbUsername,
+		"sslmode":  sslMode,
+	})
+
+	return gorm.Open(newPostgresDialector(stdlib.OpenDB(*config)), &gorm.Config{})
+}
+
+// generatePostgresConfigFromEnv builds the connection config from the same TLS settings
+// as MySQL. The DB_TLS values are mapped to the equivalent PostgreSQL "sslmode", and when
+// custom certs are provided they are loaded into the TLS config as for MySQL.
+func generatePostgresConfigFromEnv(host, port, username, password, name string) (*pgx.ConnConfig, string, error) {
+	caCert := viper.GetString(caCertProp)
+	clientCertStr := viper.GetString(clientCertProp)
+	clientKeyStr := viper.GetString(clientKeyProp)
+	skipVerify := viper.GetBool(customTLSSkipVerify)
+
+	customCerts := caCert != "" && clientCertStr != "" && clientKeyStr != ""
+
+	// Custom certs set the sslmode, whatever the value of DB_TLS, as for MySQL
+	var sslMode string
+	switch {
+	case customCerts && skipVerify:
+		sslMode = "require"
+	case customCerts:
+		sslMode = "verify-full"
+	default:
+		var err error
+		if sslMode, err = postgresSSLMode(viper.GetString(dbTLS)); err != nil {
+			return nil, "", err
+		}
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(username, password),
+		Host:     net.JoinHostPort(host, port),
+		Path:     name,
+		RawQuery: url.Values{"sslmode": {sslMode}, "connect_timeout": {"30"}}.Encode(),
+	}
+
+	config, err := pgx.ParseConfig(dsn.String())
+	if err != nil {
+		return nil, "", fmt.Errorf("error parsing connection string: %s", err)
+	}
+
+	if customCerts {
+		rootCertPool := x509.NewCertPool()
+
+		if ok := rootCertPool.AppendCertsFromPEM([]byte(caCert)); !ok {
+			return nil, "", errors.New("error appending cert: the CA_CERT environment variable does not contain a PEM encoded certificate")
+		}
+
+		certs, err := tls.X509KeyPair([]byte(clientCertStr), []byte(clientKeyStr))
+		if err != nil {
+			return nil, "", fmt.Errorf("error parsing cert pair from the CLIENT_CERT and CLIENT_KEY environment variables: %s", err)
+		}
+
+		config.TLSConfig.RootCAs = rootCertPool
+		config.TLSConfig.Certificates = []tls.Certificate{certs}
+	}
+
+	return config, sslMode, nil
+}
+
+// postgresSSLMode maps the MySQL style DB_TLS values onto PostgreSQL "sslmode" values.
+// Any other value must already be a valid "sslmode". The "custom" value needs custom certs.
+func postgresSSLMode(dbTLS string) (string, error) {
+	switch dbTLS {
+	case "true":
+		return "verify-full", nil
+	case "false":
+		return "disable", nil
+	case "skip-verify":
+		return "require", nil
+	case "preferred":
+		return "prefer", nil
+	case "", "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		return dbTLS, nil
+	case "custom":
+		return "", errors.New("the DB_TLS environment variable is \"custom\", but the CA_CERT, CLIENT_CERT and CLIENT_KEY environment variables are not all set")
+	default:
+		return "", fmt.Errorf("invalid value %q for the DB_TLS environment variable", dbTLS)
+	}
+}
+
+func dbPortOrDefault(defaultPort string) string {
+	if dbPort := viper.GetString(dbPortProp); dbPort != "" {
+		return dbPort
+	}
+	return defaultPort
+}
+
 func generateTLSStringFromEnv() (string, error) {
 	caCert := viper.GetString(caCertProp)
 	clientCertStr := viper.GetString(clientCertProp)
@@ -0,0 +1,185 @@
+// Copyright 2023 the Service Broker Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbservice
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestGeneratePostgresConfigFromEnv(t *testing.T) {
+	cases := map[string]struct {
+		DBTLS              string
+		ExpectedSSLMode    string
+		ExpectedTLS        bool
+		ExpectedSkipVerify bool
+	}{
+		"tls enabled": {
+			DBTLS:              "true",
+			ExpectedSSLMode:    "verify-full",
+			ExpectedTLS:        true,
+			ExpectedSkipVerify: false,
+		},
+		"tls disabled": {
+			DBTLS:           "false",
+			ExpectedSSLMode: "disable",
+			ExpectedTLS:     false,
+		},
+		"skip verify": {
+			DBTLS:              "skip-verify",
+			ExpectedSSLMode:    "require",
+			ExpectedTLS:        true,
+			ExpectedSkipVerify: true,
+		},
+		"postgres sslmode": {
+			DBTLS:              "verify-ca",
+			ExpectedSSLMode:    "verify-ca",
+			ExpectedTLS:        true,
+			ExpectedSkipVerify: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			previous := viper.GetString(dbTLS)
+			viper.Set(dbTLS, tc.DBTLS)
+			defer viper.Set(dbTLS, previous)
+
+			config, sslMode, err := generatePostgresConfigFromEnv("10.0.0.30", "5432", "pgadmin", "p@ss/word", "servicebroker")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if sslMode != tc.ExpectedSSLMode {
+				t.Errorf("expected sslmode %q, got %q", tc.ExpectedSSLMode, sslMode)
+			}
+			if config.Host != "10.0.0.30" || config.Port != 5432 || config.User != "pgadmin" || config.Password != "p@ss/word" || config.Database != "servicebroker" {
+				t.Errorf("unexpected connection config: %#v", config.Config)
+			}
+
+			switch {
+			case !tc.ExpectedTLS && config.TLSConfig != nil:
+				t.Error("expected TLS to be disabled")
+			case tc.ExpectedTLS && config.TLSConfig == nil:
+				t.Error("expected TLS to be enabled")
+			case tc.ExpectedTLS && config.TLSConfig.InsecureSkipVerify != tc.ExpectedSkipVerify:
+				t.Errorf("expected InsecureSkipVerify to be %t", tc.ExpectedSkipVerify)
+			}
+		})
+	}
+}
+
+func TestGeneratePostgresConfigFromEnvCustomCerts(t *testing.T) {
+	cert, key := generateCertificate(t)
+	settings := map[string]any{
+		dbTLS:               "custom",
+		caCertProp:          cert,
+		clientCertProp:      cert,
+		clientKeyProp:       key,
+		customTLSSkipVerify: false,
+	}
+	for key, value := range settings {
+		previous := viper.Get(key)
+		viper.Set(key, value)
+		defer viper.Set(key, previous)
+	}
+
+	config, sslMode, err := generatePostgresConfigFromEnv("10.0.0.30", "5432", "pgadmin", "p@ss/word", "servicebroker")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sslMode != "verify-full" {
+		t.Errorf("expected sslmode %q, got %q", "verify-full", sslMode)
+	}
+	if config.TLSConfig == nil || config.TLSConfig.RootCAs == nil || len(config.TLSConfig.Certificates) != 1 {
+		t.Errorf("expected the custom certs in the TLS config, got %#v", config.TLSConfig)
+	}
+}
+
+// generateCertificate returns a PEM encoded self-signed certificate and its key
+func generateCertificate(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestGeneratePostgresConfigFromEnvErrors(t *testing.T) {
+	cases := map[string]struct {
+		Settings      map[string]string
+		ExpectedError string
+	}{
+		"invalid DB_TLS": {
+			Settings:      map[string]string{dbTLS: "sometimes"},
+			ExpectedError: `invalid value "sometimes" for the DB_TLS environment variable`,
+		},
+		"custom DB_TLS without certs": {
+			Settings:      map[string]string{dbTLS: "custom"},
+			ExpectedError: `the DB_TLS environment variable is "custom", but the CA_CERT, CLIENT_CERT and CLIENT_KEY environment variables are not all set`,
+		},
+		"invalid CA_CERT": {
+			Settings:      map[string]string{caCertProp: "not a cert", clientCertProp: "cert", clientKeyProp: "key"},
+			ExpectedError: "error appending cert: the CA_CERT environment variable does not contain a PEM encoded certificate",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			for key, value := range tc.Settings {
+				previous := viper.GetString(key)
+				viper.Set(key, value)
+				defer viper.Set(key, previous)
+			}
+
+			_, _, err := generatePostgresConfigFromEnv("10.0.0.30", "5432", "pgadmin", "p@ss/word", "servicebroker")
+			if err == nil || err.Error() != tc.ExpectedError {
+				t.Errorf("expected error %q, got %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
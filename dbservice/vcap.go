@@ -58,7 +58,7 @@ func SetDatabaseCredentials(vcapService VcapService) error {
 	}
 
 	// Set up database credentials using environment variables
-	viper.Set(dbTypeProp, DBTypeMySQL)
+	viper.Set(dbTypeProp, vcapDatabaseType(vcapService))
 	viper.Set(dbHostProp, coalesce(vcapService.Credentials["host"], vcapService.Credentials["hostname"]))
 	viper.Set(dbUserProp, coalesce(u.User.Username(), vcapService.Credentials["Username"], vcapService.Credentials["username"]))
 	if pw, _ := u.User.Password(); pw != "" {
@@ -67,6 +67,11 @@ func SetDatabaseCredentials(vcapService VcapService) error {
 		viper.Set(dbPassProp, coalesce(vcapService.Credentials["Password"], vcapService.Credentials["password"]))
 	}
 	viper.Set(dbNameProp, coalesce(vcapService.Credentials["database_name"], vcapService.Credentials["name"]))
+	if port := u.Port(); port != "" {
+		viper.Set(dbPortProp, port)
+	} else if port := coalesce(vcapService.Credentials["port"]); port != "" {
+		viper.Set(dbPortProp, port)
+	}
 
 	// If database is provided by gcp service broker, retrieve the client_cert, ca_cert and client_key fields
 	if contains(vcapService.Tags, "gcp") {
@@ -100,15 +105,30 @@ func ParseVcapServices(vcapServicesData string) (VcapService, error) {
 		return VcapService{}, fmt.Errorf("error unmarshalling VCAP_SERVICES: %s", err)
 	}
 
+	if len(vcapMap) == 0 {
+		return VcapService{}, fmt.Errorf("error parsing VCAP_SERVICES")
+	}
+
+	var vcapServices []VcapService
 	for _, vcapArray := range vcapMap {
-		vcapService, err := findMySQLTag(vcapArray, "mysql")
-		if err != nil {
-			return VcapService{}, fmt.Errorf("error finding MySQL tag: %s", err)
-		}
-		return vcapService, nil
+		vcapServices = append(vcapServices, vcapArray...)
+	}
+
+	vcapService, err := findDatabaseTag(vcapServices)
+	if err != nil {
+		return VcapService{}, fmt.Errorf("error finding database tag: %s", err)
 	}
+	return vcapService, nil
+}
 
-	return VcapService{}, fmt.Errorf("error parsing VCAP_SERVICES")
+// vcapDatabaseType returns the database type for a VcapService found by findDatabaseTag
+func vcapDatabaseType(vcapService VcapService) string {
+	for _, tag := range postgresTags {
+		if contains(vcapService.Tags, tag) {
+			return DBTypePostgres
+		}
+	}
+	return DBTypeMySQL
 }
 
 // whether a given string array arr contains string key
@@ -121,18 +141,28 @@ func contains(arr []string, key string) bool {
 	return false
 }
 
-// return the index of the VcapService with a tag of "mysql" in the list of VcapServices, fail if we find more or fewer than 1
-func findMySQLTag(vcapServices []VcapService, key string) (VcapService, error) {
+var (
+	databaseTags = []string{"mysql", "postgres", "postgresql"}
+	postgresTags = []string{"postgres", "postgresql"}
+)
+
+// return the VcapService with a tag of "mysql", "postgres" or "postgresql" in the list of VcapServices, fail if we find more or fewer than 1
+func findDatabaseTag(vcapServices []VcapService) (VcapService, error) {
 	index := -1
 	count := 0
 	for i, vcapService := range vcapServices {
-		if contains(vcapService.Tags, key) {
-			count += 1
-			index = i
+		for _, tag := range databaseTags {
+			if contains(vcapService.Tags, tag) {
+				count += 1
+				index = i
+				break
+			}
 		}
 	}
 	if count != 1 {
-		return VcapService{}, fmt.Errorf("the variable VCAP_SERVICES must have one VCAP service with a tag of %s. There are currently %d VCAP services with the tag %s", "'mysql'", count, "'mysql'")
+		return VcapService{}, fmt.Errorf("the variable VCAP_SERVICES must have one VCAP service with a tag of %s. There are currently %d VCAP services with one of those tags", databaseTagsDescription, count)
 	}
 	return vcapServices[index], nil
 }
+
+const databaseTagsDescription = "'mysql', 'postgres' or 'postgresql'"
@@ -10,13 +10,18 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/internal/brokerpak/platform"
 )
 
-const HashicorpURLTemplate = "https://releases.hashicorp.com/${name}/${version}/${name}_${version}_${os}_${arch}.zip"
+const (
+	HashicorpURLTemplate = "https://releases.hashicorp.com/${name}/${version}/${name}_${version}_${os}_${arch}.zip"
+	OpenTofuURLTemplate  = "https://github.com/opentofu/opentofu/releases/download/v${version}/${name}_${version}_${os}_${arch}.zip"
+)
 
 func URL(name, version, urlTemplate string, plat platform.Platform) string {
 	replacer := strings.NewReplacer("${name}", name, "${version}", version, "${os}", plat.Os, "${arch}", plat.Arch)
 	var template string
 
 	switch {
+	case urlTemplate == "" && name == "tofu":
+		template = OpenTofuURLTemplate
 	case urlTemplate == "":
 		template = HashicorpURLTemplate
 	case isURL(urlTemplate):
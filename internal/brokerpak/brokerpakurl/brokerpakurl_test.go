@@ -33,6 +33,17 @@ func TestTerraformResource_URL(t *testing.T) {
 			},
 			ExpectedURL: fmt.Sprintf("https://releases.hashicorp.com/%s/%s/%s_%s_%s_%s.zip", "foo", "1.0", "foo", "1.0", "my_os", "my_arch"),
 		},
+		"opentofu": {
+			Resource: manifest.TerraformResource{
+				Name:    "tofu",
+				Version: "1.6.0",
+			},
+			Plat: platform.Platform{
+				Os:   "my_os",
+				Arch: "my_arch",
+			},
+			ExpectedURL: "https://github.com/opentofu/opentofu/releases/download/v1.6.0/tofu_1.6.0_my_os_my_arch.zip",
+		},
 		"custom": {
 			Resource: manifest.TerraformResource{
 				Name:        "foo",
@@ -6,6 +6,7 @@ import (
 
 	"github.com/cloudfoundry/cloud-service-broker/internal/brokerpak/platform"
 	"github.com/cloudfoundry/cloud-service-broker/internal/tfproviderfqn"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 )
 
 // Manifest is the internal model for the brokerpak manifest
@@ -24,9 +25,12 @@ type Manifest struct {
 	EnvConfigMapping                   map[string]string
 	TerraformUpgradePath               []*version.Version
 	TerraformStateProviderReplacements map[string]string
+	TerraformRetryRules                []executor.RetryRule
 }
 
 type TerraformVersion struct {
+	// Name is "tofu" for OpenTofu, and "terraform" or empty for HashiCorp Terraform
+	Name        string
 	Version     *version.Version
 	Default     bool
 	Source      string
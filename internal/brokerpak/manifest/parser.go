@@ -9,6 +9,7 @@ import (
 
 	"github.com/cloudfoundry/cloud-service-broker/internal/brokerpak/platform"
 	"github.com/cloudfoundry/cloud-service-broker/internal/tfproviderfqn"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/validation"
 )
 
@@ -27,6 +28,7 @@ type parser struct {
 	EnvConfigMapping                   map[string]string      `yaml:"env_config_mapping"`
 	TerraformUpgradePath               []TerraformUpgradePath `yaml:"terraform_upgrade_path,omitempty"`
 	TerraformStateProviderReplacements map[string]string      `yaml:"terraform_state_provider_replacements,omitempty"`
+	TerraformRetryRules                []executor.RetryRule   `yaml:"terraform_retry_rules,omitempty"`
 }
 
 func Parse(input []byte) (*Manifest, error) {
@@ -49,6 +51,7 @@ func Parse(input []byte) (*Manifest, error) {
 	result.RequiredEnvVars = receiver.RequiredEnvVars
 	result.EnvConfigMapping = receiver.EnvConfigMapping
 	result.TerraformStateProviderReplacements = receiver.TerraformStateProviderReplacements
+	result.TerraformRetryRules = receiver.TerraformRetryRules
 
 	steps := []func() *validation.FieldError{
 		func() (errs *validation.FieldError) {
@@ -79,7 +82,7 @@ func Parse(input []byte) (*Manifest, error) {
 func parseTerraformUpgradePath(p parser) (result []*version.Version, errs *validation.FieldError) {
 	availableTerraformVersions := make(map[string]bool)
 	for _, v := range p.TerraformResources {
-		if v.Name == "terraform" {
+		if v.resourceType() == terraformVersion {
 			availableTerraformVersions[v.Version] = v.Default
 		}
 	}
@@ -120,6 +123,13 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 		return nil, nil, nil, validation.ErrMissingField("terraform_binaries")
 	}
 
+	// Providers are named for the default version, and OpenTofu installs them from its own registry.
+	// When the versions mix Terraform and OpenTofu, the providers are installed for both.
+	newProviderFQN := tfproviderfqn.New
+	if defaultsToOpenTofu(p.TerraformResources) {
+		newProviderFQN = tfproviderfqn.NewForOpenTofu
+	}
+
 	terraformVersionCache := make(map[string]struct{})
 	for i, r := range p.TerraformResources {
 		var (
@@ -134,7 +144,7 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 		)
 
 		if r.resourceType() == terraformProvider {
-			providerFQN, err = tfproviderfqn.New(r.Name, r.Provider)
+			providerFQN, err = newProviderFQN(r.Name, r.Provider)
 			if err != nil {
 				errs = errs.Also((&validation.FieldError{
 					Message: err.Error(),
@@ -145,7 +155,7 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 
 		if r.Default && r.resourceType() != terraformVersion {
 			errs = errs.Also((&validation.FieldError{
-				Message: "This field is only valid for `terraform`",
+				Message: "This field is only valid for `terraform` or `tofu`",
 				Paths:   []string{"default"},
 			}).ViaFieldIndex("terraform_binaries", i))
 		}
@@ -165,6 +175,7 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 		switch r.resourceType() {
 		case terraformVersion:
 			versions = append(versions, TerraformVersion{
+				Name:        r.Name,
 				Version:     ver,
 				Default:     r.Default,
 				Source:      r.Source,
@@ -220,6 +231,22 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 	return versions, providers, binaries, errs
 }
 
+// defaultsToOpenTofu reports whether the default version, or the only version, is OpenTofu
+func defaultsToOpenTofu(resources []TerraformResource) bool {
+	var versions []TerraformResource
+	for _, r := range resources {
+		switch {
+		case r.resourceType() != terraformVersion:
+		case r.Default:
+			return r.Name == openTofuName
+		default:
+			versions = append(versions, r)
+		}
+	}
+
+	return len(versions) == 1 && versions[0].Name == openTofuName
+}
+
 var _ validation.Validatable = (*parser)(nil)
 
 func (m *parser) Validate() (errs *validation.FieldError) {
@@ -230,6 +257,7 @@ func (m *parser) Validate() (errs *validation.FieldError) {
 		m.validatePlatforms,
 		m.validateServiceDefinitions,
 		m.validateParameters,
+		m.validateTerraformRetryRules,
 	}
 
 	for _, v := range validators {
@@ -288,3 +316,11 @@ func (m *parser) validateParameters() (errs *validation.FieldError) {
 
 	return errs
 }
+
+func (m *parser) validateTerraformRetryRules() (errs *validation.FieldError) {
+	for i, rule := range m.TerraformRetryRules {
+		errs = errs.Also(rule.Validate().ViaFieldIndex("terraform_retry_rules", i))
+	}
+
+	return errs
+}
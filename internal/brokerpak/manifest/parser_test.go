@@ -12,6 +12,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/internal/brokerpak/manifest"
 	"github.com/cloudfoundry/cloud-service-broker/internal/brokerpak/platform"
 	"github.com/cloudfoundry/cloud-service-broker/internal/tfproviderfqn"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 )
 
 var _ = Describe("Parser", func() {
@@ -36,6 +37,7 @@ var _ = Describe("Parser", func() {
 			},
 			TerraformVersions: []manifest.TerraformVersion{
 				{
+					Name:        "terraform",
 					Version:     version.Must(version.NewVersion("1.1.4")),
 					Source:      "https://github.com/hashicorp/terraform/archive/v1.1.4.zip",
 					URLTemplate: "https://releases.hashicorp.com/${name}/${version}/${name}_${version}_${os}_${arch}.zip",
@@ -99,16 +101,19 @@ var _ = Describe("Parser", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(m.TerraformVersions).To(ConsistOf(
 				manifest.TerraformVersion{
+					Name:        "terraform",
 					Version:     version.Must(version.NewVersion("1.1.4")),
 					Source:      "https://github.com/hashicorp/terraform/archive/v1.1.4.zip",
 					Default:     false,
 					URLTemplate: "https://releases.hashicorp.com/${name}/${version}/${name}_${version}_${os}_${arch}.zip",
 				},
 				manifest.TerraformVersion{
+					Name:    "terraform",
 					Version: version.Must(version.NewVersion("1.1.5")),
 					Default: false,
 				},
 				manifest.TerraformVersion{
+					Name:    "terraform",
 					Version: version.Must(version.NewVersion("1.1.6")),
 					Default: true,
 				},
@@ -181,6 +186,91 @@ var _ = Describe("Parser", func() {
 		})
 	})
 
+	Context("OpenTofu", func() {
+		It("can parse a manifest that uses OpenTofu", func() {
+			m, err := manifest.Parse(fakeManifest(
+				with("terraform_binaries", []map[string]any{
+					{"name": "tofu", "version": "1.6.0"},
+					{"name": "terraform-provider-random", "version": "3.1.0"},
+					{"name": "terraform-provider-mysql", "version": "1.9.0", "provider": "petoju/mysql"},
+					{"name": "terraform-provider-postgresql", "version": "1.19.0", "provider": "registry.terraform.io/cyrilgdn/postgresql"},
+				}),
+			))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.TerraformVersions).To(Equal([]manifest.TerraformVersion{{Name: "tofu", Version: version.Must(version.NewVersion("1.6.0"))}}))
+			Expect(m.DefaultsToOpenTofu()).To(BeTrue())
+			Expect(m.MixesTerraformAndOpenTofu()).To(BeFalse())
+			Expect(m.OpenTofuVersions()).To(Equal([]*version.Version{version.Must(version.NewVersion("1.6.0"))}))
+
+			var providers []string
+			for _, p := range m.TerraformProviders {
+				providers = append(providers, p.Provider.String())
+			}
+			Expect(providers).To(Equal([]string{
+				"registry.opentofu.org/hashicorp/random",
+				"registry.opentofu.org/petoju/mysql",
+				"registry.terraform.io/cyrilgdn/postgresql",
+			}))
+		})
+
+		It("can upgrade from Terraform to OpenTofu", func() {
+			m, err := manifest.Parse(fakeManifest(
+				withAdditionalEntry("terraform_binaries", map[string]any{
+					"name":    "tofu",
+					"version": "1.6.0",
+					"default": true,
+				}),
+				with("terraform_upgrade_path",
+					[]map[string]any{
+						{"version": "1.1.4"},
+						{"version": "1.6.0"},
+					},
+				),
+			))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.DefaultsToOpenTofu()).To(BeTrue())
+			Expect(m.MixesTerraformAndOpenTofu()).To(BeTrue())
+			Expect(m.OpenTofuVersions()).To(Equal([]*version.Version{version.Must(version.NewVersion("1.6.0"))}))
+			Expect(m.TerraformUpgradePath).To(Equal([]*version.Version{
+				version.Must(version.NewVersion("1.1.4")),
+				version.Must(version.NewVersion("1.6.0")),
+			}))
+		})
+
+		It("does not use OpenTofu when Terraform is the default", func() {
+			m, err := manifest.Parse(fakeManifest(
+				withAdditionalEntry("terraform_binaries", map[string]any{
+					"name":    "tofu",
+					"version": "1.0.0",
+				}),
+				withAdditionalEntry("terraform_binaries", map[string]any{
+					"name":    "terraform",
+					"version": "1.1.5",
+					"default": true,
+				}),
+				withAdditionalEntry("terraform_binaries", map[string]any{
+					"name":    "terraform-provider-null",
+					"version": "3.1.0",
+				}),
+			))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.DefaultsToOpenTofu()).To(BeFalse())
+			Expect(m.TerraformProviders[1].Provider.String()).To(Equal("registry.terraform.io/hashicorp/null"))
+		})
+
+		It("does not allow Terraform and OpenTofu to have the same version", func() {
+			m, err := manifest.Parse(fakeManifest(withAdditionalEntry("terraform_binaries", map[string]any{
+				"name":    "tofu",
+				"version": "1.1.4",
+				"default": true,
+			})))
+			Expect(err).To(MatchError("error validating manifest: duplicated value, must be unique: 1.1.4: version"))
+			Expect(m).To(BeNil())
+		})
+	})
+
 	Context("terraform_state_provider_replacements", func() {
 		It("can parse and validate the provider replacements", func() {
 			m, err := manifest.Parse(fakeManifest(with("terraform_state_provider_replacements",
@@ -197,6 +287,32 @@ var _ = Describe("Parser", func() {
 		})
 	})
 
+	Context("terraform_retry_rules", func() {
+		It("can parse and validate the retry rules", func() {
+			m, err := manifest.Parse(fakeManifest(with("terraform_retry_rules",
+				[]map[string]any{
+					{"pattern": "Error 409", "max_attempts": 3, "initial_backoff": "5s", "max_backoff": "1m"},
+				},
+			)))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.TerraformRetryRules).To(Equal([]executor.RetryRule{
+				{Pattern: "Error 409", MaxAttempts: 3, InitialBackoff: "5s", MaxBackoff: "1m"},
+			}))
+		})
+
+		It("fails when a rule is not valid", func() {
+			m, err := manifest.Parse(fakeManifest(with("terraform_retry_rules",
+				[]map[string]any{
+					{"pattern": "Error 409", "max_attempts": 1},
+				},
+			)))
+
+			Expect(err).To(MatchError("error validating manifest: invalid value: 1: terraform_retry_rules[0].max_attempts"))
+			Expect(m).To(BeNil())
+		})
+	})
+
 	Context("terraform_upgrade_path", func() {
 		It("can parse and validate the upgrade path", func() {
 			m, err := manifest.Parse(fakeManifest(
@@ -361,7 +477,7 @@ var _ = Describe("Parser", func() {
 				"source":  "https://github.com/terraform-providers/terraform-provider-random/archive/v3.1.0.zip",
 				"default": true,
 			})))
-			Expect(err).To(MatchError(ContainSubstring("This field is only valid for `terraform`: terraform_binaries[3].default")))
+			Expect(err).To(MatchError(ContainSubstring("This field is only valid for `terraform` or `tofu`: terraform_binaries[3].default")))
 			Expect(m).To(BeNil())
 		})
 	})
@@ -14,6 +14,7 @@ func (m *Manifest) Serialize() ([]byte, error) {
 		RequiredEnvVars:                    m.RequiredEnvVars,
 		EnvConfigMapping:                   m.EnvConfigMapping,
 		TerraformStateProviderReplacements: m.TerraformStateProviderReplacements,
+		TerraformRetryRules:                m.TerraformRetryRules,
 	}
 
 	for _, v := range m.TerraformUpgradePath {
@@ -22,7 +23,7 @@ func (m *Manifest) Serialize() ([]byte, error) {
 
 	for _, v := range m.TerraformVersions {
 		p.TerraformResources = append(p.TerraformResources, TerraformResource{
-			Name:        "terraform",
+			Name:        v.BinaryName(),
 			Version:     v.Version.String(),
 			Source:      v.Source,
 			URLTemplate: v.URLTemplate,
@@ -22,10 +22,15 @@ type TerraformResource struct {
 	// If non is specified HashicorpUrlTemplate is used.
 	URLTemplate string `yaml:"url_template,omitempty"`
 
-	// Default is used to mark the default Terraform version when there is more than one
+	// Default is used to mark the default Terraform or OpenTofu version when there is more than one
 	Default bool `yaml:"default,omitempty"`
 }
 
+const (
+	terraformName = "terraform"
+	openTofuName  = "tofu"
+)
+
 type terraformResourceType int
 
 const (
@@ -39,7 +44,7 @@ func (tr *TerraformResource) resourceType() terraformResourceType {
 	switch {
 	case tr.Name == "":
 		return invalidType
-	case tr.Name == "terraform":
+	case tr.Name == terraformName, tr.Name == openTofuName:
 		return terraformVersion
 	case strings.HasPrefix(tr.Name, "terraform-provider-"):
 		return terraformProvider
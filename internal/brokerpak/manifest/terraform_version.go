@@ -24,3 +24,48 @@ func (m *Manifest) DefaultTerraformVersion() (*version.Version, error) {
 		return &version.Version{}, fmt.Errorf("no default terraform found")
 	}
 }
+
+// BinaryName returns the name of the binary, which is "terraform" unless the version is OpenTofu
+func (v TerraformVersion) BinaryName() string {
+	if v.Name == "" {
+		return terraformName
+	}
+	return v.Name
+}
+
+// IsOpenTofu reports whether this version is an OpenTofu binary rather than HashiCorp Terraform
+func (v TerraformVersion) IsOpenTofu() bool {
+	return v.Name == openTofuName
+}
+
+// OpenTofuVersions returns the versions that are OpenTofu binaries rather than HashiCorp Terraform
+func (m *Manifest) OpenTofuVersions() (versions []*version.Version) {
+	for _, r := range m.TerraformVersions {
+		if r.IsOpenTofu() {
+			versions = append(versions, r.Version)
+		}
+	}
+	return versions
+}
+
+// MixesTerraformAndOpenTofu reports whether some versions are OpenTofu binaries and others are
+// HashiCorp Terraform, as in an upgrade path from Terraform to OpenTofu
+func (m *Manifest) MixesTerraformAndOpenTofu() bool {
+	openTofuVersions := len(m.OpenTofuVersions())
+	return openTofuVersions > 0 && openTofuVersions < len(m.TerraformVersions)
+}
+
+// DefaultsToOpenTofu reports whether the default version is an OpenTofu binary
+func (m *Manifest) DefaultsToOpenTofu() bool {
+	defaultVersion, err := m.DefaultTerraformVersion()
+	if err != nil {
+		return false
+	}
+
+	for _, r := range m.TerraformVersions {
+		if r.Version.Equal(defaultVersion) {
+			return r.IsOpenTofu()
+		}
+	}
+	return false
+}
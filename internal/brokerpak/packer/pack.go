@@ -72,7 +72,7 @@ func packSources(m *manifest.Manifest, tmp string, cachePath string) error {
 	}
 
 	for _, resource := range m.TerraformVersions {
-		if err := packSource(resource.Source, "terraform"); err != nil {
+		if err := packSource(resource.Source, resource.BinaryName()); err != nil {
 			return err
 		}
 	}
@@ -103,7 +103,7 @@ func packBinaries(m *manifest.Manifest, tmp string, cachePath string) error {
 		p := filepath.Join(tmp, "bin", platform.Os, platform.Arch)
 
 		for _, resource := range m.TerraformVersions {
-			if err := cachedFetchFile(getAny, brokerpakurl.URL("terraform", resource.Version.String(), resource.URLTemplate, platform), filepath.Join(p, resource.Version.String()), cachePath); err != nil {
+			if err := cachedFetchFile(getAny, brokerpakurl.URL(resource.BinaryName(), resource.Version.String(), resource.URLTemplate, platform), filepath.Join(p, resource.Version.String()), cachePath); err != nil {
 				return err
 			}
 		}
@@ -29,6 +29,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/internal/brokerpak/fetcher"
 	"github.com/cloudfoundry/cloud-service-broker/internal/brokerpak/manifest"
 	"github.com/cloudfoundry/cloud-service-broker/internal/brokerpak/platform"
+	"github.com/cloudfoundry/cloud-service-broker/internal/tfproviderfqn"
 	"github.com/cloudfoundry/cloud-service-broker/internal/zippy"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
 	"github.com/cloudfoundry/cloud-service-broker/utils/stream"
@@ -110,10 +111,23 @@ func (pak *BrokerPakReader) Validate() error {
 		return fmt.Errorf("couldn't list services: %v", err)
 	}
 
+	pakManifest, err := pak.Manifest()
+	if err != nil {
+		return err
+	}
+
+	var tfVersions []*version.Version
+	for _, v := range pakManifest.TerraformVersions {
+		tfVersions = append(tfVersions, v.Version)
+	}
+
 	for _, svc := range services {
 		if err := svc.Validate(); err != nil {
 			return fmt.Errorf("service %q failed validation: %v", svc.Name, err)
 		}
+		if err := svc.ValidateTerraformVersions(tfVersions); err != nil {
+			return fmt.Errorf("service %q failed validation: %v", svc.Name, err)
+		}
 	}
 
 	return nil
@@ -147,7 +161,7 @@ func (pak *BrokerPakReader) ExtractPlatformBins(destination string) error {
 	}
 
 	for _, r := range mf.TerraformProviders {
-		if err := pak.extractProvider(r, destination, terraformVersion); err != nil {
+		if err := pak.extractProvider(r, destination, terraformVersion, mf.MixesTerraformAndOpenTofu()); err != nil {
 			return err
 		}
 	}
@@ -165,14 +179,32 @@ func (pak *BrokerPakReader) ExtractPlatformBins(destination string) error {
 	return nil
 }
 
-func (pak *BrokerPakReader) extractProvider(r manifest.TerraformProvider, destination string, terraformVersion *version.Version) error {
+// extractProvider installs a provider for the default version. When the versions mix Terraform and
+// OpenTofu, a provider from their registries is installed for both, as Terraform and OpenTofu look
+// for providers without a hostname in their own registry.
+func (pak *BrokerPakReader) extractProvider(r manifest.TerraformProvider, destination string, terraformVersion *version.Version, forTerraformAndOpenTofu bool) error {
 	filePath, err := pak.findFileInZip(fmt.Sprintf("%s_v%s", r.Name, r.Version))
 	if err != nil {
 		return err
 	}
 
-	if err := pak.contents.ExtractFile(filePath, providerInstallPath(terraformVersion, destination, r)); err != nil {
-		return fmt.Errorf("error extracting terraform-provider file: %w", err)
+	providers := []tfproviderfqn.TfProviderFQN{r.Provider}
+	if forTerraformAndOpenTofu {
+		providers = r.Provider.ForTerraformAndOpenTofu()
+	}
+
+	installPaths := make(map[string]struct{})
+	for _, provider := range providers {
+		r.Provider = provider
+		installPath := providerInstallPath(terraformVersion, destination, r)
+		if _, ok := installPaths[installPath]; ok {
+			continue
+		}
+		installPaths[installPath] = struct{}{}
+
+		if err := pak.contents.ExtractFile(filePath, installPath); err != nil {
+			return fmt.Errorf("error extracting terraform-provider file: %w", err)
+		}
 	}
 
 	return nil
@@ -193,15 +225,19 @@ func (pak *BrokerPakReader) extractBinary(r manifest.Binary, destination string)
 
 func (pak *BrokerPakReader) extractTerraform(r manifest.TerraformVersion, destination string) error {
 	plat := platform.CurrentPlatform()
-	versionedPath := path.Join("bin", plat.Os, plat.Arch, r.Version.String(), "terraform")
+	versionedPath := path.Join("bin", plat.Os, plat.Arch, r.Version.String(), r.BinaryName())
 	if pak.fileExistsInZip(versionedPath) {
 		if err := pak.contents.ExtractFile(versionedPath, filepath.Join(destination, "versions", r.Version.String())); err != nil {
-			return fmt.Errorf("error extracting versioned terraform binary: %w", err)
+			return fmt.Errorf("error extracting versioned %s binary: %w", r.BinaryName(), err)
 		}
 
 		return nil
 	}
 
+	if r.IsOpenTofu() {
+		return fmt.Errorf("could not find OpenTofu version %s in brokerpak", r.Version)
+	}
+
 	// For compatibility with brokerpaks built with older versions
 	unversionedPath := path.Join("bin", plat.Os, plat.Arch, "terraform")
 	if pak.fileExistsInZip(unversionedPath) {
@@ -121,6 +121,50 @@ var _ = Describe("reader", func() {
 			})
 		})
 
+		Context("OpenTofu", func() {
+			It("extracts OpenTofu and the providers from the OpenTofu and Terraform registries", func() {
+				pk := fakeBrokerpak(
+					withTerraform(terraformV13),
+					withDefaultOpenTofu("1.6.0"),
+					withProvider("registry.opentofu.org/hashicorp/google-beta", "terraform-provider-google-beta", "1.19.0", "x4"),
+				)
+
+				pakReader, err := reader.OpenBrokerPak(pk)
+				Expect(err).NotTo(HaveOccurred())
+
+				binOutput := GinkgoT().TempDir()
+				Expect(pakReader.ExtractPlatformBins(binOutput)).NotTo(HaveOccurred())
+
+				data, err := os.ReadFile(filepath.Join(binOutput, "versions", "1.6.0", "tofu"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(data).To(Equal([]byte("1.6.0")))
+				Expect(filepath.Join(binOutput, "versions", terraformV13, "terraform")).To(BeAnExistingFile())
+
+				plat := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+				Expect(filepath.Join(binOutput, "registry.opentofu.org", "hashicorp", "google-beta", "1.19.0", plat, "terraform-provider-google-beta_v1.19.0_x4")).To(BeAnExistingFile())
+
+				By("checking the providers are also installed for the Terraform versions in the upgrade path")
+				Expect(filepath.Join(binOutput, "registry.terraform.io", "hashicorp", "google-beta", "1.19.0", plat, "terraform-provider-google-beta_v1.19.0_x4")).To(BeAnExistingFile())
+			})
+
+			It("only extracts the providers from the OpenTofu registry when all the versions are OpenTofu", func() {
+				pk := fakeBrokerpak(
+					withDefaultOpenTofu("1.6.0"),
+					withProvider("registry.opentofu.org/hashicorp/google-beta", "terraform-provider-google-beta", "1.19.0", "x4"),
+				)
+
+				pakReader, err := reader.OpenBrokerPak(pk)
+				Expect(err).NotTo(HaveOccurred())
+
+				binOutput := GinkgoT().TempDir()
+				Expect(pakReader.ExtractPlatformBins(binOutput)).NotTo(HaveOccurred())
+
+				plat := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+				Expect(filepath.Join(binOutput, "registry.opentofu.org", "hashicorp", "google-beta", "1.19.0", plat, "terraform-provider-google-beta_v1.19.0_x4")).To(BeAnExistingFile())
+				Expect(filepath.Join(binOutput, "registry.terraform.io")).NotTo(BeAnExistingFile())
+			})
+		})
+
 		Context("multiple providers share same name and version", func() {
 			It("should return an error", func() {
 				pk := fakeBrokerpak(
@@ -257,6 +301,21 @@ func withDefaultTerraform(tfVersion string) option {
 	}
 }
 
+func withDefaultOpenTofu(tofuVersion string) option {
+	return func(c *config) {
+		fakeFile := filepath.Join(c.dir, tofuVersion, "tofu")
+		Expect(stream.Copy(stream.FromString(tofuVersion), stream.ToFile(fakeFile))).NotTo(HaveOccurred())
+
+		c.manifest.TerraformVersions = append(c.manifest.TerraformVersions, manifest.TerraformVersion{
+			Name:        "tofu",
+			Version:     version.Must(version.NewVersion(tofuVersion)),
+			Default:     true,
+			Source:      fakeFile,
+			URLTemplate: fakeFile,
+		})
+	}
+}
+
 func withProvider(provider, name, providerVersion, suffix string) option {
 	return func(c *config) {
 		fakeFile := filepath.Join(c.dir, fmt.Sprintf("%s_v%s_%s", name, providerVersion, suffix))
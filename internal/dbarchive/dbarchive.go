@@ -0,0 +1,102 @@
+// Package dbarchive reads and writes archives of the broker database records, so
+// that the broker state can be moved between databases
+package dbarchive
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/encryption/gcmencryptor"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+// Version is incremented whenever the archive format changes in an incompatible way
+const Version = 1
+
+type archive struct {
+	Version          int             `json:"version"`
+	CreatedAt        time.Time       `json:"created_at"`
+	Salt             []byte          `json:"salt,omitempty"`
+	Records          json.RawMessage `json:"records,omitempty"`
+	EncryptedRecords []byte          `json:"encrypted_records,omitempty"`
+}
+
+// Write writes the records as an archive. When a passphrase is specified, the records
+// are encrypted with a key derived from the passphrase.
+func Write(w io.Writer, records storage.RecordSet, passphrase string) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("error marshalling records: %w", err)
+	}
+
+	a := archive{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	switch passphrase {
+	case "":
+		a.Records = data
+	default:
+		a.Salt = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, a.Salt); err != nil {
+			return fmt.Errorf("error generating salt: %w", err)
+		}
+
+		a.EncryptedRecords, err = gcmencryptor.New(deriveKey(passphrase, a.Salt)).Encrypt(data)
+		if err != nil {
+			return fmt.Errorf("error encrypting records: %w", err)
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(a); err != nil {
+		return fmt.Errorf("error writing archive: %w", err)
+	}
+
+	return nil
+}
+
+// Read reads the records from an archive. The passphrase must be specified if
+// the archive is encrypted.
+func Read(r io.Reader, passphrase string) (storage.RecordSet, error) {
+	var a archive
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return storage.RecordSet{}, fmt.Errorf("error reading archive: %w", err)
+	}
+
+	if a.Version != Version {
+		return storage.RecordSet{}, fmt.Errorf("unsupported archive version %d, expected version %d", a.Version, Version)
+	}
+
+	data := []byte(a.Records)
+	if a.EncryptedRecords != nil {
+		if passphrase == "" {
+			return storage.RecordSet{}, errors.New("the archive is encrypted and no passphrase was specified")
+		}
+
+		var err error
+		data, err = gcmencryptor.New(deriveKey(passphrase, a.Salt)).Decrypt(a.EncryptedRecords)
+		if err != nil {
+			return storage.RecordSet{}, fmt.Errorf("error decrypting records, check the passphrase: %w", err)
+		}
+	}
+
+	var records storage.RecordSet
+	if err := json.Unmarshal(data, &records); err != nil {
+		return storage.RecordSet{}, fmt.Errorf("error unmarshalling records: %w", err)
+	}
+
+	return records, nil
+}
+
+func deriveKey(passphrase string, salt []byte) (key [32]byte) {
+	copy(key[:], pbkdf2.Key([]byte(passphrase), salt, 100000, 32, sha256.New))
+	return key
+}
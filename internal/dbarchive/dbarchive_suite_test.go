@@ -0,0 +1,13 @@
+package dbarchive_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDBArchive(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "DB Archive Suite")
+}
@@ -0,0 +1,76 @@
+package dbarchive_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/dbarchive"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+var _ = Describe("DB Archive", func() {
+	var records storage.RecordSet
+
+	BeforeEach(func() {
+		records = storage.RecordSet{
+			ServiceInstanceDetails: []models.ServiceInstanceDetails{
+				{ID: "fake-instance-id", OtherDetails: []byte(`{"password":"fake-secret"}`)},
+			},
+			TerraformDeployments: []models.TerraformDeployment{
+				{ID: "tf:fake-instance-id:", Workspace: []byte(`{"tfstate":"fake"}`)},
+			},
+		}
+	})
+
+	It("can read an archive that it wrote", func() {
+		var buf bytes.Buffer
+		Expect(dbarchive.Write(&buf, records, "")).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring(`"version":1`))
+
+		actual, err := dbarchive.Read(&buf, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual).To(Equal(records))
+	})
+
+	When("a passphrase is specified", func() {
+		var buf bytes.Buffer
+
+		BeforeEach(func() {
+			buf.Reset()
+			Expect(dbarchive.Write(&buf, records, "fake-passphrase")).To(Succeed())
+		})
+
+		It("encrypts the records", func() {
+			Expect(buf.String()).To(ContainSubstring(`"encrypted_records"`))
+			Expect(buf.String()).NotTo(ContainSubstring(`fake-instance-id`))
+
+			actual, err := dbarchive.Read(&buf, "fake-passphrase")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(Equal(records))
+		})
+
+		It("fails to read with the wrong passphrase", func() {
+			_, err := dbarchive.Read(&buf, "wrong-passphrase")
+			Expect(err).To(MatchError(HavePrefix("error decrypting records, check the passphrase:")))
+		})
+
+		It("fails to read without a passphrase", func() {
+			_, err := dbarchive.Read(&buf, "")
+			Expect(err).To(MatchError("the archive is encrypted and no passphrase was specified"))
+		})
+	})
+
+	It("fails to read an archive with an unsupported version", func() {
+		_, err := dbarchive.Read(strings.NewReader(`{"version":2,"records":{}}`), "")
+		Expect(err).To(MatchError("unsupported archive version 2, expected version 1"))
+	})
+
+	It("fails to read something that is not an archive", func() {
+		_, err := dbarchive.Read(strings.NewReader(`not-an-archive`), "")
+		Expect(err).To(MatchError(HavePrefix("error reading archive:")))
+	})
+})
@@ -0,0 +1,132 @@
+// Package drift checks whether the resources of Terraform deployments have been changed
+// outside of the broker, by running "terraform plan" and recording whether the plan is empty.
+package drift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/lager/v3"
+	"github.com/pborman/uuid"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+//counterfeiter:generate . Store
+
+type Store interface {
+	broker.ServiceProviderStorage
+	GetAllTerraformDeployments() ([]storage.TerraformDeploymentListEntry, error)
+	GetServiceInstanceDetails(guid string) (storage.ServiceInstanceDetails, error)
+	StoreTerraformDrift(d storage.TerraformDrift) error
+}
+
+type Checker struct {
+	registry broker.BrokerRegistry
+	store    Store
+	logger   lager.Logger
+	// leaseHolder identifies this Checker when it locks the deployments that it checks
+	leaseHolder string
+}
+
+func NewChecker(registry broker.BrokerRegistry, store Store, logger lager.Logger) *Checker {
+	return &Checker{
+		registry:    registry,
+		store:       store,
+		logger:      logger.Session("drift-checker"),
+		leaseHolder: uuid.New(),
+	}
+}
+
+// CheckAll checks every deployment that is not in the middle of an operation, and
+// that has not been deprovisioned or unbound. It returns the recorded results.
+func (c *Checker) CheckAll(ctx context.Context) ([]storage.TerraformDrift, error) {
+	deployments, err := c.store.GetAllTerraformDeployments()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, d := range deployments {
+		switch {
+		case d.LastOperationState == tf.InProgress:
+			c.logger.Info("skipping-in-progress", lager.Data{"deploymentID": d.ID})
+		case d.LastOperationState == tf.Succeeded && (d.LastOperationType == models.DeprovisionOperationType || d.LastOperationType == models.UnbindOperationType):
+			c.logger.Debug("skipping-deleted", lager.Data{"deploymentID": d.ID})
+		default:
+			ids = append(ids, d.ID)
+		}
+	}
+
+	return c.Check(ctx, ids...)
+}
+
+// Check checks the specified deployments. A failure to check a deployment is recorded
+// against that deployment, so an error is only returned when a result cannot be stored.
+// Deployments that are locked by an operation, or by another broker checking them, are skipped.
+func (c *Checker) Check(ctx context.Context, deploymentIDs ...string) ([]storage.TerraformDrift, error) {
+	var results []storage.TerraformDrift
+	for _, id := range deploymentIDs {
+		result, err := c.checkLocked(ctx, id)
+		switch {
+		case errors.Is(err, storage.ErrLeaseHeld):
+			c.logger.Info("skipping-locked", correlation.ID(ctx), lager.Data{"deploymentID": id})
+			continue
+		case err != nil:
+			c.logger.Error("check-failed", err, correlation.ID(ctx), lager.Data{"deploymentID": id})
+			result = storage.TerraformDrift{DeploymentID: id, CheckError: err.Error()}
+		}
+
+		if err := c.store.StoreTerraformDrift(result); err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// checkLocked locks the deployment while it is checked, so that an operation cannot start
+// on the deployment, or change its workspace, while Terraform plans it
+func (c *Checker) checkLocked(ctx context.Context, deploymentID string) (storage.TerraformDrift, error) {
+	if err := c.store.AcquireTerraformDeploymentLease(deploymentID, c.leaseHolder); err != nil {
+		return storage.TerraformDrift{}, err
+	}
+	defer func() { _ = c.store.ReleaseTerraformDeploymentLease(deploymentID, c.leaseHolder) }()
+
+	return c.check(ctx, deploymentID)
+}
+
+func (c *Checker) check(ctx context.Context, deploymentID string) (storage.TerraformDrift, error) {
+	instanceID, err := instanceIDFromDeploymentID(deploymentID)
+	if err != nil {
+		return storage.TerraformDrift{}, err
+	}
+
+	instance, err := c.store.GetServiceInstanceDetails(instanceID)
+	if err != nil {
+		return storage.TerraformDrift{}, err
+	}
+
+	serviceDefinition, err := c.registry.GetServiceByID(instance.ServiceGUID)
+	if err != nil {
+		return storage.TerraformDrift{}, err
+	}
+
+	return serviceDefinition.ProviderBuilder(c.logger, c.store).CheckDrift(ctx, deploymentID)
+}
+
+func instanceIDFromDeploymentID(deploymentID string) (string, error) {
+	parts := strings.Split(deploymentID, ":")
+	if len(parts) != 3 || parts[0] != "tf" {
+		return "", fmt.Errorf("malformed deployment ID: %q", deploymentID)
+	}
+	return parts[1], nil
+}
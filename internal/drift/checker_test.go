@@ -0,0 +1,167 @@
+package drift_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/lager/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/drift"
+	"github.com/cloudfoundry/cloud-service-broker/internal/drift/driftfakes"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+)
+
+var _ = Describe("Checker", func() {
+	var (
+		fakeStore    *driftfakes.FakeStore
+		fakeProvider *brokerfakes.FakeServiceProvider
+		checker      *drift.Checker
+	)
+
+	BeforeEach(func() {
+		fakeStore = &driftfakes.FakeStore{}
+		fakeProvider = &brokerfakes.FakeServiceProvider{}
+
+		registry := broker.BrokerRegistry{
+			"fake-service": &broker.ServiceDefinition{
+				ID: "fake-service-id",
+				ProviderBuilder: func(lager.Logger, broker.ServiceProviderStorage) broker.ServiceProvider {
+					return fakeProvider
+				},
+			},
+		}
+
+		fakeStore.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{ServiceGUID: "fake-service-id"}, nil)
+		fakeProvider.CheckDriftStub = func(_ context.Context, id string) (storage.TerraformDrift, error) {
+			return storage.TerraformDrift{DeploymentID: id, Drifted: id == "tf:instance-2:"}, nil
+		}
+
+		checker = drift.NewChecker(registry, fakeStore, utils.NewLogger("test"))
+	})
+
+	Describe("Check", func() {
+		It("checks and stores the result for each deployment", func() {
+			results, err := checker.Check(context.TODO(), "tf:instance-1:binding-1", "tf:instance-2:")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(Equal([]storage.TerraformDrift{
+				{DeploymentID: "tf:instance-1:binding-1"},
+				{DeploymentID: "tf:instance-2:", Drifted: true},
+			}))
+
+			Expect(fakeStore.GetServiceInstanceDetailsCallCount()).To(Equal(2))
+			Expect(fakeStore.GetServiceInstanceDetailsArgsForCall(0)).To(Equal("instance-1"))
+			Expect(fakeStore.GetServiceInstanceDetailsArgsForCall(1)).To(Equal("instance-2"))
+
+			Expect(fakeStore.StoreTerraformDriftCallCount()).To(Equal(2))
+			Expect(fakeStore.StoreTerraformDriftArgsForCall(0)).To(Equal(results[0]))
+			Expect(fakeStore.StoreTerraformDriftArgsForCall(1)).To(Equal(results[1]))
+		})
+
+		It("records a failed check against the deployment", func() {
+			fakeProvider.CheckDriftStub = nil
+			fakeProvider.CheckDriftReturns(storage.TerraformDrift{}, errors.New("plan failed"))
+
+			results, err := checker.Check(context.TODO(), "tf:instance-1:")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(Equal([]storage.TerraformDrift{{DeploymentID: "tf:instance-1:", CheckError: "plan failed"}}))
+			Expect(fakeStore.StoreTerraformDriftArgsForCall(0)).To(Equal(results[0]))
+		})
+
+		It("records an unknown service against the deployment", func() {
+			fakeStore.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{ServiceGUID: "other-service-id"}, nil)
+
+			results, err := checker.Check(context.TODO(), "tf:instance-1:")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].CheckError).To(Equal(`unknown service ID: "other-service-id"`))
+			Expect(fakeProvider.CheckDriftCallCount()).To(BeZero())
+		})
+
+		It("records a malformed deployment ID", func() {
+			results, err := checker.Check(context.TODO(), "not-a-deployment")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].CheckError).To(Equal(`malformed deployment ID: "not-a-deployment"`))
+		})
+
+		It("locks each deployment while it is checked", func() {
+			fakeProvider.CheckDriftStub = func(_ context.Context, id string) (storage.TerraformDrift, error) {
+				Expect(fakeStore.AcquireTerraformDeploymentLeaseCallCount()).To(Equal(1))
+				Expect(fakeStore.ReleaseTerraformDeploymentLeaseCallCount()).To(BeZero())
+				return storage.TerraformDrift{DeploymentID: id}, nil
+			}
+
+			_, err := checker.Check(context.TODO(), "tf:instance-1:")
+			Expect(err).NotTo(HaveOccurred())
+
+			acquiredID, acquiredHolder := fakeStore.AcquireTerraformDeploymentLeaseArgsForCall(0)
+			Expect(acquiredID).To(Equal("tf:instance-1:"))
+			Expect(fakeStore.ReleaseTerraformDeploymentLeaseCallCount()).To(Equal(1))
+			releasedID, releasedHolder := fakeStore.ReleaseTerraformDeploymentLeaseArgsForCall(0)
+			Expect(releasedID).To(Equal("tf:instance-1:"))
+			Expect(releasedHolder).To(Equal(acquiredHolder))
+		})
+
+		It("skips a deployment that is locked by an operation", func() {
+			fakeStore.AcquireTerraformDeploymentLeaseReturnsOnCall(0, fmt.Errorf("%w: tf:instance-1:", storage.ErrLeaseHeld))
+
+			results, err := checker.Check(context.TODO(), "tf:instance-1:", "tf:instance-2:")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(Equal([]storage.TerraformDrift{{DeploymentID: "tf:instance-2:", Drifted: true}}))
+			Expect(fakeProvider.CheckDriftCallCount()).To(Equal(1))
+			Expect(fakeStore.StoreTerraformDriftCallCount()).To(Equal(1))
+		})
+
+		It("records a failure to lock the deployment against it", func() {
+			fakeStore.AcquireTerraformDeploymentLeaseReturns(errors.New("db gone"))
+
+			results, err := checker.Check(context.TODO(), "tf:instance-1:")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(Equal([]storage.TerraformDrift{{DeploymentID: "tf:instance-1:", CheckError: "db gone"}}))
+			Expect(fakeProvider.CheckDriftCallCount()).To(BeZero())
+		})
+
+		It("fails when a result cannot be stored", func() {
+			fakeStore.StoreTerraformDriftReturns(errors.New("db gone"))
+
+			_, err := checker.Check(context.TODO(), "tf:instance-1:", "tf:instance-2:")
+			Expect(err).To(MatchError("db gone"))
+			Expect(fakeStore.StoreTerraformDriftCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("CheckAll", func() {
+		BeforeEach(func() {
+			fakeStore.GetAllTerraformDeploymentsReturns([]storage.TerraformDeploymentListEntry{
+				{ID: "tf:instance-1:", LastOperationType: "provision", LastOperationState: "succeeded"},
+				{ID: "tf:instance-2:", LastOperationType: "update", LastOperationState: "failed"},
+				{ID: "tf:instance-3:", LastOperationType: "update", LastOperationState: "in progress"},
+				{ID: "tf:instance-4:", LastOperationType: "deprovision", LastOperationState: "succeeded"},
+				{ID: "tf:instance-1:binding-1", LastOperationType: "unbind", LastOperationState: "succeeded"},
+				{ID: "tf:instance-1:binding-2", LastOperationType: "unbind", LastOperationState: "failed"},
+			}, nil)
+		})
+
+		It("checks deployments that are not in progress or deleted", func() {
+			results, err := checker.CheckAll(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+
+			var ids []string
+			for _, r := range results {
+				ids = append(ids, r.DeploymentID)
+			}
+			Expect(ids).To(Equal([]string{"tf:instance-1:", "tf:instance-2:", "tf:instance-1:binding-2"}))
+		})
+
+		It("fails when the deployments cannot be listed", func() {
+			fakeStore.GetAllTerraformDeploymentsReturns(nil, errors.New("db gone"))
+
+			_, err := checker.CheckAll(context.TODO())
+			Expect(err).To(MatchError("db gone"))
+		})
+	})
+})
@@ -0,0 +1,952 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package driftfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/drift"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+type FakeStore struct {
+	AcquireTerraformDeploymentLeaseStub        func(string, string) error
+	acquireTerraformDeploymentLeaseMutex       sync.RWMutex
+	acquireTerraformDeploymentLeaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	acquireTerraformDeploymentLeaseReturns struct {
+		result1 error
+	}
+	acquireTerraformDeploymentLeaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ExistsTerraformDeploymentStub        func(string) (bool, error)
+	existsTerraformDeploymentMutex       sync.RWMutex
+	existsTerraformDeploymentArgsForCall []struct {
+		arg1 string
+	}
+	existsTerraformDeploymentReturns struct {
+		result1 bool
+		result2 error
+	}
+	existsTerraformDeploymentReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	FinishOperationLogStub        func(string, string, string, string) error
+	finishOperationLogMutex       sync.RWMutex
+	finishOperationLogArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	finishOperationLogReturns struct {
+		result1 error
+	}
+	finishOperationLogReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetAllTerraformDeploymentsStub        func() ([]storage.TerraformDeploymentListEntry, error)
+	getAllTerraformDeploymentsMutex       sync.RWMutex
+	getAllTerraformDeploymentsArgsForCall []struct {
+	}
+	getAllTerraformDeploymentsReturns struct {
+		result1 []storage.TerraformDeploymentListEntry
+		result2 error
+	}
+	getAllTerraformDeploymentsReturnsOnCall map[int]struct {
+		result1 []storage.TerraformDeploymentListEntry
+		result2 error
+	}
+	GetServiceBindingIDsForServiceInstanceStub        func(string) ([]string, error)
+	getServiceBindingIDsForServiceInstanceMutex       sync.RWMutex
+	getServiceBindingIDsForServiceInstanceArgsForCall []struct {
+		arg1 string
+	}
+	getServiceBindingIDsForServiceInstanceReturns struct {
+		result1 []string
+		result2 error
+	}
+	getServiceBindingIDsForServiceInstanceReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	GetServiceInstanceDetailsStub        func(string) (storage.ServiceInstanceDetails, error)
+	getServiceInstanceDetailsMutex       sync.RWMutex
+	getServiceInstanceDetailsArgsForCall []struct {
+		arg1 string
+	}
+	getServiceInstanceDetailsReturns struct {
+		result1 storage.ServiceInstanceDetails
+		result2 error
+	}
+	getServiceInstanceDetailsReturnsOnCall map[int]struct {
+		result1 storage.ServiceInstanceDetails
+		result2 error
+	}
+	GetTerraformDeploymentStub        func(string) (storage.TerraformDeployment, error)
+	getTerraformDeploymentMutex       sync.RWMutex
+	getTerraformDeploymentArgsForCall []struct {
+		arg1 string
+	}
+	getTerraformDeploymentReturns struct {
+		result1 storage.TerraformDeployment
+		result2 error
+	}
+	getTerraformDeploymentReturnsOnCall map[int]struct {
+		result1 storage.TerraformDeployment
+		result2 error
+	}
+	ReleaseTerraformDeploymentLeaseStub        func(string, string) error
+	releaseTerraformDeploymentLeaseMutex       sync.RWMutex
+	releaseTerraformDeploymentLeaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	releaseTerraformDeploymentLeaseReturns struct {
+		result1 error
+	}
+	releaseTerraformDeploymentLeaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StartOperationLogStub        func(storage.OperationLog) error
+	startOperationLogMutex       sync.RWMutex
+	startOperationLogArgsForCall []struct {
+		arg1 storage.OperationLog
+	}
+	startOperationLogReturns struct {
+		result1 error
+	}
+	startOperationLogReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreTerraformDeploymentStub        func(storage.TerraformDeployment) error
+	storeTerraformDeploymentMutex       sync.RWMutex
+	storeTerraformDeploymentArgsForCall []struct {
+		arg1 storage.TerraformDeployment
+	}
+	storeTerraformDeploymentReturns struct {
+		result1 error
+	}
+	storeTerraformDeploymentReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreTerraformDriftStub        func(storage.TerraformDrift) error
+	storeTerraformDriftMutex       sync.RWMutex
+	storeTerraformDriftArgsForCall []struct {
+		arg1 storage.TerraformDrift
+	}
+	storeTerraformDriftReturns struct {
+		result1 error
+	}
+	storeTerraformDriftReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreTerraformLogStub        func(storage.TerraformLog) error
+	storeTerraformLogMutex       sync.RWMutex
+	storeTerraformLogArgsForCall []struct {
+		arg1 storage.TerraformLog
+	}
+	storeTerraformLogReturns struct {
+		result1 error
+	}
+	storeTerraformLogReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLease(arg1 string, arg2 string) error {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	ret, specificReturn := fake.acquireTerraformDeploymentLeaseReturnsOnCall[len(fake.acquireTerraformDeploymentLeaseArgsForCall)]
+	fake.acquireTerraformDeploymentLeaseArgsForCall = append(fake.acquireTerraformDeploymentLeaseArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AcquireTerraformDeploymentLeaseStub
+	fakeReturns := fake.acquireTerraformDeploymentLeaseReturns
+	fake.recordInvocation("AcquireTerraformDeploymentLease", []interface{}{arg1, arg2})
+	fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseCallCount() int {
+	fake.acquireTerraformDeploymentLeaseMutex.RLock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.RUnlock()
+	return len(fake.acquireTerraformDeploymentLeaseArgsForCall)
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseCalls(stub func(string, string) error) {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	fake.AcquireTerraformDeploymentLeaseStub = stub
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseArgsForCall(i int) (string, string) {
+	fake.acquireTerraformDeploymentLeaseMutex.RLock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.RUnlock()
+	argsForCall := fake.acquireTerraformDeploymentLeaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseReturns(result1 error) {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	fake.AcquireTerraformDeploymentLeaseStub = nil
+	fake.acquireTerraformDeploymentLeaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseReturnsOnCall(i int, result1 error) {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	fake.AcquireTerraformDeploymentLeaseStub = nil
+	if fake.acquireTerraformDeploymentLeaseReturnsOnCall == nil {
+		fake.acquireTerraformDeploymentLeaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.acquireTerraformDeploymentLeaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) ExistsTerraformDeployment(arg1 string) (bool, error) {
+	fake.existsTerraformDeploymentMutex.Lock()
+	ret, specificReturn := fake.existsTerraformDeploymentReturnsOnCall[len(fake.existsTerraformDeploymentArgsForCall)]
+	fake.existsTerraformDeploymentArgsForCall = append(fake.existsTerraformDeploymentArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ExistsTerraformDeploymentStub
+	fakeReturns := fake.existsTerraformDeploymentReturns
+	fake.recordInvocation("ExistsTerraformDeployment", []interface{}{arg1})
+	fake.existsTerraformDeploymentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentCallCount() int {
+	fake.existsTerraformDeploymentMutex.RLock()
+	defer fake.existsTerraformDeploymentMutex.RUnlock()
+	return len(fake.existsTerraformDeploymentArgsForCall)
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentCalls(stub func(string) (bool, error)) {
+	fake.existsTerraformDeploymentMutex.Lock()
+	defer fake.existsTerraformDeploymentMutex.Unlock()
+	fake.ExistsTerraformDeploymentStub = stub
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentArgsForCall(i int) string {
+	fake.existsTerraformDeploymentMutex.RLock()
+	defer fake.existsTerraformDeploymentMutex.RUnlock()
+	argsForCall := fake.existsTerraformDeploymentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentReturns(result1 bool, result2 error) {
+	fake.existsTerraformDeploymentMutex.Lock()
+	defer fake.existsTerraformDeploymentMutex.Unlock()
+	fake.ExistsTerraformDeploymentStub = nil
+	fake.existsTerraformDeploymentReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.existsTerraformDeploymentMutex.Lock()
+	defer fake.existsTerraformDeploymentMutex.Unlock()
+	fake.ExistsTerraformDeploymentStub = nil
+	if fake.existsTerraformDeploymentReturnsOnCall == nil {
+		fake.existsTerraformDeploymentReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.existsTerraformDeploymentReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) FinishOperationLog(arg1 string, arg2 string, arg3 string, arg4 string) error {
+	fake.finishOperationLogMutex.Lock()
+	ret, specificReturn := fake.finishOperationLogReturnsOnCall[len(fake.finishOperationLogArgsForCall)]
+	fake.finishOperationLogArgsForCall = append(fake.finishOperationLogArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.FinishOperationLogStub
+	fakeReturns := fake.finishOperationLogReturns
+	fake.recordInvocation("FinishOperationLog", []interface{}{arg1, arg2, arg3, arg4})
+	fake.finishOperationLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) FinishOperationLogCallCount() int {
+	fake.finishOperationLogMutex.RLock()
+	defer fake.finishOperationLogMutex.RUnlock()
+	return len(fake.finishOperationLogArgsForCall)
+}
+
+func (fake *FakeStore) FinishOperationLogCalls(stub func(string, string, string, string) error) {
+	fake.finishOperationLogMutex.Lock()
+	defer fake.finishOperationLogMutex.Unlock()
+	fake.FinishOperationLogStub = stub
+}
+
+func (fake *FakeStore) FinishOperationLogArgsForCall(i int) (string, string, string, string) {
+	fake.finishOperationLogMutex.RLock()
+	defer fake.finishOperationLogMutex.RUnlock()
+	argsForCall := fake.finishOperationLogArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStore) FinishOperationLogReturns(result1 error) {
+	fake.finishOperationLogMutex.Lock()
+	defer fake.finishOperationLogMutex.Unlock()
+	fake.FinishOperationLogStub = nil
+	fake.finishOperationLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) FinishOperationLogReturnsOnCall(i int, result1 error) {
+	fake.finishOperationLogMutex.Lock()
+	defer fake.finishOperationLogMutex.Unlock()
+	fake.FinishOperationLogStub = nil
+	if fake.finishOperationLogReturnsOnCall == nil {
+		fake.finishOperationLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.finishOperationLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) GetAllTerraformDeployments() ([]storage.TerraformDeploymentListEntry, error) {
+	fake.getAllTerraformDeploymentsMutex.Lock()
+	ret, specificReturn := fake.getAllTerraformDeploymentsReturnsOnCall[len(fake.getAllTerraformDeploymentsArgsForCall)]
+	fake.getAllTerraformDeploymentsArgsForCall = append(fake.getAllTerraformDeploymentsArgsForCall, struct {
+	}{})
+	stub := fake.GetAllTerraformDeploymentsStub
+	fakeReturns := fake.getAllTerraformDeploymentsReturns
+	fake.recordInvocation("GetAllTerraformDeployments", []interface{}{})
+	fake.getAllTerraformDeploymentsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) GetAllTerraformDeploymentsCallCount() int {
+	fake.getAllTerraformDeploymentsMutex.RLock()
+	defer fake.getAllTerraformDeploymentsMutex.RUnlock()
+	return len(fake.getAllTerraformDeploymentsArgsForCall)
+}
+
+func (fake *FakeStore) GetAllTerraformDeploymentsCalls(stub func() ([]storage.TerraformDeploymentListEntry, error)) {
+	fake.getAllTerraformDeploymentsMutex.Lock()
+	defer fake.getAllTerraformDeploymentsMutex.Unlock()
+	fake.GetAllTerraformDeploymentsStub = stub
+}
+
+func (fake *FakeStore) GetAllTerraformDeploymentsReturns(result1 []storage.TerraformDeploymentListEntry, result2 error) {
+	fake.getAllTerraformDeploymentsMutex.Lock()
+	defer fake.getAllTerraformDeploymentsMutex.Unlock()
+	fake.GetAllTerraformDeploymentsStub = nil
+	fake.getAllTerraformDeploymentsReturns = struct {
+		result1 []storage.TerraformDeploymentListEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetAllTerraformDeploymentsReturnsOnCall(i int, result1 []storage.TerraformDeploymentListEntry, result2 error) {
+	fake.getAllTerraformDeploymentsMutex.Lock()
+	defer fake.getAllTerraformDeploymentsMutex.Unlock()
+	fake.GetAllTerraformDeploymentsStub = nil
+	if fake.getAllTerraformDeploymentsReturnsOnCall == nil {
+		fake.getAllTerraformDeploymentsReturnsOnCall = make(map[int]struct {
+			result1 []storage.TerraformDeploymentListEntry
+			result2 error
+		})
+	}
+	fake.getAllTerraformDeploymentsReturnsOnCall[i] = struct {
+		result1 []storage.TerraformDeploymentListEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstance(arg1 string) ([]string, error) {
+	fake.getServiceBindingIDsForServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.getServiceBindingIDsForServiceInstanceReturnsOnCall[len(fake.getServiceBindingIDsForServiceInstanceArgsForCall)]
+	fake.getServiceBindingIDsForServiceInstanceArgsForCall = append(fake.getServiceBindingIDsForServiceInstanceArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetServiceBindingIDsForServiceInstanceStub
+	fakeReturns := fake.getServiceBindingIDsForServiceInstanceReturns
+	fake.recordInvocation("GetServiceBindingIDsForServiceInstance", []interface{}{arg1})
+	fake.getServiceBindingIDsForServiceInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceCallCount() int {
+	fake.getServiceBindingIDsForServiceInstanceMutex.RLock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.RUnlock()
+	return len(fake.getServiceBindingIDsForServiceInstanceArgsForCall)
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceCalls(stub func(string) ([]string, error)) {
+	fake.getServiceBindingIDsForServiceInstanceMutex.Lock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.Unlock()
+	fake.GetServiceBindingIDsForServiceInstanceStub = stub
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceArgsForCall(i int) string {
+	fake.getServiceBindingIDsForServiceInstanceMutex.RLock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.RUnlock()
+	argsForCall := fake.getServiceBindingIDsForServiceInstanceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceReturns(result1 []string, result2 error) {
+	fake.getServiceBindingIDsForServiceInstanceMutex.Lock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.Unlock()
+	fake.GetServiceBindingIDsForServiceInstanceStub = nil
+	fake.getServiceBindingIDsForServiceInstanceReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.getServiceBindingIDsForServiceInstanceMutex.Lock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.Unlock()
+	fake.GetServiceBindingIDsForServiceInstanceStub = nil
+	if fake.getServiceBindingIDsForServiceInstanceReturnsOnCall == nil {
+		fake.getServiceBindingIDsForServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.getServiceBindingIDsForServiceInstanceReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetServiceInstanceDetails(arg1 string) (storage.ServiceInstanceDetails, error) {
+	fake.getServiceInstanceDetailsMutex.Lock()
+	ret, specificReturn := fake.getServiceInstanceDetailsReturnsOnCall[len(fake.getServiceInstanceDetailsArgsForCall)]
+	fake.getServiceInstanceDetailsArgsForCall = append(fake.getServiceInstanceDetailsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetServiceInstanceDetailsStub
+	fakeReturns := fake.getServiceInstanceDetailsReturns
+	fake.recordInvocation("GetServiceInstanceDetails", []interface{}{arg1})
+	fake.getServiceInstanceDetailsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) GetServiceInstanceDetailsCallCount() int {
+	fake.getServiceInstanceDetailsMutex.RLock()
+	defer fake.getServiceInstanceDetailsMutex.RUnlock()
+	return len(fake.getServiceInstanceDetailsArgsForCall)
+}
+
+func (fake *FakeStore) GetServiceInstanceDetailsCalls(stub func(string) (storage.ServiceInstanceDetails, error)) {
+	fake.getServiceInstanceDetailsMutex.Lock()
+	defer fake.getServiceInstanceDetailsMutex.Unlock()
+	fake.GetServiceInstanceDetailsStub = stub
+}
+
+func (fake *FakeStore) GetServiceInstanceDetailsArgsForCall(i int) string {
+	fake.getServiceInstanceDetailsMutex.RLock()
+	defer fake.getServiceInstanceDetailsMutex.RUnlock()
+	argsForCall := fake.getServiceInstanceDetailsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) GetServiceInstanceDetailsReturns(result1 storage.ServiceInstanceDetails, result2 error) {
+	fake.getServiceInstanceDetailsMutex.Lock()
+	defer fake.getServiceInstanceDetailsMutex.Unlock()
+	fake.GetServiceInstanceDetailsStub = nil
+	fake.getServiceInstanceDetailsReturns = struct {
+		result1 storage.ServiceInstanceDetails
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetServiceInstanceDetailsReturnsOnCall(i int, result1 storage.ServiceInstanceDetails, result2 error) {
+	fake.getServiceInstanceDetailsMutex.Lock()
+	defer fake.getServiceInstanceDetailsMutex.Unlock()
+	fake.GetServiceInstanceDetailsStub = nil
+	if fake.getServiceInstanceDetailsReturnsOnCall == nil {
+		fake.getServiceInstanceDetailsReturnsOnCall = make(map[int]struct {
+			result1 storage.ServiceInstanceDetails
+			result2 error
+		})
+	}
+	fake.getServiceInstanceDetailsReturnsOnCall[i] = struct {
+		result1 storage.ServiceInstanceDetails
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetTerraformDeployment(arg1 string) (storage.TerraformDeployment, error) {
+	fake.getTerraformDeploymentMutex.Lock()
+	ret, specificReturn := fake.getTerraformDeploymentReturnsOnCall[len(fake.getTerraformDeploymentArgsForCall)]
+	fake.getTerraformDeploymentArgsForCall = append(fake.getTerraformDeploymentArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetTerraformDeploymentStub
+	fakeReturns := fake.getTerraformDeploymentReturns
+	fake.recordInvocation("GetTerraformDeployment", []interface{}{arg1})
+	fake.getTerraformDeploymentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) GetTerraformDeploymentCallCount() int {
+	fake.getTerraformDeploymentMutex.RLock()
+	defer fake.getTerraformDeploymentMutex.RUnlock()
+	return len(fake.getTerraformDeploymentArgsForCall)
+}
+
+func (fake *FakeStore) GetTerraformDeploymentCalls(stub func(string) (storage.TerraformDeployment, error)) {
+	fake.getTerraformDeploymentMutex.Lock()
+	defer fake.getTerraformDeploymentMutex.Unlock()
+	fake.GetTerraformDeploymentStub = stub
+}
+
+func (fake *FakeStore) GetTerraformDeploymentArgsForCall(i int) string {
+	fake.getTerraformDeploymentMutex.RLock()
+	defer fake.getTerraformDeploymentMutex.RUnlock()
+	argsForCall := fake.getTerraformDeploymentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) GetTerraformDeploymentReturns(result1 storage.TerraformDeployment, result2 error) {
+	fake.getTerraformDeploymentMutex.Lock()
+	defer fake.getTerraformDeploymentMutex.Unlock()
+	fake.GetTerraformDeploymentStub = nil
+	fake.getTerraformDeploymentReturns = struct {
+		result1 storage.TerraformDeployment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetTerraformDeploymentReturnsOnCall(i int, result1 storage.TerraformDeployment, result2 error) {
+	fake.getTerraformDeploymentMutex.Lock()
+	defer fake.getTerraformDeploymentMutex.Unlock()
+	fake.GetTerraformDeploymentStub = nil
+	if fake.getTerraformDeploymentReturnsOnCall == nil {
+		fake.getTerraformDeploymentReturnsOnCall = make(map[int]struct {
+			result1 storage.TerraformDeployment
+			result2 error
+		})
+	}
+	fake.getTerraformDeploymentReturnsOnCall[i] = struct {
+		result1 storage.TerraformDeployment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLease(arg1 string, arg2 string) error {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	ret, specificReturn := fake.releaseTerraformDeploymentLeaseReturnsOnCall[len(fake.releaseTerraformDeploymentLeaseArgsForCall)]
+	fake.releaseTerraformDeploymentLeaseArgsForCall = append(fake.releaseTerraformDeploymentLeaseArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ReleaseTerraformDeploymentLeaseStub
+	fakeReturns := fake.releaseTerraformDeploymentLeaseReturns
+	fake.recordInvocation("ReleaseTerraformDeploymentLease", []interface{}{arg1, arg2})
+	fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseCallCount() int {
+	fake.releaseTerraformDeploymentLeaseMutex.RLock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.RUnlock()
+	return len(fake.releaseTerraformDeploymentLeaseArgsForCall)
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseCalls(stub func(string, string) error) {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	fake.ReleaseTerraformDeploymentLeaseStub = stub
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseArgsForCall(i int) (string, string) {
+	fake.releaseTerraformDeploymentLeaseMutex.RLock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.RUnlock()
+	argsForCall := fake.releaseTerraformDeploymentLeaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseReturns(result1 error) {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	fake.ReleaseTerraformDeploymentLeaseStub = nil
+	fake.releaseTerraformDeploymentLeaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseReturnsOnCall(i int, result1 error) {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	fake.ReleaseTerraformDeploymentLeaseStub = nil
+	if fake.releaseTerraformDeploymentLeaseReturnsOnCall == nil {
+		fake.releaseTerraformDeploymentLeaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseTerraformDeploymentLeaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StartOperationLog(arg1 storage.OperationLog) error {
+	fake.startOperationLogMutex.Lock()
+	ret, specificReturn := fake.startOperationLogReturnsOnCall[len(fake.startOperationLogArgsForCall)]
+	fake.startOperationLogArgsForCall = append(fake.startOperationLogArgsForCall, struct {
+		arg1 storage.OperationLog
+	}{arg1})
+	stub := fake.StartOperationLogStub
+	fakeReturns := fake.startOperationLogReturns
+	fake.recordInvocation("StartOperationLog", []interface{}{arg1})
+	fake.startOperationLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) StartOperationLogCallCount() int {
+	fake.startOperationLogMutex.RLock()
+	defer fake.startOperationLogMutex.RUnlock()
+	return len(fake.startOperationLogArgsForCall)
+}
+
+func (fake *FakeStore) StartOperationLogCalls(stub func(storage.OperationLog) error) {
+	fake.startOperationLogMutex.Lock()
+	defer fake.startOperationLogMutex.Unlock()
+	fake.StartOperationLogStub = stub
+}
+
+func (fake *FakeStore) StartOperationLogArgsForCall(i int) storage.OperationLog {
+	fake.startOperationLogMutex.RLock()
+	defer fake.startOperationLogMutex.RUnlock()
+	argsForCall := fake.startOperationLogArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) StartOperationLogReturns(result1 error) {
+	fake.startOperationLogMutex.Lock()
+	defer fake.startOperationLogMutex.Unlock()
+	fake.StartOperationLogStub = nil
+	fake.startOperationLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StartOperationLogReturnsOnCall(i int, result1 error) {
+	fake.startOperationLogMutex.Lock()
+	defer fake.startOperationLogMutex.Unlock()
+	fake.StartOperationLogStub = nil
+	if fake.startOperationLogReturnsOnCall == nil {
+		fake.startOperationLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.startOperationLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformDeployment(arg1 storage.TerraformDeployment) error {
+	fake.storeTerraformDeploymentMutex.Lock()
+	ret, specificReturn := fake.storeTerraformDeploymentReturnsOnCall[len(fake.storeTerraformDeploymentArgsForCall)]
+	fake.storeTerraformDeploymentArgsForCall = append(fake.storeTerraformDeploymentArgsForCall, struct {
+		arg1 storage.TerraformDeployment
+	}{arg1})
+	stub := fake.StoreTerraformDeploymentStub
+	fakeReturns := fake.storeTerraformDeploymentReturns
+	fake.recordInvocation("StoreTerraformDeployment", []interface{}{arg1})
+	fake.storeTerraformDeploymentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentCallCount() int {
+	fake.storeTerraformDeploymentMutex.RLock()
+	defer fake.storeTerraformDeploymentMutex.RUnlock()
+	return len(fake.storeTerraformDeploymentArgsForCall)
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentCalls(stub func(storage.TerraformDeployment) error) {
+	fake.storeTerraformDeploymentMutex.Lock()
+	defer fake.storeTerraformDeploymentMutex.Unlock()
+	fake.StoreTerraformDeploymentStub = stub
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentArgsForCall(i int) storage.TerraformDeployment {
+	fake.storeTerraformDeploymentMutex.RLock()
+	defer fake.storeTerraformDeploymentMutex.RUnlock()
+	argsForCall := fake.storeTerraformDeploymentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentReturns(result1 error) {
+	fake.storeTerraformDeploymentMutex.Lock()
+	defer fake.storeTerraformDeploymentMutex.Unlock()
+	fake.StoreTerraformDeploymentStub = nil
+	fake.storeTerraformDeploymentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentReturnsOnCall(i int, result1 error) {
+	fake.storeTerraformDeploymentMutex.Lock()
+	defer fake.storeTerraformDeploymentMutex.Unlock()
+	fake.StoreTerraformDeploymentStub = nil
+	if fake.storeTerraformDeploymentReturnsOnCall == nil {
+		fake.storeTerraformDeploymentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeTerraformDeploymentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformDrift(arg1 storage.TerraformDrift) error {
+	fake.storeTerraformDriftMutex.Lock()
+	ret, specificReturn := fake.storeTerraformDriftReturnsOnCall[len(fake.storeTerraformDriftArgsForCall)]
+	fake.storeTerraformDriftArgsForCall = append(fake.storeTerraformDriftArgsForCall, struct {
+		arg1 storage.TerraformDrift
+	}{arg1})
+	stub := fake.StoreTerraformDriftStub
+	fakeReturns := fake.storeTerraformDriftReturns
+	fake.recordInvocation("StoreTerraformDrift", []interface{}{arg1})
+	fake.storeTerraformDriftMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) StoreTerraformDriftCallCount() int {
+	fake.storeTerraformDriftMutex.RLock()
+	defer fake.storeTerraformDriftMutex.RUnlock()
+	return len(fake.storeTerraformDriftArgsForCall)
+}
+
+func (fake *FakeStore) StoreTerraformDriftCalls(stub func(storage.TerraformDrift) error) {
+	fake.storeTerraformDriftMutex.Lock()
+	defer fake.storeTerraformDriftMutex.Unlock()
+	fake.StoreTerraformDriftStub = stub
+}
+
+func (fake *FakeStore) StoreTerraformDriftArgsForCall(i int) storage.TerraformDrift {
+	fake.storeTerraformDriftMutex.RLock()
+	defer fake.storeTerraformDriftMutex.RUnlock()
+	argsForCall := fake.storeTerraformDriftArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) StoreTerraformDriftReturns(result1 error) {
+	fake.storeTerraformDriftMutex.Lock()
+	defer fake.storeTerraformDriftMutex.Unlock()
+	fake.StoreTerraformDriftStub = nil
+	fake.storeTerraformDriftReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformDriftReturnsOnCall(i int, result1 error) {
+	fake.storeTerraformDriftMutex.Lock()
+	defer fake.storeTerraformDriftMutex.Unlock()
+	fake.StoreTerraformDriftStub = nil
+	if fake.storeTerraformDriftReturnsOnCall == nil {
+		fake.storeTerraformDriftReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeTerraformDriftReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformLog(arg1 storage.TerraformLog) error {
+	fake.storeTerraformLogMutex.Lock()
+	ret, specificReturn := fake.storeTerraformLogReturnsOnCall[len(fake.storeTerraformLogArgsForCall)]
+	fake.storeTerraformLogArgsForCall = append(fake.storeTerraformLogArgsForCall, struct {
+		arg1 storage.TerraformLog
+	}{arg1})
+	stub := fake.StoreTerraformLogStub
+	fakeReturns := fake.storeTerraformLogReturns
+	fake.recordInvocation("StoreTerraformLog", []interface{}{arg1})
+	fake.storeTerraformLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) StoreTerraformLogCallCount() int {
+	fake.storeTerraformLogMutex.RLock()
+	defer fake.storeTerraformLogMutex.RUnlock()
+	return len(fake.storeTerraformLogArgsForCall)
+}
+
+func (fake *FakeStore) StoreTerraformLogCalls(stub func(storage.TerraformLog) error) {
+	fake.storeTerraformLogMutex.Lock()
+	defer fake.storeTerraformLogMutex.Unlock()
+	fake.StoreTerraformLogStub = stub
+}
+
+func (fake *FakeStore) StoreTerraformLogArgsForCall(i int) storage.TerraformLog {
+	fake.storeTerraformLogMutex.RLock()
+	defer fake.storeTerraformLogMutex.RUnlock()
+	argsForCall := fake.storeTerraformLogArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) StoreTerraformLogReturns(result1 error) {
+	fake.storeTerraformLogMutex.Lock()
+	defer fake.storeTerraformLogMutex.Unlock()
+	fake.StoreTerraformLogStub = nil
+	fake.storeTerraformLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformLogReturnsOnCall(i int, result1 error) {
+	fake.storeTerraformLogMutex.Lock()
+	defer fake.storeTerraformLogMutex.Unlock()
+	fake.StoreTerraformLogStub = nil
+	if fake.storeTerraformLogReturnsOnCall == nil {
+		fake.storeTerraformLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeTerraformLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.acquireTerraformDeploymentLeaseMutex.RLock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.RUnlock()
+	fake.existsTerraformDeploymentMutex.RLock()
+	defer fake.existsTerraformDeploymentMutex.RUnlock()
+	fake.finishOperationLogMutex.RLock()
+	defer fake.finishOperationLogMutex.RUnlock()
+	fake.getAllTerraformDeploymentsMutex.RLock()
+	defer fake.getAllTerraformDeploymentsMutex.RUnlock()
+	fake.getServiceBindingIDsForServiceInstanceMutex.RLock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.RUnlock()
+	fake.getServiceInstanceDetailsMutex.RLock()
+	defer fake.getServiceInstanceDetailsMutex.RUnlock()
+	fake.getTerraformDeploymentMutex.RLock()
+	defer fake.getTerraformDeploymentMutex.RUnlock()
+	fake.releaseTerraformDeploymentLeaseMutex.RLock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.RUnlock()
+	fake.startOperationLogMutex.RLock()
+	defer fake.startOperationLogMutex.RUnlock()
+	fake.storeTerraformDeploymentMutex.RLock()
+	defer fake.storeTerraformDeploymentMutex.RUnlock()
+	fake.storeTerraformDriftMutex.RLock()
+	defer fake.storeTerraformDriftMutex.RUnlock()
+	fake.storeTerraformLogMutex.RLock()
+	defer fake.storeTerraformLogMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ drift.Store = new(FakeStore)
@@ -11,7 +11,7 @@ import (
 func UpdatePasswordMetadata(db *gorm.DB, configuredPrimaryLabel string) error {
 	return db.Transaction(func(tx *gorm.DB) error {
 		var passwordMetadata []models.PasswordMetadata
-		if err := tx.Where("`primary` = true").Find(&passwordMetadata).Error; err != nil {
+		if err := tx.Where(map[string]any{"primary": true}).Find(&passwordMetadata).Error; err != nil {
 			return err
 		}
 
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/pivotal-cf/brokerapi/v9/domain"
+)
+
+// BrokerWrapper records metrics for each request to the wrapped ServiceBroker
+type BrokerWrapper struct {
+	domain.ServiceBroker
+}
+
+// NewBrokerWrapper wraps the given ServiceBroker so that its requests are counted and timed
+func NewBrokerWrapper(wrapped domain.ServiceBroker) domain.ServiceBroker {
+	return &BrokerWrapper{ServiceBroker: wrapped}
+}
+
+func (w *BrokerWrapper) Services(ctx context.Context) ([]domain.Service, error) {
+	start := time.Now()
+	services, err := w.ServiceBroker.Services(ctx)
+	ObserveOSBRequest("catalog", "", "", time.Since(start), err)
+	return services, err
+}
+
+func (w *BrokerWrapper) Provision(ctx context.Context, instanceID string, details domain.ProvisionDetails, asyncAllowed bool) (domain.ProvisionedServiceSpec, error) {
+	start := time.Now()
+	spec, err := w.ServiceBroker.Provision(ctx, instanceID, details, asyncAllowed)
+	ObserveOSBRequest("provision", details.ServiceID, details.PlanID, time.Since(start), err)
+	return spec, err
+}
+
+func (w *BrokerWrapper) Deprovision(ctx context.Context, instanceID string, details domain.DeprovisionDetails, asyncAllowed bool) (domain.DeprovisionServiceSpec, error) {
+	start := time.Now()
+	spec, err := w.ServiceBroker.Deprovision(ctx, instanceID, details, asyncAllowed)
+	ObserveOSBRequest("deprovision", details.ServiceID, details.PlanID, time.Since(start), err)
+	return spec, err
+}
+
+func (w *BrokerWrapper) GetInstance(ctx context.Context, instanceID string, details domain.FetchInstanceDetails) (domain.GetInstanceDetailsSpec, error) {
+	start := time.Now()
+	spec, err := w.ServiceBroker.GetInstance(ctx, instanceID, details)
+	ObserveOSBRequest("get_instance", details.ServiceID, details.PlanID, time.Since(start), err)
+	return spec, err
+}
+
+func (w *BrokerWrapper) Update(ctx context.Context, instanceID string, details domain.UpdateDetails, asyncAllowed bool) (domain.UpdateServiceSpec, error) {
+	start := time.Now()
+	spec, err := w.ServiceBroker.Update(ctx, instanceID, details, asyncAllowed)
+
+	// The plan ID is only required when the plan is being changed
+	planID := details.PlanID
+	if planID == "" {
+		planID = details.PreviousValues.PlanID
+	}
+	ObserveOSBRequest("update", details.ServiceID, planID, time.Since(start), err)
+	return spec, err
+}
+
+func (w *BrokerWrapper) LastOperation(ctx context.Context, instanceID string, details domain.PollDetails) (domain.LastOperation, error) {
+	start := time.Now()
+	operation, err := w.ServiceBroker.LastOperation(ctx, instanceID, details)
+	ObserveOSBRequest("last_operation", details.ServiceID, details.PlanID, time.Since(start), err)
+	return operation, err
+}
+
+func (w *BrokerWrapper) Bind(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, asyncAllowed bool) (domain.Binding, error) {
+	start := time.Now()
+	binding, err := w.ServiceBroker.Bind(ctx, instanceID, bindingID, details, asyncAllowed)
+	ObserveOSBRequest("bind", details.ServiceID, details.PlanID, time.Since(start), err)
+	return binding, err
+}
+
+func (w *BrokerWrapper) Unbind(ctx context.Context, instanceID, bindingID string, details domain.UnbindDetails, asyncAllowed bool) (domain.UnbindSpec, error) {
+	start := time.Now()
+	spec, err := w.ServiceBroker.Unbind(ctx, instanceID, bindingID, details, asyncAllowed)
+	ObserveOSBRequest("unbind", details.ServiceID, details.PlanID, time.Since(start), err)
+	return spec, err
+}
+
+func (w *BrokerWrapper) GetBinding(ctx context.Context, instanceID, bindingID string, details domain.FetchBindingDetails) (domain.GetBindingSpec, error) {
+	start := time.Now()
+	spec, err := w.ServiceBroker.GetBinding(ctx, instanceID, bindingID, details)
+	ObserveOSBRequest("get_binding", details.ServiceID, details.PlanID, time.Since(start), err)
+	return spec, err
+}
+
+func (w *BrokerWrapper) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details domain.PollDetails) (domain.LastOperation, error) {
+	start := time.Now()
+	operation, err := w.ServiceBroker.LastBindingOperation(ctx, instanceID, bindingID, details)
+	ObserveOSBRequest("last_binding_operation", details.ServiceID, details.PlanID, time.Since(start), err)
+	return operation, err
+}
@@ -0,0 +1,55 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi/v9/domain"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/server/fakes"
+)
+
+var _ = Describe("BrokerWrapper", func() {
+	var (
+		fakeBroker *fakes.FakeServiceBroker
+		broker     domain.ServiceBroker
+	)
+
+	BeforeEach(func() {
+		fakeBroker = &fakes.FakeServiceBroker{}
+		broker = metrics.NewBrokerWrapper(fakeBroker)
+	})
+
+	It("passes requests to the wrapped broker and counts them", func() {
+		fakeBroker.ProvisionReturns(domain.ProvisionedServiceSpec{IsAsync: true}, nil)
+
+		spec, err := broker.Provision(context.TODO(), "fake-instance-id", domain.ProvisionDetails{ServiceID: "provision-service-id", PlanID: "provision-plan-id"}, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec).To(Equal(domain.ProvisionedServiceSpec{IsAsync: true}))
+		Expect(fakeBroker.ProvisionCallCount()).To(Equal(1))
+
+		Expect(scrape()).To(ContainSubstring(`csb_osb_requests_total{endpoint="provision",plan_id="provision-plan-id",result="success",service_id="provision-service-id"} 1`))
+	})
+
+	It("counts failed requests", func() {
+		fakeBroker.BindReturns(domain.Binding{}, errors.New("boom"))
+
+		_, err := broker.Bind(context.TODO(), "fake-instance-id", "fake-binding-id", domain.BindDetails{ServiceID: "bind-service-id", PlanID: "bind-plan-id"}, true)
+		Expect(err).To(MatchError("boom"))
+
+		Expect(scrape()).To(ContainSubstring(`csb_osb_requests_total{endpoint="bind",plan_id="bind-plan-id",result="failure",service_id="bind-service-id"} 1`))
+	})
+
+	It("uses the previous plan when an update does not change the plan", func() {
+		_, err := broker.Update(context.TODO(), "fake-instance-id", domain.UpdateDetails{
+			ServiceID:      "update-service-id",
+			PreviousValues: domain.PreviousValues{PlanID: "update-previous-plan-id"},
+		}, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(scrape()).To(ContainSubstring(`csb_osb_requests_total{endpoint="update",plan_id="update-previous-plan-id",result="success",service_id="update-service-id"} 1`))
+	})
+})
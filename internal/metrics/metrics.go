@@ -0,0 +1,153 @@
+// Package metrics collects Prometheus metrics for the broker
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes the names of the broker metrics
+const Namespace = "csb"
+
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	osbRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "osb_requests_total",
+		Help:      "Number of Open Service Broker API requests.",
+	}, []string{"endpoint", "service_id", "plan_id", "result"})
+
+	osbRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "osb_request_duration_seconds",
+		Help:      "Latency of Open Service Broker API requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint", "service_id", "plan_id"})
+
+	terraformInvocationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "terraform_invocation_duration_seconds",
+		Help:      "Duration of Terraform invocations.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"command"})
+
+	terraformInvocationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "terraform_invocation_failures_total",
+		Help:      "Number of Terraform invocations that failed.",
+	}, []string{"command"})
+
+	terraformInitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "terraform_init_duration_seconds",
+		Help:      "Duration of Terraform init, with and without the shared provider plugin cache.",
+		Buckets:   prometheus.ExponentialBuckets(0.25, 2, 10),
+	}, []string{"plugin_cache"})
+
+	terraformPendingOperations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "terraform_pending_operations",
+		Help:      "Number of operations admitted to a Terraform execution pool that have not finished.",
+	}, []string{"pool"})
+
+	terraformQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "terraform_queue_depth",
+		Help:      "Number of Terraform commands waiting for a worker in a Terraform execution pool.",
+	}, []string{"pool"})
+
+	terraformRunningCommands = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "terraform_running_commands",
+		Help:      "Number of Terraform commands running in a Terraform execution pool.",
+	}, []string{"pool"})
+
+	terraformRejectedOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "terraform_rejected_operations_total",
+		Help:      "Number of operations rejected because a Terraform execution pool was full.",
+	}, []string{"pool"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		osbRequests,
+		osbRequestDuration,
+		terraformInvocationDuration,
+		terraformInvocationFailures,
+		terraformInitDuration,
+		terraformPendingOperations,
+		terraformQueueDepth,
+		terraformRunningCommands,
+		terraformRejectedOperations,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Register adds collectors to the metrics that are served
+func Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObserveOSBRequest records an Open Service Broker API request
+func ObserveOSBRequest(endpoint, serviceID, planID string, duration time.Duration, err error) {
+	osbRequests.WithLabelValues(endpoint, serviceID, planID, result(err)).Inc()
+	osbRequestDuration.WithLabelValues(endpoint, serviceID, planID).Observe(duration.Seconds())
+}
+
+// ObserveTerraformInvocation records an invocation of a Terraform command such as "apply"
+func ObserveTerraformInvocation(command string, duration time.Duration, err error) {
+	terraformInvocationDuration.WithLabelValues(command).Observe(duration.Seconds())
+	if err != nil {
+		terraformInvocationFailures.WithLabelValues(command).Inc()
+	}
+}
+
+// ObserveTerraformInit records the duration of Terraform init, and whether the plugin cache was used
+func ObserveTerraformInit(pluginCache bool, duration time.Duration) {
+	label := "disabled"
+	if pluginCache {
+		label = "enabled"
+	}
+	terraformInitDuration.WithLabelValues(label).Observe(duration.Seconds())
+}
+
+// SetTerraformPool records the load on a Terraform execution pool
+func SetTerraformPool(pool string, pendingOperations, queuedCommands, runningCommands int) {
+	terraformPendingOperations.WithLabelValues(pool).Set(float64(pendingOperations))
+	terraformQueueDepth.WithLabelValues(pool).Set(float64(queuedCommands))
+	terraformRunningCommands.WithLabelValues(pool).Set(float64(runningCommands))
+}
+
+// ObserveTerraformRejection records an operation rejected because a Terraform execution pool was full
+func ObserveTerraformRejection(pool string) {
+	terraformRejectedOperations.WithLabelValues(pool).Inc()
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultFailure
+	}
+	return resultSuccess
+}
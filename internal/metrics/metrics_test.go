@@ -0,0 +1,79 @@
+package metrics_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics"
+)
+
+var _ = Describe("Metrics", func() {
+	Describe("ObserveTerraformInvocation", func() {
+		It("records the duration and failures per command", func() {
+			metrics.ObserveTerraformInvocation("fake-apply", 3*time.Second, nil)
+			metrics.ObserveTerraformInvocation("fake-apply", 5*time.Second, errors.New("boom"))
+
+			body := scrape()
+			Expect(body).To(ContainSubstring(`csb_terraform_invocation_duration_seconds_count{command="fake-apply"} 2`))
+			Expect(body).To(ContainSubstring(`csb_terraform_invocation_duration_seconds_sum{command="fake-apply"} 8`))
+			Expect(body).To(ContainSubstring(`csb_terraform_invocation_failures_total{command="fake-apply"} 1`))
+		})
+	})
+
+	Describe("ObserveTerraformInit", func() {
+		It("records the duration with and without the plugin cache", func() {
+			metrics.ObserveTerraformInit(true, time.Second)
+			metrics.ObserveTerraformInit(false, 4*time.Second)
+
+			body := scrape()
+			Expect(body).To(ContainSubstring(`csb_terraform_init_duration_seconds_sum{plugin_cache="enabled"} 1`))
+			Expect(body).To(ContainSubstring(`csb_terraform_init_duration_seconds_sum{plugin_cache="disabled"} 4`))
+		})
+	})
+
+	Describe("SetTerraformPool and ObserveTerraformRejection", func() {
+		It("records the load on the pool", func() {
+			metrics.SetTerraformPool("fake-pool", 5, 2, 3)
+			metrics.ObserveTerraformRejection("fake-pool")
+
+			body := scrape()
+			Expect(body).To(ContainSubstring(`csb_terraform_pending_operations{pool="fake-pool"} 5`))
+			Expect(body).To(ContainSubstring(`csb_terraform_queue_depth{pool="fake-pool"} 2`))
+			Expect(body).To(ContainSubstring(`csb_terraform_running_commands{pool="fake-pool"} 3`))
+			Expect(body).To(ContainSubstring(`csb_terraform_rejected_operations_total{pool="fake-pool"} 1`))
+		})
+	})
+
+	Describe("ObserveOSBRequest", func() {
+		It("records the requests and their latency per endpoint, service and plan", func() {
+			metrics.ObserveOSBRequest("fake-endpoint", "fake-service-id", "fake-plan-id", time.Second, nil)
+			metrics.ObserveOSBRequest("fake-endpoint", "fake-service-id", "fake-plan-id", time.Second, errors.New("boom"))
+
+			body := scrape()
+			Expect(body).To(ContainSubstring(`csb_osb_requests_total{endpoint="fake-endpoint",plan_id="fake-plan-id",result="success",service_id="fake-service-id"} 1`))
+			Expect(body).To(ContainSubstring(`csb_osb_requests_total{endpoint="fake-endpoint",plan_id="fake-plan-id",result="failure",service_id="fake-service-id"} 1`))
+			Expect(body).To(ContainSubstring(`csb_osb_request_duration_seconds_count{endpoint="fake-endpoint",plan_id="fake-plan-id",service_id="fake-service-id"} 2`))
+		})
+	})
+
+	Describe("Register", func() {
+		It("serves the metrics of the registered collectors", func() {
+			gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "fake_gauge", Help: "A fake gauge."})
+			gauge.Set(42)
+
+			Expect(metrics.Register(gauge)).To(Succeed())
+			Expect(scrape()).To(ContainSubstring("fake_gauge 42"))
+		})
+
+		It("fails when a collector is registered twice", func() {
+			counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "fake_counter", Help: "A fake counter."})
+
+			Expect(metrics.Register(counter)).To(Succeed())
+			Expect(metrics.Register(counter)).To(HaveOccurred())
+		})
+	})
+})
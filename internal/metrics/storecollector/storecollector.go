@@ -0,0 +1,84 @@
+// Package storecollector collects metrics about the records in the broker database
+package storecollector
+
+import (
+	"code.cloudfoundry.org/lager/v3"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+//counterfeiter:generate . Store
+type Store interface {
+	CountServiceInstancesByPlan() ([]storage.PlanCount, error)
+	CountServiceBindingsByPlan() ([]storage.PlanCount, error)
+	CountTerraformDeploymentsByLastOperation() ([]storage.OperationCount, error)
+}
+
+var (
+	serviceInstancesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "", "service_instances"),
+		"Number of service instances.",
+		[]string{"service_id", "plan_id"}, nil,
+	)
+
+	serviceBindingsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "", "service_bindings"),
+		"Number of service bindings.",
+		[]string{"service_id", "plan_id"}, nil,
+	)
+
+	lastOperationsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "", "last_operations"),
+		`Number of service instances and bindings by the type and state of their last operation. Operations that are running have the state "in progress".`,
+		[]string{"operation_type", "state"}, nil,
+	)
+)
+
+// Collector reads the number of service instances, bindings and operations
+// from the database each time that the metrics are collected
+type Collector struct {
+	store  Store
+	logger lager.Logger
+}
+
+// New returns a collector for the records in the database
+func New(store Store, logger lager.Logger) *Collector {
+	return &Collector{store: store, logger: logger.Session("metrics")}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- serviceInstancesDesc
+	ch <- serviceBindingsDesc
+	ch <- lastOperationsDesc
+}
+
+// Collect sends the metrics that can be read. When the database cannot be read,
+// the error is logged and the affected metrics are left out.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	if counts, err := c.store.CountServiceInstancesByPlan(); err != nil {
+		c.logger.Error("collect-service-instances", err)
+	} else {
+		for _, count := range counts {
+			ch <- prometheus.MustNewConstMetric(serviceInstancesDesc, prometheus.GaugeValue, float64(count.Count), count.ServiceID, count.PlanID)
+		}
+	}
+
+	if counts, err := c.store.CountServiceBindingsByPlan(); err != nil {
+		c.logger.Error("collect-service-bindings", err)
+	} else {
+		for _, count := range counts {
+			ch <- prometheus.MustNewConstMetric(serviceBindingsDesc, prometheus.GaugeValue, float64(count.Count), count.ServiceID, count.PlanID)
+		}
+	}
+
+	if counts, err := c.store.CountTerraformDeploymentsByLastOperation(); err != nil {
+		c.logger.Error("collect-last-operations", err)
+	} else {
+		for _, count := range counts {
+			ch <- prometheus.MustNewConstMetric(lastOperationsDesc, prometheus.GaugeValue, float64(count.Count), count.OperationType, count.OperationState)
+		}
+	}
+}
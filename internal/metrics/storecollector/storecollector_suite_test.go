@@ -0,0 +1,13 @@
+package storecollector_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestStorecollector(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Storecollector Suite")
+}
@@ -0,0 +1,63 @@
+package storecollector_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics/storecollector"
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics/storecollector/storecollectorfakes"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+)
+
+var _ = Describe("Collector", func() {
+	var (
+		fakeStore *storecollectorfakes.FakeStore
+		collector *storecollector.Collector
+	)
+
+	BeforeEach(func() {
+		fakeStore = &storecollectorfakes.FakeStore{}
+		collector = storecollector.New(fakeStore, utils.NewLogger("test"))
+
+		fakeStore.CountServiceInstancesByPlanReturns([]storage.PlanCount{
+			{ServiceID: "fake-service-id", PlanID: "fake-plan-1", Count: 3},
+			{ServiceID: "fake-service-id", PlanID: "fake-plan-2", Count: 1},
+		}, nil)
+		fakeStore.CountServiceBindingsByPlanReturns([]storage.PlanCount{
+			{ServiceID: "fake-service-id", PlanID: "fake-plan-1", Count: 5},
+		}, nil)
+		fakeStore.CountTerraformDeploymentsByLastOperationReturns([]storage.OperationCount{
+			{OperationType: "provision", OperationState: "in progress", Count: 2},
+			{OperationType: "update", OperationState: "failed", Count: 1},
+		}, nil)
+	})
+
+	It("collects the number of instances, bindings and operations", func() {
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP csb_last_operations Number of service instances and bindings by the type and state of their last operation. Operations that are running have the state "in progress".
+# TYPE csb_last_operations gauge
+csb_last_operations{operation_type="provision",state="in progress"} 2
+csb_last_operations{operation_type="update",state="failed"} 1
+# HELP csb_service_bindings Number of service bindings.
+# TYPE csb_service_bindings gauge
+csb_service_bindings{plan_id="fake-plan-1",service_id="fake-service-id"} 5
+# HELP csb_service_instances Number of service instances.
+# TYPE csb_service_instances gauge
+csb_service_instances{plan_id="fake-plan-1",service_id="fake-service-id"} 3
+csb_service_instances{plan_id="fake-plan-2",service_id="fake-service-id"} 1
+`))).To(Succeed())
+	})
+
+	It("leaves out the metrics that cannot be read", func() {
+		fakeStore.CountServiceInstancesByPlanReturns(nil, errors.New("boom"))
+
+		Expect(testutil.CollectAndCount(collector, "csb_service_instances")).To(BeZero())
+		Expect(testutil.CollectAndCount(collector, "csb_service_bindings")).To(Equal(1))
+		Expect(testutil.CollectAndCount(collector, "csb_last_operations")).To(Equal(2))
+	})
+})
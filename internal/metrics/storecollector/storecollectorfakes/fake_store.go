@@ -0,0 +1,248 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package storecollectorfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics/storecollector"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+type FakeStore struct {
+	CountServiceBindingsByPlanStub        func() ([]storage.PlanCount, error)
+	countServiceBindingsByPlanMutex       sync.RWMutex
+	countServiceBindingsByPlanArgsForCall []struct {
+	}
+	countServiceBindingsByPlanReturns struct {
+		result1 []storage.PlanCount
+		result2 error
+	}
+	countServiceBindingsByPlanReturnsOnCall map[int]struct {
+		result1 []storage.PlanCount
+		result2 error
+	}
+	CountServiceInstancesByPlanStub        func() ([]storage.PlanCount, error)
+	countServiceInstancesByPlanMutex       sync.RWMutex
+	countServiceInstancesByPlanArgsForCall []struct {
+	}
+	countServiceInstancesByPlanReturns struct {
+		result1 []storage.PlanCount
+		result2 error
+	}
+	countServiceInstancesByPlanReturnsOnCall map[int]struct {
+		result1 []storage.PlanCount
+		result2 error
+	}
+	CountTerraformDeploymentsByLastOperationStub        func() ([]storage.OperationCount, error)
+	countTerraformDeploymentsByLastOperationMutex       sync.RWMutex
+	countTerraformDeploymentsByLastOperationArgsForCall []struct {
+	}
+	countTerraformDeploymentsByLastOperationReturns struct {
+		result1 []storage.OperationCount
+		result2 error
+	}
+	countTerraformDeploymentsByLastOperationReturnsOnCall map[int]struct {
+		result1 []storage.OperationCount
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStore) CountServiceBindingsByPlan() ([]storage.PlanCount, error) {
+	fake.countServiceBindingsByPlanMutex.Lock()
+	ret, specificReturn := fake.countServiceBindingsByPlanReturnsOnCall[len(fake.countServiceBindingsByPlanArgsForCall)]
+	fake.countServiceBindingsByPlanArgsForCall = append(fake.countServiceBindingsByPlanArgsForCall, struct {
+	}{})
+	stub := fake.CountServiceBindingsByPlanStub
+	fakeReturns := fake.countServiceBindingsByPlanReturns
+	fake.recordInvocation("CountServiceBindingsByPlan", []interface{}{})
+	fake.countServiceBindingsByPlanMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) CountServiceBindingsByPlanCallCount() int {
+	fake.countServiceBindingsByPlanMutex.RLock()
+	defer fake.countServiceBindingsByPlanMutex.RUnlock()
+	return len(fake.countServiceBindingsByPlanArgsForCall)
+}
+
+func (fake *FakeStore) CountServiceBindingsByPlanCalls(stub func() ([]storage.PlanCount, error)) {
+	fake.countServiceBindingsByPlanMutex.Lock()
+	defer fake.countServiceBindingsByPlanMutex.Unlock()
+	fake.CountServiceBindingsByPlanStub = stub
+}
+
+func (fake *FakeStore) CountServiceBindingsByPlanReturns(result1 []storage.PlanCount, result2 error) {
+	fake.countServiceBindingsByPlanMutex.Lock()
+	defer fake.countServiceBindingsByPlanMutex.Unlock()
+	fake.CountServiceBindingsByPlanStub = nil
+	fake.countServiceBindingsByPlanReturns = struct {
+		result1 []storage.PlanCount
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) CountServiceBindingsByPlanReturnsOnCall(i int, result1 []storage.PlanCount, result2 error) {
+	fake.countServiceBindingsByPlanMutex.Lock()
+	defer fake.countServiceBindingsByPlanMutex.Unlock()
+	fake.CountServiceBindingsByPlanStub = nil
+	if fake.countServiceBindingsByPlanReturnsOnCall == nil {
+		fake.countServiceBindingsByPlanReturnsOnCall = make(map[int]struct {
+			result1 []storage.PlanCount
+			result2 error
+		})
+	}
+	fake.countServiceBindingsByPlanReturnsOnCall[i] = struct {
+		result1 []storage.PlanCount
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) CountServiceInstancesByPlan() ([]storage.PlanCount, error) {
+	fake.countServiceInstancesByPlanMutex.Lock()
+	ret, specificReturn := fake.countServiceInstancesByPlanReturnsOnCall[len(fake.countServiceInstancesByPlanArgsForCall)]
+	fake.countServiceInstancesByPlanArgsForCall = append(fake.countServiceInstancesByPlanArgsForCall, struct {
+	}{})
+	stub := fake.CountServiceInstancesByPlanStub
+	fakeReturns := fake.countServiceInstancesByPlanReturns
+	fake.recordInvocation("CountServiceInstancesByPlan", []interface{}{})
+	fake.countServiceInstancesByPlanMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) CountServiceInstancesByPlanCallCount() int {
+	fake.countServiceInstancesByPlanMutex.RLock()
+	defer fake.countServiceInstancesByPlanMutex.RUnlock()
+	return len(fake.countServiceInstancesByPlanArgsForCall)
+}
+
+func (fake *FakeStore) CountServiceInstancesByPlanCalls(stub func() ([]storage.PlanCount, error)) {
+	fake.countServiceInstancesByPlanMutex.Lock()
+	defer fake.countServiceInstancesByPlanMutex.Unlock()
+	fake.CountServiceInstancesByPlanStub = stub
+}
+
+func (fake *FakeStore) CountServiceInstancesByPlanReturns(result1 []storage.PlanCount, result2 error) {
+	fake.countServiceInstancesByPlanMutex.Lock()
+	defer fake.countServiceInstancesByPlanMutex.Unlock()
+	fake.CountServiceInstancesByPlanStub = nil
+	fake.countServiceInstancesByPlanReturns = struct {
+		result1 []storage.PlanCount
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) CountServiceInstancesByPlanReturnsOnCall(i int, result1 []storage.PlanCount, result2 error) {
+	fake.countServiceInstancesByPlanMutex.Lock()
+	defer fake.countServiceInstancesByPlanMutex.Unlock()
+	fake.CountServiceInstancesByPlanStub = nil
+	if fake.countServiceInstancesByPlanReturnsOnCall == nil {
+		fake.countServiceInstancesByPlanReturnsOnCall = make(map[int]struct {
+			result1 []storage.PlanCount
+			result2 error
+		})
+	}
+	fake.countServiceInstancesByPlanReturnsOnCall[i] = struct {
+		result1 []storage.PlanCount
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) CountTerraformDeploymentsByLastOperation() ([]storage.OperationCount, error) {
+	fake.countTerraformDeploymentsByLastOperationMutex.Lock()
+	ret, specificReturn := fake.countTerraformDeploymentsByLastOperationReturnsOnCall[len(fake.countTerraformDeploymentsByLastOperationArgsForCall)]
+	fake.countTerraformDeploymentsByLastOperationArgsForCall = append(fake.countTerraformDeploymentsByLastOperationArgsForCall, struct {
+	}{})
+	stub := fake.CountTerraformDeploymentsByLastOperationStub
+	fakeReturns := fake.countTerraformDeploymentsByLastOperationReturns
+	fake.recordInvocation("CountTerraformDeploymentsByLastOperation", []interface{}{})
+	fake.countTerraformDeploymentsByLastOperationMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) CountTerraformDeploymentsByLastOperationCallCount() int {
+	fake.countTerraformDeploymentsByLastOperationMutex.RLock()
+	defer fake.countTerraformDeploymentsByLastOperationMutex.RUnlock()
+	return len(fake.countTerraformDeploymentsByLastOperationArgsForCall)
+}
+
+func (fake *FakeStore) CountTerraformDeploymentsByLastOperationCalls(stub func() ([]storage.OperationCount, error)) {
+	fake.countTerraformDeploymentsByLastOperationMutex.Lock()
+	defer fake.countTerraformDeploymentsByLastOperationMutex.Unlock()
+	fake.CountTerraformDeploymentsByLastOperationStub = stub
+}
+
+func (fake *FakeStore) CountTerraformDeploymentsByLastOperationReturns(result1 []storage.OperationCount, result2 error) {
+	fake.countTerraformDeploymentsByLastOperationMutex.Lock()
+	defer fake.countTerraformDeploymentsByLastOperationMutex.Unlock()
+	fake.CountTerraformDeploymentsByLastOperationStub = nil
+	fake.countTerraformDeploymentsByLastOperationReturns = struct {
+		result1 []storage.OperationCount
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) CountTerraformDeploymentsByLastOperationReturnsOnCall(i int, result1 []storage.OperationCount, result2 error) {
+	fake.countTerraformDeploymentsByLastOperationMutex.Lock()
+	defer fake.countTerraformDeploymentsByLastOperationMutex.Unlock()
+	fake.CountTerraformDeploymentsByLastOperationStub = nil
+	if fake.countTerraformDeploymentsByLastOperationReturnsOnCall == nil {
+		fake.countTerraformDeploymentsByLastOperationReturnsOnCall = make(map[int]struct {
+			result1 []storage.OperationCount
+			result2 error
+		})
+	}
+	fake.countTerraformDeploymentsByLastOperationReturnsOnCall[i] = struct {
+		result1 []storage.OperationCount
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.countServiceBindingsByPlanMutex.RLock()
+	defer fake.countServiceBindingsByPlanMutex.RUnlock()
+	fake.countServiceInstancesByPlanMutex.RLock()
+	defer fake.countServiceInstancesByPlanMutex.RUnlock()
+	fake.countTerraformDeploymentsByLastOperationMutex.RLock()
+	defer fake.countTerraformDeploymentsByLastOperationMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ storecollector.Store = new(FakeStore)
@@ -0,0 +1,64 @@
+// Package operationshandler handles the /operations endpoint
+package operationshandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+// Path is where the handler is served. The service instance ID follows the path.
+const Path = "/operations/"
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+//counterfeiter:generate . Store
+type Store interface {
+	GetOperationLogs(serviceInstanceID string) ([]storage.OperationLog, error)
+}
+
+// New returns a handler that lists the operations on a service instance and its bindings, newest first
+func New(store Store) http.HandlerFunc {
+	type payload struct {
+		Operations []storage.OperationLog `json:"operations"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		serviceInstanceID := strings.TrimPrefix(r.URL.Path, Path)
+		if serviceInstanceID == "" || strings.Contains(serviceInstanceID, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		operations, err := store.GetOperationLogs(serviceInstanceID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error reading operations: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		if operations == nil {
+			operations = []storage.OperationLog{}
+		}
+
+		data, err := json.Marshal(payload{Operations: operations})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error marshalling operations payload: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(data); err != nil {
+			http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
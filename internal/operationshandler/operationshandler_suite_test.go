@@ -0,0 +1,13 @@
+package operationshandler_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOperationshandler(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Operationshandler Suite")
+}
@@ -0,0 +1,99 @@
+package operationshandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/operationshandler"
+	"github.com/cloudfoundry/cloud-service-broker/internal/operationshandler/operationshandlerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+var _ = Describe("Operations Handler", func() {
+	var (
+		fakeStore *operationshandlerfakes.FakeStore
+		server    *httptest.Server
+		client    *http.Client
+	)
+
+	BeforeEach(func() {
+		fakeStore = &operationshandlerfakes.FakeStore{}
+
+		router := http.NewServeMux()
+		router.Handle(operationshandler.Path, operationshandler.New(fakeStore))
+		server = httptest.NewServer(router)
+		client = server.Client()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the operations on the service instance", func() {
+		finishedAt := time.Date(2023, time.March, 2, 10, 5, 0, 0, time.UTC)
+		fakeStore.GetOperationLogsReturns([]storage.OperationLog{{
+			DeploymentID:  "tf:fake-instance-id:",
+			OperationType: "provision",
+			State:         "failed",
+			Message:       "provision failed: boom",
+			CorrelationID: "fake-correlation-id",
+			Stderr:        "Error: boom",
+			StartedAt:     time.Date(2023, time.March, 2, 10, 0, 0, 0, time.UTC),
+			FinishedAt:    &finishedAt,
+		}}, nil)
+
+		resp, err := client.Get(server.URL + "/operations/fake-instance-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(HaveHTTPStatus(http.StatusOK))
+		Expect(resp).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+		Expect(resp).To(HaveHTTPBody(MatchJSON(`{
+			"operations": [{
+				"deployment_id": "tf:fake-instance-id:",
+				"operation_type": "provision",
+				"state": "failed",
+				"message": "provision failed: boom",
+				"correlation_id": "fake-correlation-id",
+				"stderr": "Error: boom",
+				"started_at": "2023-03-02T10:00:00Z",
+				"finished_at": "2023-03-02T10:05:00Z"
+			}]
+		}`)))
+
+		Expect(fakeStore.GetOperationLogsCallCount()).To(Equal(1))
+		Expect(fakeStore.GetOperationLogsArgsForCall(0)).To(Equal("fake-instance-id"))
+	})
+
+	It("returns an empty list when there are no operations", func() {
+		resp, err := client.Get(server.URL + "/operations/fake-instance-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(HaveHTTPStatus(http.StatusOK))
+		Expect(resp).To(HaveHTTPBody(MatchJSON(`{"operations":[]}`)))
+	})
+
+	It("returns not found when there is no service instance ID", func() {
+		resp, err := client.Get(server.URL + "/operations/")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(HaveHTTPStatus(http.StatusNotFound))
+		Expect(fakeStore.GetOperationLogsCallCount()).To(BeZero())
+	})
+
+	It("only allows GET", func() {
+		resp, err := client.Post(server.URL+"/operations/fake-instance-id", "application/json", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(HaveHTTPStatus(http.StatusMethodNotAllowed))
+	})
+
+	It("fails when the operations cannot be read", func() {
+		fakeStore.GetOperationLogsReturns(nil, errors.New("boom"))
+
+		resp, err := client.Get(server.URL + "/operations/fake-instance-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp).To(HaveHTTPStatus(http.StatusInternalServerError))
+		Expect(resp).To(HaveHTTPBody(ContainSubstring("error reading operations: boom")))
+	})
+})
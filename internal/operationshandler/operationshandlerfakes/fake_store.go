@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package operationshandlerfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/operationshandler"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+type FakeStore struct {
+	GetOperationLogsStub        func(string) ([]storage.OperationLog, error)
+	getOperationLogsMutex       sync.RWMutex
+	getOperationLogsArgsForCall []struct {
+		arg1 string
+	}
+	getOperationLogsReturns struct {
+		result1 []storage.OperationLog
+		result2 error
+	}
+	getOperationLogsReturnsOnCall map[int]struct {
+		result1 []storage.OperationLog
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStore) GetOperationLogs(arg1 string) ([]storage.OperationLog, error) {
+	fake.getOperationLogsMutex.Lock()
+	ret, specificReturn := fake.getOperationLogsReturnsOnCall[len(fake.getOperationLogsArgsForCall)]
+	fake.getOperationLogsArgsForCall = append(fake.getOperationLogsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetOperationLogsStub
+	fakeReturns := fake.getOperationLogsReturns
+	fake.recordInvocation("GetOperationLogs", []interface{}{arg1})
+	fake.getOperationLogsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) GetOperationLogsCallCount() int {
+	fake.getOperationLogsMutex.RLock()
+	defer fake.getOperationLogsMutex.RUnlock()
+	return len(fake.getOperationLogsArgsForCall)
+}
+
+func (fake *FakeStore) GetOperationLogsCalls(stub func(string) ([]storage.OperationLog, error)) {
+	fake.getOperationLogsMutex.Lock()
+	defer fake.getOperationLogsMutex.Unlock()
+	fake.GetOperationLogsStub = stub
+}
+
+func (fake *FakeStore) GetOperationLogsArgsForCall(i int) string {
+	fake.getOperationLogsMutex.RLock()
+	defer fake.getOperationLogsMutex.RUnlock()
+	argsForCall := fake.getOperationLogsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) GetOperationLogsReturns(result1 []storage.OperationLog, result2 error) {
+	fake.getOperationLogsMutex.Lock()
+	defer fake.getOperationLogsMutex.Unlock()
+	fake.GetOperationLogsStub = nil
+	fake.getOperationLogsReturns = struct {
+		result1 []storage.OperationLog
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetOperationLogsReturnsOnCall(i int, result1 []storage.OperationLog, result2 error) {
+	fake.getOperationLogsMutex.Lock()
+	defer fake.getOperationLogsMutex.Unlock()
+	fake.GetOperationLogsStub = nil
+	if fake.getOperationLogsReturnsOnCall == nil {
+		fake.getOperationLogsReturnsOnCall = make(map[int]struct {
+			result1 []storage.OperationLog
+			result2 error
+		})
+	}
+	fake.getOperationLogsReturnsOnCall[i] = struct {
+		result1 []storage.OperationLog
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getOperationLogsMutex.RLock()
+	defer fake.getOperationLogsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ operationshandler.Store = new(FakeStore)
@@ -0,0 +1,126 @@
+// Package reconciler recovers Terraform operations that were left in progress when a broker
+// process stopped. Terraform runs in the background of the broker process that accepted the
+// request, so if that process stops, nothing would ever finish the operation.
+package reconciler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
+)
+
+// ErrInterrupted is recorded as the cause of the failure of an operation that was left in progress
+var ErrInterrupted = errors.New("the broker stopped while the operation was in progress, so the resources may be incomplete: retry the operation")
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+//counterfeiter:generate . Store
+
+type Store interface {
+	broker.ServiceProviderStorage
+	HeartbeatTerraformDeployments(state string) error
+	GetStaleTerraformDeploymentIDs(state string, since time.Time) ([]string, error)
+	ClaimStaleTerraformDeployment(id, state string, since time.Time) (bool, error)
+	GetCancelRequestedTerraformDeploymentIDs() ([]string, error)
+}
+
+type Reconciler struct {
+	store             Store
+	deploymentManager *tf.DeploymentManager
+	staleAfter        time.Duration
+	logger            lager.Logger
+}
+
+// New returns a Reconciler. An operation is considered to have been left in progress when
+// its owner has not recorded a heartbeat for the staleAfter duration, so Heartbeat should
+// be called several times within that duration.
+func New(store Store, staleAfter time.Duration, logger lager.Logger) *Reconciler {
+	return &Reconciler{
+		store:             store,
+		deploymentManager: tf.NewDeploymentManager(store),
+		staleAfter:        staleAfter,
+		logger:            logger.Session("reconciler"),
+	}
+}
+
+// Heartbeat records that the operations run by this broker process are still in progress,
+// and cancels those of them that an operator has asked to be cancelled
+func (r *Reconciler) Heartbeat() error {
+	if err := r.store.HeartbeatTerraformDeployments(tf.InProgress); err != nil {
+		return err
+	}
+
+	ids, err := r.store.GetCancelRequestedTerraformDeploymentIDs()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if tf.CancelOperation(id) {
+			r.logger.Info("cancelled", lager.Data{"deploymentID": id})
+		}
+	}
+
+	return nil
+}
+
+// Reconcile marks the operations that were left in progress as failed, so that the platform
+// stops polling them and the operation can be retried. Re-running Terraform is not safe,
+// because the resources that were created before the broker stopped are not in the state.
+// When several broker processes reconcile at the same time, each operation is only marked
+// failed by one of them. It returns the IDs of the deployments that were marked failed.
+func (r *Reconciler) Reconcile() ([]string, error) {
+	since := time.Now().Add(-r.staleAfter)
+	ids, err := r.store.GetStaleTerraformDeploymentIDs(tf.InProgress, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		failed []string
+		errs   []string
+	)
+	for _, id := range ids {
+		claimed, err := r.store.ClaimStaleTerraformDeployment(id, tf.InProgress, since)
+		switch {
+		case err != nil:
+			errs = append(errs, err.Error())
+			continue
+		case !claimed:
+			r.logger.Info("claimed-by-other-broker", lager.Data{"deploymentID": id})
+			continue
+		}
+
+		if err := r.markFailed(id); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		r.logger.Info("marked-failed", lager.Data{"deploymentID": id})
+		failed = append(failed, id)
+	}
+
+	if len(errs) > 0 {
+		return failed, fmt.Errorf("error reconciling terraform deployments: %s", strings.Join(errs, ", "))
+	}
+
+	return failed, nil
+}
+
+func (r *Reconciler) markFailed(id string) error {
+	deployment, err := r.store.GetTerraformDeployment(id)
+	if err != nil {
+		return fmt.Errorf("error reading terraform deployment %q: %w", id, err)
+	}
+
+	if err := r.deploymentManager.MarkOperationFinished(&deployment, ErrInterrupted); err != nil {
+		return fmt.Errorf("error marking terraform deployment %q as failed: %w", id, err)
+	}
+
+	return nil
+}
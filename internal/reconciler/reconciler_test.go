@@ -0,0 +1,115 @@
+package reconciler_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/reconciler"
+	"github.com/cloudfoundry/cloud-service-broker/internal/reconciler/reconcilerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		fakeStore *reconcilerfakes.FakeStore
+		r         *reconciler.Reconciler
+	)
+
+	BeforeEach(func() {
+		fakeStore = &reconcilerfakes.FakeStore{}
+		fakeStore.GetStaleTerraformDeploymentIDsReturns([]string{"tf:instance-1:", "tf:instance-2:binding-1"}, nil)
+		fakeStore.ClaimStaleTerraformDeploymentReturns(true, nil)
+		fakeStore.GetTerraformDeploymentStub = func(id string) (storage.TerraformDeployment, error) {
+			return storage.TerraformDeployment{
+				ID:                 id,
+				Workspace:          &workspace.TerraformWorkspace{},
+				LastOperationType:  "provision",
+				LastOperationState: "in progress",
+			}, nil
+		}
+
+		r = reconciler.New(fakeStore, time.Minute, utils.NewLogger("test"))
+	})
+
+	Describe("Reconcile", func() {
+		It("marks the stale operations as failed", func() {
+			failed, err := r.Reconcile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failed).To(Equal([]string{"tf:instance-1:", "tf:instance-2:binding-1"}))
+
+			Expect(fakeStore.GetStaleTerraformDeploymentIDsCallCount()).To(Equal(1))
+			state, since := fakeStore.GetStaleTerraformDeploymentIDsArgsForCall(0)
+			Expect(state).To(Equal("in progress"))
+			Expect(since).To(BeTemporally("~", time.Now().Add(-time.Minute), time.Second))
+
+			Expect(fakeStore.StoreTerraformDeploymentCallCount()).To(Equal(2))
+			stored := fakeStore.StoreTerraformDeploymentArgsForCall(0)
+			Expect(stored.ID).To(Equal("tf:instance-1:"))
+			Expect(stored.LastOperationState).To(Equal("failed"))
+			Expect(stored.LastOperationMessage).To(Equal("provision failed: " + reconciler.ErrInterrupted.Error()))
+
+			Expect(fakeStore.FinishOperationLogCallCount()).To(Equal(2))
+			deploymentID, state, _, _ := fakeStore.FinishOperationLogArgsForCall(1)
+			Expect(deploymentID).To(Equal("tf:instance-2:binding-1"))
+			Expect(state).To(Equal("failed"))
+		})
+
+		It("only marks the operations that it claims", func() {
+			fakeStore.ClaimStaleTerraformDeploymentReturnsOnCall(0, false, nil)
+
+			failed, err := r.Reconcile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failed).To(Equal([]string{"tf:instance-2:binding-1"}))
+
+			id, state, since := fakeStore.ClaimStaleTerraformDeploymentArgsForCall(0)
+			Expect(id).To(Equal("tf:instance-1:"))
+			Expect(state).To(Equal("in progress"))
+			_, staleSince := fakeStore.GetStaleTerraformDeploymentIDsArgsForCall(0)
+			Expect(since).To(Equal(staleSince))
+
+			Expect(fakeStore.StoreTerraformDeploymentCallCount()).To(Equal(1))
+			Expect(fakeStore.StoreTerraformDeploymentArgsForCall(0).ID).To(Equal("tf:instance-2:binding-1"))
+		})
+
+		It("carries on after an error, and reports it", func() {
+			fakeStore.GetTerraformDeploymentReturnsOnCall(0, storage.TerraformDeployment{}, errors.New("boom"))
+
+			failed, err := r.Reconcile()
+			Expect(err).To(MatchError(`error reconciling terraform deployments: error reading terraform deployment "tf:instance-1:": boom`))
+			Expect(failed).To(Equal([]string{"tf:instance-2:binding-1"}))
+		})
+
+		It("fails when the stale operations cannot be found", func() {
+			fakeStore.GetStaleTerraformDeploymentIDsReturns(nil, errors.New("boom"))
+
+			_, err := r.Reconcile()
+			Expect(err).To(MatchError("boom"))
+		})
+	})
+
+	Describe("Heartbeat", func() {
+		It("records a heartbeat for the operations in progress", func() {
+			Expect(r.Heartbeat()).To(Succeed())
+			Expect(fakeStore.HeartbeatTerraformDeploymentsCallCount()).To(Equal(1))
+			Expect(fakeStore.HeartbeatTerraformDeploymentsArgsForCall(0)).To(Equal("in progress"))
+		})
+
+		It("looks for operations to cancel", func() {
+			fakeStore.GetCancelRequestedTerraformDeploymentIDsReturns([]string{"tf:not-running:"}, nil)
+
+			Expect(r.Heartbeat()).To(Succeed())
+			Expect(fakeStore.GetCancelRequestedTerraformDeploymentIDsCallCount()).To(Equal(1))
+		})
+
+		It("fails when the operations to cancel cannot be found", func() {
+			fakeStore.GetCancelRequestedTerraformDeploymentIDsReturns(nil, errors.New("boom"))
+
+			Expect(r.Heartbeat()).To(MatchError("boom"))
+		})
+	})
+})
@@ -0,0 +1,1038 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package reconcilerfakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/reconciler"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+type FakeStore struct {
+	AcquireTerraformDeploymentLeaseStub        func(string, string) error
+	acquireTerraformDeploymentLeaseMutex       sync.RWMutex
+	acquireTerraformDeploymentLeaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	acquireTerraformDeploymentLeaseReturns struct {
+		result1 error
+	}
+	acquireTerraformDeploymentLeaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ClaimStaleTerraformDeploymentStub        func(string, string, time.Time) (bool, error)
+	claimStaleTerraformDeploymentMutex       sync.RWMutex
+	claimStaleTerraformDeploymentArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 time.Time
+	}
+	claimStaleTerraformDeploymentReturns struct {
+		result1 bool
+		result2 error
+	}
+	claimStaleTerraformDeploymentReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	ExistsTerraformDeploymentStub        func(string) (bool, error)
+	existsTerraformDeploymentMutex       sync.RWMutex
+	existsTerraformDeploymentArgsForCall []struct {
+		arg1 string
+	}
+	existsTerraformDeploymentReturns struct {
+		result1 bool
+		result2 error
+	}
+	existsTerraformDeploymentReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	FinishOperationLogStub        func(string, string, string, string) error
+	finishOperationLogMutex       sync.RWMutex
+	finishOperationLogArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	finishOperationLogReturns struct {
+		result1 error
+	}
+	finishOperationLogReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetCancelRequestedTerraformDeploymentIDsStub        func() ([]string, error)
+	getCancelRequestedTerraformDeploymentIDsMutex       sync.RWMutex
+	getCancelRequestedTerraformDeploymentIDsArgsForCall []struct {
+	}
+	getCancelRequestedTerraformDeploymentIDsReturns struct {
+		result1 []string
+		result2 error
+	}
+	getCancelRequestedTerraformDeploymentIDsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	GetServiceBindingIDsForServiceInstanceStub        func(string) ([]string, error)
+	getServiceBindingIDsForServiceInstanceMutex       sync.RWMutex
+	getServiceBindingIDsForServiceInstanceArgsForCall []struct {
+		arg1 string
+	}
+	getServiceBindingIDsForServiceInstanceReturns struct {
+		result1 []string
+		result2 error
+	}
+	getServiceBindingIDsForServiceInstanceReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	GetStaleTerraformDeploymentIDsStub        func(string, time.Time) ([]string, error)
+	getStaleTerraformDeploymentIDsMutex       sync.RWMutex
+	getStaleTerraformDeploymentIDsArgsForCall []struct {
+		arg1 string
+		arg2 time.Time
+	}
+	getStaleTerraformDeploymentIDsReturns struct {
+		result1 []string
+		result2 error
+	}
+	getStaleTerraformDeploymentIDsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	GetTerraformDeploymentStub        func(string) (storage.TerraformDeployment, error)
+	getTerraformDeploymentMutex       sync.RWMutex
+	getTerraformDeploymentArgsForCall []struct {
+		arg1 string
+	}
+	getTerraformDeploymentReturns struct {
+		result1 storage.TerraformDeployment
+		result2 error
+	}
+	getTerraformDeploymentReturnsOnCall map[int]struct {
+		result1 storage.TerraformDeployment
+		result2 error
+	}
+	HeartbeatTerraformDeploymentsStub        func(string) error
+	heartbeatTerraformDeploymentsMutex       sync.RWMutex
+	heartbeatTerraformDeploymentsArgsForCall []struct {
+		arg1 string
+	}
+	heartbeatTerraformDeploymentsReturns struct {
+		result1 error
+	}
+	heartbeatTerraformDeploymentsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReleaseTerraformDeploymentLeaseStub        func(string, string) error
+	releaseTerraformDeploymentLeaseMutex       sync.RWMutex
+	releaseTerraformDeploymentLeaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	releaseTerraformDeploymentLeaseReturns struct {
+		result1 error
+	}
+	releaseTerraformDeploymentLeaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StartOperationLogStub        func(storage.OperationLog) error
+	startOperationLogMutex       sync.RWMutex
+	startOperationLogArgsForCall []struct {
+		arg1 storage.OperationLog
+	}
+	startOperationLogReturns struct {
+		result1 error
+	}
+	startOperationLogReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreTerraformDeploymentStub        func(storage.TerraformDeployment) error
+	storeTerraformDeploymentMutex       sync.RWMutex
+	storeTerraformDeploymentArgsForCall []struct {
+		arg1 storage.TerraformDeployment
+	}
+	storeTerraformDeploymentReturns struct {
+		result1 error
+	}
+	storeTerraformDeploymentReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreTerraformLogStub        func(storage.TerraformLog) error
+	storeTerraformLogMutex       sync.RWMutex
+	storeTerraformLogArgsForCall []struct {
+		arg1 storage.TerraformLog
+	}
+	storeTerraformLogReturns struct {
+		result1 error
+	}
+	storeTerraformLogReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLease(arg1 string, arg2 string) error {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	ret, specificReturn := fake.acquireTerraformDeploymentLeaseReturnsOnCall[len(fake.acquireTerraformDeploymentLeaseArgsForCall)]
+	fake.acquireTerraformDeploymentLeaseArgsForCall = append(fake.acquireTerraformDeploymentLeaseArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AcquireTerraformDeploymentLeaseStub
+	fakeReturns := fake.acquireTerraformDeploymentLeaseReturns
+	fake.recordInvocation("AcquireTerraformDeploymentLease", []interface{}{arg1, arg2})
+	fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseCallCount() int {
+	fake.acquireTerraformDeploymentLeaseMutex.RLock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.RUnlock()
+	return len(fake.acquireTerraformDeploymentLeaseArgsForCall)
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseCalls(stub func(string, string) error) {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	fake.AcquireTerraformDeploymentLeaseStub = stub
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseArgsForCall(i int) (string, string) {
+	fake.acquireTerraformDeploymentLeaseMutex.RLock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.RUnlock()
+	argsForCall := fake.acquireTerraformDeploymentLeaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseReturns(result1 error) {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	fake.AcquireTerraformDeploymentLeaseStub = nil
+	fake.acquireTerraformDeploymentLeaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) AcquireTerraformDeploymentLeaseReturnsOnCall(i int, result1 error) {
+	fake.acquireTerraformDeploymentLeaseMutex.Lock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.Unlock()
+	fake.AcquireTerraformDeploymentLeaseStub = nil
+	if fake.acquireTerraformDeploymentLeaseReturnsOnCall == nil {
+		fake.acquireTerraformDeploymentLeaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.acquireTerraformDeploymentLeaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) ClaimStaleTerraformDeployment(arg1 string, arg2 string, arg3 time.Time) (bool, error) {
+	fake.claimStaleTerraformDeploymentMutex.Lock()
+	ret, specificReturn := fake.claimStaleTerraformDeploymentReturnsOnCall[len(fake.claimStaleTerraformDeploymentArgsForCall)]
+	fake.claimStaleTerraformDeploymentArgsForCall = append(fake.claimStaleTerraformDeploymentArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 time.Time
+	}{arg1, arg2, arg3})
+	stub := fake.ClaimStaleTerraformDeploymentStub
+	fakeReturns := fake.claimStaleTerraformDeploymentReturns
+	fake.recordInvocation("ClaimStaleTerraformDeployment", []interface{}{arg1, arg2, arg3})
+	fake.claimStaleTerraformDeploymentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) ClaimStaleTerraformDeploymentCallCount() int {
+	fake.claimStaleTerraformDeploymentMutex.RLock()
+	defer fake.claimStaleTerraformDeploymentMutex.RUnlock()
+	return len(fake.claimStaleTerraformDeploymentArgsForCall)
+}
+
+func (fake *FakeStore) ClaimStaleTerraformDeploymentCalls(stub func(string, string, time.Time) (bool, error)) {
+	fake.claimStaleTerraformDeploymentMutex.Lock()
+	defer fake.claimStaleTerraformDeploymentMutex.Unlock()
+	fake.ClaimStaleTerraformDeploymentStub = stub
+}
+
+func (fake *FakeStore) ClaimStaleTerraformDeploymentArgsForCall(i int) (string, string, time.Time) {
+	fake.claimStaleTerraformDeploymentMutex.RLock()
+	defer fake.claimStaleTerraformDeploymentMutex.RUnlock()
+	argsForCall := fake.claimStaleTerraformDeploymentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStore) ClaimStaleTerraformDeploymentReturns(result1 bool, result2 error) {
+	fake.claimStaleTerraformDeploymentMutex.Lock()
+	defer fake.claimStaleTerraformDeploymentMutex.Unlock()
+	fake.ClaimStaleTerraformDeploymentStub = nil
+	fake.claimStaleTerraformDeploymentReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) ClaimStaleTerraformDeploymentReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.claimStaleTerraformDeploymentMutex.Lock()
+	defer fake.claimStaleTerraformDeploymentMutex.Unlock()
+	fake.ClaimStaleTerraformDeploymentStub = nil
+	if fake.claimStaleTerraformDeploymentReturnsOnCall == nil {
+		fake.claimStaleTerraformDeploymentReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.claimStaleTerraformDeploymentReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) ExistsTerraformDeployment(arg1 string) (bool, error) {
+	fake.existsTerraformDeploymentMutex.Lock()
+	ret, specificReturn := fake.existsTerraformDeploymentReturnsOnCall[len(fake.existsTerraformDeploymentArgsForCall)]
+	fake.existsTerraformDeploymentArgsForCall = append(fake.existsTerraformDeploymentArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ExistsTerraformDeploymentStub
+	fakeReturns := fake.existsTerraformDeploymentReturns
+	fake.recordInvocation("ExistsTerraformDeployment", []interface{}{arg1})
+	fake.existsTerraformDeploymentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentCallCount() int {
+	fake.existsTerraformDeploymentMutex.RLock()
+	defer fake.existsTerraformDeploymentMutex.RUnlock()
+	return len(fake.existsTerraformDeploymentArgsForCall)
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentCalls(stub func(string) (bool, error)) {
+	fake.existsTerraformDeploymentMutex.Lock()
+	defer fake.existsTerraformDeploymentMutex.Unlock()
+	fake.ExistsTerraformDeploymentStub = stub
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentArgsForCall(i int) string {
+	fake.existsTerraformDeploymentMutex.RLock()
+	defer fake.existsTerraformDeploymentMutex.RUnlock()
+	argsForCall := fake.existsTerraformDeploymentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentReturns(result1 bool, result2 error) {
+	fake.existsTerraformDeploymentMutex.Lock()
+	defer fake.existsTerraformDeploymentMutex.Unlock()
+	fake.ExistsTerraformDeploymentStub = nil
+	fake.existsTerraformDeploymentReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) ExistsTerraformDeploymentReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.existsTerraformDeploymentMutex.Lock()
+	defer fake.existsTerraformDeploymentMutex.Unlock()
+	fake.ExistsTerraformDeploymentStub = nil
+	if fake.existsTerraformDeploymentReturnsOnCall == nil {
+		fake.existsTerraformDeploymentReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.existsTerraformDeploymentReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) FinishOperationLog(arg1 string, arg2 string, arg3 string, arg4 string) error {
+	fake.finishOperationLogMutex.Lock()
+	ret, specificReturn := fake.finishOperationLogReturnsOnCall[len(fake.finishOperationLogArgsForCall)]
+	fake.finishOperationLogArgsForCall = append(fake.finishOperationLogArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.FinishOperationLogStub
+	fakeReturns := fake.finishOperationLogReturns
+	fake.recordInvocation("FinishOperationLog", []interface{}{arg1, arg2, arg3, arg4})
+	fake.finishOperationLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) FinishOperationLogCallCount() int {
+	fake.finishOperationLogMutex.RLock()
+	defer fake.finishOperationLogMutex.RUnlock()
+	return len(fake.finishOperationLogArgsForCall)
+}
+
+func (fake *FakeStore) FinishOperationLogCalls(stub func(string, string, string, string) error) {
+	fake.finishOperationLogMutex.Lock()
+	defer fake.finishOperationLogMutex.Unlock()
+	fake.FinishOperationLogStub = stub
+}
+
+func (fake *FakeStore) FinishOperationLogArgsForCall(i int) (string, string, string, string) {
+	fake.finishOperationLogMutex.RLock()
+	defer fake.finishOperationLogMutex.RUnlock()
+	argsForCall := fake.finishOperationLogArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStore) FinishOperationLogReturns(result1 error) {
+	fake.finishOperationLogMutex.Lock()
+	defer fake.finishOperationLogMutex.Unlock()
+	fake.FinishOperationLogStub = nil
+	fake.finishOperationLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) FinishOperationLogReturnsOnCall(i int, result1 error) {
+	fake.finishOperationLogMutex.Lock()
+	defer fake.finishOperationLogMutex.Unlock()
+	fake.FinishOperationLogStub = nil
+	if fake.finishOperationLogReturnsOnCall == nil {
+		fake.finishOperationLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.finishOperationLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) GetCancelRequestedTerraformDeploymentIDs() ([]string, error) {
+	fake.getCancelRequestedTerraformDeploymentIDsMutex.Lock()
+	ret, specificReturn := fake.getCancelRequestedTerraformDeploymentIDsReturnsOnCall[len(fake.getCancelRequestedTerraformDeploymentIDsArgsForCall)]
+	fake.getCancelRequestedTerraformDeploymentIDsArgsForCall = append(fake.getCancelRequestedTerraformDeploymentIDsArgsForCall, struct {
+	}{})
+	stub := fake.GetCancelRequestedTerraformDeploymentIDsStub
+	fakeReturns := fake.getCancelRequestedTerraformDeploymentIDsReturns
+	fake.recordInvocation("GetCancelRequestedTerraformDeploymentIDs", []interface{}{})
+	fake.getCancelRequestedTerraformDeploymentIDsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) GetCancelRequestedTerraformDeploymentIDsCallCount() int {
+	fake.getCancelRequestedTerraformDeploymentIDsMutex.RLock()
+	defer fake.getCancelRequestedTerraformDeploymentIDsMutex.RUnlock()
+	return len(fake.getCancelRequestedTerraformDeploymentIDsArgsForCall)
+}
+
+func (fake *FakeStore) GetCancelRequestedTerraformDeploymentIDsCalls(stub func() ([]string, error)) {
+	fake.getCancelRequestedTerraformDeploymentIDsMutex.Lock()
+	defer fake.getCancelRequestedTerraformDeploymentIDsMutex.Unlock()
+	fake.GetCancelRequestedTerraformDeploymentIDsStub = stub
+}
+
+func (fake *FakeStore) GetCancelRequestedTerraformDeploymentIDsReturns(result1 []string, result2 error) {
+	fake.getCancelRequestedTerraformDeploymentIDsMutex.Lock()
+	defer fake.getCancelRequestedTerraformDeploymentIDsMutex.Unlock()
+	fake.GetCancelRequestedTerraformDeploymentIDsStub = nil
+	fake.getCancelRequestedTerraformDeploymentIDsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetCancelRequestedTerraformDeploymentIDsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.getCancelRequestedTerraformDeploymentIDsMutex.Lock()
+	defer fake.getCancelRequestedTerraformDeploymentIDsMutex.Unlock()
+	fake.GetCancelRequestedTerraformDeploymentIDsStub = nil
+	if fake.getCancelRequestedTerraformDeploymentIDsReturnsOnCall == nil {
+		fake.getCancelRequestedTerraformDeploymentIDsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.getCancelRequestedTerraformDeploymentIDsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstance(arg1 string) ([]string, error) {
+	fake.getServiceBindingIDsForServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.getServiceBindingIDsForServiceInstanceReturnsOnCall[len(fake.getServiceBindingIDsForServiceInstanceArgsForCall)]
+	fake.getServiceBindingIDsForServiceInstanceArgsForCall = append(fake.getServiceBindingIDsForServiceInstanceArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetServiceBindingIDsForServiceInstanceStub
+	fakeReturns := fake.getServiceBindingIDsForServiceInstanceReturns
+	fake.recordInvocation("GetServiceBindingIDsForServiceInstance", []interface{}{arg1})
+	fake.getServiceBindingIDsForServiceInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceCallCount() int {
+	fake.getServiceBindingIDsForServiceInstanceMutex.RLock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.RUnlock()
+	return len(fake.getServiceBindingIDsForServiceInstanceArgsForCall)
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceCalls(stub func(string) ([]string, error)) {
+	fake.getServiceBindingIDsForServiceInstanceMutex.Lock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.Unlock()
+	fake.GetServiceBindingIDsForServiceInstanceStub = stub
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceArgsForCall(i int) string {
+	fake.getServiceBindingIDsForServiceInstanceMutex.RLock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.RUnlock()
+	argsForCall := fake.getServiceBindingIDsForServiceInstanceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceReturns(result1 []string, result2 error) {
+	fake.getServiceBindingIDsForServiceInstanceMutex.Lock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.Unlock()
+	fake.GetServiceBindingIDsForServiceInstanceStub = nil
+	fake.getServiceBindingIDsForServiceInstanceReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetServiceBindingIDsForServiceInstanceReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.getServiceBindingIDsForServiceInstanceMutex.Lock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.Unlock()
+	fake.GetServiceBindingIDsForServiceInstanceStub = nil
+	if fake.getServiceBindingIDsForServiceInstanceReturnsOnCall == nil {
+		fake.getServiceBindingIDsForServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.getServiceBindingIDsForServiceInstanceReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetStaleTerraformDeploymentIDs(arg1 string, arg2 time.Time) ([]string, error) {
+	fake.getStaleTerraformDeploymentIDsMutex.Lock()
+	ret, specificReturn := fake.getStaleTerraformDeploymentIDsReturnsOnCall[len(fake.getStaleTerraformDeploymentIDsArgsForCall)]
+	fake.getStaleTerraformDeploymentIDsArgsForCall = append(fake.getStaleTerraformDeploymentIDsArgsForCall, struct {
+		arg1 string
+		arg2 time.Time
+	}{arg1, arg2})
+	stub := fake.GetStaleTerraformDeploymentIDsStub
+	fakeReturns := fake.getStaleTerraformDeploymentIDsReturns
+	fake.recordInvocation("GetStaleTerraformDeploymentIDs", []interface{}{arg1, arg2})
+	fake.getStaleTerraformDeploymentIDsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) GetStaleTerraformDeploymentIDsCallCount() int {
+	fake.getStaleTerraformDeploymentIDsMutex.RLock()
+	defer fake.getStaleTerraformDeploymentIDsMutex.RUnlock()
+	return len(fake.getStaleTerraformDeploymentIDsArgsForCall)
+}
+
+func (fake *FakeStore) GetStaleTerraformDeploymentIDsCalls(stub func(string, time.Time) ([]string, error)) {
+	fake.getStaleTerraformDeploymentIDsMutex.Lock()
+	defer fake.getStaleTerraformDeploymentIDsMutex.Unlock()
+	fake.GetStaleTerraformDeploymentIDsStub = stub
+}
+
+func (fake *FakeStore) GetStaleTerraformDeploymentIDsArgsForCall(i int) (string, time.Time) {
+	fake.getStaleTerraformDeploymentIDsMutex.RLock()
+	defer fake.getStaleTerraformDeploymentIDsMutex.RUnlock()
+	argsForCall := fake.getStaleTerraformDeploymentIDsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStore) GetStaleTerraformDeploymentIDsReturns(result1 []string, result2 error) {
+	fake.getStaleTerraformDeploymentIDsMutex.Lock()
+	defer fake.getStaleTerraformDeploymentIDsMutex.Unlock()
+	fake.GetStaleTerraformDeploymentIDsStub = nil
+	fake.getStaleTerraformDeploymentIDsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetStaleTerraformDeploymentIDsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.getStaleTerraformDeploymentIDsMutex.Lock()
+	defer fake.getStaleTerraformDeploymentIDsMutex.Unlock()
+	fake.GetStaleTerraformDeploymentIDsStub = nil
+	if fake.getStaleTerraformDeploymentIDsReturnsOnCall == nil {
+		fake.getStaleTerraformDeploymentIDsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.getStaleTerraformDeploymentIDsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetTerraformDeployment(arg1 string) (storage.TerraformDeployment, error) {
+	fake.getTerraformDeploymentMutex.Lock()
+	ret, specificReturn := fake.getTerraformDeploymentReturnsOnCall[len(fake.getTerraformDeploymentArgsForCall)]
+	fake.getTerraformDeploymentArgsForCall = append(fake.getTerraformDeploymentArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetTerraformDeploymentStub
+	fakeReturns := fake.getTerraformDeploymentReturns
+	fake.recordInvocation("GetTerraformDeployment", []interface{}{arg1})
+	fake.getTerraformDeploymentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) GetTerraformDeploymentCallCount() int {
+	fake.getTerraformDeploymentMutex.RLock()
+	defer fake.getTerraformDeploymentMutex.RUnlock()
+	return len(fake.getTerraformDeploymentArgsForCall)
+}
+
+func (fake *FakeStore) GetTerraformDeploymentCalls(stub func(string) (storage.TerraformDeployment, error)) {
+	fake.getTerraformDeploymentMutex.Lock()
+	defer fake.getTerraformDeploymentMutex.Unlock()
+	fake.GetTerraformDeploymentStub = stub
+}
+
+func (fake *FakeStore) GetTerraformDeploymentArgsForCall(i int) string {
+	fake.getTerraformDeploymentMutex.RLock()
+	defer fake.getTerraformDeploymentMutex.RUnlock()
+	argsForCall := fake.getTerraformDeploymentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) GetTerraformDeploymentReturns(result1 storage.TerraformDeployment, result2 error) {
+	fake.getTerraformDeploymentMutex.Lock()
+	defer fake.getTerraformDeploymentMutex.Unlock()
+	fake.GetTerraformDeploymentStub = nil
+	fake.getTerraformDeploymentReturns = struct {
+		result1 storage.TerraformDeployment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) GetTerraformDeploymentReturnsOnCall(i int, result1 storage.TerraformDeployment, result2 error) {
+	fake.getTerraformDeploymentMutex.Lock()
+	defer fake.getTerraformDeploymentMutex.Unlock()
+	fake.GetTerraformDeploymentStub = nil
+	if fake.getTerraformDeploymentReturnsOnCall == nil {
+		fake.getTerraformDeploymentReturnsOnCall = make(map[int]struct {
+			result1 storage.TerraformDeployment
+			result2 error
+		})
+	}
+	fake.getTerraformDeploymentReturnsOnCall[i] = struct {
+		result1 storage.TerraformDeployment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) HeartbeatTerraformDeployments(arg1 string) error {
+	fake.heartbeatTerraformDeploymentsMutex.Lock()
+	ret, specificReturn := fake.heartbeatTerraformDeploymentsReturnsOnCall[len(fake.heartbeatTerraformDeploymentsArgsForCall)]
+	fake.heartbeatTerraformDeploymentsArgsForCall = append(fake.heartbeatTerraformDeploymentsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.HeartbeatTerraformDeploymentsStub
+	fakeReturns := fake.heartbeatTerraformDeploymentsReturns
+	fake.recordInvocation("HeartbeatTerraformDeployments", []interface{}{arg1})
+	fake.heartbeatTerraformDeploymentsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) HeartbeatTerraformDeploymentsCallCount() int {
+	fake.heartbeatTerraformDeploymentsMutex.RLock()
+	defer fake.heartbeatTerraformDeploymentsMutex.RUnlock()
+	return len(fake.heartbeatTerraformDeploymentsArgsForCall)
+}
+
+func (fake *FakeStore) HeartbeatTerraformDeploymentsCalls(stub func(string) error) {
+	fake.heartbeatTerraformDeploymentsMutex.Lock()
+	defer fake.heartbeatTerraformDeploymentsMutex.Unlock()
+	fake.HeartbeatTerraformDeploymentsStub = stub
+}
+
+func (fake *FakeStore) HeartbeatTerraformDeploymentsArgsForCall(i int) string {
+	fake.heartbeatTerraformDeploymentsMutex.RLock()
+	defer fake.heartbeatTerraformDeploymentsMutex.RUnlock()
+	argsForCall := fake.heartbeatTerraformDeploymentsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) HeartbeatTerraformDeploymentsReturns(result1 error) {
+	fake.heartbeatTerraformDeploymentsMutex.Lock()
+	defer fake.heartbeatTerraformDeploymentsMutex.Unlock()
+	fake.HeartbeatTerraformDeploymentsStub = nil
+	fake.heartbeatTerraformDeploymentsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) HeartbeatTerraformDeploymentsReturnsOnCall(i int, result1 error) {
+	fake.heartbeatTerraformDeploymentsMutex.Lock()
+	defer fake.heartbeatTerraformDeploymentsMutex.Unlock()
+	fake.HeartbeatTerraformDeploymentsStub = nil
+	if fake.heartbeatTerraformDeploymentsReturnsOnCall == nil {
+		fake.heartbeatTerraformDeploymentsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.heartbeatTerraformDeploymentsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLease(arg1 string, arg2 string) error {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	ret, specificReturn := fake.releaseTerraformDeploymentLeaseReturnsOnCall[len(fake.releaseTerraformDeploymentLeaseArgsForCall)]
+	fake.releaseTerraformDeploymentLeaseArgsForCall = append(fake.releaseTerraformDeploymentLeaseArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ReleaseTerraformDeploymentLeaseStub
+	fakeReturns := fake.releaseTerraformDeploymentLeaseReturns
+	fake.recordInvocation("ReleaseTerraformDeploymentLease", []interface{}{arg1, arg2})
+	fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseCallCount() int {
+	fake.releaseTerraformDeploymentLeaseMutex.RLock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.RUnlock()
+	return len(fake.releaseTerraformDeploymentLeaseArgsForCall)
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseCalls(stub func(string, string) error) {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	fake.ReleaseTerraformDeploymentLeaseStub = stub
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseArgsForCall(i int) (string, string) {
+	fake.releaseTerraformDeploymentLeaseMutex.RLock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.RUnlock()
+	argsForCall := fake.releaseTerraformDeploymentLeaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseReturns(result1 error) {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	fake.ReleaseTerraformDeploymentLeaseStub = nil
+	fake.releaseTerraformDeploymentLeaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) ReleaseTerraformDeploymentLeaseReturnsOnCall(i int, result1 error) {
+	fake.releaseTerraformDeploymentLeaseMutex.Lock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.Unlock()
+	fake.ReleaseTerraformDeploymentLeaseStub = nil
+	if fake.releaseTerraformDeploymentLeaseReturnsOnCall == nil {
+		fake.releaseTerraformDeploymentLeaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseTerraformDeploymentLeaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StartOperationLog(arg1 storage.OperationLog) error {
+	fake.startOperationLogMutex.Lock()
+	ret, specificReturn := fake.startOperationLogReturnsOnCall[len(fake.startOperationLogArgsForCall)]
+	fake.startOperationLogArgsForCall = append(fake.startOperationLogArgsForCall, struct {
+		arg1 storage.OperationLog
+	}{arg1})
+	stub := fake.StartOperationLogStub
+	fakeReturns := fake.startOperationLogReturns
+	fake.recordInvocation("StartOperationLog", []interface{}{arg1})
+	fake.startOperationLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) StartOperationLogCallCount() int {
+	fake.startOperationLogMutex.RLock()
+	defer fake.startOperationLogMutex.RUnlock()
+	return len(fake.startOperationLogArgsForCall)
+}
+
+func (fake *FakeStore) StartOperationLogCalls(stub func(storage.OperationLog) error) {
+	fake.startOperationLogMutex.Lock()
+	defer fake.startOperationLogMutex.Unlock()
+	fake.StartOperationLogStub = stub
+}
+
+func (fake *FakeStore) StartOperationLogArgsForCall(i int) storage.OperationLog {
+	fake.startOperationLogMutex.RLock()
+	defer fake.startOperationLogMutex.RUnlock()
+	argsForCall := fake.startOperationLogArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) StartOperationLogReturns(result1 error) {
+	fake.startOperationLogMutex.Lock()
+	defer fake.startOperationLogMutex.Unlock()
+	fake.StartOperationLogStub = nil
+	fake.startOperationLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StartOperationLogReturnsOnCall(i int, result1 error) {
+	fake.startOperationLogMutex.Lock()
+	defer fake.startOperationLogMutex.Unlock()
+	fake.StartOperationLogStub = nil
+	if fake.startOperationLogReturnsOnCall == nil {
+		fake.startOperationLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.startOperationLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformDeployment(arg1 storage.TerraformDeployment) error {
+	fake.storeTerraformDeploymentMutex.Lock()
+	ret, specificReturn := fake.storeTerraformDeploymentReturnsOnCall[len(fake.storeTerraformDeploymentArgsForCall)]
+	fake.storeTerraformDeploymentArgsForCall = append(fake.storeTerraformDeploymentArgsForCall, struct {
+		arg1 storage.TerraformDeployment
+	}{arg1})
+	stub := fake.StoreTerraformDeploymentStub
+	fakeReturns := fake.storeTerraformDeploymentReturns
+	fake.recordInvocation("StoreTerraformDeployment", []interface{}{arg1})
+	fake.storeTerraformDeploymentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentCallCount() int {
+	fake.storeTerraformDeploymentMutex.RLock()
+	defer fake.storeTerraformDeploymentMutex.RUnlock()
+	return len(fake.storeTerraformDeploymentArgsForCall)
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentCalls(stub func(storage.TerraformDeployment) error) {
+	fake.storeTerraformDeploymentMutex.Lock()
+	defer fake.storeTerraformDeploymentMutex.Unlock()
+	fake.StoreTerraformDeploymentStub = stub
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentArgsForCall(i int) storage.TerraformDeployment {
+	fake.storeTerraformDeploymentMutex.RLock()
+	defer fake.storeTerraformDeploymentMutex.RUnlock()
+	argsForCall := fake.storeTerraformDeploymentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentReturns(result1 error) {
+	fake.storeTerraformDeploymentMutex.Lock()
+	defer fake.storeTerraformDeploymentMutex.Unlock()
+	fake.StoreTerraformDeploymentStub = nil
+	fake.storeTerraformDeploymentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformDeploymentReturnsOnCall(i int, result1 error) {
+	fake.storeTerraformDeploymentMutex.Lock()
+	defer fake.storeTerraformDeploymentMutex.Unlock()
+	fake.StoreTerraformDeploymentStub = nil
+	if fake.storeTerraformDeploymentReturnsOnCall == nil {
+		fake.storeTerraformDeploymentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeTerraformDeploymentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformLog(arg1 storage.TerraformLog) error {
+	fake.storeTerraformLogMutex.Lock()
+	ret, specificReturn := fake.storeTerraformLogReturnsOnCall[len(fake.storeTerraformLogArgsForCall)]
+	fake.storeTerraformLogArgsForCall = append(fake.storeTerraformLogArgsForCall, struct {
+		arg1 storage.TerraformLog
+	}{arg1})
+	stub := fake.StoreTerraformLogStub
+	fakeReturns := fake.storeTerraformLogReturns
+	fake.recordInvocation("StoreTerraformLog", []interface{}{arg1})
+	fake.storeTerraformLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) StoreTerraformLogCallCount() int {
+	fake.storeTerraformLogMutex.RLock()
+	defer fake.storeTerraformLogMutex.RUnlock()
+	return len(fake.storeTerraformLogArgsForCall)
+}
+
+func (fake *FakeStore) StoreTerraformLogCalls(stub func(storage.TerraformLog) error) {
+	fake.storeTerraformLogMutex.Lock()
+	defer fake.storeTerraformLogMutex.Unlock()
+	fake.StoreTerraformLogStub = stub
+}
+
+func (fake *FakeStore) StoreTerraformLogArgsForCall(i int) storage.TerraformLog {
+	fake.storeTerraformLogMutex.RLock()
+	defer fake.storeTerraformLogMutex.RUnlock()
+	argsForCall := fake.storeTerraformLogArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStore) StoreTerraformLogReturns(result1 error) {
+	fake.storeTerraformLogMutex.Lock()
+	defer fake.storeTerraformLogMutex.Unlock()
+	fake.StoreTerraformLogStub = nil
+	fake.storeTerraformLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) StoreTerraformLogReturnsOnCall(i int, result1 error) {
+	fake.storeTerraformLogMutex.Lock()
+	defer fake.storeTerraformLogMutex.Unlock()
+	fake.StoreTerraformLogStub = nil
+	if fake.storeTerraformLogReturnsOnCall == nil {
+		fake.storeTerraformLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeTerraformLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.acquireTerraformDeploymentLeaseMutex.RLock()
+	defer fake.acquireTerraformDeploymentLeaseMutex.RUnlock()
+	fake.claimStaleTerraformDeploymentMutex.RLock()
+	defer fake.claimStaleTerraformDeploymentMutex.RUnlock()
+	fake.existsTerraformDeploymentMutex.RLock()
+	defer fake.existsTerraformDeploymentMutex.RUnlock()
+	fake.finishOperationLogMutex.RLock()
+	defer fake.finishOperationLogMutex.RUnlock()
+	fake.getCancelRequestedTerraformDeploymentIDsMutex.RLock()
+	defer fake.getCancelRequestedTerraformDeploymentIDsMutex.RUnlock()
+	fake.getServiceBindingIDsForServiceInstanceMutex.RLock()
+	defer fake.getServiceBindingIDsForServiceInstanceMutex.RUnlock()
+	fake.getStaleTerraformDeploymentIDsMutex.RLock()
+	defer fake.getStaleTerraformDeploymentIDsMutex.RUnlock()
+	fake.getTerraformDeploymentMutex.RLock()
+	defer fake.getTerraformDeploymentMutex.RUnlock()
+	fake.heartbeatTerraformDeploymentsMutex.RLock()
+	defer fake.heartbeatTerraformDeploymentsMutex.RUnlock()
+	fake.releaseTerraformDeploymentLeaseMutex.RLock()
+	defer fake.releaseTerraformDeploymentLeaseMutex.RUnlock()
+	fake.startOperationLogMutex.RLock()
+	defer fake.startOperationLogMutex.RUnlock()
+	fake.storeTerraformDeploymentMutex.RLock()
+	defer fake.storeTerraformDeploymentMutex.RUnlock()
+	fake.storeTerraformLogMutex.RLock()
+	defer fake.storeTerraformLogMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ reconciler.Store = new(FakeStore)
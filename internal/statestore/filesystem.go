@@ -0,0 +1,88 @@
+package statestore
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Filesystem is a state store that holds each state in a file in a directory,
+// for example on a shared volume
+type Filesystem struct {
+	dir string
+}
+
+func NewFilesystem(dir string) (*Filesystem, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating state store directory: %w", err)
+	}
+
+	return &Filesystem{dir: dir}, nil
+}
+
+func (f *Filesystem) Put(key string, data []byte) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so that a failed write never leaves a truncated state
+	fd, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(fd.Name())
+
+	if _, err := fd.Write(data); err != nil {
+		_ = fd.Close()
+		return fmt.Errorf("error writing state %q: %w", key, err)
+	}
+	if err := fd.Close(); err != nil {
+		return fmt.Errorf("error writing state %q: %w", key, err)
+	}
+
+	if err := os.Rename(fd.Name(), path); err != nil {
+		return fmt.Errorf("error writing state %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (f *Filesystem) Get(key string) ([]byte, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading state %q: %w", key, err)
+	}
+
+	return data, nil
+}
+
+func (f *Filesystem) Delete(key string) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error deleting state %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (f *Filesystem) path(key string) (string, error) {
+	name := url.PathEscape(key)
+	switch name {
+	case "", ".", "..":
+		return "", fmt.Errorf("invalid state key: %q", key)
+	}
+
+	return filepath.Join(f.dir, name), nil
+}
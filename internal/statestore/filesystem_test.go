@@ -0,0 +1,55 @@
+package statestore_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/statestore"
+)
+
+var _ = Describe("Filesystem", func() {
+	var (
+		dir string
+		fs  *statestore.Filesystem
+	)
+
+	BeforeEach(func() {
+		dir = filepath.Join(GinkgoT().TempDir(), "states")
+
+		var err error
+		fs, err = statestore.NewFilesystem(dir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("creates the directory", func() {
+		Expect(dir).To(BeADirectory())
+	})
+
+	It("writes, reads and deletes states", func() {
+		Expect(fs.Put("tf:instance:binding", []byte("first"))).To(Succeed())
+		Expect(fs.Put("tf:instance:binding", []byte("second"))).To(Succeed())
+		Expect(fs.Get("tf:instance:binding")).To(Equal([]byte("second")))
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+
+		Expect(fs.Delete("tf:instance:binding")).To(Succeed())
+		_, err = fs.Get("tf:instance:binding")
+		Expect(err).To(MatchError(ContainSubstring(`error reading state "tf:instance:binding"`)))
+	})
+
+	It("keeps states inside the directory", func() {
+		Expect(fs.Put("../escape", []byte("data"))).To(Succeed())
+		Expect(filepath.Join(dir, "..", "escape")).NotTo(BeAnExistingFile())
+
+		Expect(fs.Put("..", []byte("data"))).To(MatchError(`invalid state key: ".."`))
+	})
+
+	It("does not fail when deleting a state that does not exist", func() {
+		Expect(fs.Delete("not-there")).To(Succeed())
+	})
+})
@@ -0,0 +1,102 @@
+package statestore_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/statestore"
+)
+
+var _ = Describe("S3", func() {
+	var (
+		server  *httptest.Server
+		objects *fakeBucket
+		s3      *statestore.S3
+	)
+
+	BeforeEach(func() {
+		objects = &fakeBucket{bucket: "states", objects: make(map[string][]byte)}
+		server = httptest.NewServer(objects)
+		DeferCleanup(server.Close)
+
+		var err error
+		s3, err = statestore.NewS3(statestore.S3Config{
+			Endpoint:        server.URL,
+			Region:          "us-east-1",
+			Bucket:          "states",
+			Prefix:          "broker/",
+			AccessKeyID:     "fake-access-key-id",
+			SecretAccessKey: "fake-secret-access-key",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("writes, reads and deletes states", func() {
+		Expect(s3.Put("tf:instance:", []byte("state"))).To(Succeed())
+		Expect(objects.objects).To(HaveKeyWithValue("broker/tf:instance:", []byte("state")))
+
+		Expect(s3.Get("tf:instance:")).To(Equal([]byte("state")))
+
+		Expect(s3.Delete("tf:instance:")).To(Succeed())
+		Expect(objects.objects).To(BeEmpty())
+	})
+
+	It("fails to read a state that does not exist", func() {
+		_, err := s3.Get("not-there")
+		Expect(err).To(MatchError(ContainSubstring(`error reading state "not-there": NoSuchKey`)))
+	})
+
+	It("requires a bucket", func() {
+		_, err := statestore.NewS3(statestore.S3Config{})
+		Expect(err).To(MatchError("a bucket must be specified for the S3 state store"))
+	})
+})
+
+// fakeBucket is a minimal stand-in for an S3-compatible server such as MinIO,
+// that supports path-style object requests for a single bucket
+type fakeBucket struct {
+	lock    sync.Mutex
+	bucket  string
+	objects map[string][]byte
+}
+
+func (f *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		http.Error(w, "unknown bucket", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		f.objects[key] = data
+	case http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
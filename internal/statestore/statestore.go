@@ -0,0 +1,47 @@
+// Package statestore implements stores for Terraform state that is held
+// outside of the broker database
+package statestore
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+const (
+	TypeDatabase   = "database"
+	TypeFilesystem = "filesystem"
+	TypeS3         = "s3"
+)
+
+type Config struct {
+	Type string
+	Path string
+	S3   S3Config
+}
+
+// New creates the configured state store. For the database type, or when no type is
+// specified, it returns nil so that the state continues to be held in the broker database.
+func New(cfg Config) (storage.StateStore, error) {
+	switch cfg.Type {
+	case "", TypeDatabase:
+		return nil, nil
+	case TypeFilesystem:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("a path must be specified for the filesystem state store")
+		}
+		fs, err := NewFilesystem(cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+		return fs, nil
+	case TypeS3:
+		s3, err := NewS3(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		return s3, nil
+	default:
+		return nil, fmt.Errorf("unknown state store type %q, must be one of %q, %q or %q", cfg.Type, TypeDatabase, TypeFilesystem, TypeS3)
+	}
+}
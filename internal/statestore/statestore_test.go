@@ -0,0 +1,37 @@
+package statestore_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/statestore"
+)
+
+var _ = Describe("New", func() {
+	It("returns no state store for the database type", func() {
+		Expect(statestore.New(statestore.Config{Type: statestore.TypeDatabase})).To(BeNil())
+		Expect(statestore.New(statestore.Config{})).To(BeNil())
+	})
+
+	It("creates a filesystem state store", func() {
+		stateStore, err := statestore.New(statestore.Config{Type: statestore.TypeFilesystem, Path: GinkgoT().TempDir()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stateStore).To(BeAssignableToTypeOf(&statestore.Filesystem{}))
+	})
+
+	It("requires a path for the filesystem state store", func() {
+		_, err := statestore.New(statestore.Config{Type: statestore.TypeFilesystem})
+		Expect(err).To(MatchError("a path must be specified for the filesystem state store"))
+	})
+
+	It("creates an S3 state store", func() {
+		stateStore, err := statestore.New(statestore.Config{Type: statestore.TypeS3, S3: statestore.S3Config{Bucket: "states", Region: "us-east-1"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stateStore).To(BeAssignableToTypeOf(&statestore.S3{}))
+	})
+
+	It("fails for an unknown type", func() {
+		_, err := statestore.New(statestore.Config{Type: "floppy"})
+		Expect(err).To(MatchError(`unknown state store type "floppy", must be one of "database", "filesystem" or "s3"`))
+	})
+})
@@ -19,6 +19,7 @@ func (s *Storage) CheckAllRecords() error {
 		s.checkAllProvisionRequestDetails,
 		s.checkAllServiceInstanceDetails,
 		s.checkAllTerraformDeployments,
+		s.checkAllTerraformDeploymentSnapshots,
 	}
 	for _, e := range checkers {
 		if err := e(); err != nil {
@@ -109,6 +110,12 @@ func (s *Storage) checkAllTerraformDeployments() (errs *multierror.Error) {
 			if err := s.decodeJSON(terraformDeploymentBatch[i].Workspace, &tfWorkspace); err != nil {
 				errs = multierror.Append(fmt.Errorf("decode error for terraform deployment %q: %w", terraformDeploymentBatch[i].ID, err), errs)
 			}
+
+			if terraformDeploymentBatch[i].StateRef != "" {
+				if _, err := s.loadState(terraformDeploymentBatch[i].StateRef, terraformDeploymentBatch[i].StateChecksum); err != nil {
+					errs = multierror.Append(fmt.Errorf("state error for terraform deployment %q: %w", terraformDeploymentBatch[i].ID, err), errs)
+				}
+			}
 		}
 
 		return nil
@@ -119,3 +126,22 @@ func (s *Storage) checkAllTerraformDeployments() (errs *multierror.Error) {
 
 	return errs
 }
+
+func (s *Storage) checkAllTerraformDeploymentSnapshots() (errs *multierror.Error) {
+	var terraformDeploymentSnapshotBatch []models.TerraformDeploymentSnapshot
+	result := s.db.FindInBatches(&terraformDeploymentSnapshotBatch, 100, func(tx *gorm.DB, batchNumber int) error {
+		for i := range terraformDeploymentSnapshotBatch {
+			m := terraformDeploymentSnapshotBatch[i]
+			if _, err := s.decodeWorkspace(m.Workspace, m.StateRef, m.StateChecksum); err != nil {
+				errs = multierror.Append(fmt.Errorf("decode error for terraform deployment %q revision %d: %w", m.DeploymentID, m.Revision, err), errs)
+			}
+		}
+
+		return nil
+	})
+	if result.Error != nil {
+		errs = multierror.Append(fmt.Errorf("error re-encoding terraform deployment snapshot: %w", result.Error), errs)
+	}
+
+	return errs
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+)
+
+// PlanCount is the number of service instances or bindings of a plan
+type PlanCount struct {
+	ServiceID string
+	PlanID    string
+	Count     int
+}
+
+// OperationCount is the number of Terraform deployments whose last operation has a type and state
+type OperationCount struct {
+	OperationType  string
+	OperationState string
+	Count          int
+}
+
+// CountServiceInstancesByPlan returns the number of service instances of each plan
+func (s *Storage) CountServiceInstancesByPlan() ([]PlanCount, error) {
+	var result []PlanCount
+	if err := s.db.Model(&models.ServiceInstanceDetails{}).
+		Select("service_id, plan_id, count(*) as count").
+		Group("service_id, plan_id").
+		Order("service_id, plan_id").
+		Scan(&result).Error; err != nil {
+		return nil, fmt.Errorf("error counting service instances: %w", err)
+	}
+
+	return result, nil
+}
+
+// CountServiceBindingsByPlan returns the number of service bindings of each plan
+func (s *Storage) CountServiceBindingsByPlan() ([]PlanCount, error) {
+	var result []PlanCount
+	if err := s.db.Model(&models.ServiceBindingCredentials{}).
+		Select("service_instance_details.service_id, service_instance_details.plan_id, count(*) as count").
+		Joins("JOIN service_instance_details ON service_instance_details.id = service_binding_credentials.service_instance_id").
+		Group("service_instance_details.service_id, service_instance_details.plan_id").
+		Order("service_instance_details.service_id, service_instance_details.plan_id").
+		Scan(&result).Error; err != nil {
+		return nil, fmt.Errorf("error counting service bindings: %w", err)
+	}
+
+	return result, nil
+}
+
+// CountTerraformDeploymentsByLastOperation returns the number of Terraform deployments
+// with each type and state of last operation
+func (s *Storage) CountTerraformDeploymentsByLastOperation() ([]OperationCount, error) {
+	var result []OperationCount
+	if err := s.db.Model(&models.TerraformDeployment{}).
+		Select("last_operation_type as operation_type, last_operation_state as operation_state, count(*) as count").
+		Group("last_operation_type, last_operation_state").
+		Order("last_operation_type, last_operation_state").
+		Scan(&result).Error; err != nil {
+		return nil, fmt.Errorf("error counting terraform deployments: %w", err)
+	}
+
+	return result, nil
+}
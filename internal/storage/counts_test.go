@@ -0,0 +1,66 @@
+package storage_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+var _ = Describe("Counts", func() {
+	BeforeEach(func() {
+		for _, m := range []models.ServiceInstanceDetails{
+			{ID: "fake-instance-1", ServiceID: "fake-service-id", PlanID: "fake-plan-1"},
+			{ID: "fake-instance-2", ServiceID: "fake-service-id", PlanID: "fake-plan-1"},
+			{ID: "fake-instance-3", ServiceID: "fake-service-id", PlanID: "fake-plan-2"},
+		} {
+			Expect(db.Create(&m).Error).NotTo(HaveOccurred())
+		}
+
+		for _, m := range []models.ServiceBindingCredentials{
+			{ServiceInstanceID: "fake-instance-1", BindingID: "fake-binding-1"},
+			{ServiceInstanceID: "fake-instance-2", BindingID: "fake-binding-2"},
+			{ServiceInstanceID: "fake-instance-3", BindingID: "fake-binding-3"},
+		} {
+			Expect(db.Create(&m).Error).NotTo(HaveOccurred())
+		}
+
+		for _, m := range []models.TerraformDeployment{
+			{ID: "tf:fake-instance-1:", LastOperationType: "provision", LastOperationState: "succeeded"},
+			{ID: "tf:fake-instance-2:", LastOperationType: "provision", LastOperationState: "succeeded"},
+			{ID: "tf:fake-instance-3:", LastOperationType: "update", LastOperationState: "in progress"},
+		} {
+			Expect(db.Create(&m).Error).NotTo(HaveOccurred())
+		}
+	})
+
+	Describe("CountServiceInstancesByPlan", func() {
+		It("counts the service instances of each plan", func() {
+			Expect(store.CountServiceInstancesByPlan()).To(Equal([]storage.PlanCount{
+				{ServiceID: "fake-service-id", PlanID: "fake-plan-1", Count: 2},
+				{ServiceID: "fake-service-id", PlanID: "fake-plan-2", Count: 1},
+			}))
+		})
+	})
+
+	Describe("CountServiceBindingsByPlan", func() {
+		It("counts the service bindings of each plan", func() {
+			Expect(store.DeleteServiceBindingCredentials("fake-binding-2", "fake-instance-2")).To(Succeed())
+
+			Expect(store.CountServiceBindingsByPlan()).To(Equal([]storage.PlanCount{
+				{ServiceID: "fake-service-id", PlanID: "fake-plan-1", Count: 1},
+				{ServiceID: "fake-service-id", PlanID: "fake-plan-2", Count: 1},
+			}))
+		})
+	})
+
+	Describe("CountTerraformDeploymentsByLastOperation", func() {
+		It("counts the terraform deployments with each type and state of last operation", func() {
+			Expect(store.CountTerraformDeploymentsByLastOperation()).To(Equal([]storage.OperationCount{
+				{OperationType: "provision", OperationState: "succeeded", Count: 2},
+				{OperationType: "update", OperationState: "in progress", Count: 1},
+			}))
+		})
+	})
+})
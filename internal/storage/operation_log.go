@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+)
+
+// OperationLog records an operation on a Terraform deployment. Unlike the last
+// operation of a TerraformDeployment, it is kept after the next operation starts.
+type OperationLog struct {
+	DeploymentID        string         `json:"deployment_id"`
+	OperationType       string         `json:"operation_type"`
+	State               string         `json:"state"`
+	Message             string         `json:"message"`
+	CorrelationID       string         `json:"correlation_id,omitempty"`
+	RequestID           string         `json:"request_id,omitempty"`
+	OriginatingIdentity map[string]any `json:"originating_identity,omitempty"`
+	Stderr              string         `json:"stderr,omitempty"`
+	StartedAt           time.Time      `json:"started_at"`
+	FinishedAt          *time.Time     `json:"finished_at,omitempty"`
+}
+
+// StartOperationLog records the start of an operation. The originating identity and the
+// Terraform error output are encrypted, because they can contain personal data and the
+// values of sensitive attributes.
+func (s *Storage) StartOperationLog(l OperationLog) error {
+	var identity []byte
+	if l.OriginatingIdentity != nil {
+		var err error
+		identity, err = s.encodeJSON(l.OriginatingIdentity)
+		if err != nil {
+			return fmt.Errorf("error encoding originating identity: %w", err)
+		}
+	}
+
+	stderr, err := s.encodeStderr(l.Stderr)
+	if err != nil {
+		return err
+	}
+
+	m := models.OperationLog{
+		DeploymentID:        l.DeploymentID,
+		OperationType:       l.OperationType,
+		State:               l.State,
+		Message:             l.Message,
+		CorrelationID:       l.CorrelationID,
+		RequestID:           l.RequestID,
+		OriginatingIdentity: identity,
+		Stderr:              stderr,
+		StartedAt:           l.StartedAt,
+		FinishedAt:          l.FinishedAt,
+	}
+	if m.StartedAt.IsZero() {
+		m.StartedAt = time.Now()
+	}
+	if err := s.db.Create(&m).Error; err != nil {
+		return fmt.Errorf("error creating operation log: %w", err)
+	}
+
+	return nil
+}
+
+// FinishOperationLog records the outcome of the latest unfinished operation on a
+// Terraform deployment. It does nothing when there is no unfinished operation, for
+// instance when the operation was started before operation logs were introduced.
+func (s *Storage) FinishOperationLog(deploymentID, state, message, stderr string) error {
+	var m models.OperationLog
+	err := s.db.Where("deployment_id = ? AND finished_at IS NULL", deploymentID).Order("id desc").Limit(1).Find(&m).Error
+	switch {
+	case err != nil:
+		return fmt.Errorf("error finding operation log: %w", err)
+	case m.ID == 0:
+		return nil
+	}
+
+	encodedStderr, err := s.encodeStderr(stderr)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	m.State = state
+	m.Message = message
+	m.Stderr = encodedStderr
+	m.FinishedAt = &now
+	if err := s.db.Save(&m).Error; err != nil {
+		return fmt.Errorf("error saving operation log: %w", err)
+	}
+
+	return nil
+}
+
+// GetOperationLogs returns the operations on a service instance and its bindings, newest first
+func (s *Storage) GetOperationLogs(serviceInstanceID string) ([]OperationLog, error) {
+	prefix := fmt.Sprintf("tf:%s:", serviceInstanceID)
+
+	var receiver []models.OperationLog
+	if err := s.db.Where("deployment_id LIKE ?", prefix+"%").Order("id desc").Find(&receiver).Error; err != nil {
+		return nil, fmt.Errorf("error finding operation logs: %w", err)
+	}
+
+	result := make([]OperationLog, 0, len(receiver))
+	for _, m := range receiver {
+		// LIKE treats "_" and "%" in the service instance ID as wildcards
+		if !strings.HasPrefix(m.DeploymentID, prefix) {
+			continue
+		}
+
+		var identity map[string]any
+		if len(m.OriginatingIdentity) > 0 {
+			if err := s.decodeJSON(m.OriginatingIdentity, &identity); err != nil {
+				return nil, fmt.Errorf("error decoding originating identity for %q: %w", m.DeploymentID, err)
+			}
+		}
+
+		var stderr []byte
+		if len(m.Stderr) > 0 {
+			var err error
+			stderr, err = s.decodeBytes(m.Stderr)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding stderr for %q: %w", m.DeploymentID, err)
+			}
+		}
+
+		result = append(result, OperationLog{
+			DeploymentID:        m.DeploymentID,
+			OperationType:       m.OperationType,
+			State:               m.State,
+			Message:             m.Message,
+			CorrelationID:       m.CorrelationID,
+			RequestID:           m.RequestID,
+			OriginatingIdentity: identity,
+			Stderr:              string(stderr),
+			StartedAt:           m.StartedAt,
+			FinishedAt:          m.FinishedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// encodeStderr encrypts the Terraform error output. Operations without error output
+// are stored without it, so that they do not need to be decrypted.
+func (s *Storage) encodeStderr(stderr string) ([]byte, error) {
+	if stderr == "" {
+		return nil, nil
+	}
+
+	encoded, err := s.encodeBytes([]byte(stderr))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding stderr: %w", err)
+	}
+
+	return encoded, nil
+}
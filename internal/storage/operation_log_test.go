@@ -0,0 +1,114 @@
+package storage_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+var _ = Describe("OperationLogs", func() {
+	start := func(deploymentID, operationType string) {
+		Expect(store.StartOperationLog(storage.OperationLog{
+			DeploymentID:  deploymentID,
+			OperationType: operationType,
+			State:         "in progress",
+			Message:       operationType + " in progress",
+			CorrelationID: "fake-correlation-id",
+			RequestID:     "fake-request-id",
+			OriginatingIdentity: map[string]any{
+				"platform": "cloudfoundry",
+				"value":    map[string]any{"user_id": "fake-user"},
+			},
+		})).To(Succeed())
+	}
+
+	Describe("StartOperationLog", func() {
+		It("records an unfinished operation", func() {
+			start("tf:fake-instance-id:", "provision")
+
+			logs, err := store.GetOperationLogs("fake-instance-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logs).To(HaveLen(1))
+			Expect(logs[0].DeploymentID).To(Equal("tf:fake-instance-id:"))
+			Expect(logs[0].OperationType).To(Equal("provision"))
+			Expect(logs[0].State).To(Equal("in progress"))
+			Expect(logs[0].Message).To(Equal("provision in progress"))
+			Expect(logs[0].CorrelationID).To(Equal("fake-correlation-id"))
+			Expect(logs[0].RequestID).To(Equal("fake-request-id"))
+			Expect(logs[0].OriginatingIdentity).To(Equal(map[string]any{
+				"decrypted": map[string]any{
+					"encrypted": map[string]any{
+						"platform": "cloudfoundry",
+						"value":    map[string]any{"user_id": "fake-user"},
+					},
+				},
+			}))
+			Expect(logs[0].StartedAt).NotTo(BeZero())
+			Expect(logs[0].FinishedAt).To(BeNil())
+		})
+
+		It("encrypts the originating identity", func() {
+			start("tf:fake-instance-id:", "provision")
+
+			var receiver []models.OperationLog
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver).To(HaveLen(1))
+			Expect(receiver[0].OriginatingIdentity).To(MatchJSON(`{"encrypted":{"platform":"cloudfoundry","value":{"user_id":"fake-user"}}}`))
+			Expect(receiver[0].Stderr).To(BeEmpty())
+		})
+	})
+
+	Describe("FinishOperationLog", func() {
+		It("records the outcome of the latest unfinished operation", func() {
+			start("tf:fake-instance-id:", "provision")
+			Expect(store.FinishOperationLog("tf:fake-instance-id:", "failed", "provision failed: boom", "Error: boom")).To(Succeed())
+			start("tf:fake-instance-id:", "update")
+			Expect(store.FinishOperationLog("tf:fake-instance-id:", "succeeded", "update succeeded", "")).To(Succeed())
+
+			logs, err := store.GetOperationLogs("fake-instance-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logs).To(HaveLen(2))
+
+			Expect(logs[0].OperationType).To(Equal("update"))
+			Expect(logs[0].State).To(Equal("succeeded"))
+			Expect(logs[0].FinishedAt).NotTo(BeNil())
+
+			Expect(logs[1].OperationType).To(Equal("provision"))
+			Expect(logs[1].State).To(Equal("failed"))
+			Expect(logs[1].Message).To(Equal("provision failed: boom"))
+			Expect(logs[1].Stderr).To(Equal(`{"decrypted":{"encrypted":Error: boom}}`))
+			Expect(logs[1].FinishedAt).NotTo(BeNil())
+		})
+
+		It("encrypts the error output", func() {
+			start("tf:fake-instance-id:", "provision")
+			Expect(store.FinishOperationLog("tf:fake-instance-id:", "failed", "provision failed: boom", "Error: boom")).To(Succeed())
+
+			var receiver []models.OperationLog
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver).To(HaveLen(1))
+			Expect(string(receiver[0].Stderr)).To(Equal(`{"encrypted":Error: boom}`))
+		})
+
+		It("does nothing when there is no unfinished operation", func() {
+			Expect(store.FinishOperationLog("tf:fake-instance-id:", "succeeded", "provision succeeded", "")).To(Succeed())
+			Expect(store.GetOperationLogs("fake-instance-id")).To(BeEmpty())
+		})
+	})
+
+	Describe("GetOperationLogs", func() {
+		It("returns the operations on the service instance and its bindings", func() {
+			start("tf:fake-instance-id:", "provision")
+			start("tf:fake-instance-id:fake-binding-id", "bind")
+			start("tf:other-instance-id:", "provision")
+
+			logs, err := store.GetOperationLogs("fake-instance-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logs).To(HaveLen(2))
+			Expect(logs[0].DeploymentID).To(Equal("tf:fake-instance-id:fake-binding-id"))
+			Expect(logs[1].DeploymentID).To(Equal("tf:fake-instance-id:"))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+)
+
+// WithOperationOwner returns a Storage that records the specified owner on the Terraform
+// deployments that it stores. The owner should identify the broker process, so that
+// operations left in progress by a broker process that has stopped can be recognised.
+func (s *Storage) WithOperationOwner(owner string) *Storage {
+	c := *s
+	c.operationOwner = owner
+	return &c
+}
+
+// HeartbeatTerraformDeployments records that the owner is still running the operations
+// on the Terraform deployments that it owns and that have the specified operation state,
+// and renews the leases that it holds on them
+func (s *Storage) HeartbeatTerraformDeployments(state string) error {
+	err := s.db.Model(&models.TerraformDeployment{}).
+		Where("operation_owner = ? AND last_operation_state = ?", s.operationOwner, state).
+		UpdateColumn("operation_heartbeat_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("error updating terraform deployment heartbeats: %w", err)
+	}
+
+	return s.renewTerraformDeploymentLeases(state)
+}
+
+// GetStaleTerraformDeploymentIDs returns the IDs of the Terraform deployments that have the
+// specified operation state and whose owner has not recorded a heartbeat since the specified
+// time. Deployments stored before heartbeats were recorded are judged by when they were updated.
+func (s *Storage) GetStaleTerraformDeploymentIDs(state string, since time.Time) ([]string, error) {
+	var ids []string
+	err := s.db.Model(&models.TerraformDeployment{}).
+		Where("last_operation_state = ? AND COALESCE(operation_heartbeat_at, updated_at) < ?", state, since).
+		Order("id").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding stale terraform deployments: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ClaimStaleTerraformDeployment makes this Storage the owner of a Terraform deployment if it is
+// still stale. Only one of several broker processes that claim the same deployment succeeds.
+func (s *Storage) ClaimStaleTerraformDeployment(id, state string, since time.Time) (bool, error) {
+	result := s.db.Model(&models.TerraformDeployment{}).
+		Where("id = ? AND last_operation_state = ? AND COALESCE(operation_heartbeat_at, updated_at) < ?", id, state, since).
+		UpdateColumns(map[string]any{
+			"operation_owner":        s.operationOwner,
+			"operation_heartbeat_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("error claiming terraform deployment %q: %w", id, result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
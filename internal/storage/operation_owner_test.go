@@ -0,0 +1,105 @@
+package storage_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+)
+
+var _ = Describe("OperationOwner", func() {
+	var owner, other *storage.Storage
+
+	BeforeEach(func() {
+		owner = store.WithOperationOwner("fake-owner")
+		other = store.WithOperationOwner("fake-other-owner")
+	})
+
+	storeDeployment := func(s *storage.Storage, id, state string) {
+		Expect(s.StoreTerraformDeployment(storage.TerraformDeployment{
+			ID:                 id,
+			Workspace:          &workspace.TerraformWorkspace{},
+			LastOperationType:  "provision",
+			LastOperationState: state,
+		})).To(Succeed())
+	}
+
+	setHeartbeat := func(id string, t time.Time) {
+		Expect(db.Model(&models.TerraformDeployment{}).Where("id = ?", id).UpdateColumn("operation_heartbeat_at", t).Error).To(Succeed())
+	}
+
+	getModel := func(id string) models.TerraformDeployment {
+		var m models.TerraformDeployment
+		Expect(db.Where("id = ?", id).First(&m).Error).To(Succeed())
+		return m
+	}
+
+	Describe("StoreTerraformDeployment", func() {
+		It("records the owner and a heartbeat", func() {
+			storeDeployment(owner, "fake-id", "in progress")
+
+			m := getModel("fake-id")
+			Expect(m.OperationOwner).To(Equal("fake-owner"))
+			Expect(m.OperationHeartbeatAt).To(PointTo(BeTemporally("~", time.Now(), time.Second)))
+		})
+	})
+
+	Describe("HeartbeatTerraformDeployments", func() {
+		It("only refreshes the deployments with the owner and state", func() {
+			storeDeployment(owner, "fake-owned", "in progress")
+			storeDeployment(owner, "fake-owned-succeeded", "succeeded")
+			storeDeployment(other, "fake-other", "in progress")
+
+			old := time.Now().Add(-time.Hour)
+			for _, id := range []string{"fake-owned", "fake-owned-succeeded", "fake-other"} {
+				setHeartbeat(id, old)
+			}
+
+			Expect(owner.HeartbeatTerraformDeployments("in progress")).To(Succeed())
+
+			Expect(getModel("fake-owned").OperationHeartbeatAt).To(PointTo(BeTemporally("~", time.Now(), time.Second)))
+			Expect(getModel("fake-owned-succeeded").OperationHeartbeatAt).To(PointTo(BeTemporally("~", old, time.Second)))
+			Expect(getModel("fake-other").OperationHeartbeatAt).To(PointTo(BeTemporally("~", old, time.Second)))
+		})
+	})
+
+	Describe("GetStaleTerraformDeploymentIDs and ClaimStaleTerraformDeployment", func() {
+		BeforeEach(func() {
+			storeDeployment(other, "fake-stale", "in progress")
+			storeDeployment(other, "fake-live", "in progress")
+			storeDeployment(other, "fake-stale-succeeded", "succeeded")
+
+			setHeartbeat("fake-stale", time.Now().Add(-time.Hour))
+			setHeartbeat("fake-stale-succeeded", time.Now().Add(-time.Hour))
+		})
+
+		It("finds the deployments without a recent heartbeat", func() {
+			Expect(owner.GetStaleTerraformDeploymentIDs("in progress", time.Now().Add(-time.Minute))).To(Equal([]string{"fake-stale"}))
+		})
+
+		It("judges deployments without a heartbeat by when they were updated", func() {
+			Expect(db.Model(&models.TerraformDeployment{}).Where("id = ?", "fake-live").UpdateColumns(map[string]any{
+				"operation_heartbeat_at": nil,
+				"updated_at":             time.Now().Add(-time.Hour),
+			}).Error).To(Succeed())
+
+			Expect(owner.GetStaleTerraformDeploymentIDs("in progress", time.Now().Add(-time.Minute))).To(Equal([]string{"fake-live", "fake-stale"}))
+		})
+
+		It("lets only one owner claim a stale deployment", func() {
+			since := time.Now().Add(-time.Minute)
+
+			Expect(owner.ClaimStaleTerraformDeployment("fake-stale", "in progress", since)).To(BeTrue())
+			Expect(other.ClaimStaleTerraformDeployment("fake-stale", "in progress", since)).To(BeFalse())
+			Expect(owner.ClaimStaleTerraformDeployment("fake-live", "in progress", since)).To(BeFalse())
+
+			Expect(getModel("fake-stale").OperationOwner).To(Equal("fake-owner"))
+			Expect(getModel("fake-live").OperationOwner).To(Equal("fake-other-owner"))
+		})
+	})
+})
@@ -0,0 +1,349 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+)
+
+// RecordSet is a copy of every record in the database, with the encrypted fields decrypted.
+// It is used to move the broker state from one database to another.
+type RecordSet struct {
+	ServiceInstanceDetails       []models.ServiceInstanceDetails      `json:"service_instance_details"`
+	ProvisionRequestDetails      []models.ProvisionRequestDetails     `json:"provision_request_details"`
+	BindRequestDetails           []models.BindRequestDetails          `json:"bind_request_details"`
+	ServiceBindingCredentials    []models.ServiceBindingCredentials   `json:"service_binding_credentials"`
+	TerraformDeployments         []models.TerraformDeployment         `json:"terraform_deployments"`
+	TerraformDrifts              []models.TerraformDrift              `json:"terraform_drifts"`
+	TerraformDeploymentSnapshots []models.TerraformDeploymentSnapshot `json:"terraform_deployment_snapshots"`
+	OperationLogs                []models.OperationLog                `json:"operation_logs"`
+	TerraformLogs                []models.TerraformLog                `json:"terraform_logs"`
+}
+
+// ExportAllRecords reads every record, decrypting the encrypted fields
+func (s *Storage) ExportAllRecords() (RecordSet, error) {
+	var r RecordSet
+
+	if err := s.db.Find(&r.ServiceInstanceDetails).Error; err != nil {
+		return RecordSet{}, fmt.Errorf("error reading service instance details: %w", err)
+	}
+	for i := range r.ServiceInstanceDetails {
+		data, err := s.decodeBytes(r.ServiceInstanceDetails[i].OtherDetails)
+		if err != nil {
+			return RecordSet{}, fmt.Errorf("decode error for service instance details %q: %w", r.ServiceInstanceDetails[i].ID, err)
+		}
+		r.ServiceInstanceDetails[i].OtherDetails = data
+	}
+
+	if err := s.db.Find(&r.ProvisionRequestDetails).Error; err != nil {
+		return RecordSet{}, fmt.Errorf("error reading provision request details: %w", err)
+	}
+	for i := range r.ProvisionRequestDetails {
+		data, err := s.decodeBytes(r.ProvisionRequestDetails[i].RequestDetails)
+		if err != nil {
+			return RecordSet{}, fmt.Errorf("decode error for provision request details %q: %w", r.ProvisionRequestDetails[i].ServiceInstanceID, err)
+		}
+		r.ProvisionRequestDetails[i].RequestDetails = data
+	}
+
+	if err := s.db.Find(&r.BindRequestDetails).Error; err != nil {
+		return RecordSet{}, fmt.Errorf("error reading bind request details: %w", err)
+	}
+	for i := range r.BindRequestDetails {
+		data, err := s.decodeBytes(r.BindRequestDetails[i].RequestDetails)
+		if err != nil {
+			return RecordSet{}, fmt.Errorf("decode error for bind request details %q: %w", r.BindRequestDetails[i].ServiceBindingID, err)
+		}
+		r.BindRequestDetails[i].RequestDetails = data
+	}
+
+	if err := s.db.Find(&r.ServiceBindingCredentials).Error; err != nil {
+		return RecordSet{}, fmt.Errorf("error reading service binding credentials: %w", err)
+	}
+	for i := range r.ServiceBindingCredentials {
+		data, err := s.decodeBytes(r.ServiceBindingCredentials[i].OtherDetails)
+		if err != nil {
+			return RecordSet{}, fmt.Errorf("decode error for service binding credentials %q: %w", r.ServiceBindingCredentials[i].BindingID, err)
+		}
+		r.ServiceBindingCredentials[i].OtherDetails = data
+	}
+
+	if err := s.db.Find(&r.TerraformDeployments).Error; err != nil {
+		return RecordSet{}, fmt.Errorf("error reading terraform deployments: %w", err)
+	}
+	for i := range r.TerraformDeployments {
+		data, err := s.decodeBytes(r.TerraformDeployments[i].Workspace)
+		if err != nil {
+			return RecordSet{}, fmt.Errorf("decode error for terraform deployment %q: %w", r.TerraformDeployments[i].ID, err)
+		}
+		r.TerraformDeployments[i].Workspace = data
+
+		if r.TerraformDeployments[i].StateRef != "" {
+			r.TerraformDeployments[i].Workspace, err = s.inlineState(data, r.TerraformDeployments[i].StateRef, r.TerraformDeployments[i].StateChecksum)
+			if err != nil {
+				return RecordSet{}, fmt.Errorf("state error for terraform deployment %q: %w", r.TerraformDeployments[i].ID, err)
+			}
+			r.TerraformDeployments[i].StateRef = ""
+			r.TerraformDeployments[i].StateChecksum = ""
+		}
+	}
+
+	if err := s.db.Find(&r.TerraformDrifts).Error; err != nil {
+		return RecordSet{}, fmt.Errorf("error reading terraform drifts: %w", err)
+	}
+
+	if err := s.db.Find(&r.TerraformDeploymentSnapshots).Error; err != nil {
+		return RecordSet{}, fmt.Errorf("error reading terraform deployment snapshots: %w", err)
+	}
+	for i := range r.TerraformDeploymentSnapshots {
+		data, err := s.decodeBytes(r.TerraformDeploymentSnapshots[i].Workspace)
+		if err != nil {
+			return RecordSet{}, fmt.Errorf("decode error for terraform deployment %q revision %d: %w", r.TerraformDeploymentSnapshots[i].DeploymentID, r.TerraformDeploymentSnapshots[i].Revision, err)
+		}
+		r.TerraformDeploymentSnapshots[i].Workspace = data
+
+		if r.TerraformDeploymentSnapshots[i].StateRef != "" {
+			r.TerraformDeploymentSnapshots[i].Workspace, err = s.inlineState(data, r.TerraformDeploymentSnapshots[i].StateRef, r.TerraformDeploymentSnapshots[i].StateChecksum)
+			if err != nil {
+				return RecordSet{}, fmt.Errorf("state error for terraform deployment %q revision %d: %w", r.TerraformDeploymentSnapshots[i].DeploymentID, r.TerraformDeploymentSnapshots[i].Revision, err)
+			}
+			r.TerraformDeploymentSnapshots[i].StateRef = ""
+			r.TerraformDeploymentSnapshots[i].StateChecksum = ""
+		}
+	}
+
+	if err := s.db.Find(&r.OperationLogs).Error; err != nil {
+		return RecordSet{}, fmt.Errorf("error reading operation logs: %w", err)
+	}
+	for i := range r.OperationLogs {
+		for _, field := range []*[]byte{&r.OperationLogs[i].OriginatingIdentity, &r.OperationLogs[i].Stderr} {
+			if len(*field) == 0 {
+				continue
+			}
+			data, err := s.decodeBytes(*field)
+			if err != nil {
+				return RecordSet{}, fmt.Errorf("decode error for operation log %q: %w", r.OperationLogs[i].DeploymentID, err)
+			}
+			*field = data
+		}
+	}
+
+	if err := s.db.Find(&r.TerraformLogs).Error; err != nil {
+		return RecordSet{}, fmt.Errorf("error reading terraform logs: %w", err)
+	}
+	for i := range r.TerraformLogs {
+		for _, output := range []*[]byte{&r.TerraformLogs[i].Stdout, &r.TerraformLogs[i].Stderr, &r.TerraformLogs[i].TFLog} {
+			data, err := s.decodeBytes(*output)
+			if err != nil {
+				return RecordSet{}, fmt.Errorf("decode error for terraform log %q: %w", r.TerraformLogs[i].DeploymentID, err)
+			}
+			*output = data
+		}
+	}
+
+	return r, nil
+}
+
+// ImportAllRecords writes every record, encrypting the encrypted fields with the
+// current encryptor. It refuses to import into a database that already holds broker
+// state. The password metadata of the exporting broker is not part of the records,
+// because the database encryption is governed by the configuration of the importing broker.
+func (s *Storage) ImportAllRecords(r RecordSet) error {
+	empty, err := s.isEmpty()
+	switch {
+	case err != nil:
+		return err
+	case !empty:
+		return errors.New("the database already contains service instances, bindings or terraform deployments")
+	}
+
+	// The states written to the StateStore are only referred to when the transaction commits
+	var written []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for _, m := range r.ServiceInstanceDetails {
+			encoded, err := s.encodeBytes(m.OtherDetails)
+			if err != nil {
+				return fmt.Errorf("encode error for service instance details %q: %w", m.ID, err)
+			}
+			m.OtherDetails = encoded
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("error creating service instance details %q: %w", m.ID, err)
+			}
+		}
+
+		for _, m := range r.ProvisionRequestDetails {
+			encoded, err := s.encodeBytes(m.RequestDetails)
+			if err != nil {
+				return fmt.Errorf("encode error for provision request details %q: %w", m.ServiceInstanceID, err)
+			}
+			m.ID = 0
+			m.RequestDetails = encoded
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("error creating provision request details %q: %w", m.ServiceInstanceID, err)
+			}
+		}
+
+		for _, m := range r.BindRequestDetails {
+			encoded, err := s.encodeBytes(m.RequestDetails)
+			if err != nil {
+				return fmt.Errorf("encode error for bind request details %q: %w", m.ServiceBindingID, err)
+			}
+			m.ID = 0
+			m.RequestDetails = encoded
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("error creating bind request details %q: %w", m.ServiceBindingID, err)
+			}
+		}
+
+		for _, m := range r.ServiceBindingCredentials {
+			encoded, err := s.encodeBytes(m.OtherDetails)
+			if err != nil {
+				return fmt.Errorf("encode error for service binding credentials %q: %w", m.BindingID, err)
+			}
+			m.ID = 0
+			m.OtherDetails = encoded
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("error creating service binding credentials %q: %w", m.BindingID, err)
+			}
+		}
+
+		for _, m := range r.TerraformDeployments {
+			encoded, ref, checksum, err := s.importWorkspace(m.ID, m.Workspace)
+			if err != nil {
+				return fmt.Errorf("encode error for terraform deployment %q: %w", m.ID, err)
+			}
+			written = append(written, ref)
+			m.Workspace = encoded
+			m.StateRef = ref
+			m.StateChecksum = checksum
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("error creating terraform deployment %q: %w", m.ID, err)
+			}
+		}
+
+		for _, m := range r.TerraformDrifts {
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("error creating terraform drift %q: %w", m.ID, err)
+			}
+		}
+
+		for _, m := range r.TerraformDeploymentSnapshots {
+			encoded, ref, checksum, err := s.importWorkspace(snapshotKey(m.DeploymentID, m.Revision), m.Workspace)
+			if err != nil {
+				return fmt.Errorf("encode error for terraform deployment %q revision %d: %w", m.DeploymentID, m.Revision, err)
+			}
+			written = append(written, ref)
+			m.ID = 0
+			m.Workspace = encoded
+			m.StateRef = ref
+			m.StateChecksum = checksum
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("error creating terraform deployment snapshot %q revision %d: %w", m.DeploymentID, m.Revision, err)
+			}
+		}
+
+		for _, m := range r.OperationLogs {
+			for _, field := range []*[]byte{&m.OriginatingIdentity, &m.Stderr} {
+				if len(*field) == 0 {
+					continue
+				}
+				encoded, err := s.encodeBytes(*field)
+				if err != nil {
+					return fmt.Errorf("encode error for operation log %q: %w", m.DeploymentID, err)
+				}
+				*field = encoded
+			}
+			m.ID = 0
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("error creating operation log %q: %w", m.DeploymentID, err)
+			}
+		}
+
+		for _, m := range r.TerraformLogs {
+			for _, output := range []*[]byte{&m.Stdout, &m.Stderr, &m.TFLog} {
+				encoded, err := s.encodeBytes(*output)
+				if err != nil {
+					return fmt.Errorf("encode error for terraform log %q: %w", m.DeploymentID, err)
+				}
+				*output = encoded
+			}
+			m.ID = 0
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("error creating terraform log %q: %w", m.DeploymentID, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		for _, ref := range written {
+			s.releaseState(ref, "")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// inlineState moves the state from the StateStore into a decrypted workspace,
+// so that the exported records do not depend on the StateStore
+func (s *Storage) inlineState(data []byte, ref, checksum string) ([]byte, error) {
+	state, err := s.loadState(ref, checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	var tfWorkspace workspace.TerraformWorkspace
+	if err := json.Unmarshal(data, &tfWorkspace); err != nil {
+		return nil, fmt.Errorf("error parsing workspace: %w", err)
+	}
+	tfWorkspace.State = state
+
+	result, err := json.Marshal(&tfWorkspace)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing workspace: %w", err)
+	}
+
+	return result, nil
+}
+
+// importWorkspace encodes a decrypted workspace, writing the state to the StateStore
+// when there is one
+func (s *Storage) importWorkspace(key string, data []byte) (encoded []byte, ref, checksum string, err error) {
+	if s.stateStore == nil {
+		encoded, err = s.encodeBytes(data)
+		return encoded, "", "", err
+	}
+
+	var tfWorkspace workspace.TerraformWorkspace
+	if err := json.Unmarshal(data, &tfWorkspace); err != nil {
+		return nil, "", "", fmt.Errorf("error parsing workspace: %w", err)
+	}
+
+	return s.encodeWorkspace(key, &tfWorkspace, "", "")
+}
+
+func (s *Storage) isEmpty() (bool, error) {
+	for _, m := range []any{
+		&models.ServiceInstanceDetails{},
+		&models.ProvisionRequestDetails{},
+		&models.BindRequestDetails{},
+		&models.ServiceBindingCredentials{},
+		&models.TerraformDeployment{},
+	} {
+		var count int64
+		if err := s.db.Model(m).Count(&count).Error; err != nil {
+			return false, fmt.Errorf("error counting records: %w", err)
+		}
+		if count != 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,188 @@
+package storage_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+var _ = Describe("RecordSet", func() {
+	BeforeEach(func() {
+		Expect(db.Migrator().CreateTable(&models.PasswordMetadata{})).NotTo(HaveOccurred())
+	})
+
+	Describe("ExportAllRecords", func() {
+		BeforeEach(func() {
+			addFakeServiceCredentialBindings()
+			addFakeProvisionRequestDetails()
+			addFakeBindRequestDetails()
+			addFakeServiceInstanceDetails()
+			addFakeTerraformDeployments()
+			Expect(db.Create(&models.TerraformLog{
+				DeploymentID: "fake-id-1",
+				Command:      "apply",
+				Stdout:       []byte(`"fake-stdout"`),
+			}).Error).NotTo(HaveOccurred())
+		})
+
+		It("exports all the records decrypted", func() {
+			r, err := store.ExportAllRecords()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.ServiceInstanceDetails).To(HaveLen(3))
+			Expect(r.ServiceInstanceDetails[0].ID).To(Equal("fake-id-1"))
+			Expect(r.ServiceInstanceDetails[0].OtherDetails).To(MatchJSON(`{"decrypted":{"foo":"bar-1"}}`))
+
+			Expect(r.ProvisionRequestDetails).To(HaveLen(3))
+			Expect(r.ProvisionRequestDetails[0].RequestDetails).To(MatchJSON(`{"decrypted":{"foo":"bar"}}`))
+
+			Expect(r.BindRequestDetails).To(HaveLen(3))
+			Expect(r.BindRequestDetails[0].RequestDetails).To(MatchJSON(`{"decrypted":{"foo":"bar"}}`))
+
+			Expect(r.ServiceBindingCredentials).To(HaveLen(3))
+			Expect(r.ServiceBindingCredentials[0].OtherDetails).To(MatchJSON(`{"decrypted":{"foo":"bar"}}`))
+
+			Expect(r.TerraformDeployments).To(HaveLen(3))
+			Expect(r.TerraformDeployments[0].ID).To(Equal("fake-id-1"))
+			Expect(r.TerraformDeployments[0].Workspace).To(HavePrefix(`{"decrypted":`))
+
+			Expect(r.TerraformLogs).To(HaveLen(1))
+			Expect(r.TerraformLogs[0].DeploymentID).To(Equal("fake-id-1"))
+			Expect(r.TerraformLogs[0].Stdout).To(MatchJSON(`{"decrypted":"fake-stdout"}`))
+		})
+
+		When("a record cannot be decrypted", func() {
+			BeforeEach(func() {
+				Expect(db.Model(&models.TerraformDeployment{}).Where("id = ?", "fake-id-2").Update("workspace", []byte("cannot-be-decrypted")).Error).NotTo(HaveOccurred())
+			})
+
+			It("returns an error", func() {
+				_, err := store.ExportAllRecords()
+				Expect(err).To(MatchError(`decode error for terraform deployment "fake-id-2": decryption error: fake decryption error`))
+			})
+		})
+	})
+
+	Describe("ImportAllRecords", func() {
+		var records storage.RecordSet
+
+		BeforeEach(func() {
+			records = storage.RecordSet{
+				ServiceInstanceDetails: []models.ServiceInstanceDetails{
+					{ID: "fake-instance-id", ServiceID: "fake-service-id", OtherDetails: []byte(`{"foo":"bar"}`)},
+				},
+				ProvisionRequestDetails: []models.ProvisionRequestDetails{
+					{ServiceInstanceID: "fake-instance-id", RequestDetails: []byte(`{"foo":"baz"}`)},
+				},
+				BindRequestDetails: []models.BindRequestDetails{
+					{ServiceInstanceID: "fake-instance-id", ServiceBindingID: "fake-binding-id", RequestDetails: []byte(`{"foo":"quz"}`)},
+				},
+				ServiceBindingCredentials: []models.ServiceBindingCredentials{
+					{ServiceInstanceID: "fake-instance-id", BindingID: "fake-binding-id", OtherDetails: []byte(`{"foo":"boz"}`)},
+				},
+				TerraformDeployments: []models.TerraformDeployment{
+					{ID: "tf:fake-instance-id:", Workspace: []byte(`{"tfstate":"fake"}`), LastOperationState: "succeeded"},
+				},
+				TerraformLogs: []models.TerraformLog{
+					{ID: 7, DeploymentID: "tf:fake-instance-id:", Command: "apply", Stdout: []byte(`"fake-stdout"`)},
+				},
+			}
+			records.ProvisionRequestDetails[0].ID = 42
+
+			Expect(db.Create(&models.PasswordMetadata{
+				Label:   "existing-label",
+				Salt:    []byte("existing-salt"),
+				Canary:  []byte("existing-canary"),
+				Primary: true,
+			}).Error).NotTo(HaveOccurred())
+		})
+
+		It("imports all the records encrypted", func() {
+			Expect(store.ImportAllRecords(records)).To(Succeed())
+
+			By("checking service instance details", func() {
+				var receiver []models.ServiceInstanceDetails
+				Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+				Expect(receiver).To(HaveLen(1))
+				Expect(receiver[0].ID).To(Equal("fake-instance-id"))
+				Expect(receiver[0].OtherDetails).To(MatchJSON(`{"encrypted":{"foo":"bar"}}`))
+			})
+
+			By("checking provision request details", func() {
+				var receiver []models.ProvisionRequestDetails
+				Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+				Expect(receiver).To(HaveLen(1))
+				Expect(receiver[0].ID).NotTo(Equal(uint(42)))
+				Expect(receiver[0].RequestDetails).To(MatchJSON(`{"encrypted":{"foo":"baz"}}`))
+			})
+
+			By("checking bind request details", func() {
+				var receiver []models.BindRequestDetails
+				Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+				Expect(receiver).To(HaveLen(1))
+				Expect(receiver[0].RequestDetails).To(MatchJSON(`{"encrypted":{"foo":"quz"}}`))
+			})
+
+			By("checking service binding credentials", func() {
+				var receiver []models.ServiceBindingCredentials
+				Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+				Expect(receiver).To(HaveLen(1))
+				Expect(receiver[0].OtherDetails).To(MatchJSON(`{"encrypted":{"foo":"boz"}}`))
+			})
+
+			By("checking terraform deployments", func() {
+				var receiver []models.TerraformDeployment
+				Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+				Expect(receiver).To(HaveLen(1))
+				Expect(receiver[0].ID).To(Equal("tf:fake-instance-id:"))
+				Expect(receiver[0].Workspace).To(MatchJSON(`{"encrypted":{"tfstate":"fake"}}`))
+			})
+
+			By("checking terraform logs", func() {
+				var receiver []models.TerraformLog
+				Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+				Expect(receiver).To(HaveLen(1))
+				Expect(receiver[0].ID).NotTo(Equal(uint(7)))
+				Expect(receiver[0].DeploymentID).To(Equal("tf:fake-instance-id:"))
+				Expect(receiver[0].Stdout).To(MatchJSON(`{"encrypted":"fake-stdout"}`))
+			})
+
+			By("leaving the password metadata of the importing broker unchanged", func() {
+				var receiver []models.PasswordMetadata
+				Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+				Expect(receiver).To(HaveLen(1))
+				Expect(receiver[0].Label).To(Equal("existing-label"))
+				Expect(receiver[0].Salt).To(Equal([]byte("existing-salt")))
+				Expect(receiver[0].Primary).To(BeTrue())
+			})
+		})
+
+		When("the database is not empty", func() {
+			BeforeEach(func() {
+				addFakeTerraformDeployments()
+			})
+
+			It("returns an error", func() {
+				err := store.ImportAllRecords(records)
+				Expect(err).To(MatchError("the database already contains service instances, bindings or terraform deployments"))
+			})
+		})
+
+		When("a record cannot be encrypted", func() {
+			BeforeEach(func() {
+				records.TerraformDeployments[0].Workspace = []byte(`"cannot-be-encrypted"`)
+			})
+
+			It("returns an error and imports nothing", func() {
+				err := store.ImportAllRecords(records)
+				Expect(err).To(MatchError(`encode error for terraform deployment "tf:fake-instance-id:": encryption error: fake encryption error`))
+
+				var count int64
+				Expect(db.Model(&models.ServiceInstanceDetails{}).Count(&count).Error).NotTo(HaveOccurred())
+				Expect(count).To(BeZero())
+			})
+		})
+	})
+})
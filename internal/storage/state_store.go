@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+)
+
+// stateRefSeparator separates the key of a record from the checksum in the refs of states
+const stateRefSeparator = "."
+
+//counterfeiter:generate . StateStore
+
+// StateStore holds Terraform state outside of the broker database. When no StateStore
+// is configured, the state is held in the workspace in the broker database.
+type StateStore interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+}
+
+// WithStateStore returns a Storage that keeps Terraform state in the specified StateStore.
+// Deployments that already have their state in the workspace are moved to the StateStore
+// the next time that they are stored.
+func (s *Storage) WithStateStore(stateStore StateStore) *Storage {
+	c := *s
+	c.stateStore = stateStore
+	return &c
+}
+
+// encodeWorkspace encodes a workspace for the database. When there is a StateStore, the
+// state is encrypted and written to the StateStore unless it matches the previous checksum,
+// and the returned workspace does not contain the state. The state is written under a new
+// ref, so the previous state stays valid until the database refers to the new one.
+func (s *Storage) encodeWorkspace(key string, w workspace.Workspace, previousRef, previousChecksum string) (encoded []byte, ref, checksum string, err error) {
+	tfWorkspace, ok := w.(*workspace.TerraformWorkspace)
+	if s.stateStore == nil || !ok || tfWorkspace.State == nil {
+		encoded, err = s.encodeJSON(w)
+		return encoded, "", "", err
+	}
+
+	checksum = stateChecksum(tfWorkspace.State)
+	ref = previousRef
+	if ref == "" || checksum != previousChecksum {
+		ref, err = s.putState(key, tfWorkspace.State)
+		if err != nil {
+			return nil, "", "", err
+		}
+	}
+
+	encoded, err = s.encodeJSON(&workspace.TerraformWorkspace{
+		Modules:     tfWorkspace.Modules,
+		Instances:   tfWorkspace.Instances,
+		Transformer: tfWorkspace.Transformer,
+	})
+	if err != nil {
+		s.releaseState(ref, previousRef)
+		return nil, "", "", err
+	}
+
+	return encoded, ref, checksum, nil
+}
+
+// putState encrypts the state and writes it to the StateStore. The ref is the key of the record
+// followed by the checksum of the encrypted state, so that the state never replaces a state that
+// a record may still refer to, including when it is re-encrypted with a different password.
+func (s *Storage) putState(key string, state []byte) (string, error) {
+	encodedState, err := s.encodeBytes(state)
+	if err != nil {
+		return "", err
+	}
+
+	ref := fmt.Sprintf("%s%s%s", key, stateRefSeparator, stateChecksum(encodedState))
+	if err := s.stateStore.Put(ref, encodedState); err != nil {
+		return "", fmt.Errorf("error writing state to state store: %w", err)
+	}
+
+	return ref, nil
+}
+
+// stateKey returns the key of the record that a ref was written for. Refs that were written
+// before refs included a checksum are the key.
+func stateKey(ref string) string {
+	if i := strings.LastIndex(ref, stateRefSeparator); i >= 0 {
+		return ref[:i]
+	}
+	return ref
+}
+
+// releaseState deletes a state that is no longer referred to from the StateStore, unless it is
+// the state that is kept. It is called once the database no longer refers to the state, so a
+// failure to delete it only leaves an unused object behind, and is ignored.
+func (s *Storage) releaseState(ref, kept string) {
+	if ref == "" || ref == kept || s.stateStore == nil {
+		return
+	}
+
+	_ = s.stateStore.Delete(ref)
+}
+
+// decodeWorkspace decodes a workspace from the database, reading the state from the StateStore when it is held there
+func (s *Storage) decodeWorkspace(encoded []byte, ref, checksum string) (*workspace.TerraformWorkspace, error) {
+	var tfWorkspace workspace.TerraformWorkspace
+	if err := s.decodeJSON(encoded, &tfWorkspace); err != nil {
+		return nil, err
+	}
+
+	if ref != "" {
+		state, err := s.loadState(ref, checksum)
+		if err != nil {
+			return nil, err
+		}
+		tfWorkspace.State = state
+	}
+
+	return &tfWorkspace, nil
+}
+
+// loadState reads state from the StateStore and checks that it matches the checksum
+func (s *Storage) loadState(ref, checksum string) ([]byte, error) {
+	if s.stateStore == nil {
+		return nil, fmt.Errorf("state %q is held in a state store, but no state store is configured", ref)
+	}
+
+	encodedState, err := s.stateStore.Get(ref)
+	if err != nil {
+		return nil, fmt.Errorf("error reading state from state store: %w", err)
+	}
+
+	state, err := s.decodeBytes(encodedState)
+	if err != nil {
+		return nil, err
+	}
+
+	if actual := stateChecksum(state); actual != checksum {
+		return nil, fmt.Errorf("checksum mismatch for state %q: expected %s, got %s", ref, checksum, actual)
+	}
+
+	return state, nil
+}
+
+func stateChecksum(state []byte) string {
+	sum := sha256.Sum256(state)
+	return hex.EncodeToString(sum[:])
+}
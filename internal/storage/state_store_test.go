@@ -0,0 +1,287 @@
+package storage_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage/storagefakes"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+)
+
+var _ = Describe("StateStore", func() {
+	const (
+		state         = `{"terraform_version":"1.1.0"}`
+		stateChecksum = "2becef3ef7ea6b4dbdca8c477033ed53b79b281aec4e90763f9d4886c98b7506"
+	)
+
+	var (
+		fakeStateStore *storagefakes.FakeStateStore
+		states         map[string][]byte
+	)
+
+	stateRef := func(key string, encodedState string) string {
+		sum := sha256.Sum256([]byte(encodedState))
+		return key + "." + hex.EncodeToString(sum[:])
+	}
+
+	BeforeEach(func() {
+		By("using a reversible encryptor")
+		encryptor = &storagefakes.FakeEncryptor{
+			DecryptStub: func(b []byte) ([]byte, error) {
+				if !bytes.HasPrefix(b, []byte("encrypted:")) {
+					return nil, errors.New("fake decryption error")
+				}
+				return bytes.TrimPrefix(b, []byte("encrypted:")), nil
+			},
+			EncryptStub: func(b []byte) ([]byte, error) {
+				return append([]byte("encrypted:"), b...), nil
+			},
+		}
+
+		states = make(map[string][]byte)
+		fakeStateStore = &storagefakes.FakeStateStore{
+			PutStub: func(key string, data []byte) error {
+				states[key] = data
+				return nil
+			},
+			GetStub: func(key string) ([]byte, error) {
+				data, ok := states[key]
+				if !ok {
+					return nil, errors.New("not found")
+				}
+				return data, nil
+			},
+			DeleteStub: func(key string) error {
+				delete(states, key)
+				return nil
+			},
+		}
+
+		store = storage.New(db, encryptor).WithStateStore(fakeStateStore)
+	})
+
+	storeDeployment := func(state string) {
+		Expect(store.StoreTerraformDeployment(storage.TerraformDeployment{
+			ID: "fake-id",
+			Workspace: &workspace.TerraformWorkspace{
+				Modules: []workspace.ModuleDefinition{{Name: "first"}},
+				State:   []byte(state),
+			},
+			LastOperationType:  "create",
+			LastOperationState: "succeeded",
+		})).To(Succeed())
+	}
+
+	Describe("StoreTerraformDeployment", func() {
+		It("writes the encrypted state to the state store and a reference to the database", func() {
+			storeDeployment(state)
+
+			ref := stateRef("fake-id", "encrypted:"+state)
+			Expect(states).To(HaveKeyWithValue(ref, []byte("encrypted:"+state)))
+
+			var receiver models.TerraformDeployment
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver.StateRef).To(Equal(ref))
+			Expect(receiver.StateChecksum).To(Equal(stateChecksum))
+
+			var tfWorkspace workspace.TerraformWorkspace
+			Expect(json.Unmarshal(bytes.TrimPrefix(receiver.Workspace, []byte("encrypted:")), &tfWorkspace)).To(Succeed())
+			Expect(tfWorkspace.State).To(BeNil())
+			Expect(tfWorkspace.Modules).To(Equal([]workspace.ModuleDefinition{{Name: "first"}}))
+		})
+
+		It("does not rewrite an unchanged state", func() {
+			storeDeployment(state)
+			storeDeployment(state)
+			Expect(fakeStateStore.PutCallCount()).To(Equal(1))
+
+			storeDeployment(`{"terraform_version":"1.2.0"}`)
+			Expect(fakeStateStore.PutCallCount()).To(Equal(2))
+		})
+
+		It("writes a changed state under a new ref and deletes the previous state once the database refers to the new one", func() {
+			const nextState = `{"terraform_version":"1.2.0"}`
+			storeDeployment(state)
+			storeDeployment(nextState)
+
+			ref := stateRef("fake-id", "encrypted:"+nextState)
+			Expect(states).To(Equal(map[string][]byte{ref: []byte("encrypted:" + nextState)}))
+
+			var receiver models.TerraformDeployment
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver.StateRef).To(Equal(ref))
+		})
+
+		It("keeps the previous state when the database cannot be updated", func() {
+			storeDeployment(state)
+			Expect(db.Exec("CREATE TRIGGER fail_update BEFORE UPDATE ON terraform_deployments BEGIN SELECT RAISE(FAIL, 'fake database error'); END").Error).To(Succeed())
+
+			err := store.StoreTerraformDeployment(storage.TerraformDeployment{
+				ID:        "fake-id",
+				Workspace: &workspace.TerraformWorkspace{State: []byte(`{"terraform_version":"1.2.0"}`)},
+			})
+			Expect(err).To(MatchError(ContainSubstring("fake database error")))
+
+			Expect(states).To(Equal(map[string][]byte{stateRef("fake-id", "encrypted:"+state): []byte("encrypted:" + state)}))
+			deployment, err := store.GetTerraformDeployment("fake-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.TFWorkspace().State).To(Equal([]byte(state)))
+		})
+
+		It("replaces a state that was written before refs included a checksum", func() {
+			storeDeployment(state)
+			Expect(db.Model(&models.TerraformDeployment{}).Where("id = ?", "fake-id").Update("state_ref", "fake-id").Error).To(Succeed())
+			states = map[string][]byte{"fake-id": []byte("encrypted:" + state)}
+
+			deployment, err := store.GetTerraformDeployment("fake-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.TFWorkspace().State).To(Equal([]byte(state)))
+
+			storeDeployment(`{"terraform_version":"1.2.0"}`)
+			Expect(states).NotTo(HaveKey("fake-id"))
+			Expect(states).To(HaveLen(1))
+		})
+
+		It("does not write to the state store when there is no state", func() {
+			Expect(store.StoreTerraformDeployment(storage.TerraformDeployment{
+				ID:        "fake-id",
+				Workspace: &workspace.TerraformWorkspace{},
+			})).To(Succeed())
+
+			Expect(fakeStateStore.PutCallCount()).To(BeZero())
+		})
+
+		It("returns an error when the state store fails", func() {
+			fakeStateStore.PutStub = nil
+			fakeStateStore.PutReturns(errors.New("bucket gone"))
+
+			err := store.StoreTerraformDeployment(storage.TerraformDeployment{
+				ID:        "fake-id",
+				Workspace: &workspace.TerraformWorkspace{State: []byte(state)},
+			})
+			Expect(err).To(MatchError("error encoding workspace: error writing state to state store: bucket gone"))
+		})
+	})
+
+	Describe("GetTerraformDeployment", func() {
+		It("reads the state from the state store", func() {
+			storeDeployment(state)
+
+			deployment, err := store.GetTerraformDeployment("fake-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.TFWorkspace().State).To(Equal([]byte(state)))
+			Expect(deployment.TFWorkspace().Modules).To(Equal([]workspace.ModuleDefinition{{Name: "first"}}))
+		})
+
+		It("reads state that is still held in the workspace", func() {
+			Expect(storage.New(db, encryptor).StoreTerraformDeployment(storage.TerraformDeployment{
+				ID:        "fake-id",
+				Workspace: &workspace.TerraformWorkspace{State: []byte(state)},
+			})).To(Succeed())
+
+			deployment, err := store.GetTerraformDeployment("fake-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.TFWorkspace().State).To(Equal([]byte(state)))
+			Expect(fakeStateStore.GetCallCount()).To(BeZero())
+		})
+
+		It("fails when the checksum does not match", func() {
+			storeDeployment(state)
+			ref := stateRef("fake-id", "encrypted:"+state)
+			states[ref] = []byte(`encrypted:{"terraform_version":"0.0.1"}`)
+
+			_, err := store.GetTerraformDeployment("fake-id")
+			Expect(err).To(MatchError(ContainSubstring(`checksum mismatch for state "` + ref + `": expected ` + stateChecksum)))
+		})
+
+		It("fails when no state store is configured", func() {
+			storeDeployment(state)
+
+			_, err := storage.New(db, encryptor).GetTerraformDeployment("fake-id")
+			Expect(err).To(MatchError(`error decoding workspace "fake-id": state "` + stateRef("fake-id", "encrypted:"+state) + `" is held in a state store, but no state store is configured`))
+		})
+	})
+
+	Describe("GetAllTerraformDeployments", func() {
+		It("reads the state version from the state store", func() {
+			storeDeployment(state)
+
+			results, err := store.GetAllTerraformDeployments()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].StateVersion.String()).To(Equal("1.1.0"))
+		})
+	})
+
+	Describe("DeleteTerraformDeployment", func() {
+		It("deletes the state from the state store", func() {
+			storeDeployment(state)
+
+			Expect(store.DeleteTerraformDeployment("fake-id")).To(Succeed())
+			Expect(states).To(BeEmpty())
+		})
+	})
+
+	Describe("UpdateAllRecords", func() {
+		It("writes the re-encrypted state under a new ref and deletes the previous state", func() {
+			storeDeployment(state)
+
+			rotationEncryptor := &storagefakes.FakeEncryptor{
+				DecryptStub: encryptor.DecryptStub,
+				EncryptStub: func(b []byte) ([]byte, error) {
+					return append([]byte("rotated:"), b...), nil
+				},
+			}
+			Expect(storage.New(db, rotationEncryptor).WithStateStore(fakeStateStore).UpdateAllRecords()).To(Succeed())
+
+			ref := stateRef("fake-id", "rotated:"+state)
+			Expect(states).To(Equal(map[string][]byte{ref: []byte("rotated:" + state)}))
+
+			var receiver models.TerraformDeployment
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver.StateRef).To(Equal(ref))
+			Expect(receiver.StateChecksum).To(Equal(stateChecksum))
+		})
+	})
+
+	Describe("CheckAllRecords", func() {
+		It("reports state that cannot be read", func() {
+			storeDeployment(state)
+			delete(states, stateRef("fake-id", "encrypted:"+state))
+
+			err := store.CheckAllRecords()
+			Expect(err).To(MatchError(ContainSubstring(`state error for terraform deployment "fake-id": error reading state from state store: not found`)))
+		})
+	})
+
+	Describe("ExportAllRecords and ImportAllRecords", func() {
+		It("moves the state through the records", func() {
+			Expect(db.Migrator().CreateTable(&models.PasswordMetadata{})).To(Succeed())
+			storeDeployment(state)
+
+			records, err := store.ExportAllRecords()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records.TerraformDeployments).To(HaveLen(1))
+			Expect(records.TerraformDeployments[0].StateRef).To(BeEmpty())
+			Expect(records.TerraformDeployments[0].Workspace).To(ContainSubstring(`"tfstate":`))
+
+			Expect(store.DeleteTerraformDeployment("fake-id")).To(Succeed())
+			Expect(states).To(BeEmpty())
+
+			Expect(store.ImportAllRecords(records)).To(Succeed())
+			Expect(states).To(HaveKeyWithValue(stateRef("fake-id", "encrypted:"+state), []byte("encrypted:"+state)))
+
+			deployment, err := store.GetTerraformDeployment("fake-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.TFWorkspace().State).To(Equal([]byte(state)))
+		})
+	})
+})
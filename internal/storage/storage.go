@@ -1,11 +1,22 @@
 // Package storage implements a Database Access Object (DAO)
 package storage
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Storage struct {
-	db        *gorm.DB
-	encryptor Encryptor
+	db                *gorm.DB
+	encryptor         Encryptor
+	stateStore        StateStore
+	stateHistoryLimit int
+	operationOwner    string
+	leaseDuration     time.Duration
+
+	terraformLogRetention time.Duration
+	terraformLogMaxSize   int
 }
 
 func New(db *gorm.DB, encryptor Encryptor) *Storage {
@@ -35,6 +35,11 @@ var _ = BeforeEach(func() {
 	Expect(db.Migrator().CreateTable(&models.BindRequestDetails{})).NotTo(HaveOccurred())
 	Expect(db.Migrator().CreateTable(&models.ServiceInstanceDetails{})).NotTo(HaveOccurred())
 	Expect(db.Migrator().CreateTable(&models.TerraformDeployment{})).NotTo(HaveOccurred())
+	Expect(db.Migrator().CreateTable(&models.TerraformDrift{})).NotTo(HaveOccurred())
+	Expect(db.Migrator().CreateTable(&models.TerraformDeploymentSnapshot{})).NotTo(HaveOccurred())
+	Expect(db.Migrator().CreateTable(&models.OperationLog{})).NotTo(HaveOccurred())
+	Expect(db.Migrator().CreateTable(&models.TerraformDeploymentLease{})).NotTo(HaveOccurred())
+	Expect(db.Migrator().CreateTable(&models.TerraformLog{})).NotTo(HaveOccurred())
 
 	encryptor = &storagefakes.FakeEncryptor{
 		DecryptStub: func(bytes []byte) ([]byte, error) {
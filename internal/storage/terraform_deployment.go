@@ -33,33 +33,54 @@ func (deployment *TerraformDeployment) TFWorkspace() *workspace.TerraformWorkspa
 }
 
 func (s *Storage) StoreTerraformDeployment(t TerraformDeployment) error {
-	encoded, err := s.encodeJSON(t.Workspace)
-	if err != nil {
-		return fmt.Errorf("error encoding workspace: %w", err)
-	}
-
 	var m models.TerraformDeployment
 	if err := s.loadTerraformDeploymentIfExists(t.ID, &m); err != nil {
 		return err
 	}
 
+	if m.ID != "" && s.stateHistoryLimit > 0 {
+		if err := s.snapshotTerraformDeployment(m, t.Workspace); err != nil {
+			return fmt.Errorf("error taking snapshot of terraform deployment: %w", err)
+		}
+	}
+
+	previousRef := m.StateRef
+	encoded, ref, checksum, err := s.encodeWorkspace(t.ID, t.Workspace, previousRef, m.StateChecksum)
+	if err != nil {
+		return fmt.Errorf("error encoding workspace: %w", err)
+	}
+
 	m.Workspace = encoded
+	m.StateRef = ref
+	m.StateChecksum = checksum
+	if m.LastOperationState != t.LastOperationState {
+		// A cancellation request applies to the operation that was in progress when it
+		// was made, so it is dropped when the operation state changes
+		m.CancelRequestedAt = nil
+	}
 	m.LastOperationType = t.LastOperationType
 	m.LastOperationState = t.LastOperationState
 	m.LastOperationMessage = t.LastOperationMessage
 
+	now := time.Now()
+	m.OperationOwner = s.operationOwner
+	m.OperationHeartbeatAt = &now
+
 	switch m.ID {
 	case "":
 		m.ID = t.ID
 		if err := s.db.Create(&m).Error; err != nil {
+			s.releaseState(ref, previousRef)
 			return fmt.Errorf("error creating terraform deployment: %w", err)
 		}
 	default:
 		if err := s.db.Save(&m).Error; err != nil {
+			s.releaseState(ref, previousRef)
 			return fmt.Errorf("error saving terraform deployment: %w", err)
 		}
 	}
 
+	s.releaseState(previousRef, ref)
 	return nil
 }
 
@@ -77,16 +98,17 @@ func (s *Storage) GetTerraformDeployment(id string) (TerraformDeployment, error)
 		return TerraformDeployment{}, fmt.Errorf("error finding terraform deployment: %w", err)
 	}
 
-	var tfWorkspace workspace.TerraformWorkspace
-	if err = s.decodeJSON(receiver.Workspace, &tfWorkspace); err != nil {
+	tfWorkspace, err := s.decodeWorkspace(receiver.Workspace, receiver.StateRef, receiver.StateChecksum)
+	if err != nil {
 		return TerraformDeployment{}, fmt.Errorf("error decoding workspace %q: %w", id, err)
 	}
+
 	return TerraformDeployment{
 		ID:                   id,
 		LastOperationType:    receiver.LastOperationType,
 		LastOperationState:   receiver.LastOperationState,
 		LastOperationMessage: receiver.LastOperationMessage,
-		Workspace:            &tfWorkspace,
+		Workspace:            tfWorkspace,
 	}, nil
 }
 
@@ -101,6 +123,11 @@ func (s *Storage) GetAllTerraformDeployments() ([]TerraformDeploymentListEntry,
 				return fmt.Errorf("error decoding workspace %q: %w", terraformDeploymentBatch[i].ID, err)
 			}
 
+			if terraformDeploymentBatch[i].StateRef != "" {
+				// The state version is informational, so a state that cannot be loaded is listed without one
+				tfWorkspace.State, _ = s.loadState(terraformDeploymentBatch[i].StateRef, terraformDeploymentBatch[i].StateChecksum)
+			}
+
 			tfVersion, err := tfWorkspace.StateTFVersion()
 			if err != nil {
 				tfVersion = nil
@@ -134,11 +161,31 @@ func (s *Storage) ExistsTerraformDeployment(id string) (bool, error) {
 }
 
 func (s *Storage) DeleteTerraformDeployment(id string) error {
+	var m models.TerraformDeployment
+	if err := s.loadTerraformDeploymentIfExists(id, &m); err != nil {
+		return err
+	}
+
 	err := s.db.Where("id = ?", id).Delete(&models.TerraformDeployment{}).Error
 	if err != nil {
 		return fmt.Errorf("error deleting terraform deployment: %w", err)
 	}
-	return nil
+
+	if err := s.db.Where("id = ?", id).Delete(&models.TerraformDeploymentLease{}).Error; err != nil {
+		return fmt.Errorf("error deleting terraform deployment lease: %w", err)
+	}
+
+	if m.StateRef != "" && s.stateStore != nil {
+		if err := s.stateStore.Delete(m.StateRef); err != nil {
+			return fmt.Errorf("error deleting state from state store: %w", err)
+		}
+	}
+
+	if err := s.deleteAllTerraformDeploymentSnapshots(id); err != nil {
+		return err
+	}
+
+	return s.DeleteTerraformDrift(id)
 }
 
 func (s *Storage) loadTerraformDeploymentIfExists(id string, receiver any) error {
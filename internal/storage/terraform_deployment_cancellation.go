@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+)
+
+// RequestTerraformDeploymentCancellation records that an operator has asked for the operation in
+// progress on a Terraform deployment to be cancelled. The broker process running the operation
+// finds the request with GetCancelRequestedTerraformDeploymentIDs.
+func (s *Storage) RequestTerraformDeploymentCancellation(id string) error {
+	result := s.db.Model(&models.TerraformDeployment{}).
+		Where("id = ?", id).
+		UpdateColumn("cancel_requested_at", time.Now())
+	switch {
+	case result.Error != nil:
+		return fmt.Errorf("error requesting cancellation of terraform deployment %q: %w", id, result.Error)
+	case result.RowsAffected == 0:
+		return fmt.Errorf("could not find terraform deployment: %s", id)
+	}
+
+	return nil
+}
+
+// GetCancelRequestedTerraformDeploymentIDs returns the IDs of the Terraform deployments
+// for which cancellation of the operation has been requested
+func (s *Storage) GetCancelRequestedTerraformDeploymentIDs() ([]string, error) {
+	var ids []string
+	err := s.db.Model(&models.TerraformDeployment{}).
+		Where("cancel_requested_at IS NOT NULL").
+		Order("id").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("error finding cancelled terraform deployments: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,49 @@
+package storage_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+)
+
+var _ = Describe("TerraformDeploymentCancellation", func() {
+	storeDeployment := func(id, state string) {
+		Expect(store.StoreTerraformDeployment(storage.TerraformDeployment{
+			ID:                 id,
+			Workspace:          &workspace.TerraformWorkspace{},
+			LastOperationType:  "provision",
+			LastOperationState: state,
+		})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		storeDeployment("fake-cancelled", "in progress")
+		storeDeployment("fake-running", "in progress")
+	})
+
+	It("returns the deployments for which cancellation was requested", func() {
+		Expect(store.RequestTerraformDeploymentCancellation("fake-cancelled")).To(Succeed())
+
+		Expect(store.GetCancelRequestedTerraformDeploymentIDs()).To(ConsistOf("fake-cancelled"))
+	})
+
+	It("keeps the request while the operation state does not change", func() {
+		Expect(store.RequestTerraformDeploymentCancellation("fake-cancelled")).To(Succeed())
+		storeDeployment("fake-cancelled", "in progress")
+
+		Expect(store.GetCancelRequestedTerraformDeploymentIDs()).To(ConsistOf("fake-cancelled"))
+	})
+
+	It("drops the request when the operation state changes", func() {
+		Expect(store.RequestTerraformDeploymentCancellation("fake-cancelled")).To(Succeed())
+		storeDeployment("fake-cancelled", "failed")
+
+		Expect(store.GetCancelRequestedTerraformDeploymentIDs()).To(BeEmpty())
+	})
+
+	It("fails when the deployment does not exist", func() {
+		Expect(store.RequestTerraformDeploymentCancellation("fake-missing")).To(MatchError(`could not find terraform deployment: fake-missing`))
+	})
+})
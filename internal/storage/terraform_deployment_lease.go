@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+)
+
+// ErrLeaseHeld is returned when a lease on a Terraform deployment is held by another operation
+var ErrLeaseHeld = errors.New("terraform deployment is locked by another operation")
+
+// WithLeaseDuration returns a Storage that locks Terraform deployments for the specified
+// duration when a lease is acquired or renewed. A duration of zero disables locking.
+func (s *Storage) WithLeaseDuration(d time.Duration) *Storage {
+	c := *s
+	c.leaseDuration = d
+	return &c
+}
+
+// AcquireTerraformDeploymentLease locks a Terraform deployment for the specified holder.
+// The holder can acquire a lease that it already holds again, and any holder can acquire
+// a lease that has expired. Otherwise an error wrapping ErrLeaseHeld is returned.
+func (s *Storage) AcquireTerraformDeploymentLease(id, holder string) error {
+	if s.leaseDuration == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.leaseDuration)
+
+	result := s.db.Model(&models.TerraformDeploymentLease{}).
+		Where("id = ? AND (holder = ? OR expires_at < ?)", id, holder, now).
+		UpdateColumns(map[string]any{
+			"holder":     holder,
+			"owner":      s.operationOwner,
+			"expires_at": expiresAt,
+		})
+	switch {
+	case result.Error != nil:
+		return fmt.Errorf("error acquiring lease on terraform deployment %q: %w", id, result.Error)
+	case result.RowsAffected == 1:
+		return nil
+	}
+
+	err := s.db.Create(&models.TerraformDeploymentLease{
+		ID:        id,
+		Holder:    holder,
+		Owner:     s.operationOwner,
+		ExpiresAt: expiresAt,
+	}).Error
+	if err == nil {
+		return nil
+	}
+
+	// The insert fails when another operation holds the lease
+	var exists int64
+	if countErr := s.db.Model(&models.TerraformDeploymentLease{}).Where("id = ?", id).Count(&exists).Error; countErr == nil && exists != 0 {
+		return fmt.Errorf("%w: %s", ErrLeaseHeld, id)
+	}
+	return fmt.Errorf("error acquiring lease on terraform deployment %q: %w", id, err)
+}
+
+// ReleaseTerraformDeploymentLease unlocks a Terraform deployment if the specified holder holds the lease
+func (s *Storage) ReleaseTerraformDeploymentLease(id, holder string) error {
+	if err := s.db.Where("id = ? AND holder = ?", id, holder).Delete(&models.TerraformDeploymentLease{}).Error; err != nil {
+		return fmt.Errorf("error releasing lease on terraform deployment %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// renewTerraformDeploymentLeases extends the leases taken by this Storage on the Terraform
+// deployments that have the specified operation state
+func (s *Storage) renewTerraformDeploymentLeases(state string) error {
+	if s.leaseDuration == 0 {
+		return nil
+	}
+
+	deployments := s.db.Model(&models.TerraformDeployment{}).Select("id").Where("last_operation_state = ?", state)
+	err := s.db.Model(&models.TerraformDeploymentLease{}).
+		Where("owner = ? AND id IN (?)", s.operationOwner, deployments).
+		UpdateColumn("expires_at", time.Now().Add(s.leaseDuration)).Error
+	if err != nil {
+		return fmt.Errorf("error renewing terraform deployment leases: %w", err)
+	}
+
+	return nil
+}
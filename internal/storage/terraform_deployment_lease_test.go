@@ -0,0 +1,129 @@
+package storage_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+)
+
+var _ = Describe("TerraformDeploymentLease", func() {
+	var owner, other *storage.Storage
+
+	BeforeEach(func() {
+		owner = store.WithOperationOwner("fake-owner").WithLeaseDuration(time.Minute)
+		other = store.WithOperationOwner("fake-other-owner").WithLeaseDuration(time.Minute)
+	})
+
+	getLease := func(id string) models.TerraformDeploymentLease {
+		var m models.TerraformDeploymentLease
+		Expect(db.Where("id = ?", id).First(&m).Error).To(Succeed())
+		return m
+	}
+
+	expireLease := func(id string) {
+		Expect(db.Model(&models.TerraformDeploymentLease{}).Where("id = ?", id).UpdateColumn("expires_at", time.Now().Add(-time.Second)).Error).To(Succeed())
+	}
+
+	lockDeployment := func(s *storage.Storage, id, state, holder string) {
+		Expect(s.StoreTerraformDeployment(storage.TerraformDeployment{
+			ID:                 id,
+			Workspace:          &workspace.TerraformWorkspace{},
+			LastOperationState: state,
+		})).To(Succeed())
+		Expect(s.AcquireTerraformDeploymentLease(id, holder)).To(Succeed())
+		expireLease(id)
+	}
+
+	Describe("AcquireTerraformDeploymentLease", func() {
+		It("locks the deployment", func() {
+			Expect(owner.AcquireTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+
+			m := getLease("fake-id")
+			Expect(m.Holder).To(Equal("fake-holder"))
+			Expect(m.Owner).To(Equal("fake-owner"))
+			Expect(m.ExpiresAt).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		})
+
+		It("can be acquired again by the holder", func() {
+			Expect(owner.AcquireTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+			Expect(owner.AcquireTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+		})
+
+		It("fails when another holder has the lease", func() {
+			Expect(owner.AcquireTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+
+			err := other.AcquireTerraformDeploymentLease("fake-id", "fake-other-holder")
+			Expect(err).To(MatchError(storage.ErrLeaseHeld))
+			Expect(err).To(MatchError(ContainSubstring("fake-id")))
+			Expect(getLease("fake-id").Holder).To(Equal("fake-holder"))
+		})
+
+		It("takes over an expired lease", func() {
+			Expect(owner.AcquireTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+			expireLease("fake-id")
+
+			Expect(other.AcquireTerraformDeploymentLease("fake-id", "fake-other-holder")).To(Succeed())
+
+			m := getLease("fake-id")
+			Expect(m.Holder).To(Equal("fake-other-holder"))
+			Expect(m.Owner).To(Equal("fake-other-owner"))
+		})
+
+		It("does nothing when the lease duration is zero", func() {
+			Expect(store.AcquireTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+
+			var count int64
+			Expect(db.Model(&models.TerraformDeploymentLease{}).Count(&count).Error).To(Succeed())
+			Expect(count).To(BeZero())
+		})
+	})
+
+	Describe("ReleaseTerraformDeploymentLease", func() {
+		It("unlocks the deployment", func() {
+			Expect(owner.AcquireTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+			Expect(owner.ReleaseTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+
+			Expect(other.AcquireTerraformDeploymentLease("fake-id", "fake-other-holder")).To(Succeed())
+		})
+
+		It("does not release a lease held by another holder", func() {
+			Expect(owner.AcquireTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+			Expect(other.ReleaseTerraformDeploymentLease("fake-id", "fake-other-holder")).To(Succeed())
+
+			Expect(getLease("fake-id").Holder).To(Equal("fake-holder"))
+		})
+	})
+
+	Describe("HeartbeatTerraformDeployments", func() {
+		It("renews the leases of the owner on deployments in the state", func() {
+			lockDeployment(owner, "fake-in-progress", "in progress", "fake-holder")
+			lockDeployment(owner, "fake-succeeded", "succeeded", "fake-holder")
+			lockDeployment(other, "fake-other", "in progress", "fake-other-holder")
+
+			Expect(owner.HeartbeatTerraformDeployments("in progress")).To(Succeed())
+
+			Expect(getLease("fake-in-progress").ExpiresAt).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+			Expect(getLease("fake-succeeded").ExpiresAt).To(BeTemporally("<", time.Now()))
+			Expect(getLease("fake-other").ExpiresAt).To(BeTemporally("<", time.Now()))
+		})
+	})
+
+	Describe("DeleteTerraformDeployment", func() {
+		It("removes the lease", func() {
+			Expect(owner.StoreTerraformDeployment(storage.TerraformDeployment{
+				ID:        "fake-id",
+				Workspace: &workspace.TerraformWorkspace{},
+			})).To(Succeed())
+			Expect(owner.AcquireTerraformDeploymentLease("fake-id", "fake-holder")).To(Succeed())
+
+			Expect(owner.DeleteTerraformDeployment("fake-id")).To(Succeed())
+
+			Expect(other.AcquireTerraformDeploymentLease("fake-id", "fake-other-holder")).To(Succeed())
+		})
+	})
+})
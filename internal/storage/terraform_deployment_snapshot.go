@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+)
+
+// TerraformDeploymentSnapshot is a previous version of the workspace of a Terraform deployment.
+// The OperationType and OperationState describe the last operation on the deployment when the
+// snapshot was taken, which is when the Terraform state in the workspace was replaced.
+type TerraformDeploymentSnapshot struct {
+	DeploymentID   string
+	Revision       int
+	OperationType  string
+	OperationState string
+	CreatedAt      time.Time
+	Workspace      *workspace.TerraformWorkspace
+}
+
+// WithStateHistoryLimit returns a Storage that keeps up to the specified number of previous
+// workspaces for each Terraform deployment. A snapshot of the workspace is taken each time
+// that the Terraform state changes. A limit of zero disables the history.
+func (s *Storage) WithStateHistoryLimit(limit int) *Storage {
+	c := *s
+	c.stateHistoryLimit = limit
+	return &c
+}
+
+// GetTerraformDeploymentHistory returns the snapshots of a Terraform deployment, newest first
+func (s *Storage) GetTerraformDeploymentHistory(id string) ([]TerraformDeploymentSnapshot, error) {
+	var receiver []models.TerraformDeploymentSnapshot
+	if err := s.db.Where("deployment_id = ?", id).Order("revision desc").Find(&receiver).Error; err != nil {
+		return nil, fmt.Errorf("error finding terraform deployment snapshots: %w", err)
+	}
+
+	result := make([]TerraformDeploymentSnapshot, 0, len(receiver))
+	for _, m := range receiver {
+		snapshot, err := s.decodeSnapshot(m)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, snapshot)
+	}
+
+	return result, nil
+}
+
+// GetTerraformDeploymentSnapshot returns a specific revision of a Terraform deployment
+func (s *Storage) GetTerraformDeploymentSnapshot(id string, revision int) (TerraformDeploymentSnapshot, error) {
+	var receiver models.TerraformDeploymentSnapshot
+	err := s.db.Where("deployment_id = ? AND revision = ?", id, revision).First(&receiver).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return TerraformDeploymentSnapshot{}, fmt.Errorf("could not find revision %d of terraform deployment: %s", revision, id)
+	case err != nil:
+		return TerraformDeploymentSnapshot{}, fmt.Errorf("error finding terraform deployment snapshot: %w", err)
+	}
+
+	return s.decodeSnapshot(receiver)
+}
+
+func (s *Storage) decodeSnapshot(m models.TerraformDeploymentSnapshot) (TerraformDeploymentSnapshot, error) {
+	tfWorkspace, err := s.decodeWorkspace(m.Workspace, m.StateRef, m.StateChecksum)
+	if err != nil {
+		return TerraformDeploymentSnapshot{}, fmt.Errorf("error decoding revision %d of workspace %q: %w", m.Revision, m.DeploymentID, err)
+	}
+
+	return TerraformDeploymentSnapshot{
+		DeploymentID:   m.DeploymentID,
+		Revision:       m.Revision,
+		OperationType:  m.OperationType,
+		OperationState: m.OperationState,
+		CreatedAt:      m.CreatedAt,
+		Workspace:      tfWorkspace,
+	}, nil
+}
+
+// snapshotTerraformDeployment stores the previous workspace of a Terraform deployment
+// when the next workspace has a different state, and removes the oldest snapshots
+// that exceed the history limit
+func (s *Storage) snapshotTerraformDeployment(previous models.TerraformDeployment, next workspace.Workspace) error {
+	var nextState []byte
+	if tfWorkspace, ok := next.(*workspace.TerraformWorkspace); ok {
+		nextState = tfWorkspace.State
+	}
+
+	var previousWorkspace *workspace.TerraformWorkspace
+	previousChecksum := previous.StateChecksum
+	if previous.StateRef == "" {
+		var err error
+		previousWorkspace, err = s.decodeWorkspace(previous.Workspace, "", "")
+		switch {
+		case err != nil:
+			return fmt.Errorf("error decoding previous workspace: %w", err)
+		case previousWorkspace.State == nil:
+			return nil
+		}
+		previousChecksum = stateChecksum(previousWorkspace.State)
+	}
+
+	if previousChecksum == stateChecksum(nextState) {
+		return nil
+	}
+
+	if previousWorkspace == nil {
+		var err error
+		previousWorkspace, err = s.decodeWorkspace(previous.Workspace, previous.StateRef, previous.StateChecksum)
+		if err != nil {
+			return fmt.Errorf("error decoding previous workspace: %w", err)
+		}
+	}
+
+	var lastRevision int
+	if err := s.db.Model(&models.TerraformDeploymentSnapshot{}).Where("deployment_id = ?", previous.ID).Select("COALESCE(MAX(revision), 0)").Scan(&lastRevision).Error; err != nil {
+		return fmt.Errorf("error finding last revision: %w", err)
+	}
+
+	m := models.TerraformDeploymentSnapshot{
+		DeploymentID:   previous.ID,
+		Revision:       lastRevision + 1,
+		OperationType:  previous.LastOperationType,
+		OperationState: previous.LastOperationState,
+	}
+
+	var err error
+	m.Workspace, m.StateRef, m.StateChecksum, err = s.encodeWorkspace(snapshotKey(m.DeploymentID, m.Revision), previousWorkspace, "", "")
+	if err != nil {
+		return fmt.Errorf("error encoding previous workspace: %w", err)
+	}
+
+	if err := s.db.Create(&m).Error; err != nil {
+		s.releaseState(m.StateRef, "")
+		return fmt.Errorf("error creating terraform deployment snapshot: %w", err)
+	}
+
+	var expired []models.TerraformDeploymentSnapshot
+	if err := s.db.Where("deployment_id = ? AND revision <= ?", m.DeploymentID, m.Revision-s.stateHistoryLimit).Find(&expired).Error; err != nil {
+		return fmt.Errorf("error finding expired terraform deployment snapshots: %w", err)
+	}
+
+	return s.deleteTerraformDeploymentSnapshots(expired)
+}
+
+func (s *Storage) deleteAllTerraformDeploymentSnapshots(id string) error {
+	var receiver []models.TerraformDeploymentSnapshot
+	if err := s.db.Where("deployment_id = ?", id).Find(&receiver).Error; err != nil {
+		return fmt.Errorf("error finding terraform deployment snapshots: %w", err)
+	}
+
+	return s.deleteTerraformDeploymentSnapshots(receiver)
+}
+
+func (s *Storage) deleteTerraformDeploymentSnapshots(snapshots []models.TerraformDeploymentSnapshot) error {
+	for _, m := range snapshots {
+		if err := s.db.Delete(&m).Error; err != nil {
+			return fmt.Errorf("error deleting terraform deployment snapshot: %w", err)
+		}
+
+		if m.StateRef != "" && s.stateStore != nil {
+			if err := s.stateStore.Delete(m.StateRef); err != nil {
+				return fmt.Errorf("error deleting state from state store: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func snapshotKey(id string, revision int) string {
+	return fmt.Sprintf("%s@%d", id, revision)
+}
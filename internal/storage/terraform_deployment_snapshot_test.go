@@ -0,0 +1,196 @@
+package storage_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage/storagefakes"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+)
+
+var _ = Describe("TerraformDeploymentSnapshots", func() {
+	BeforeEach(func() {
+		By("using a reversible encryptor")
+		encryptor = &storagefakes.FakeEncryptor{
+			DecryptStub: func(b []byte) ([]byte, error) {
+				return bytes.TrimPrefix(b, []byte("encrypted:")), nil
+			},
+			EncryptStub: func(b []byte) ([]byte, error) {
+				return append([]byte("encrypted:"), b...), nil
+			},
+		}
+
+		store = storage.New(db, encryptor).WithStateHistoryLimit(2)
+	})
+
+	storeDeployment := func(operationType, state string) {
+		var tfState []byte
+		if state != "" {
+			tfState = []byte(state)
+		}
+
+		Expect(store.StoreTerraformDeployment(storage.TerraformDeployment{
+			ID:                 "fake-id",
+			Workspace:          &workspace.TerraformWorkspace{State: tfState},
+			LastOperationType:  operationType,
+			LastOperationState: "succeeded",
+		})).To(Succeed())
+	}
+
+	state := func(n int) string {
+		return fmt.Sprintf(`{"terraform_version":"1.%d.0"}`, n)
+	}
+
+	Describe("StoreTerraformDeployment", func() {
+		It("takes a snapshot when the state changes", func() {
+			storeDeployment("provision", state(1))
+			storeDeployment("provision", state(1))
+			storeDeployment("update", state(2))
+
+			history, err := store.GetTerraformDeploymentHistory("fake-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].DeploymentID).To(Equal("fake-id"))
+			Expect(history[0].Revision).To(Equal(1))
+			Expect(history[0].OperationType).To(Equal("provision"))
+			Expect(history[0].OperationState).To(Equal("succeeded"))
+			Expect(history[0].CreatedAt).NotTo(BeZero())
+			Expect(history[0].Workspace.State).To(Equal([]byte(state(1))))
+		})
+
+		It("does not take a snapshot when there was no state", func() {
+			storeDeployment("provision", "")
+			storeDeployment("provision", state(1))
+
+			Expect(store.GetTerraformDeploymentHistory("fake-id")).To(BeEmpty())
+		})
+
+		It("keeps a bounded history", func() {
+			for i := 1; i <= 4; i++ {
+				storeDeployment("update", state(i))
+			}
+
+			history, err := store.GetTerraformDeploymentHistory("fake-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(2))
+			Expect(history[0].Revision).To(Equal(3))
+			Expect(history[0].Workspace.State).To(Equal([]byte(state(3))))
+			Expect(history[1].Revision).To(Equal(2))
+			Expect(history[1].Workspace.State).To(Equal([]byte(state(2))))
+		})
+
+		It("does not take snapshots when the history is disabled", func() {
+			store = store.WithStateHistoryLimit(0)
+			storeDeployment("provision", state(1))
+			storeDeployment("update", state(2))
+
+			Expect(store.GetTerraformDeploymentHistory("fake-id")).To(BeEmpty())
+		})
+	})
+
+	Describe("GetTerraformDeploymentSnapshot", func() {
+		BeforeEach(func() {
+			storeDeployment("provision", state(1))
+			storeDeployment("update", state(2))
+		})
+
+		It("returns the revision", func() {
+			snapshot, err := store.GetTerraformDeploymentSnapshot("fake-id", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshot.Revision).To(Equal(1))
+			Expect(snapshot.Workspace.State).To(Equal([]byte(state(1))))
+		})
+
+		It("fails when the revision does not exist", func() {
+			_, err := store.GetTerraformDeploymentSnapshot("fake-id", 42)
+			Expect(err).To(MatchError("could not find revision 42 of terraform deployment: fake-id"))
+		})
+
+		It("can be restored, adding the current workspace to the history", func() {
+			snapshot, err := store.GetTerraformDeploymentSnapshot("fake-id", 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(store.StoreTerraformDeployment(storage.TerraformDeployment{ID: "fake-id", Workspace: snapshot.Workspace})).To(Succeed())
+
+			deployment, err := store.GetTerraformDeployment("fake-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.TFWorkspace().State).To(Equal([]byte(state(1))))
+
+			history, err := store.GetTerraformDeploymentHistory("fake-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(2))
+			Expect(history[0].Revision).To(Equal(2))
+			Expect(history[0].Workspace.State).To(Equal([]byte(state(2))))
+		})
+	})
+
+	Describe("DeleteTerraformDeployment", func() {
+		It("deletes the history", func() {
+			storeDeployment("provision", state(1))
+			storeDeployment("update", state(2))
+
+			Expect(store.DeleteTerraformDeployment("fake-id")).To(Succeed())
+
+			var count int64
+			Expect(db.Model(&models.TerraformDeploymentSnapshot{}).Count(&count).Error).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+	})
+
+	When("there is a state store", func() {
+		var states map[string][]byte
+
+		BeforeEach(func() {
+			states = make(map[string][]byte)
+			store = store.WithStateStore(&storagefakes.FakeStateStore{
+				PutStub: func(key string, data []byte) error {
+					states[key] = data
+					return nil
+				},
+				GetStub: func(key string) ([]byte, error) {
+					data, ok := states[key]
+					if !ok {
+						return nil, errors.New("not found")
+					}
+					return data, nil
+				},
+				DeleteStub: func(key string) error {
+					delete(states, key)
+					return nil
+				},
+			})
+		})
+
+		It("keeps the snapshot states in the state store", func() {
+			for i := 1; i <= 4; i++ {
+				storeDeployment("update", state(i))
+			}
+
+			statesByKey := make(map[string][]byte)
+			for ref, data := range states {
+				key, _, _ := strings.Cut(ref, ".")
+				statesByKey[key] = data
+			}
+			Expect(states).To(HaveLen(3))
+			Expect(statesByKey).To(Equal(map[string][]byte{
+				"fake-id":   []byte("encrypted:" + state(4)),
+				"fake-id@2": []byte("encrypted:" + state(2)),
+				"fake-id@3": []byte("encrypted:" + state(3)),
+			}))
+
+			snapshot, err := store.GetTerraformDeploymentSnapshot("fake-id", 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshot.Workspace.State).To(Equal([]byte(state(3))))
+
+			Expect(store.DeleteTerraformDeployment("fake-id")).To(Succeed())
+			Expect(states).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+)
+
+// TerraformDrift is the result of the last drift check for a Terraform deployment
+type TerraformDrift struct {
+	DeploymentID    string
+	Drifted         bool
+	ResourceChanges []TerraformResourceChange
+	CheckError      string
+	CheckedAt       time.Time
+}
+
+// TerraformResourceChange describes a resource that "terraform plan" would change
+type TerraformResourceChange struct {
+	Address    string   `json:"address"`
+	Action     string   `json:"action"`
+	Attributes []string `json:"attributes,omitempty"`
+}
+
+func (s *Storage) StoreTerraformDrift(d TerraformDrift) error {
+	// Resource changes only contain resource addresses and attribute names,
+	// so unlike other fields they are not encrypted
+	changes, err := json.Marshal(d.ResourceChanges)
+	if err != nil {
+		return fmt.Errorf("error encoding resource changes: %w", err)
+	}
+
+	m := models.TerraformDrift{
+		ID:              d.DeploymentID,
+		Drifted:         d.Drifted,
+		ResourceChanges: changes,
+		CheckError:      d.CheckError,
+	}
+	if err := s.db.Save(&m).Error; err != nil {
+		return fmt.Errorf("error saving terraform drift: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetAllTerraformDrifts() ([]TerraformDrift, error) {
+	var receiver []models.TerraformDrift
+	if err := s.db.Order("id").Find(&receiver).Error; err != nil {
+		return nil, fmt.Errorf("error finding terraform drifts: %w", err)
+	}
+
+	result := make([]TerraformDrift, 0, len(receiver))
+	for _, m := range receiver {
+		var changes []TerraformResourceChange
+		if err := json.Unmarshal(m.ResourceChanges, &changes); err != nil {
+			return nil, fmt.Errorf("error decoding resource changes for %q: %w", m.ID, err)
+		}
+
+		result = append(result, TerraformDrift{
+			DeploymentID:    m.ID,
+			Drifted:         m.Drifted,
+			ResourceChanges: changes,
+			CheckError:      m.CheckError,
+			CheckedAt:       m.UpdatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Storage) DeleteTerraformDrift(id string) error {
+	if err := s.db.Where("id = ?", id).Delete(&models.TerraformDrift{}).Error; err != nil {
+		return fmt.Errorf("error deleting terraform drift: %w", err)
+	}
+	return nil
+}
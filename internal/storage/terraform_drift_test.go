@@ -0,0 +1,88 @@
+package storage_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+var _ = Describe("TerraformDrifts", func() {
+	Describe("StoreTerraformDrift", func() {
+		It("creates the right object in the database", func() {
+			err := store.StoreTerraformDrift(storage.TerraformDrift{
+				DeploymentID: "fake-id",
+				Drifted:      true,
+				ResourceChanges: []storage.TerraformResourceChange{
+					{Address: "random_password.password", Action: "update", Attributes: []string{"length"}},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			var receiver models.TerraformDrift
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver.ID).To(Equal("fake-id"))
+			Expect(receiver.Drifted).To(BeTrue())
+			Expect(receiver.ResourceChanges).To(MatchJSON(`[{"address":"random_password.password","action":"update","attributes":["length"]}]`))
+			Expect(receiver.CheckError).To(BeEmpty())
+		})
+
+		It("replaces the result of the previous check", func() {
+			Expect(store.StoreTerraformDrift(storage.TerraformDrift{DeploymentID: "fake-id", Drifted: true})).To(Succeed())
+			Expect(store.StoreTerraformDrift(storage.TerraformDrift{DeploymentID: "fake-id", CheckError: "boom"})).To(Succeed())
+
+			var receiver []models.TerraformDrift
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver).To(HaveLen(1))
+			Expect(receiver[0].Drifted).To(BeFalse())
+			Expect(receiver[0].CheckError).To(Equal("boom"))
+		})
+	})
+
+	Describe("GetAllTerraformDrifts", func() {
+		BeforeEach(func() {
+			Expect(store.StoreTerraformDrift(storage.TerraformDrift{DeploymentID: "fake-id-2"})).To(Succeed())
+			Expect(store.StoreTerraformDrift(storage.TerraformDrift{
+				DeploymentID: "fake-id-1",
+				Drifted:      true,
+				ResourceChanges: []storage.TerraformResourceChange{
+					{Address: "random_string.name", Action: "replace", Attributes: []string{"length", "special"}},
+				},
+			})).To(Succeed())
+		})
+
+		It("reads the results from the database", func() {
+			r, err := store.GetAllTerraformDrifts()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r).To(HaveLen(2))
+			Expect(r[0].DeploymentID).To(Equal("fake-id-1"))
+			Expect(r[0].Drifted).To(BeTrue())
+			Expect(r[0].ResourceChanges).To(Equal([]storage.TerraformResourceChange{
+				{Address: "random_string.name", Action: "replace", Attributes: []string{"length", "special"}},
+			}))
+			Expect(r[0].CheckedAt).NotTo(BeZero())
+			Expect(r[1].DeploymentID).To(Equal("fake-id-2"))
+			Expect(r[1].Drifted).To(BeFalse())
+			Expect(r[1].ResourceChanges).To(BeEmpty())
+		})
+	})
+
+	Describe("DeleteTerraformDrift", func() {
+		It("is deleted with the terraform deployment", func() {
+			addFakeTerraformDeployments()
+			Expect(store.StoreTerraformDrift(storage.TerraformDrift{DeploymentID: "fake-id-3"})).To(Succeed())
+
+			Expect(store.DeleteTerraformDeployment("fake-id-3")).To(Succeed())
+
+			r, err := store.GetAllTerraformDrifts()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r).To(BeEmpty())
+		})
+
+		It("is idempotent", func() {
+			Expect(store.DeleteTerraformDrift("not-there")).To(Succeed())
+		})
+	})
+})
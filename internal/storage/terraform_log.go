@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+)
+
+// TerraformLog is the output of a Terraform command that ran for an operation on a Terraform deployment
+type TerraformLog struct {
+	DeploymentID string    `json:"deployment_id"`
+	Command      string    `json:"command"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	Error        string    `json:"error,omitempty"`
+	Stdout       string    `json:"stdout,omitempty"`
+	Stderr       string    `json:"stderr,omitempty"`
+	TFLog        string    `json:"tf_log,omitempty"`
+}
+
+// WithTerraformLogs returns a Storage that keeps the output of Terraform commands for the specified
+// retention period. Each output is truncated to the maximum size, keeping the end where Terraform
+// reports errors. A retention period of zero disables the logs.
+func (s *Storage) WithTerraformLogs(retention time.Duration, maxSize int) *Storage {
+	c := *s
+	c.terraformLogRetention = retention
+	c.terraformLogMaxSize = maxSize
+	return &c
+}
+
+// StoreTerraformLog records the output of a Terraform command, and removes the logs
+// that are older than the retention period. The output is encrypted, because the JSON
+// of a plan and the TF_LOG output can contain the values of sensitive attributes.
+func (s *Storage) StoreTerraformLog(l TerraformLog) error {
+	if s.terraformLogRetention <= 0 {
+		return nil
+	}
+
+	stdout, err := s.encodeBytes([]byte(s.truncateTerraformLog(l.Stdout)))
+	if err != nil {
+		return fmt.Errorf("error encoding terraform log stdout: %w", err)
+	}
+	stderr, err := s.encodeBytes([]byte(s.truncateTerraformLog(l.Stderr)))
+	if err != nil {
+		return fmt.Errorf("error encoding terraform log stderr: %w", err)
+	}
+	tfLog, err := s.encodeBytes([]byte(s.truncateTerraformLog(l.TFLog)))
+	if err != nil {
+		return fmt.Errorf("error encoding terraform log TF_LOG output: %w", err)
+	}
+
+	m := models.TerraformLog{
+		DeploymentID: l.DeploymentID,
+		Command:      l.Command,
+		StartedAt:    l.StartedAt,
+		FinishedAt:   l.FinishedAt,
+		Error:        l.Error,
+		Stdout:       stdout,
+		Stderr:       stderr,
+		TFLog:        tfLog,
+	}
+	if err := s.db.Create(&m).Error; err != nil {
+		return fmt.Errorf("error creating terraform log: %w", err)
+	}
+
+	if err := s.db.Where("started_at < ?", time.Now().Add(-s.terraformLogRetention)).Delete(&models.TerraformLog{}).Error; err != nil {
+		return fmt.Errorf("error deleting expired terraform logs: %w", err)
+	}
+
+	return nil
+}
+
+// GetTerraformLogs returns the logs of the Terraform commands for a deployment, oldest first
+func (s *Storage) GetTerraformLogs(deploymentID string) ([]TerraformLog, error) {
+	var receiver []models.TerraformLog
+	if err := s.db.Where("deployment_id = ?", deploymentID).Order("id").Find(&receiver).Error; err != nil {
+		return nil, fmt.Errorf("error finding terraform logs: %w", err)
+	}
+
+	result := make([]TerraformLog, 0, len(receiver))
+	for _, m := range receiver {
+		stdout, err := s.decodeBytes(m.Stdout)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding terraform log stdout for %q: %w", m.DeploymentID, err)
+		}
+		stderr, err := s.decodeBytes(m.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding terraform log stderr for %q: %w", m.DeploymentID, err)
+		}
+		tfLog, err := s.decodeBytes(m.TFLog)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding terraform log TF_LOG output for %q: %w", m.DeploymentID, err)
+		}
+
+		result = append(result, TerraformLog{
+			DeploymentID: m.DeploymentID,
+			Command:      m.Command,
+			StartedAt:    m.StartedAt,
+			FinishedAt:   m.FinishedAt,
+			Error:        m.Error,
+			Stdout:       string(stdout),
+			Stderr:       string(stderr),
+			TFLog:        string(tfLog),
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Storage) truncateTerraformLog(output string) string {
+	if s.terraformLogMaxSize <= 0 || len(output) <= s.terraformLogMaxSize {
+		return output
+	}
+
+	return fmt.Sprintf("[truncated %d bytes]\n", len(output)-s.terraformLogMaxSize) + output[len(output)-s.terraformLogMaxSize:]
+}
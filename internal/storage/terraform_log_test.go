@@ -0,0 +1,113 @@
+package storage_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+)
+
+var _ = Describe("TerraformLogs", func() {
+	BeforeEach(func() {
+		store = store.WithTerraformLogs(time.Hour, 10)
+	})
+
+	Describe("StoreTerraformLog", func() {
+		It("records the encrypted output of the command", func() {
+			startedAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+			finishedAt := time.Now().Truncate(time.Second)
+
+			Expect(store.StoreTerraformLog(storage.TerraformLog{
+				DeploymentID: "fake-deployment-id",
+				Command:      "apply",
+				StartedAt:    startedAt,
+				FinishedAt:   finishedAt,
+				Error:        "exit status 1",
+				Stdout:       "fake out",
+				Stderr:       "fake err",
+				TFLog:        "fake log",
+			})).To(Succeed())
+
+			var receiver []models.TerraformLog
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver).To(HaveLen(1))
+			Expect(receiver[0].DeploymentID).To(Equal("fake-deployment-id"))
+			Expect(receiver[0].Command).To(Equal("apply"))
+			Expect(receiver[0].StartedAt).To(BeTemporally("==", startedAt))
+			Expect(receiver[0].FinishedAt).To(BeTemporally("==", finishedAt))
+			Expect(receiver[0].Error).To(Equal("exit status 1"))
+			Expect(string(receiver[0].Stdout)).To(Equal(`{"encrypted":fake out}`))
+			Expect(string(receiver[0].Stderr)).To(Equal(`{"encrypted":fake err}`))
+			Expect(string(receiver[0].TFLog)).To(Equal(`{"encrypted":fake log}`))
+		})
+
+		It("keeps the end of output that exceeds the maximum size", func() {
+			Expect(store.StoreTerraformLog(storage.TerraformLog{
+				DeploymentID: "fake-deployment-id",
+				StartedAt:    time.Now(),
+				Stdout:       strings.Repeat("a", 10) + "0123456789",
+				Stderr:       "short",
+			})).To(Succeed())
+
+			var receiver []models.TerraformLog
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(string(receiver[0].Stdout)).To(Equal("{\"encrypted\":[truncated 10 bytes]\n0123456789}"))
+			Expect(string(receiver[0].Stderr)).To(Equal(`{"encrypted":short}`))
+		})
+
+		When("the output cannot be encrypted", func() {
+			It("returns an error", func() {
+				store = store.WithTerraformLogs(time.Hour, 0)
+				err := store.StoreTerraformLog(storage.TerraformLog{DeploymentID: "fake-deployment-id", StartedAt: time.Now(), Stderr: "cannot-be-encrypted"})
+				Expect(err).To(MatchError("error encoding terraform log stderr: encryption error: fake encryption error"))
+			})
+		})
+
+		It("removes the logs that are older than the retention period", func() {
+			Expect(store.StoreTerraformLog(storage.TerraformLog{DeploymentID: "fake-deployment-id", Command: "init", StartedAt: time.Now().Add(-2 * time.Hour)})).To(Succeed())
+			Expect(store.StoreTerraformLog(storage.TerraformLog{DeploymentID: "other-deployment-id", Command: "apply", StartedAt: time.Now()})).To(Succeed())
+
+			var count int64
+			Expect(db.Model(&models.TerraformLog{}).Count(&count).Error).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(1)))
+		})
+
+		It("does not record anything when the logs are disabled", func() {
+			store = store.WithTerraformLogs(0, 0)
+			Expect(store.StoreTerraformLog(storage.TerraformLog{DeploymentID: "fake-deployment-id", StartedAt: time.Now()})).To(Succeed())
+
+			var count int64
+			Expect(db.Model(&models.TerraformLog{}).Count(&count).Error).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+	})
+
+	Describe("GetTerraformLogs", func() {
+		It("returns the decrypted logs of the deployment, oldest first", func() {
+			Expect(db.Create(&models.TerraformLog{DeploymentID: "fake-deployment-id", Command: "init", Stdout: []byte(`"init out"`)}).Error).NotTo(HaveOccurred())
+			Expect(db.Create(&models.TerraformLog{DeploymentID: "other-deployment-id", Command: "init"}).Error).NotTo(HaveOccurred())
+			Expect(db.Create(&models.TerraformLog{DeploymentID: "fake-deployment-id", Command: "apply", TFLog: []byte(`"apply log"`)}).Error).NotTo(HaveOccurred())
+
+			logs, err := store.GetTerraformLogs("fake-deployment-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logs).To(HaveLen(2))
+			Expect(logs[0].Command).To(Equal("init"))
+			Expect(logs[0].Stdout).To(MatchJSON(`{"decrypted":"init out"}`))
+			Expect(logs[1].Command).To(Equal("apply"))
+			Expect(logs[1].TFLog).To(MatchJSON(`{"decrypted":"apply log"}`))
+		})
+
+		When("the output cannot be decrypted", func() {
+			It("returns an error", func() {
+				Expect(db.Create(&models.TerraformLog{DeploymentID: "fake-deployment-id", Stderr: []byte("cannot-be-decrypted")}).Error).NotTo(HaveOccurred())
+
+				_, err := store.GetTerraformLogs("fake-deployment-id")
+				Expect(err).To(MatchError(`error decoding terraform log stderr for "fake-deployment-id": decryption error: fake decryption error`))
+			})
+		})
+	})
+})
@@ -15,6 +15,9 @@ func (s *Storage) UpdateAllRecords() error {
 		s.updateAllProvisionRequestDetails,
 		s.updateAllServiceInstanceDetails,
 		s.updateAllTerraformDeployments,
+		s.updateAllTerraformDeploymentSnapshots,
+		s.updateAllTerraformLogs,
+		s.updateAllOperationLogs,
 	}
 	for _, e := range updaters {
 		if err := e(); err != nil {
@@ -123,7 +126,10 @@ func (s *Storage) updateAllServiceInstanceDetails() error {
 
 func (s *Storage) updateAllTerraformDeployments() error {
 	var terraformDeploymentBatch []models.TerraformDeployment
-	result := s.db.FindInBatches(&terraformDeploymentBatch, 100, func(tx *gorm.DB, batchNumber int) error {
+	var states replacedStates
+	result := s.db.FindInBatches(&terraformDeploymentBatch, 100, func(tx *gorm.DB, batchNumber int) (err error) {
+		defer func() { states.release(s, err != nil) }()
+
 		for i := range terraformDeploymentBatch {
 			data, err := s.decodeBytes(terraformDeploymentBatch[i].Workspace)
 			if err != nil {
@@ -134,6 +140,15 @@ func (s *Storage) updateAllTerraformDeployments() error {
 			if err != nil {
 				return fmt.Errorf("encode error for %q: %w", terraformDeploymentBatch[i].ID, err)
 			}
+
+			if terraformDeploymentBatch[i].StateRef != "" {
+				ref, err := s.updateState(terraformDeploymentBatch[i].StateRef, terraformDeploymentBatch[i].StateChecksum)
+				if err != nil {
+					return fmt.Errorf("state error for %q: %w", terraformDeploymentBatch[i].ID, err)
+				}
+				states.replace(terraformDeploymentBatch[i].StateRef, ref)
+				terraformDeploymentBatch[i].StateRef = ref
+			}
 		}
 
 		return tx.Save(&terraformDeploymentBatch).Error
@@ -144,3 +159,128 @@ func (s *Storage) updateAllTerraformDeployments() error {
 
 	return nil
 }
+
+func (s *Storage) updateAllTerraformDeploymentSnapshots() error {
+	var terraformDeploymentSnapshotBatch []models.TerraformDeploymentSnapshot
+	var states replacedStates
+	result := s.db.FindInBatches(&terraformDeploymentSnapshotBatch, 100, func(tx *gorm.DB, batchNumber int) (err error) {
+		defer func() { states.release(s, err != nil) }()
+
+		for i := range terraformDeploymentSnapshotBatch {
+			data, err := s.decodeBytes(terraformDeploymentSnapshotBatch[i].Workspace)
+			if err != nil {
+				return fmt.Errorf("decode error for %q revision %d: %w", terraformDeploymentSnapshotBatch[i].DeploymentID, terraformDeploymentSnapshotBatch[i].Revision, err)
+			}
+
+			terraformDeploymentSnapshotBatch[i].Workspace, err = s.encodeBytes(data)
+			if err != nil {
+				return fmt.Errorf("encode error for %q revision %d: %w", terraformDeploymentSnapshotBatch[i].DeploymentID, terraformDeploymentSnapshotBatch[i].Revision, err)
+			}
+
+			if terraformDeploymentSnapshotBatch[i].StateRef != "" {
+				ref, err := s.updateState(terraformDeploymentSnapshotBatch[i].StateRef, terraformDeploymentSnapshotBatch[i].StateChecksum)
+				if err != nil {
+					return fmt.Errorf("state error for %q revision %d: %w", terraformDeploymentSnapshotBatch[i].DeploymentID, terraformDeploymentSnapshotBatch[i].Revision, err)
+				}
+				states.replace(terraformDeploymentSnapshotBatch[i].StateRef, ref)
+				terraformDeploymentSnapshotBatch[i].StateRef = ref
+			}
+		}
+
+		return tx.Save(&terraformDeploymentSnapshotBatch).Error
+	})
+	if result.Error != nil {
+		return fmt.Errorf("error re-encoding terraform deployment snapshot: %w", result.Error)
+	}
+
+	return nil
+}
+
+func (s *Storage) updateAllTerraformLogs() error {
+	var terraformLogBatch []models.TerraformLog
+	result := s.db.FindInBatches(&terraformLogBatch, 100, func(tx *gorm.DB, batchNumber int) error {
+		for i := range terraformLogBatch {
+			for _, output := range []*[]byte{&terraformLogBatch[i].Stdout, &terraformLogBatch[i].Stderr, &terraformLogBatch[i].TFLog} {
+				data, err := s.decodeBytes(*output)
+				if err != nil {
+					return fmt.Errorf("decode error for terraform log %q: %w", terraformLogBatch[i].DeploymentID, err)
+				}
+
+				*output, err = s.encodeBytes(data)
+				if err != nil {
+					return fmt.Errorf("encode error for terraform log %q: %w", terraformLogBatch[i].DeploymentID, err)
+				}
+			}
+		}
+
+		return tx.Save(&terraformLogBatch).Error
+	})
+	if result.Error != nil {
+		return fmt.Errorf("error re-encoding terraform logs: %w", result.Error)
+	}
+
+	return nil
+}
+
+func (s *Storage) updateAllOperationLogs() error {
+	var operationLogBatch []models.OperationLog
+	result := s.db.FindInBatches(&operationLogBatch, 100, func(tx *gorm.DB, batchNumber int) error {
+		for i := range operationLogBatch {
+			for _, field := range []*[]byte{&operationLogBatch[i].OriginatingIdentity, &operationLogBatch[i].Stderr} {
+				if len(*field) == 0 {
+					continue
+				}
+
+				data, err := s.decodeBytes(*field)
+				if err != nil {
+					return fmt.Errorf("decode error for operation log %q: %w", operationLogBatch[i].DeploymentID, err)
+				}
+
+				*field, err = s.encodeBytes(data)
+				if err != nil {
+					return fmt.Errorf("encode error for operation log %q: %w", operationLogBatch[i].DeploymentID, err)
+				}
+			}
+		}
+
+		return tx.Save(&operationLogBatch).Error
+	})
+	if result.Error != nil {
+		return fmt.Errorf("error re-encoding operation logs: %w", result.Error)
+	}
+
+	return nil
+}
+
+// updateState writes the re-encrypted state to the StateStore under a new ref
+func (s *Storage) updateState(ref, checksum string) (string, error) {
+	state, err := s.loadState(ref, checksum)
+	if err != nil {
+		return "", err
+	}
+
+	return s.putState(stateKey(ref), state)
+}
+
+// replacedStates tracks the states that a batch of records is moved from and to,
+// so that the states that are no longer referred to can be deleted once the batch is saved
+type replacedStates struct {
+	previous, next []string
+}
+
+func (r *replacedStates) replace(previous, next string) {
+	r.previous = append(r.previous, previous)
+	r.next = append(r.next, next)
+}
+
+// release deletes the previous states when the batch was saved, or the next states when it failed
+func (r *replacedStates) release(s *Storage, failed bool) {
+	refs := r.previous
+	if failed {
+		refs = r.next
+	}
+	for _, ref := range refs {
+		s.releaseState(ref, "")
+	}
+	*r = replacedStates{}
+}
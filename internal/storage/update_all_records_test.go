@@ -15,6 +15,18 @@ var _ = Describe("UpdateAllRecords", func() {
 		addFakeBindRequestDetails()
 		addFakeServiceInstanceDetails()
 		addFakeTerraformDeployments()
+		Expect(db.Create(&models.TerraformLog{
+			DeploymentID: "fake-id-1",
+			Stdout:       []byte(`"fake-stdout"`),
+			Stderr:       []byte(`"fake-stderr"`),
+			TFLog:        []byte(`"fake-tf-log"`),
+		}).Error).NotTo(HaveOccurred())
+		Expect(db.Create(&models.OperationLog{
+			DeploymentID:        "fake-id-1",
+			OriginatingIdentity: []byte(`{"platform":"cloudfoundry"}`),
+			Stderr:              []byte(`"fake-stderr"`),
+		}).Error).NotTo(HaveOccurred())
+		Expect(db.Create(&models.OperationLog{DeploymentID: "fake-id-2"}).Error).NotTo(HaveOccurred())
 	})
 
 	It("updates all the records with the latest encoding", func() {
@@ -64,6 +76,25 @@ var _ = Describe("UpdateAllRecords", func() {
 			Expect(receiver[1].Workspace).To(Equal(fakeEncryptedWorkspace("fake-2", "")))
 			Expect(receiver[2].Workspace).To(Equal(fakeEncryptedWorkspace("fake-3", "1.2.4")))
 		})
+
+		By("checking terraform logs", func() {
+			var receiver []models.TerraformLog
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver).To(HaveLen(1))
+			Expect(receiver[0].Stdout).To(MatchJSON(`{"encrypted":{"decrypted":"fake-stdout"}}`))
+			Expect(receiver[0].Stderr).To(MatchJSON(`{"encrypted":{"decrypted":"fake-stderr"}}`))
+			Expect(receiver[0].TFLog).To(MatchJSON(`{"encrypted":{"decrypted":"fake-tf-log"}}`))
+		})
+
+		By("checking operation logs", func() {
+			var receiver []models.OperationLog
+			Expect(db.Order("id").Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver).To(HaveLen(2))
+			Expect(receiver[0].OriginatingIdentity).To(MatchJSON(`{"encrypted":{"decrypted":{"platform":"cloudfoundry"}}}`))
+			Expect(receiver[0].Stderr).To(MatchJSON(`{"encrypted":{"decrypted":"fake-stderr"}}`))
+			Expect(receiver[1].OriginatingIdentity).To(BeEmpty())
+			Expect(receiver[1].Stderr).To(BeEmpty())
+		})
 	})
 
 	Describe("errors", func() {
@@ -5,18 +5,18 @@ import (
 	"strings"
 )
 
-func newFromProvider(provider string) (TfProviderFQN, error) {
+func newFromProvider(provider, registry string) (TfProviderFQN, error) {
 	parts := strings.Split(provider, "/")
 	switch len(parts) {
 	case 1:
 		return TfProviderFQN{
-			Hostname:  defaultRegistry,
+			Hostname:  registry,
 			Namespace: defaultNamespace,
 			Type:      parts[0],
 		}, nil
 	case 2:
 		return TfProviderFQN{
-			Hostname:  defaultRegistry,
+			Hostname:  registry,
 			Namespace: parts[0],
 			Type:      parts[1],
 		}, nil
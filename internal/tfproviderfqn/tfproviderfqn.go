@@ -6,15 +6,26 @@ import "fmt"
 const (
 	prefix           = "terraform-provider-"
 	defaultRegistry  = "registry.terraform.io"
+	openTofuRegistry = "registry.opentofu.org"
 	defaultNamespace = "hashicorp"
 )
 
 func New(name, provider string) (TfProviderFQN, error) {
+	return newWithRegistry(name, provider, defaultRegistry)
+}
+
+// NewForOpenTofu is like New, but when no hostname is specified it uses the
+// registry that OpenTofu installs providers from, rather than the Terraform registry
+func NewForOpenTofu(name, provider string) (TfProviderFQN, error) {
+	return newWithRegistry(name, provider, openTofuRegistry)
+}
+
+func newWithRegistry(name, provider, registry string) (TfProviderFQN, error) {
 	switch provider {
 	case "":
-		return newFromName(name)
+		return newFromName(name, registry)
 	default:
-		return newFromProvider(provider)
+		return newFromProvider(provider, registry)
 	}
 }
 
@@ -40,3 +51,17 @@ func (t TfProviderFQN) String() string {
 	}
 	return fmt.Sprintf("%s/%s/%s", t.Hostname, t.Namespace, t.Type)
 }
+
+// ForTerraformAndOpenTofu returns the names of a provider from the Terraform or OpenTofu registry in
+// both registries, so that the provider can be installed for both. Other providers keep their name.
+func (t TfProviderFQN) ForTerraformAndOpenTofu() []TfProviderFQN {
+	switch t.Hostname {
+	case defaultRegistry, openTofuRegistry:
+		terraform, openTofu := t, t
+		terraform.Hostname = defaultRegistry
+		openTofu.Hostname = openTofuRegistry
+		return []TfProviderFQN{terraform, openTofu}
+	default:
+		return []TfProviderFQN{t}
+	}
+}
@@ -52,6 +52,43 @@ var _ = Describe("TfProviderFQN", func() {
 		})
 	})
 
+	Context("for OpenTofu", func() {
+		It("uses the OpenTofu registry when there is no hostname", func() {
+			n, err := tfproviderfqn.NewForOpenTofu("terraform-provider-mysql", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.String()).To(Equal("registry.opentofu.org/hashicorp/mysql"))
+
+			n, err = tfproviderfqn.NewForOpenTofu("", "cyrilgdn/postgresql")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.String()).To(Equal("registry.opentofu.org/cyrilgdn/postgresql"))
+		})
+
+		It("keeps the hostname when it is specified", func() {
+			n, err := tfproviderfqn.NewForOpenTofu("", "myreg.mydomain.com/cyrilgdn/postgresql")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.String()).To(Equal("myreg.mydomain.com/cyrilgdn/postgresql"))
+		})
+	})
+
+	Context("for Terraform and OpenTofu", func() {
+		It("names a provider from either registry in both registries", func() {
+			for _, provider := range []string{"registry.terraform.io/cyrilgdn/postgresql", "registry.opentofu.org/cyrilgdn/postgresql"} {
+				n, err := tfproviderfqn.New("", provider)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(n.ForTerraformAndOpenTofu()).To(Equal([]tfproviderfqn.TfProviderFQN{
+					{Hostname: "registry.terraform.io", Namespace: "cyrilgdn", Type: "postgresql"},
+					{Hostname: "registry.opentofu.org", Namespace: "cyrilgdn", Type: "postgresql"},
+				}))
+			}
+		})
+
+		It("keeps the name of a provider from another registry", func() {
+			n, err := tfproviderfqn.New("", "myreg.mydomain.com/cyrilgdn/postgresql")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.ForTerraformAndOpenTofu()).To(Equal([]tfproviderfqn.TfProviderFQN{n}))
+		})
+	})
+
 	Context("empty", func() {
 		It("is an empty string", func() {
 			Expect(tfproviderfqn.TfProviderFQN{}.String()).To(BeEmpty())
@@ -26,6 +26,34 @@ type FakeServiceProvider struct {
 		result1 map[string]any
 		result2 error
 	}
+	BindAsyncStub        func(context.Context, *varcontext.VarContext) (string, error)
+	bindAsyncMutex       sync.RWMutex
+	bindAsyncArgsForCall []struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+	}
+	bindAsyncReturns struct {
+		result1 string
+		result2 error
+	}
+	bindAsyncReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	CheckDriftStub        func(context.Context, string) (storage.TerraformDrift, error)
+	checkDriftMutex       sync.RWMutex
+	checkDriftArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	checkDriftReturns struct {
+		result1 storage.TerraformDrift
+		result2 error
+	}
+	checkDriftReturnsOnCall map[int]struct {
+		result1 storage.TerraformDrift
+		result2 error
+	}
 	CheckOperationConstraintsStub        func(string, string) error
 	checkOperationConstraintsMutex       sync.RWMutex
 	checkOperationConstraintsArgsForCall []struct {
@@ -64,6 +92,21 @@ type FakeServiceProvider struct {
 		result1 *string
 		result2 error
 	}
+	GetBindingOutputsStub        func(context.Context, string, string) (storage.JSONObject, error)
+	getBindingOutputsMutex       sync.RWMutex
+	getBindingOutputsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getBindingOutputsReturns struct {
+		result1 storage.JSONObject
+		result2 error
+	}
+	getBindingOutputsReturnsOnCall map[int]struct {
+		result1 storage.JSONObject
+		result2 error
+	}
 	GetImportedPropertiesStub        func(context.Context, string, []broker.BrokerVariable, map[string]any) (map[string]any, error)
 	getImportedPropertiesMutex       sync.RWMutex
 	getImportedPropertiesArgsForCall []struct {
@@ -94,6 +137,37 @@ type FakeServiceProvider struct {
 		result1 storage.JSONObject
 		result2 error
 	}
+	PlanUpdateStub        func(context.Context, *varcontext.VarContext) ([]storage.TerraformResourceChange, error)
+	planUpdateMutex       sync.RWMutex
+	planUpdateArgsForCall []struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+	}
+	planUpdateReturns struct {
+		result1 []storage.TerraformResourceChange
+		result2 error
+	}
+	planUpdateReturnsOnCall map[int]struct {
+		result1 []storage.TerraformResourceChange
+		result2 error
+	}
+	PollBindingStub        func(context.Context, string, string) (bool, string, error)
+	pollBindingMutex       sync.RWMutex
+	pollBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	pollBindingReturns struct {
+		result1 bool
+		result2 string
+		result3 error
+	}
+	pollBindingReturnsOnCall map[int]struct {
+		result1 bool
+		result2 string
+		result3 error
+	}
 	PollInstanceStub        func(context.Context, string) (bool, string, error)
 	pollInstanceMutex       sync.RWMutex
 	pollInstanceArgsForCall []struct {
@@ -138,6 +212,22 @@ type FakeServiceProvider struct {
 	unbindReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UnbindAsyncStub        func(context.Context, string, string, *varcontext.VarContext) (string, error)
+	unbindAsyncMutex       sync.RWMutex
+	unbindAsyncArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *varcontext.VarContext
+	}
+	unbindAsyncReturns struct {
+		result1 string
+		result2 error
+	}
+	unbindAsyncReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	UpdateStub        func(context.Context, *varcontext.VarContext) (models.ServiceInstanceDetails, error)
 	updateMutex       sync.RWMutex
 	updateArgsForCall []struct {
@@ -248,6 +338,136 @@ func (fake *FakeServiceProvider) BindReturnsOnCall(i int, result1 map[string]any
 	}{result1, result2}
 }
 
+func (fake *FakeServiceProvider) BindAsync(arg1 context.Context, arg2 *varcontext.VarContext) (string, error) {
+	fake.bindAsyncMutex.Lock()
+	ret, specificReturn := fake.bindAsyncReturnsOnCall[len(fake.bindAsyncArgsForCall)]
+	fake.bindAsyncArgsForCall = append(fake.bindAsyncArgsForCall, struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+	}{arg1, arg2})
+	stub := fake.BindAsyncStub
+	fakeReturns := fake.bindAsyncReturns
+	fake.recordInvocation("BindAsync", []interface{}{arg1, arg2})
+	fake.bindAsyncMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceProvider) BindAsyncCallCount() int {
+	fake.bindAsyncMutex.RLock()
+	defer fake.bindAsyncMutex.RUnlock()
+	return len(fake.bindAsyncArgsForCall)
+}
+
+func (fake *FakeServiceProvider) BindAsyncCalls(stub func(context.Context, *varcontext.VarContext) (string, error)) {
+	fake.bindAsyncMutex.Lock()
+	defer fake.bindAsyncMutex.Unlock()
+	fake.BindAsyncStub = stub
+}
+
+func (fake *FakeServiceProvider) BindAsyncArgsForCall(i int) (context.Context, *varcontext.VarContext) {
+	fake.bindAsyncMutex.RLock()
+	defer fake.bindAsyncMutex.RUnlock()
+	argsForCall := fake.bindAsyncArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceProvider) BindAsyncReturns(result1 string, result2 error) {
+	fake.bindAsyncMutex.Lock()
+	defer fake.bindAsyncMutex.Unlock()
+	fake.BindAsyncStub = nil
+	fake.bindAsyncReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) BindAsyncReturnsOnCall(i int, result1 string, result2 error) {
+	fake.bindAsyncMutex.Lock()
+	defer fake.bindAsyncMutex.Unlock()
+	fake.BindAsyncStub = nil
+	if fake.bindAsyncReturnsOnCall == nil {
+		fake.bindAsyncReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.bindAsyncReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) CheckDrift(arg1 context.Context, arg2 string) (storage.TerraformDrift, error) {
+	fake.checkDriftMutex.Lock()
+	ret, specificReturn := fake.checkDriftReturnsOnCall[len(fake.checkDriftArgsForCall)]
+	fake.checkDriftArgsForCall = append(fake.checkDriftArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CheckDriftStub
+	fakeReturns := fake.checkDriftReturns
+	fake.recordInvocation("CheckDrift", []interface{}{arg1, arg2})
+	fake.checkDriftMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceProvider) CheckDriftCallCount() int {
+	fake.checkDriftMutex.RLock()
+	defer fake.checkDriftMutex.RUnlock()
+	return len(fake.checkDriftArgsForCall)
+}
+
+func (fake *FakeServiceProvider) CheckDriftCalls(stub func(context.Context, string) (storage.TerraformDrift, error)) {
+	fake.checkDriftMutex.Lock()
+	defer fake.checkDriftMutex.Unlock()
+	fake.CheckDriftStub = stub
+}
+
+func (fake *FakeServiceProvider) CheckDriftArgsForCall(i int) (context.Context, string) {
+	fake.checkDriftMutex.RLock()
+	defer fake.checkDriftMutex.RUnlock()
+	argsForCall := fake.checkDriftArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceProvider) CheckDriftReturns(result1 storage.TerraformDrift, result2 error) {
+	fake.checkDriftMutex.Lock()
+	defer fake.checkDriftMutex.Unlock()
+	fake.CheckDriftStub = nil
+	fake.checkDriftReturns = struct {
+		result1 storage.TerraformDrift
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) CheckDriftReturnsOnCall(i int, result1 storage.TerraformDrift, result2 error) {
+	fake.checkDriftMutex.Lock()
+	defer fake.checkDriftMutex.Unlock()
+	fake.CheckDriftStub = nil
+	if fake.checkDriftReturnsOnCall == nil {
+		fake.checkDriftReturnsOnCall = make(map[int]struct {
+			result1 storage.TerraformDrift
+			result2 error
+		})
+	}
+	fake.checkDriftReturnsOnCall[i] = struct {
+		result1 storage.TerraformDrift
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceProvider) CheckOperationConstraints(arg1 string, arg2 string) error {
 	fake.checkOperationConstraintsMutex.Lock()
 	ret, specificReturn := fake.checkOperationConstraintsReturnsOnCall[len(fake.checkOperationConstraintsArgsForCall)]
@@ -437,6 +657,72 @@ func (fake *FakeServiceProvider) DeprovisionReturnsOnCall(i int, result1 *string
 	}{result1, result2}
 }
 
+func (fake *FakeServiceProvider) GetBindingOutputs(arg1 context.Context, arg2 string, arg3 string) (storage.JSONObject, error) {
+	fake.getBindingOutputsMutex.Lock()
+	ret, specificReturn := fake.getBindingOutputsReturnsOnCall[len(fake.getBindingOutputsArgsForCall)]
+	fake.getBindingOutputsArgsForCall = append(fake.getBindingOutputsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetBindingOutputsStub
+	fakeReturns := fake.getBindingOutputsReturns
+	fake.recordInvocation("GetBindingOutputs", []interface{}{arg1, arg2, arg3})
+	fake.getBindingOutputsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsCallCount() int {
+	fake.getBindingOutputsMutex.RLock()
+	defer fake.getBindingOutputsMutex.RUnlock()
+	return len(fake.getBindingOutputsArgsForCall)
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsCalls(stub func(context.Context, string, string) (storage.JSONObject, error)) {
+	fake.getBindingOutputsMutex.Lock()
+	defer fake.getBindingOutputsMutex.Unlock()
+	fake.GetBindingOutputsStub = stub
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsArgsForCall(i int) (context.Context, string, string) {
+	fake.getBindingOutputsMutex.RLock()
+	defer fake.getBindingOutputsMutex.RUnlock()
+	argsForCall := fake.getBindingOutputsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsReturns(result1 storage.JSONObject, result2 error) {
+	fake.getBindingOutputsMutex.Lock()
+	defer fake.getBindingOutputsMutex.Unlock()
+	fake.GetBindingOutputsStub = nil
+	fake.getBindingOutputsReturns = struct {
+		result1 storage.JSONObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsReturnsOnCall(i int, result1 storage.JSONObject, result2 error) {
+	fake.getBindingOutputsMutex.Lock()
+	defer fake.getBindingOutputsMutex.Unlock()
+	fake.GetBindingOutputsStub = nil
+	if fake.getBindingOutputsReturnsOnCall == nil {
+		fake.getBindingOutputsReturnsOnCall = make(map[int]struct {
+			result1 storage.JSONObject
+			result2 error
+		})
+	}
+	fake.getBindingOutputsReturnsOnCall[i] = struct {
+		result1 storage.JSONObject
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceProvider) GetImportedProperties(arg1 context.Context, arg2 string, arg3 []broker.BrokerVariable, arg4 map[string]any) (map[string]any, error) {
 	var arg3Copy []broker.BrokerVariable
 	if arg3 != nil {
@@ -574,6 +860,140 @@ func (fake *FakeServiceProvider) GetTerraformOutputsReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *FakeServiceProvider) PlanUpdate(arg1 context.Context, arg2 *varcontext.VarContext) ([]storage.TerraformResourceChange, error) {
+	fake.planUpdateMutex.Lock()
+	ret, specificReturn := fake.planUpdateReturnsOnCall[len(fake.planUpdateArgsForCall)]
+	fake.planUpdateArgsForCall = append(fake.planUpdateArgsForCall, struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+	}{arg1, arg2})
+	stub := fake.PlanUpdateStub
+	fakeReturns := fake.planUpdateReturns
+	fake.recordInvocation("PlanUpdate", []interface{}{arg1, arg2})
+	fake.planUpdateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceProvider) PlanUpdateCallCount() int {
+	fake.planUpdateMutex.RLock()
+	defer fake.planUpdateMutex.RUnlock()
+	return len(fake.planUpdateArgsForCall)
+}
+
+func (fake *FakeServiceProvider) PlanUpdateCalls(stub func(context.Context, *varcontext.VarContext) ([]storage.TerraformResourceChange, error)) {
+	fake.planUpdateMutex.Lock()
+	defer fake.planUpdateMutex.Unlock()
+	fake.PlanUpdateStub = stub
+}
+
+func (fake *FakeServiceProvider) PlanUpdateArgsForCall(i int) (context.Context, *varcontext.VarContext) {
+	fake.planUpdateMutex.RLock()
+	defer fake.planUpdateMutex.RUnlock()
+	argsForCall := fake.planUpdateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceProvider) PlanUpdateReturns(result1 []storage.TerraformResourceChange, result2 error) {
+	fake.planUpdateMutex.Lock()
+	defer fake.planUpdateMutex.Unlock()
+	fake.PlanUpdateStub = nil
+	fake.planUpdateReturns = struct {
+		result1 []storage.TerraformResourceChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) PlanUpdateReturnsOnCall(i int, result1 []storage.TerraformResourceChange, result2 error) {
+	fake.planUpdateMutex.Lock()
+	defer fake.planUpdateMutex.Unlock()
+	fake.PlanUpdateStub = nil
+	if fake.planUpdateReturnsOnCall == nil {
+		fake.planUpdateReturnsOnCall = make(map[int]struct {
+			result1 []storage.TerraformResourceChange
+			result2 error
+		})
+	}
+	fake.planUpdateReturnsOnCall[i] = struct {
+		result1 []storage.TerraformResourceChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) PollBinding(arg1 context.Context, arg2 string, arg3 string) (bool, string, error) {
+	fake.pollBindingMutex.Lock()
+	ret, specificReturn := fake.pollBindingReturnsOnCall[len(fake.pollBindingArgsForCall)]
+	fake.pollBindingArgsForCall = append(fake.pollBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.PollBindingStub
+	fakeReturns := fake.pollBindingReturns
+	fake.recordInvocation("PollBinding", []interface{}{arg1, arg2, arg3})
+	fake.pollBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeServiceProvider) PollBindingCallCount() int {
+	fake.pollBindingMutex.RLock()
+	defer fake.pollBindingMutex.RUnlock()
+	return len(fake.pollBindingArgsForCall)
+}
+
+func (fake *FakeServiceProvider) PollBindingCalls(stub func(context.Context, string, string) (bool, string, error)) {
+	fake.pollBindingMutex.Lock()
+	defer fake.pollBindingMutex.Unlock()
+	fake.PollBindingStub = stub
+}
+
+func (fake *FakeServiceProvider) PollBindingArgsForCall(i int) (context.Context, string, string) {
+	fake.pollBindingMutex.RLock()
+	defer fake.pollBindingMutex.RUnlock()
+	argsForCall := fake.pollBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeServiceProvider) PollBindingReturns(result1 bool, result2 string, result3 error) {
+	fake.pollBindingMutex.Lock()
+	defer fake.pollBindingMutex.Unlock()
+	fake.PollBindingStub = nil
+	fake.pollBindingReturns = struct {
+		result1 bool
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeServiceProvider) PollBindingReturnsOnCall(i int, result1 bool, result2 string, result3 error) {
+	fake.pollBindingMutex.Lock()
+	defer fake.pollBindingMutex.Unlock()
+	fake.PollBindingStub = nil
+	if fake.pollBindingReturnsOnCall == nil {
+		fake.pollBindingReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 string
+			result3 error
+		})
+	}
+	fake.pollBindingReturnsOnCall[i] = struct {
+		result1 bool
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeServiceProvider) PollInstance(arg1 context.Context, arg2 string) (bool, string, error) {
 	fake.pollInstanceMutex.Lock()
 	ret, specificReturn := fake.pollInstanceReturnsOnCall[len(fake.pollInstanceArgsForCall)]
@@ -771,6 +1191,73 @@ func (fake *FakeServiceProvider) UnbindReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeServiceProvider) UnbindAsync(arg1 context.Context, arg2 string, arg3 string, arg4 *varcontext.VarContext) (string, error) {
+	fake.unbindAsyncMutex.Lock()
+	ret, specificReturn := fake.unbindAsyncReturnsOnCall[len(fake.unbindAsyncArgsForCall)]
+	fake.unbindAsyncArgsForCall = append(fake.unbindAsyncArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *varcontext.VarContext
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UnbindAsyncStub
+	fakeReturns := fake.unbindAsyncReturns
+	fake.recordInvocation("UnbindAsync", []interface{}{arg1, arg2, arg3, arg4})
+	fake.unbindAsyncMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncCallCount() int {
+	fake.unbindAsyncMutex.RLock()
+	defer fake.unbindAsyncMutex.RUnlock()
+	return len(fake.unbindAsyncArgsForCall)
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncCalls(stub func(context.Context, string, string, *varcontext.VarContext) (string, error)) {
+	fake.unbindAsyncMutex.Lock()
+	defer fake.unbindAsyncMutex.Unlock()
+	fake.UnbindAsyncStub = stub
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncArgsForCall(i int) (context.Context, string, string, *varcontext.VarContext) {
+	fake.unbindAsyncMutex.RLock()
+	defer fake.unbindAsyncMutex.RUnlock()
+	argsForCall := fake.unbindAsyncArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncReturns(result1 string, result2 error) {
+	fake.unbindAsyncMutex.Lock()
+	defer fake.unbindAsyncMutex.Unlock()
+	fake.UnbindAsyncStub = nil
+	fake.unbindAsyncReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncReturnsOnCall(i int, result1 string, result2 error) {
+	fake.unbindAsyncMutex.Lock()
+	defer fake.unbindAsyncMutex.Unlock()
+	fake.UnbindAsyncStub = nil
+	if fake.unbindAsyncReturnsOnCall == nil {
+		fake.unbindAsyncReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.unbindAsyncReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceProvider) Update(arg1 context.Context, arg2 *varcontext.VarContext) (models.ServiceInstanceDetails, error) {
 	fake.updateMutex.Lock()
 	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
@@ -974,22 +1461,34 @@ func (fake *FakeServiceProvider) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.bindMutex.RLock()
 	defer fake.bindMutex.RUnlock()
+	fake.bindAsyncMutex.RLock()
+	defer fake.bindAsyncMutex.RUnlock()
+	fake.checkDriftMutex.RLock()
+	defer fake.checkDriftMutex.RUnlock()
 	fake.checkOperationConstraintsMutex.RLock()
 	defer fake.checkOperationConstraintsMutex.RUnlock()
 	fake.checkUpgradeAvailableMutex.RLock()
 	defer fake.checkUpgradeAvailableMutex.RUnlock()
 	fake.deprovisionMutex.RLock()
 	defer fake.deprovisionMutex.RUnlock()
+	fake.getBindingOutputsMutex.RLock()
+	defer fake.getBindingOutputsMutex.RUnlock()
 	fake.getImportedPropertiesMutex.RLock()
 	defer fake.getImportedPropertiesMutex.RUnlock()
 	fake.getTerraformOutputsMutex.RLock()
 	defer fake.getTerraformOutputsMutex.RUnlock()
+	fake.planUpdateMutex.RLock()
+	defer fake.planUpdateMutex.RUnlock()
+	fake.pollBindingMutex.RLock()
+	defer fake.pollBindingMutex.RUnlock()
 	fake.pollInstanceMutex.RLock()
 	defer fake.pollInstanceMutex.RUnlock()
 	fake.provisionMutex.RLock()
 	defer fake.provisionMutex.RUnlock()
 	fake.unbindMutex.RLock()
 	defer fake.unbindMutex.RUnlock()
+	fake.unbindAsyncMutex.RLock()
+	defer fake.unbindAsyncMutex.RUnlock()
 	fake.updateMutex.RLock()
 	defer fake.updateMutex.RUnlock()
 	fake.upgradeBindingsMutex.RLock()
@@ -52,6 +52,14 @@ type ServiceDefinition struct {
 	PlanUpdateable      bool
 	Plans               []ServicePlan
 
+	// AsyncBindings is true if bind and unbind operations for this service
+	// are run asynchronously and tracked through LastBindingOperation.
+	AsyncBindings bool
+
+	// TransientParameters are provision user inputs that only apply to the request in
+	// which they are set, so they are not stored with the service instance
+	TransientParameters []string
+
 	ProvisionInputVariables    []BrokerVariable
 	ImportInputVariables       []ImportVariable
 	ProvisionComputedVariables []varcontext.DefaultVariable
@@ -188,6 +196,20 @@ func (svc *ServiceDefinition) BindDefaultOverrides() map[string]any {
 	return viper.GetStringMap(svc.BindDefaultOverrideProperty())
 }
 
+// SensitiveFields returns the names of the inputs and outputs of the service whose values
+// are sensitive, and must not be logged
+func (svc *ServiceDefinition) SensitiveFields() []string {
+	var names []string
+	for _, variables := range [][]BrokerVariable{svc.PlanVariables, svc.ProvisionInputVariables, svc.BindInputVariables, svc.BindOutputVariables} {
+		for _, v := range variables {
+			if v.Sensitive {
+				names = append(names, v.FieldName)
+			}
+		}
+	}
+	return names
+}
+
 // TileUserDefinedPlansVariable returns the name of the user defined plans
 // variable for the broker tile.
 func (svc *ServiceDefinition) TileUserDefinedPlansVariable() string {
@@ -214,9 +236,11 @@ func (svc *ServiceDefinition) CatalogEntry() *Service {
 				ImageUrl:            svc.ImageURL,
 				SupportUrl:          svc.SupportURL,
 			},
-			Tags:          svc.Tags,
-			Bindable:      svc.Bindable,
-			PlanUpdatable: svc.PlanUpdateable,
+			Tags:                 svc.Tags,
+			Bindable:             svc.Bindable,
+			InstancesRetrievable: true,
+			BindingsRetrievable:  svc.Bindable,
+			PlanUpdatable:        svc.PlanUpdateable,
 		},
 		Plans: svc.Plans,
 	}
@@ -48,6 +48,19 @@ var _ = Describe("ServiceDefinition", func() {
 				Expect(catalogEntry.Tags).To(ConsistOf("Beta", "Tag"))
 
 			})
+
+			It("advertises that instances are retrievable", func() {
+				catalogEntry := serviceDefinition.CatalogEntry()
+
+				Expect(catalogEntry.InstancesRetrievable).To(BeTrue())
+			})
+
+			It("advertises that bindings are retrievable when the service is bindable", func() {
+				Expect(serviceDefinition.CatalogEntry().BindingsRetrievable).To(BeTrue())
+
+				serviceDefinition.Bindable = false
+				Expect(serviceDefinition.CatalogEntry().BindingsRetrievable).To(BeFalse())
+			})
 		})
 
 	})
@@ -295,6 +308,19 @@ var _ = Describe("ServiceDefinition", func() {
 		)
 	})
 
+	Describe("SensitiveFields", func() {
+		It("returns the inputs and outputs that are sensitive", func() {
+			serviceDefinition := broker.ServiceDefinition{
+				PlanVariables:           []broker.BrokerVariable{{FieldName: "plan_secret", Sensitive: true}, {FieldName: "region"}},
+				ProvisionInputVariables: []broker.BrokerVariable{{FieldName: "admin_password", Sensitive: true}, {FieldName: "name"}},
+				BindInputVariables:      []broker.BrokerVariable{{FieldName: "role"}},
+				BindOutputVariables:     []broker.BrokerVariable{{FieldName: "password", Sensitive: true}, {FieldName: "username"}},
+			}
+
+			Expect(serviceDefinition.SensitiveFields()).To(Equal([]string{"plan_secret", "admin_password", "password"}))
+		})
+	})
+
 	Describe("UserDefinedPlans", func() {
 
 		const (
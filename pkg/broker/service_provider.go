@@ -52,6 +52,17 @@ type ServiceProvider interface {
 	// Unbind deprovisions the resources created with Bind.
 	Unbind(ctx context.Context, instanceGUID, bindingID string, vc *varcontext.VarContext) error
 
+	// BindAsync starts the same work as Bind, but returns an operation ID rather than waiting for the result.
+	// The credentials can be read with GetBindingOutputs once PollBinding reports that the operation is done.
+	BindAsync(ctx context.Context, vc *varcontext.VarContext) (string, error)
+
+	// UnbindAsync starts the same work as Unbind, but returns an operation ID rather than waiting for the result.
+	UnbindAsync(ctx context.Context, instanceGUID, bindingID string, vc *varcontext.VarContext) (string, error)
+
+	PollBinding(ctx context.Context, instanceGUID, bindingID string) (bool, string, error)
+
+	GetBindingOutputs(ctx context.Context, instanceGUID, bindingID string) (storage.JSONObject, error)
+
 	// Deprovision deprovisions the service.
 	// If the deprovision is asynchronous (results in a long-running job), then operationId is returned.
 	// If no error and no operationId are returned, then the deprovision is expected to have been completed successfully.
@@ -64,6 +75,12 @@ type ServiceProvider interface {
 	CheckUpgradeAvailable(deploymentID string) error
 
 	CheckOperationConstraints(deploymentID string, operationType string) error
+
+	// CheckDrift reports whether the resources of a deployment have drifted from its Terraform state.
+	CheckDrift(ctx context.Context, deploymentID string) (storage.TerraformDrift, error)
+
+	// PlanUpdate reports the resources that an update would change, without making any changes.
+	PlanUpdate(ctx context.Context, updateContext *varcontext.VarContext) ([]storage.TerraformResourceChange, error)
 }
 
 //counterfeiter:generate . ServiceProviderStorage
@@ -72,4 +89,9 @@ type ServiceProviderStorage interface {
 	GetTerraformDeployment(id string) (storage.TerraformDeployment, error)
 	ExistsTerraformDeployment(id string) (bool, error)
 	GetServiceBindingIDsForServiceInstance(serviceInstanceID string) ([]string, error)
+	StartOperationLog(l storage.OperationLog) error
+	FinishOperationLog(deploymentID, state, message, stderr string) error
+	AcquireTerraformDeploymentLease(id, holder string) error
+	ReleaseTerraformDeploymentLease(id, holder string) error
+	StoreTerraformLog(l storage.TerraformLog) error
 }
@@ -62,6 +62,10 @@ type BrokerVariable struct {
 	ProhibitUpdate  bool           `yaml:"prohibit_update,omitempty"`
 	TFAttribute     string         `yaml:"tf_attribute,omitempty"`
 	TFAttributeSkip string         `yaml:"tf_attribute_skip,omitempty"`
+	// Sensitive values are write-only: they are masked in the broker logs and the
+	// generated documentation, are passed to Terraform as sensitive variables, and
+	// are not returned when the instance or binding is fetched.
+	Sensitive bool `yaml:"sensitive,omitempty"`
 }
 
 // ImportVariable Variable definition for TF import support
@@ -155,6 +159,10 @@ func (bv *BrokerVariable) ToSchema() map[string]any {
 		schema[validation.KeyTFAttributeSkip] = bv.TFAttributeSkip
 	}
 
+	if bv.Sensitive {
+		schema[validation.KeyWriteOnly] = true
+	}
+
 	return schema
 }
 
@@ -90,6 +90,12 @@ func TestBrokerVariable_ToSchema(t *testing.T) {
 				"tf_attribute_skip": "existing",
 			},
 		},
+		"sensitive is write only": {
+			BrokerVariable{Sensitive: true},
+			map[string]any{
+				"writeOnly": true,
+			},
+		},
 		"prohibit update is copied": {
 			BrokerVariable{ProhibitUpdate: true},
 			map[string]any{
@@ -136,7 +136,7 @@ func finfo(pack string, out io.Writer) error {
 		w := cmdTabWriter(out)
 		fmt.Fprintln(w, "NAME\tVERSION\tSOURCE")
 		for _, resource := range mf.TerraformVersions {
-			fmt.Fprintf(w, "%s\t%s\t%s\n", "terraform", resource.Version.String(), resource.Source)
+			fmt.Fprintf(w, "%s\t%s\t%s\n", resource.BinaryName(), resource.Version.String(), resource.Source)
 		}
 		for _, resource := range mf.TerraformProviders {
 			fmt.Fprintf(w, "%s\t%s\t%s\n", resource.Name, resource.Version.String(), resource.Source)
@@ -35,6 +35,12 @@ const (
 	brokerpakSourcesKey     = "brokerpak.sources"
 	brokerpakConfigKey      = "brokerpak.config"
 	brokerpakBuiltinPathKey = "brokerpak.builtin.path"
+
+	maxConcurrentExecutionsKey    = "brokerpak.terraform.max_concurrent_executions"
+	maxQueuedOperationsKey        = "brokerpak.terraform.max_queued_operations"
+	maxParallelBindingUpgradesKey = "brokerpak.terraform.max_parallel_binding_upgrades"
+	pluginCacheDirKey             = "brokerpak.terraform.plugin_cache_dir"
+	tfLogLevelKey                 = "terraform.logs.tf_log"
 )
 
 var loadBuiltinToggle = toggles.Features.Toggle("enable-builtin-brokerpaks", true, `Load brokerpaks that are built-in to the software.`)
@@ -43,6 +49,10 @@ func init() {
 	viper.SetDefault(brokerpakSourcesKey, "{}")
 	viper.SetDefault(brokerpakConfigKey, "{}")
 	viper.SetDefault(brokerpakBuiltinPathKey, BuiltinPakLocation)
+	viper.SetDefault(maxConcurrentExecutionsKey, 0)
+	viper.SetDefault(maxQueuedOperationsKey, 100)
+	viper.SetDefault(maxParallelBindingUpgradesKey, 5)
+	_ = viper.BindEnv(tfLogLevelKey, "TERRAFORM_LOGS_TF_LOG")
 }
 
 // BrokerpakSourceConfig represents a single configuration of a brokerpak.
@@ -57,6 +67,12 @@ type BrokerpakSourceConfig struct {
 	Config string `json:"config"`
 	// Notes holds user-defined notes about the Brokerpak and shouldn't be used programatically.
 	Notes string `json:"notes"`
+	// MaxConcurrentExecutions holds an optional limit on the Terraform commands of the Brokerpak that run
+	// at the same time. When set, the Brokerpak does not share the limits in the ServerConfig.
+	MaxConcurrentExecutions int `json:"max_concurrent_executions"`
+	// MaxQueuedOperations holds an optional limit on the operations of the Brokerpak that can wait for
+	// Terraform. It only applies when MaxConcurrentExecutions is set, and defaults to the limit in the ServerConfig.
+	MaxQueuedOperations int `json:"max_queued_operations"`
 }
 
 var _ validation.Validatable = (*BrokerpakSourceConfig)(nil)
@@ -72,6 +88,14 @@ func (b *BrokerpakSourceConfig) Validate() (errs *validation.FieldError) {
 
 	errs = errs.Also(validation.ErrIfNotJSON(json.RawMessage(b.Config), "config"))
 
+	if b.MaxConcurrentExecutions < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(b.MaxConcurrentExecutions, "max_concurrent_executions"))
+	}
+
+	if b.MaxQueuedOperations < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(b.MaxQueuedOperations, "max_queued_operations"))
+	}
+
 	return errs
 }
 
@@ -100,6 +124,26 @@ type ServerConfig struct {
 
 	// Brokerpaks holds list of brokerpaks to load.
 	Brokerpaks map[string]BrokerpakSourceConfig
+
+	// MaxConcurrentExecutions limits the Terraform commands that run at the same time,
+	// for the brokerpaks that do not have their own limit. Zero means no limit.
+	MaxConcurrentExecutions int
+
+	// MaxQueuedOperations limits the operations that can wait for Terraform in addition
+	// to those that are running, when MaxConcurrentExecutions is set.
+	MaxQueuedOperations int
+
+	// MaxParallelBindingUpgrades limits the bindings of a service instance that are
+	// upgraded at the same time.
+	MaxParallelBindingUpgrades int
+
+	// PluginCacheDir holds the shared provider plugin caches of the brokerpaks.
+	// The plugin cache is not used when it is empty.
+	PluginCacheDir string
+
+	// TFLogLevel holds the TF_LOG level of the Terraform logs that are recorded
+	// for each operation. Terraform does not log when it is empty.
+	TFLogLevel string
 }
 
 var _ validation.Validatable = (*ServerConfig)(nil)
@@ -125,8 +169,13 @@ func NewServerConfigFromEnv() (*ServerConfig, error) {
 	}
 
 	cfg := ServerConfig{
-		Config:     viper.GetString(brokerpakConfigKey),
-		Brokerpaks: paks,
+		Config:                     viper.GetString(brokerpakConfigKey),
+		Brokerpaks:                 paks,
+		MaxConcurrentExecutions:    viper.GetInt(maxConcurrentExecutionsKey),
+		MaxQueuedOperations:        viper.GetInt(maxQueuedOperationsKey),
+		MaxParallelBindingUpgrades: viper.GetInt(maxParallelBindingUpgradesKey),
+		PluginCacheDir:             viper.GetString(pluginCacheDirKey),
+		TFLogLevel:                 viper.GetString(tfLogLevelKey),
 	}
 
 	if err := cfg.Validate(); err != nil {
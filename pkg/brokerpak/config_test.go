@@ -107,6 +107,19 @@ func TestServiceConfig_Validate(t *testing.T) {
 			},
 			Err: "invalid JSON: Brokerpaks[good-key].config",
 		},
+		"negative terraform limits": {
+			Cfg: ServerConfig{
+				Config: "{}",
+				Brokerpaks: map[string]BrokerpakSourceConfig{
+					"good-key": {
+						BrokerpakURI:            "file:///some/path",
+						Config:                  "{}",
+						MaxConcurrentExecutions: -1,
+					},
+				},
+			},
+			Err: "invalid value: -1: Brokerpaks[good-key].max_concurrent_executions",
+		},
 	}
 
 	for tn, tc := range cases {
@@ -40,6 +40,9 @@ type registrarWalkFunc func(name string, pak BrokerpakSourceConfig, vc *varconte
 // environment variables and skipping certain services.
 type Registrar struct {
 	config *ServerConfig
+
+	// sharedPool limits Terraform for the brokerpaks that do not have their own limit
+	sharedPool *executor.Pool
 }
 
 // Register fetches the brokerpaks and registers them with the given registry.
@@ -65,6 +68,9 @@ func (r *Registrar) Register(registry broker.BrokerRegistry) error {
 		if err != nil {
 			return err
 		}
+		tfBinariesContext.Pool = r.pool(name, pak)
+		tfBinariesContext.MaxParallelBindingUpgrades = r.config.MaxParallelBindingUpgrades
+		tfBinariesContext.TFLogLevel = r.config.TFLogLevel
 
 		// register the services
 		services, err := brokerPak.Services()
@@ -72,11 +78,21 @@ func (r *Registrar) Register(registry broker.BrokerRegistry) error {
 			return err
 		}
 
+		mf, err := brokerPak.Manifest()
+		if err != nil {
+			return fmt.Errorf("error reading brokerpak manifest: %w", err)
+		}
+
 		var maintenanceInfo *domain.MaintenanceInfo
 		if featureflags.Enabled(featureflags.TfUpgradeEnabled) {
+			engine := "Terraform"
+			if mf.DefaultsToOpenTofu() {
+				engine = "OpenTofu"
+			}
+
 			maintenanceInfo = &domain.MaintenanceInfo{
 				Version:     tfBinariesContext.DefaultTfVersion.String(),
-				Description: fmt.Sprintf(`This upgrade provides support for Terraform version: %s. The upgrade operation will take a while. The instance and all associated bindings will be upgraded.`, tfBinariesContext.DefaultTfVersion.String()),
+				Description: fmt.Sprintf(`This upgrade provides support for %s version: %s. The upgrade operation will take a while. The instance and all associated bindings will be upgraded.`, engine, tfBinariesContext.DefaultTfVersion.String()),
 			}
 		}
 
@@ -97,11 +113,6 @@ func (r *Registrar) Register(registry broker.BrokerRegistry) error {
 			return errs
 		}
 
-		mf, err := brokerPak.Manifest()
-		if err != nil {
-			return fmt.Errorf("error reading brokerpak manifest: %w", err)
-		}
-
 		for env, config := range mf.EnvConfigMapping {
 			viper.BindEnv(config, env)
 		}
@@ -154,12 +165,23 @@ func (r *Registrar) extractTfBinaries(brokerPak *reader.BrokerPakReader, vc *var
 		return executor.TFBinariesContext{}, err
 	}
 
+	var pluginCache *executor.PluginCache
+	if r.config.PluginCacheDir != "" {
+		pluginCache, err = executor.NewPluginCache(r.config.PluginCacheDir, dir)
+		if err != nil {
+			return executor.TFBinariesContext{}, err
+		}
+	}
+
 	return executor.TFBinariesContext{
 		Dir:                  dir,
 		DefaultTfVersion:     tfVersion,
 		Params:               resolveParameters(manifest.Parameters, vc),
 		TfUpgradePath:        manifest.TerraformUpgradePath,
 		ProviderReplacements: manifest.TerraformStateProviderReplacements,
+		OpenTofuVersions:     manifest.OpenTofuVersions(),
+		PluginCache:          pluginCache,
+		RetryRules:           manifest.TerraformRetryRules,
 	}, nil
 }
 
@@ -188,6 +210,22 @@ func NewRegistrar(sc *ServerConfig) *Registrar {
 	return &Registrar{config: sc}
 }
 
+// pool returns the Terraform execution pool for a brokerpak
+func (r *Registrar) pool(name string, pak BrokerpakSourceConfig) *executor.Pool {
+	if pak.MaxConcurrentExecutions > 0 {
+		queueSize := pak.MaxQueuedOperations
+		if queueSize == 0 {
+			queueSize = r.config.MaxQueuedOperations
+		}
+		return executor.NewPool(name, pak.MaxConcurrentExecutions, queueSize)
+	}
+
+	if r.sharedPool == nil {
+		r.sharedPool = executor.NewPool("shared", r.config.MaxConcurrentExecutions, r.config.MaxQueuedOperations)
+	}
+	return r.sharedPool
+}
+
 // resolveParameters resolves environment variables from the given global and
 // brokerpak specific.
 func resolveParameters(params []manifest.Parameter, vc *varcontext.VarContext) map[string]string {
@@ -63,6 +63,37 @@ func TestNewRegistrar(t *testing.T) {
 	}
 }
 
+func TestRegistrar_pool(t *testing.T) {
+	registrar := NewRegistrar(&ServerConfig{MaxConcurrentExecutions: 2, MaxQueuedOperations: 1})
+
+	shared := registrar.pool("shared-pak", BrokerpakSourceConfig{})
+	if shared == nil {
+		t.Fatal("expected the shared pool, got nil")
+	}
+	if other := registrar.pool("other-pak", BrokerpakSourceConfig{}); other != shared {
+		t.Error("expected brokerpaks without their own limit to share a pool")
+	}
+
+	own := registrar.pool("own-pak", BrokerpakSourceConfig{MaxConcurrentExecutions: 1})
+	if own == nil || own == shared {
+		t.Fatal("expected a brokerpak with its own limit to have its own pool")
+	}
+
+	// the queue size defaults to the shared setting, so one operation runs and one waits
+	for i := 0; i < 2; i++ {
+		if _, err := own.Admit(); err != nil {
+			t.Fatalf("expected operation %d to be admitted, got %v", i, err)
+		}
+	}
+	if _, err := own.Admit(); !errors.Is(err, executor.ErrQueueFull) {
+		t.Errorf("expected %v, got %v", executor.ErrQueueFull, err)
+	}
+
+	if unlimited := NewRegistrar(&ServerConfig{}).pool("pak", BrokerpakSourceConfig{}); unlimited != nil {
+		t.Error("expected no pool when there are no limits")
+	}
+}
+
 func TestRegistrar_toDefinitions(t *testing.T) {
 	fakeDefn := func(name, id string) tf.TfServiceDefinitionV1 {
 		ex := tf.NewExampleTfServiceDefinition()
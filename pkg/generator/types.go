@@ -24,6 +24,7 @@ import (
 
 	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/validation"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
 )
 
 // CatalogDocumentation generates markdown documentation for the service catalog
@@ -51,7 +52,7 @@ func generateServiceDocumentation(svc *broker.ServiceDefinition) string {
 		"bindIn":             svc.BindInputVariables,
 		"bindOut":            svc.BindOutputVariables,
 		"provisionInputVars": svc.ProvisionInputVariables,
-		"examples":           svc.Examples,
+		"examples":           redactExamples(svc),
 	}
 
 	funcMap := template.FuncMap{
@@ -187,6 +188,10 @@ func varNotes(variable broker.BrokerVariable) string {
 		out += "**Required** "
 	}
 
+	if variable.Sensitive {
+		out += "**Sensitive** "
+	}
+
 	out += cleanLines(variable.Details)
 
 	if variable.Default != nil {
@@ -202,6 +207,36 @@ func varNotes(variable broker.BrokerVariable) string {
 	return out
 }
 
+// redactExamples returns the examples of the service with the values of the sensitive
+// parameters masked, so that they do not appear in the documentation
+func redactExamples(svc *broker.ServiceDefinition) []broker.ServiceExample {
+	var examples []broker.ServiceExample
+	for _, example := range svc.Examples {
+		example.ProvisionParams = redactParams(example.ProvisionParams, svc.ProvisionInputVariables)
+		example.BindParams = redactParams(example.BindParams, svc.BindInputVariables)
+		examples = append(examples, example)
+	}
+	return examples
+}
+
+func redactParams(params map[string]any, variables []broker.BrokerVariable) map[string]any {
+	if params == nil {
+		return nil
+	}
+
+	result := make(map[string]any, len(params))
+	for k, v := range params {
+		result[k] = v
+	}
+
+	for _, v := range variables {
+		if _, ok := result[v.FieldName]; ok && v.Sensitive {
+			result[v.FieldName] = redaction.Mask
+		}
+	}
+	return result
+}
+
 // constraintsToDoc converts a map of JSON Schema validation key/values to human-readable bullet points.
 func constraintsToDoc(schema map[string]any) []string {
 	// We use an anonymous struct rather than a map to get a strict ordering of
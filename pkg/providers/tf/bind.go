@@ -29,3 +29,17 @@ func (provider *TerraformProvider) Bind(ctx context.Context, bindContext *varcon
 
 	return provider.outputs(tfID, workspace.DefaultInstanceName)
 }
+
+// BindAsync creates a new backing Terraform job and returns its ID without waiting on the result.
+func (provider *TerraformProvider) BindAsync(ctx context.Context, bindContext *varcontext.VarContext) (string, error) {
+	provider.logger.Debug("terraform-bind-async", correlation.ID(ctx), lager.Data{
+		"context": bindContext.ToMap(),
+	})
+
+	tfID, err := provider.create(ctx, bindContext, provider.serviceDefinition.BindSettings, models.BindOperationType)
+	if err != nil {
+		return "", fmt.Errorf("error from provider bind: %w", err)
+	}
+
+	return tfID, nil
+}
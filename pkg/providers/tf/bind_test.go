@@ -94,7 +94,7 @@ var _ = Describe("Bind", func() {
 
 			By("checking that provision is marked as started")
 			Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
-			actualDeployment, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+			_, actualDeployment, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
 			Expect(actualDeployment).To(Equal(&deployment))
 			Expect(actualOperationType).To(Equal("bind"))
 
@@ -167,4 +167,36 @@ var _ = Describe("Bind", func() {
 		Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError("some TF issue happened"))
 	})
 
+	Describe("BindAsync", func() {
+		It("starts the bind and returns the operation ID without waiting", func() {
+			fakeDeploymentManager.CreateAndSaveDeploymentReturns(deployment, nil)
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			operationID, err := provider.BindAsync(context.TODO(), bindContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operationID).To(Equal(expectedTfID))
+
+			By("checking that bind is marked as started")
+			Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
+			_, _, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+			Expect(actualOperationType).To(Equal("bind"))
+
+			By("checking that it did not wait for the result")
+			Expect(fakeDeploymentManager.OperationStatusCallCount()).To(BeZero())
+
+			By("checking TF apply has been called")
+			Eventually(applyCallCount(fakeDefaultInvoker)).Should(Equal(1))
+			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
+		})
+
+		It("fails, when it errors saving the deployment", func() {
+			fakeDeploymentManager.CreateAndSaveDeploymentReturns(storage.TerraformDeployment{}, errors.New("cant save now"))
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			_, err := provider.BindAsync(context.TODO(), bindContext)
+
+			Expect(err).To(MatchError("error from provider bind: terraform provider create failed: cant save now"))
+		})
+	})
 })
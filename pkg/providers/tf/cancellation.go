@@ -0,0 +1,53 @@
+package tf
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrOperationCancelled is the cause of the failure of an operation that an operator cancelled
+var ErrOperationCancelled = errors.New("the operation was cancelled by an operator")
+
+// runningOperations holds the operations that run in this broker process, keyed by deployment ID,
+// so that they can be cancelled
+var runningOperations = struct {
+	lock       sync.Mutex
+	operations map[string]*runningOperation
+}{operations: make(map[string]*runningOperation)}
+
+type runningOperation struct {
+	cancel context.CancelCauseFunc
+}
+
+// CancelOperation stops Terraform for the operation in progress on a deployment, if the operation
+// runs in this broker process. The operation then fails with ErrOperationCancelled.
+// It returns whether an operation was cancelled.
+func CancelOperation(deploymentID string) bool {
+	runningOperations.lock.Lock()
+	defer runningOperations.lock.Unlock()
+
+	op, ok := runningOperations.operations[deploymentID]
+	if ok {
+		op.cancel(ErrOperationCancelled)
+	}
+	return ok
+}
+
+func trackOperation(deploymentID string, cancel context.CancelCauseFunc) (untrack func()) {
+	op := &runningOperation{cancel: cancel}
+
+	runningOperations.lock.Lock()
+	defer runningOperations.lock.Unlock()
+	runningOperations.operations[deploymentID] = op
+
+	return func() {
+		runningOperations.lock.Lock()
+		defer runningOperations.lock.Unlock()
+
+		// A later operation on the deployment may have replaced this one
+		if runningOperations.operations[deploymentID] == op {
+			delete(runningOperations.operations, deploymentID)
+		}
+	}
+}
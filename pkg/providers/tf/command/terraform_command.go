@@ -67,6 +67,45 @@ func (plan) Command() []string {
 	return []string{"plan", "-no-color"}
 }
 
+// NewPlanToFile creates a command that saves the plan to a file, so that it can be read with NewShowPlanJSON
+func NewPlanToFile(planFile string) TerraformCommand {
+	return planToFile{planFile: planFile}
+}
+
+type planToFile struct {
+	planFile string
+}
+
+func (cmd planToFile) Command() []string {
+	return []string{"plan", "-no-color", fmt.Sprintf("-out=%s", cmd.planFile)}
+}
+
+// NewShowPlanJSON creates a command that prints a saved plan in the Terraform JSON output format
+func NewShowPlanJSON(planFile string) TerraformCommand {
+	return showPlanJSON{planFile: planFile}
+}
+
+type showPlanJSON struct {
+	planFile string
+}
+
+func (cmd showPlanJSON) Command() []string {
+	return []string{"show", "-json", cmd.planFile}
+}
+
+// NewApplyPlan creates a command that applies a plan that was saved to a file with NewPlanToFile
+func NewApplyPlan(planFile string) TerraformCommand {
+	return applyPlan{planFile: planFile}
+}
+
+type applyPlan struct {
+	planFile string
+}
+
+func (cmd applyPlan) Command() []string {
+	return []string{"apply", "-no-color", cmd.planFile}
+}
+
 func NewImport(addr, id string) TerraformCommand {
 	return importCmd{Addr: addr, ID: id}
 }
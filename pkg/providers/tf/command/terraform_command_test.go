@@ -34,4 +34,25 @@ var _ = Context("Terraform Commands", func() {
 			Expect(apply.Command()).To(Equal([]string{"destroy", "-auto-approve", "-no-color"}))
 		})
 	})
+
+	Context("planToFile", func() {
+		It("calls plan with the plan file", func() {
+			plan := command.NewPlanToFile("tfplan")
+			Expect(plan.Command()).To(Equal([]string{"plan", "-no-color", "-out=tfplan"}))
+		})
+	})
+
+	Context("showPlanJSON", func() {
+		It("calls show with the plan file", func() {
+			show := command.NewShowPlanJSON("tfplan")
+			Expect(show.Command()).To(Equal([]string{"show", "-json", "tfplan"}))
+		})
+	})
+
+	Context("applyPlan", func() {
+		It("calls apply with the plan file", func() {
+			apply := command.NewApplyPlan("tfplan")
+			Expect(apply.Command()).To(Equal([]string{"apply", "-no-color", "tfplan"}))
+		})
+	})
 })
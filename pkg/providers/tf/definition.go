@@ -28,6 +28,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
 
 	"code.cloudfoundry.org/lager/v3"
+	"github.com/hashicorp/go-version"
 	"github.com/pivotal-cf/brokerapi/v9/domain"
 	"github.com/spf13/viper"
 
@@ -160,6 +161,14 @@ type TfServiceDefinitionV1 struct {
 	BindSettings        TfServiceDefinitionV1Action `yaml:"bind"`
 	Examples            []broker.ServiceExample     `yaml:"examples"`
 	PlanUpdateable      bool                        `yaml:"plan_updateable"`
+	AsyncBindings       bool                        `yaml:"async_bindings"`
+	Timeout             string                      `yaml:"timeout,omitempty"`
+	// UpdatePolicy says which resources updates can destroy or replace. Without a policy
+	// updates are not restricted.
+	UpdatePolicy *TfServiceDefinitionV1UpdatePolicy `yaml:"update_policy,omitempty"`
+	// RetryRules say which failed Terraform commands are retried. They are tried before
+	// the retry rules of the brokerpak.
+	RetryRules []executor.RetryRule `yaml:"retry_rules,omitempty"`
 
 	RequiredEnvVars []string
 }
@@ -196,13 +205,47 @@ func (tfb *TfServiceDefinitionV1) Validate() (errs *validation.FieldError) {
 	errs = errs.Also(tfb.ProvisionSettings.Validate().ViaField("provision"))
 	errs = errs.Also(tfb.BindSettings.Validate().ViaField("bind"))
 
+	errs = errs.Also(
+		validateTimeout(tfb.Timeout, "timeout"),
+		validateTimeouts(tfb.ProvisionSettings.Timeouts, provisionTimeoutOperations).ViaField("provision"),
+		validateTimeouts(tfb.BindSettings.Timeouts, bindTimeoutOperations).ViaField("bind"),
+		tfb.UpdatePolicy.Validate(tfb.ProvisionSettings.UserInputs).ViaField("update_policy"),
+	)
+
 	for i, v := range tfb.Examples {
 		errs = errs.Also(v.Validate().ViaFieldIndex("examples", i))
 	}
 
+	for i, v := range tfb.RetryRules {
+		errs = errs.Also(v.Validate().ViaFieldIndex("retry_rules", i))
+	}
+
 	return errs
 }
 
+// sensitiveVariablesVersion is the first version of Terraform that can declare variables as sensitive
+var sensitiveVariablesVersion = version.Must(version.NewVersion("0.14.0"))
+
+// ValidateTerraformVersions checks that all the versions of Terraform that the service can run
+// with, including those in the upgrade path, support the features of the service definition
+func (tfb *TfServiceDefinitionV1) ValidateTerraformVersions(versions []*version.Version) (errs *validation.FieldError) {
+	var lowest *version.Version
+	for _, v := range versions {
+		if v != nil && (lowest == nil || v.LessThan(lowest)) {
+			lowest = v
+		}
+	}
+
+	if lowest == nil || !lowest.LessThan(sensitiveVariablesVersion) {
+		return nil
+	}
+
+	return errs.Also(
+		tfb.ProvisionSettings.validateSensitiveInputs(lowest).ViaField("provision"),
+		tfb.BindSettings.validateSensitiveInputs(lowest).ViaField("bind"),
+	)
+}
+
 func (tfb *TfServiceDefinitionV1) resolveEnvVars() (map[string]string, error) {
 	vars := make(map[string]string)
 	for _, v := range tfb.RequiredEnvVars {
@@ -236,6 +279,26 @@ func (tfb *TfServiceDefinitionV1) ToService(tfBinContext executor.TFBinariesCont
 		return nil, err
 	}
 
+	if err := tfb.ValidateTerraformVersions(append([]*version.Version{tfBinContext.DefaultTfVersion}, tfBinContext.TfUpgradePath...)); err != nil {
+		return nil, err
+	}
+
+	if err := tfb.ProvisionSettings.markSensitiveVariables(); err != nil {
+		return nil, fmt.Errorf("error marking sensitive variables of provision template: %w", err)
+	}
+
+	if err := tfb.BindSettings.markSensitiveVariables(); err != nil {
+		return nil, fmt.Errorf("error marking sensitive variables of bind template: %w", err)
+	}
+
+	if err := tfb.ProvisionSettings.checkSensitiveOutputs(); err != nil {
+		return nil, fmt.Errorf("invalid outputs in provision template: %w", err)
+	}
+
+	if err := tfb.BindSettings.checkSensitiveOutputs(); err != nil {
+		return nil, fmt.Errorf("invalid outputs in bind template: %w", err)
+	}
+
 	envVars, err := tfb.resolveEnvVars()
 	if err != nil {
 		return nil, err
@@ -267,6 +330,16 @@ func (tfb *TfServiceDefinitionV1) ToService(tfBinContext executor.TFBinariesCont
 		Overwrite: true,
 	})
 
+	// The confirmation of an update must not carry over to later updates
+	var transientParameters []string
+	if tfb.UpdatePolicy != nil && tfb.UpdatePolicy.ConfirmationParameter != "" {
+		transientParameters = []string{tfb.UpdatePolicy.ConfirmationParameter}
+	}
+
+	var retryRules []executor.RetryRule
+	retryRules = append(retryRules, tfb.RetryRules...)
+	retryRules = append(retryRules, tfBinContext.RetryRules...)
+
 	constDefn := *tfb
 	return &broker.ServiceDefinition{
 		ID:                  tfb.ID,
@@ -274,6 +347,7 @@ func (tfb *TfServiceDefinitionV1) ToService(tfBinContext executor.TFBinariesCont
 		Description:         tfb.Description,
 		Bindable:            true,
 		PlanUpdateable:      tfb.PlanUpdateable,
+		AsyncBindings:       tfb.AsyncBindings,
 		DisplayName:         tfb.DisplayName,
 		DocumentationURL:    tfb.DocumentationURL,
 		ProviderDisplayName: tfb.ProviderDisplayName,
@@ -284,6 +358,7 @@ func (tfb *TfServiceDefinitionV1) ToService(tfBinContext executor.TFBinariesCont
 
 		ProvisionInputVariables: tfb.ProvisionSettings.UserInputs,
 		ImportInputVariables:    tfb.ProvisionSettings.ImportVariables,
+		TransientParameters:     transientParameters,
 		ProvisionComputedVariables: append(tfb.ProvisionSettings.Computed, varcontext.DefaultVariable{
 			Name:      "tf_id",
 			Default:   "tf:${request.instance_id}:",
@@ -295,8 +370,20 @@ func (tfb *TfServiceDefinitionV1) ToService(tfBinContext executor.TFBinariesCont
 		PlanVariables:         append(tfb.ProvisionSettings.PlanInputs, tfb.BindSettings.PlanInputs...),
 		Examples:              tfb.Examples,
 		ProviderBuilder: func(logger lager.Logger, store broker.ServiceProviderStorage) broker.ServiceProvider {
-			executorFactory := executor.NewExecutorFactory(tfBinContext.Dir, tfBinContext.Params, envVars)
-			return NewTerraformProvider(tfBinContext, invoker.NewTerraformInvokerFactory(executorFactory, tfBinContext.Dir, tfBinContext.ProviderReplacements), logger, constDefn, NewDeploymentManager(store))
+			deploymentManager := NewDeploymentManager(store)
+			executorFactory := executor.ExecutorFactory{
+				Dir:              tfBinContext.Dir,
+				OpenTofuVersions: tfBinContext.OpenTofuVersions,
+				Params:           tfBinContext.Params,
+				EnvVars:          envVars,
+				Pool:             tfBinContext.Pool,
+				PluginCache:      tfBinContext.PluginCache,
+				LogStore:         terraformLogStore{store: store},
+				TFLogLevel:       tfBinContext.TFLogLevel,
+				RetryRules:       retryRules,
+				RetryRecorder:    deploymentManager,
+			}
+			return NewTerraformProvider(tfBinContext, invoker.NewTerraformInvokerFactory(executorFactory, tfBinContext.Dir, tfBinContext.ProviderReplacements), logger, constDefn, deploymentManager)
 		},
 	}, nil
 }
@@ -364,6 +451,7 @@ type TfServiceDefinitionV1Action struct {
 	ImportParameterMappings  []ImportParameterMapping     `yaml:"import_parameter_mappings"`
 	ImportParametersToDelete []string                     `yaml:"import_parameters_to_delete"`
 	ImportParametersToAdd    []ImportParameterMapping     `yaml:"import_parameters_to_add"`
+	Timeouts                 map[string]string            `yaml:"timeouts,omitempty"`
 }
 
 var _ validation.Validatable = (*TfServiceDefinitionV1Action)(nil)
@@ -419,6 +507,73 @@ func (action *TfServiceDefinitionV1Action) LoadTemplate(srcDir string) error {
 	return nil
 }
 
+// markSensitiveVariables declares the template variables of the inputs that are sensitive as
+// sensitive Terraform variables
+func (action *TfServiceDefinitionV1Action) markSensitiveVariables() error {
+	names := action.sensitiveInputs()
+
+	var err error
+	if action.Template, err = workspace.MarkSensitiveVariables(action.Template, names); err != nil {
+		return err
+	}
+
+	for name, template := range action.Templates {
+		if action.Templates[name], err = workspace.MarkSensitiveVariables(template, names); err != nil {
+			return fmt.Errorf("template %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// checkSensitiveOutputs checks that the template outputs that use the variables of sensitive
+// inputs are declared sensitive, so that a template that Terraform would not apply is not registered
+func (action *TfServiceDefinitionV1Action) checkSensitiveOutputs() error {
+	names := action.sensitiveInputs()
+
+	if err := workspace.CheckSensitiveOutputs(action.Template, names); err != nil {
+		return err
+	}
+
+	for name, template := range action.Templates {
+		if err := workspace.CheckSensitiveOutputs(template, names); err != nil {
+			return fmt.Errorf("template %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (action *TfServiceDefinitionV1Action) sensitiveInputs() (names []string) {
+	for _, variables := range [][]broker.BrokerVariable{action.PlanInputs, action.UserInputs} {
+		for _, v := range variables {
+			if v.Sensitive {
+				names = append(names, v.FieldName)
+			}
+		}
+	}
+	return names
+}
+
+// validateSensitiveInputs returns an error for each sensitive input, as the Terraform version
+// cannot declare their variables as sensitive
+func (action *TfServiceDefinitionV1Action) validateSensitiveInputs(tfVersion *version.Version) (errs *validation.FieldError) {
+	validate := func(variables []broker.BrokerVariable, field string) {
+		for i, v := range variables {
+			if v.Sensitive {
+				errs = errs.Also((&validation.FieldError{
+					Message: fmt.Sprintf("sensitive inputs require Terraform %s or later, but Terraform %s is used", sensitiveVariablesVersion, tfVersion),
+					Paths:   []string{"sensitive"},
+				}).ViaFieldIndex(field, i))
+			}
+		}
+	}
+
+	validate(action.PlanInputs, "plan_inputs")
+	validate(action.UserInputs, "user_inputs")
+	return errs
+}
+
 // Validate implements validation.Validatable.
 func (action *TfServiceDefinitionV1Action) Validate() (errs *validation.FieldError) {
 	for i, v := range action.PlanInputs {
@@ -1,8 +1,10 @@
 package tf_test
 
 import (
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/hashicorp/go-version"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi/v9/domain"
@@ -74,6 +76,7 @@ var _ = Describe("Definition", func() {
 		)
 
 		BeforeEach(func() {
+			tfBinariesContext = executor.TFBinariesContext{}
 			serviceOffering = &tf.TfServiceDefinitionV1{
 				Version:             1,
 				Name:                "test-name",
@@ -105,6 +108,16 @@ var _ = Describe("Definition", func() {
 			Expect(service.ProviderDisplayName).To(Equal("company name"))
 			Expect(service.SupportURL).To(Equal("https://some-support-url"))
 			Expect(service.Tags).To(ConsistOf("Beta", "PostgreSQL"))
+			Expect(service.AsyncBindings).To(BeFalse())
+		})
+
+		When("async bindings are enabled", func() {
+			It("returns a broker service offering with async bindings", func() {
+				serviceOffering.AsyncBindings = true
+				service, err := serviceOffering.ToService(tfBinariesContext, maintenanceInfo)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(service.AsyncBindings).To(BeTrue())
+			})
 		})
 
 		When("no company name is configured", func() {
@@ -142,5 +155,91 @@ var _ = Describe("Definition", func() {
 
 			})
 		})
+
+		When("timeouts are configured", func() {
+			It("accepts durations for the operations of each action", func() {
+				serviceOffering.Timeout = "2h"
+				serviceOffering.ProvisionSettings.Timeouts = map[string]string{"provision": "1h", "update": "30m", "upgrade": "3h", "deprovision": "1h"}
+				serviceOffering.BindSettings.Timeouts = map[string]string{"bind": "5m", "unbind": "5m"}
+
+				_, err := serviceOffering.ToService(tfBinariesContext, maintenanceInfo)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("rejects a timeout that is not a positive duration", func() {
+				serviceOffering.Timeout = "-1h"
+				serviceOffering.ProvisionSettings.Timeouts = map[string]string{"provision": "forever"}
+
+				_, err := serviceOffering.ToService(tfBinariesContext, maintenanceInfo)
+				Expect(err).To(MatchError(ContainSubstring("invalid value: -1h: timeout")))
+				Expect(err).To(MatchError(ContainSubstring("invalid value: forever: provision.timeouts[provision]")))
+			})
+
+			It("rejects a timeout for an operation of the other action", func() {
+				serviceOffering.BindSettings.Timeouts = map[string]string{"provision": "1h"}
+
+				_, err := serviceOffering.ToService(tfBinariesContext, maintenanceInfo)
+				Expect(err).To(MatchError(ContainSubstring(`invalid key name "provision": bind.timeouts`)))
+			})
+		})
+
+		When("inputs are sensitive", func() {
+			It("declares their template variables as sensitive", func() {
+				serviceOffering.ProvisionSettings.UserInputs = []broker.BrokerVariable{
+					{FieldName: "admin_password", Type: broker.JSONTypeString, Details: "the admin password", Sensitive: true},
+					{FieldName: "name", Type: broker.JSONTypeString, Details: "the name"},
+				}
+				serviceOffering.ProvisionSettings.Templates = map[string]string{
+					"variables": "variable \"admin_password\" { type = string }\nvariable \"name\" { type = string }\n",
+				}
+
+				_, err := serviceOffering.ToService(tfBinariesContext, maintenanceInfo)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(serviceOffering.ProvisionSettings.Templates["variables"]).To(Equal("variable \"admin_password\" {\n  type      = string\n  sensitive = true\n}\nvariable \"name\" { type = string }\n"))
+			})
+
+			It("fails when a version of Terraform cannot declare sensitive variables", func() {
+				serviceOffering.BindSettings.UserInputs = []broker.BrokerVariable{
+					{FieldName: "admin_password", Type: broker.JSONTypeString, Details: "the admin password", Sensitive: true},
+				}
+				tfBinariesContext.DefaultTfVersion = version.Must(version.NewVersion("1.1.0"))
+				tfBinariesContext.TfUpgradePath = []*version.Version{version.Must(version.NewVersion("0.13.7")), version.Must(version.NewVersion("1.1.0"))}
+
+				_, err := serviceOffering.ToService(tfBinariesContext, maintenanceInfo)
+				Expect(err).To(MatchError("sensitive inputs require Terraform 0.14.0 or later, but Terraform 0.13.7 is used: bind.user_inputs[0].sensitive"))
+			})
+
+			It("fails when an output that uses a sensitive variable is not declared sensitive", func() {
+				serviceOffering.ProvisionSettings.UserInputs = []broker.BrokerVariable{
+					{FieldName: "admin_password", Type: broker.JSONTypeString, Details: "the admin password", Sensitive: true},
+				}
+				serviceOffering.ProvisionSettings.Templates = map[string]string{
+					"variables": "variable \"admin_password\" { type = string }\n",
+					"outputs":   "output \"password\" { value = var.admin_password }\n",
+				}
+				serviceOffering.ProvisionSettings.Outputs = []broker.BrokerVariable{
+					{FieldName: "password", Type: broker.JSONTypeString, Details: "the admin password"},
+				}
+
+				_, err := serviceOffering.ToService(tfBinariesContext, maintenanceInfo)
+				Expect(err).To(MatchError(`invalid outputs in provision template: template "outputs": outputs "password" use sensitive variables, so they must be declared sensitive`))
+			})
+		})
+
+		When("retry rules are configured", func() {
+			It("accepts valid rules", func() {
+				serviceOffering.RetryRules = []executor.RetryRule{{Pattern: "Error 409", MaxAttempts: 3, InitialBackoff: "5s"}}
+
+				_, err := serviceOffering.ToService(tfBinariesContext, maintenanceInfo)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("rejects a rule that is not valid", func() {
+				serviceOffering.RetryRules = []executor.RetryRule{{Pattern: "Error 409", MaxAttempts: 3, MaxBackoff: "never"}}
+
+				_, err := serviceOffering.ToService(tfBinariesContext, maintenanceInfo)
+				Expect(err).To(MatchError(ContainSubstring("invalid value: never: retry_rules[0].max_backoff")))
+			})
+		})
 	})
 })
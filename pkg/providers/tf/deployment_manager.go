@@ -1,27 +1,111 @@
 package tf
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pborman/uuid"
+	"github.com/pivotal-cf/brokerapi/v9/middlewares"
 
 	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/featureflags"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+	"github.com/cloudfoundry/cloud-service-broker/utils/request"
 )
 
+// maxOperationLogStderr is the number of bytes of Terraform error output kept in the operation log
+const maxOperationLogStderr = 4096
+
 type DeploymentManager struct {
 	store broker.ServiceProviderStorage
+	// leaseHolder identifies this DeploymentManager when it locks deployments.
+	// A DeploymentManager is created for each request, so operations started
+	// by different requests do not share a lease.
+	leaseHolder string
+
+	// retries are the retried Terraform commands of the operations in progress, by deployment
+	retries     map[string][]executor.Retry
+	retriesLock sync.Mutex
+
+	// operations are the deployments with an operation in progress that was started by this
+	// DeploymentManager, which holds their leases until the operations finish
+	operations     map[string]struct{}
+	operationsLock sync.Mutex
 }
 
 func NewDeploymentManager(store broker.ServiceProviderStorage) *DeploymentManager {
 	return &DeploymentManager{
-		store: store,
+		store:       store,
+		leaseHolder: uuid.New(),
+		retries:     make(map[string][]executor.Retry),
+		operations:  make(map[string]struct{}),
+	}
+}
+
+// RecordRetry implements executor.RetryRecorder. The retries are added to the last operation
+// message when the operation on the deployment finishes.
+func (d *DeploymentManager) RecordRetry(deploymentID string, retry executor.Retry) {
+	d.retriesLock.Lock()
+	defer d.retriesLock.Unlock()
+	d.retries[deploymentID] = append(d.retries[deploymentID], retry)
+}
+
+// takeRetries returns the retries recorded for a deployment and forgets them
+func (d *DeploymentManager) takeRetries(deploymentID string) []executor.Retry {
+	d.retriesLock.Lock()
+	defer d.retriesLock.Unlock()
+	retries := d.retries[deploymentID]
+	delete(d.retries, deploymentID)
+	return retries
+}
+
+// lockDeployment locks a deployment while its workspace is replaced, so that the workspace of an
+// operation in progress is not replaced. The returned function unlocks the deployment, unless this
+// DeploymentManager has started an operation on it, which keeps the lock until it finishes.
+func (d *DeploymentManager) lockDeployment(deploymentID string) (func(), error) {
+	if err := d.store.AcquireTerraformDeploymentLease(deploymentID, d.leaseHolder); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		if !d.operationInProgress(deploymentID) {
+			_ = d.store.ReleaseTerraformDeploymentLease(deploymentID, d.leaseHolder)
+		}
+	}, nil
+}
+
+func (d *DeploymentManager) operationInProgress(deploymentID string) bool {
+	d.operationsLock.Lock()
+	defer d.operationsLock.Unlock()
+	_, ok := d.operations[deploymentID]
+	return ok
+}
+
+func (d *DeploymentManager) setOperationInProgress(deploymentID string, inProgress bool) {
+	d.operationsLock.Lock()
+	defer d.operationsLock.Unlock()
+	if inProgress {
+		d.operations[deploymentID] = struct{}{}
+	} else {
+		delete(d.operations, deploymentID)
 	}
 }
 
+// CreateAndSaveDeployment stores a deployment with the workspace. It locks the deployment
+// while it does so, so that the workspace of an operation in progress is not replaced.
 func (d *DeploymentManager) CreateAndSaveDeployment(deploymentID string, workspace *workspace.TerraformWorkspace) (storage.TerraformDeployment, error) {
 	deployment := storage.TerraformDeployment{ID: deploymentID}
+	unlock, err := d.lockDeployment(deploymentID)
+	if err != nil {
+		return deployment, err
+	}
+	defer unlock()
+
 	exists, err := d.store.ExistsTerraformDeployment(deploymentID)
 	switch {
 	case err != nil:
@@ -38,15 +122,46 @@ func (d *DeploymentManager) CreateAndSaveDeployment(deploymentID string, workspa
 	return deployment, d.store.StoreTerraformDeployment(deployment)
 }
 
-func (d *DeploymentManager) MarkOperationStarted(deployment *storage.TerraformDeployment, operationType string) error {
+// MarkOperationStarted locks the deployment and records that the operation has started.
+// The lock is held until MarkOperationFinished is called, or until it expires. When this
+// DeploymentManager already has the operation in progress, the deployment is stored again
+// and the operation continues, without starting another entry in the operation log.
+func (d *DeploymentManager) MarkOperationStarted(ctx context.Context, deployment *storage.TerraformDeployment, operationType string) error {
+	unlock, err := d.lockDeployment(deployment.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	continued := d.operationInProgress(deployment.ID) && deployment.LastOperationType == operationType && deployment.LastOperationState == InProgress
+
 	deployment.LastOperationType = operationType
 	deployment.LastOperationState = InProgress
 	deployment.LastOperationMessage = fmt.Sprintf("%s %s", operationType, InProgress)
 
+	if !continued {
+		d.takeRetries(deployment.ID)
+
+		correlationID, _ := ctx.Value(middlewares.CorrelationIDKey).(string)
+		requestID, _ := ctx.Value(middlewares.RequestIdentityKey).(string)
+		if err := d.store.StartOperationLog(storage.OperationLog{
+			DeploymentID:        deployment.ID,
+			OperationType:       operationType,
+			State:               InProgress,
+			Message:             deployment.LastOperationMessage,
+			CorrelationID:       correlationID,
+			RequestID:           requestID,
+			OriginatingIdentity: request.DecodeOriginatingIdentityHeader(ctx),
+		}); err != nil {
+			return err
+		}
+	}
+
 	if err := d.store.StoreTerraformDeployment(*deployment); err != nil {
 		return err
 	}
 
+	d.setOperationInProgress(deployment.ID, true)
 	return nil
 }
 
@@ -61,12 +176,66 @@ func (d *DeploymentManager) MarkOperationFinished(deployment *storage.TerraformD
 		}
 		deployment.LastOperationState = Succeeded
 		deployment.LastOperationMessage = lastOperationMessage
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		deployment.LastOperationState = TimedOut
+		deployment.LastOperationMessage = fmt.Sprintf("%s %s: %s", deployment.LastOperationType, TimedOut, err)
 	} else {
 		deployment.LastOperationState = Failed
 		deployment.LastOperationMessage = fmt.Sprintf("%s %s: %s", deployment.LastOperationType, Failed, err)
 	}
 
-	return d.store.StoreTerraformDeployment(*deployment)
+	if retries := d.takeRetries(deployment.ID); len(retries) > 0 {
+		var reasons []string
+		for _, r := range retries {
+			reasons = append(reasons, r.String())
+		}
+		deployment.LastOperationMessage = fmt.Sprintf("%s (retried: %s)", deployment.LastOperationMessage, strings.Join(reasons, "; "))
+	}
+
+	// The lock is released even when the outcome cannot be stored, so that the operation can be retried
+	d.setOperationInProgress(deployment.ID, false)
+	defer func() { _ = d.store.ReleaseTerraformDeploymentLease(deployment.ID, d.leaseHolder) }()
+
+	if err := d.store.StoreTerraformDeployment(*deployment); err != nil {
+		return err
+	}
+
+	return d.store.FinishOperationLog(deployment.ID, deployment.LastOperationState, deployment.LastOperationMessage, operationStderr(err))
+}
+
+// MarkOperationFailed records that the operation in progress on a deployment has failed, for
+// when an operator knows that it will not finish. It does not stop Terraform, or release the
+// lock on the deployment, which expires once the broker running the operation has stopped it.
+func (d *DeploymentManager) MarkOperationFailed(deploymentID, message string) error {
+	deployment, err := d.store.GetTerraformDeployment(deploymentID)
+	if err != nil {
+		return err
+	}
+
+	if deployment.LastOperationState != InProgress {
+		return fmt.Errorf("no operation is in progress on %q: the last %s operation has %s", deploymentID, deployment.LastOperationType, deployment.LastOperationState)
+	}
+
+	reason := "marked as failed by an operator"
+	if message != "" {
+		reason = fmt.Sprintf("%s: %s", reason, message)
+	}
+
+	return d.MarkOperationFinished(&deployment, errors.New(reason))
+}
+
+// operationStderr returns the Terraform error output of a failed operation,
+// truncated so that a verbose failure does not bloat the operation log
+func operationStderr(err error) string {
+	var executionError *executor.ExecutionError
+	if !errors.As(err, &executionError) {
+		return ""
+	}
+
+	if len(executionError.StdErr) <= maxOperationLogStderr {
+		return executionError.StdErr
+	}
+	return executionError.StdErr[:maxOperationLogStderr] + "\n[truncated]"
 }
 
 func (d *DeploymentManager) OperationStatus(deploymentID string) (bool, string, error) {
@@ -78,7 +247,7 @@ func (d *DeploymentManager) OperationStatus(deploymentID string) (bool, string,
 	switch deployment.LastOperationState {
 	case Succeeded:
 		return true, deployment.LastOperationMessage, nil
-	case Failed:
+	case Failed, TimedOut:
 		return true, deployment.LastOperationMessage, errors.New(deployment.LastOperationMessage)
 	default:
 		return false, deployment.LastOperationMessage, nil
@@ -89,23 +258,22 @@ func (d *DeploymentManager) UpdateWorkspaceHCL(deploymentID string, serviceDefin
 	if !featureflags.Enabled(featureflags.DynamicHCLEnabled) && !featureflags.Enabled(featureflags.TfUpgradeEnabled) {
 		return nil
 	}
-	deployment, err := d.store.GetTerraformDeployment(deploymentID)
+	unlock, err := d.lockDeployment(deploymentID)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	currentWorkspace := deployment.TFWorkspace()
+	deployment, err := d.store.GetTerraformDeployment(deploymentID)
 	if err != nil {
 		return err
 	}
 
-	newWorkspace, err := workspace.NewWorkspace(templateVars, serviceDefinitionAction.Template, serviceDefinitionAction.Templates, []workspace.ParameterMapping{}, []string{}, []workspace.ParameterMapping{})
+	newWorkspace, err := regenerateWorkspace(deployment.TFWorkspace(), serviceDefinitionAction, templateVars)
 	if err != nil {
 		return err
 	}
 
-	newWorkspace.State = currentWorkspace.State
-
 	deployment.Workspace = newWorkspace
 	if err := d.store.StoreTerraformDeployment(deployment); err != nil {
 		return fmt.Errorf("terraform provider create failed: %w", err)
@@ -114,6 +282,18 @@ func (d *DeploymentManager) UpdateWorkspaceHCL(deploymentID string, serviceDefin
 	return nil
 }
 
+// regenerateWorkspace creates a workspace from the current templates of the action, which keeps
+// the state of the existing workspace
+func regenerateWorkspace(currentWorkspace *workspace.TerraformWorkspace, serviceDefinitionAction TfServiceDefinitionV1Action, templateVars map[string]any) (*workspace.TerraformWorkspace, error) {
+	newWorkspace, err := workspace.NewWorkspace(templateVars, serviceDefinitionAction.Template, serviceDefinitionAction.Templates, []workspace.ParameterMapping{}, []string{}, []workspace.ParameterMapping{})
+	if err != nil {
+		return nil, err
+	}
+
+	newWorkspace.State = currentWorkspace.State
+	return newWorkspace, nil
+}
+
 func (d *DeploymentManager) GetTerraformDeployment(deploymentID string) (storage.TerraformDeployment, error) {
 	return d.store.GetTerraformDeployment(deploymentID)
 }
@@ -1,17 +1,22 @@
 package tf_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/broker/brokerfakes"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/featureflags"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace/workspacefakes"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi/v9/middlewares"
 	"github.com/spf13/viper"
 )
 
@@ -105,6 +110,42 @@ var _ = Describe("DeploymentManager", func() {
 			Expect(err).To(MatchError("failed to get deployment"))
 		})
 
+		It("fails without storing, when the deployment is locked by another operation", func() {
+			fakeStore.AcquireTerraformDeploymentLeaseReturns(storage.ErrLeaseHeld)
+
+			_, err := deploymentManager.CreateAndSaveDeployment(deploymentID, ws)
+
+			Expect(err).To(MatchError(storage.ErrLeaseHeld))
+			Expect(fakeStore.StoreTerraformDeploymentCallCount()).To(BeZero())
+		})
+
+		It("releases the lock on the deployment once it is stored", func() {
+			_, err := deploymentManager.CreateAndSaveDeployment(deploymentID, ws)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStore.ReleaseTerraformDeploymentLeaseCallCount()).To(Equal(1))
+			releasedID, _ := fakeStore.ReleaseTerraformDeploymentLeaseArgsForCall(0)
+			Expect(releasedID).To(Equal(deploymentID))
+		})
+
+		It("releases the lock on the deployment, when storing the deployment fails", func() {
+			fakeStore.StoreTerraformDeploymentReturns(errors.New("couldn't store deployment"))
+
+			_, err := deploymentManager.CreateAndSaveDeployment(deploymentID, ws)
+
+			Expect(err).To(MatchError("couldn't store deployment"))
+			Expect(fakeStore.ReleaseTerraformDeploymentLeaseCallCount()).To(Equal(1))
+		})
+
+		It("keeps the lock of an operation that it started on the deployment", func() {
+			deployment := storage.TerraformDeployment{ID: deploymentID, Workspace: ws}
+			Expect(deploymentManager.MarkOperationStarted(context.TODO(), &deployment, "provision")).To(Succeed())
+
+			_, err := deploymentManager.CreateAndSaveDeployment(deploymentID, ws)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStore.ReleaseTerraformDeploymentLeaseCallCount()).To(BeZero())
+		})
 	})
 
 	Describe("MarkOperationStarted", func() {
@@ -129,7 +170,7 @@ var _ = Describe("DeploymentManager", func() {
 		})
 
 		It("updates last operation to in progress", func() {
-			err := deploymentManager.MarkOperationStarted(&existingDeployment, "provision")
+			err := deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "provision")
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStore.StoreTerraformDeploymentCallCount()).To(Equal(1))
@@ -145,9 +186,78 @@ var _ = Describe("DeploymentManager", func() {
 		It("fails, when storing deployment fails", func() {
 			fakeStore.StoreTerraformDeploymentReturns(errors.New("couldn't store deployment"))
 
-			err := deploymentManager.MarkOperationStarted(&existingDeployment, "provision")
+			err := deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "provision")
 
 			Expect(err).To(MatchError("couldn't store deployment"))
+			By("releasing the lock on the deployment")
+			Expect(fakeStore.ReleaseTerraformDeploymentLeaseCallCount()).To(Equal(1))
+		})
+
+		It("keeps the lock on the deployment until the operation finishes", func() {
+			err := deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "provision")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStore.ReleaseTerraformDeploymentLeaseCallCount()).To(BeZero())
+		})
+
+		It("starts an operation log with the request identity", func() {
+			ctx := context.WithValue(context.Background(), middlewares.CorrelationIDKey, "fake-correlation-id")
+			ctx = context.WithValue(ctx, middlewares.RequestIdentityKey, "fake-request-id")
+			ctx = context.WithValue(ctx, middlewares.OriginatingIdentityKey, "cloudfoundry eyJ1c2VyX2lkIjoiZmFrZS11c2VyIn0=")
+
+			err := deploymentManager.MarkOperationStarted(ctx, &existingDeployment, "provision")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStore.StartOperationLogCallCount()).To(Equal(1))
+			Expect(fakeStore.StartOperationLogArgsForCall(0)).To(Equal(storage.OperationLog{
+				DeploymentID:  "tf:instance:binding",
+				OperationType: "provision",
+				State:         "in progress",
+				Message:       "provision in progress",
+				CorrelationID: "fake-correlation-id",
+				RequestID:     "fake-request-id",
+				OriginatingIdentity: map[string]any{
+					"platform": "cloudfoundry",
+					"value":    map[string]any{"user_id": "fake-user"},
+				},
+			}))
+		})
+
+		It("fails, when starting the operation log fails", func() {
+			fakeStore.StartOperationLogReturns(errors.New("couldn't store operation log"))
+
+			err := deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "provision")
+
+			Expect(err).To(MatchError("couldn't store operation log"))
+			Expect(fakeStore.StoreTerraformDeploymentCallCount()).To(BeZero())
+		})
+
+		It("locks the deployment with the same holder for each operation", func() {
+			Expect(deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "upgrade")).To(Succeed())
+			Expect(deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "upgrade")).To(Succeed())
+
+			Expect(fakeStore.AcquireTerraformDeploymentLeaseCallCount()).To(Equal(2))
+			firstID, firstHolder := fakeStore.AcquireTerraformDeploymentLeaseArgsForCall(0)
+			secondID, secondHolder := fakeStore.AcquireTerraformDeploymentLeaseArgsForCall(1)
+			Expect(firstID).To(Equal("tf:instance:binding"))
+			Expect(secondID).To(Equal("tf:instance:binding"))
+			Expect(firstHolder).NotTo(BeEmpty())
+			Expect(secondHolder).To(Equal(firstHolder))
+
+			otherManager := tf.NewDeploymentManager(&fakeStore)
+			Expect(otherManager.MarkOperationStarted(context.TODO(), &existingDeployment, "update")).To(Succeed())
+			_, otherHolder := fakeStore.AcquireTerraformDeploymentLeaseArgsForCall(2)
+			Expect(otherHolder).NotTo(Equal(firstHolder))
+		})
+
+		It("fails, when the deployment is locked by another operation", func() {
+			fakeStore.AcquireTerraformDeploymentLeaseReturns(fmt.Errorf("%w: tf:instance:binding", storage.ErrLeaseHeld))
+
+			err := deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "provision")
+
+			Expect(err).To(MatchError(storage.ErrLeaseHeld))
+			Expect(fakeStore.StartOperationLogCallCount()).To(BeZero())
+			Expect(fakeStore.StoreTerraformDeploymentCallCount()).To(BeZero())
 		})
 	})
 
@@ -217,6 +327,139 @@ var _ = Describe("DeploymentManager", func() {
 				Expect(storedDeployment.LastOperationState).To(Equal("failed"))
 				Expect(storedDeployment.LastOperationMessage).To(Equal("provision failed: operation failed dramatically"))
 			})
+
+			It("sets operation state to timed out when the operation ran out of time", func() {
+				err := deploymentManager.MarkOperationFinished(&existingDeployment, fmt.Errorf("terraform was stopped: %w", context.DeadlineExceeded))
+
+				Expect(err).NotTo(HaveOccurred())
+
+				storedDeployment := fakeStore.StoreTerraformDeploymentArgsForCall(0)
+				Expect(storedDeployment.LastOperationState).To(Equal("timed out"))
+				Expect(storedDeployment.LastOperationMessage).To(Equal("provision timed out: terraform was stopped: context deadline exceeded"))
+			})
+
+			It("finishes the operation log with the truncated Terraform error output", func() {
+				stderr := "Error: something went wrong\n" + strings.Repeat("x", 5000)
+				var executionError error = &executor.ExecutionError{StdErr: stderr}
+
+				err := deploymentManager.MarkOperationFinished(&existingDeployment, fmt.Errorf("apply failed: %w", executionError))
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeStore.FinishOperationLogCallCount()).To(Equal(1))
+				deploymentID, state, message, actualStderr := fakeStore.FinishOperationLogArgsForCall(0)
+				Expect(deploymentID).To(Equal("deploymentID"))
+				Expect(state).To(Equal("failed"))
+				Expect(message).To(HavePrefix("provision failed: apply failed: Error: something went wrong"))
+				Expect(actualStderr).To(Equal(stderr[:4096] + "\n[truncated]"))
+			})
+		})
+
+		When("Terraform commands were retried", func() {
+			It("adds the retries to the last operation message", func() {
+				deploymentManager.RecordRetry("deploymentID", executor.Retry{Command: "apply", Attempt: 1, Reason: "Error 409: conflict"})
+				deploymentManager.RecordRetry("otherDeploymentID", executor.Retry{Command: "apply", Attempt: 1, Reason: "other"})
+				deploymentManager.RecordRetry("deploymentID", executor.Retry{Command: "apply", Attempt: 2, Reason: "Error 429: too many requests"})
+
+				err := deploymentManager.MarkOperationFinished(&existingDeployment, nil)
+
+				Expect(err).NotTo(HaveOccurred())
+				storedDeployment := fakeStore.StoreTerraformDeploymentArgsForCall(0)
+				Expect(storedDeployment.LastOperationState).To(Equal("succeeded"))
+				Expect(storedDeployment.LastOperationMessage).To(Equal(`provision succeeded (retried: apply attempt 1 failed with "Error 409: conflict"; apply attempt 2 failed with "Error 429: too many requests")`))
+				_, _, message, _ := fakeStore.FinishOperationLogArgsForCall(0)
+				Expect(message).To(Equal(storedDeployment.LastOperationMessage))
+			})
+
+			It("does not add the retries of an earlier operation", func() {
+				deploymentManager.RecordRetry("deploymentID", executor.Retry{Command: "apply", Attempt: 1, Reason: "Error 409: conflict"})
+				Expect(deploymentManager.MarkOperationFinished(&existingDeployment, nil)).To(Succeed())
+				deploymentManager.RecordRetry("deploymentID", executor.Retry{Command: "destroy", Attempt: 1, Reason: "stale"})
+				Expect(deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "deprovision")).To(Succeed())
+
+				err := deploymentManager.MarkOperationFinished(&existingDeployment, nil)
+
+				Expect(err).NotTo(HaveOccurred())
+				storedDeployment := fakeStore.StoreTerraformDeploymentArgsForCall(2)
+				Expect(storedDeployment.LastOperationMessage).To(Equal("deprovision succeeded"))
+			})
+		})
+
+		It("finishes the operation log", func() {
+			err := deploymentManager.MarkOperationFinished(&existingDeployment, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStore.FinishOperationLogCallCount()).To(Equal(1))
+			deploymentID, state, message, stderr := fakeStore.FinishOperationLogArgsForCall(0)
+			Expect(deploymentID).To(Equal("deploymentID"))
+			Expect(state).To(Equal("succeeded"))
+			Expect(message).To(Equal("provision succeeded"))
+			Expect(stderr).To(BeEmpty())
+		})
+
+		It("releases the lock on the deployment", func() {
+			Expect(deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "provision")).To(Succeed())
+
+			err := deploymentManager.MarkOperationFinished(&existingDeployment, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStore.ReleaseTerraformDeploymentLeaseCallCount()).To(Equal(1))
+			_, acquiredHolder := fakeStore.AcquireTerraformDeploymentLeaseArgsForCall(0)
+			releasedID, releasedHolder := fakeStore.ReleaseTerraformDeploymentLeaseArgsForCall(0)
+			Expect(releasedID).To(Equal("deploymentID"))
+			Expect(releasedHolder).To(Equal(acquiredHolder))
+		})
+
+		It("releases the lock on the deployment, when storing the deployment fails", func() {
+			Expect(deploymentManager.MarkOperationStarted(context.TODO(), &existingDeployment, "provision")).To(Succeed())
+			fakeStore.StoreTerraformDeploymentReturns(errors.New("couldn't store deployment"))
+
+			err := deploymentManager.MarkOperationFinished(&existingDeployment, nil)
+
+			Expect(err).To(MatchError("couldn't store deployment"))
+			Expect(fakeStore.ReleaseTerraformDeploymentLeaseCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("MarkOperationFailed", func() {
+		var (
+			fakeStore         brokerfakes.FakeServiceProviderStorage
+			deploymentManager *tf.DeploymentManager
+		)
+
+		BeforeEach(func() {
+			fakeStore = brokerfakes.FakeServiceProviderStorage{}
+			fakeStore.GetTerraformDeploymentReturns(storage.TerraformDeployment{
+				ID:                 "deploymentID",
+				Workspace:          &workspace.TerraformWorkspace{},
+				LastOperationType:  "provision",
+				LastOperationState: "in progress",
+			}, nil)
+			deploymentManager = tf.NewDeploymentManager(&fakeStore)
+		})
+
+		It("marks the operation in progress as failed with the message", func() {
+			err := deploymentManager.MarkOperationFailed("deploymentID", "the database is gone")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStore.GetTerraformDeploymentArgsForCall(0)).To(Equal("deploymentID"))
+			Expect(fakeStore.StoreTerraformDeploymentCallCount()).To(Equal(1))
+			storedDeployment := fakeStore.StoreTerraformDeploymentArgsForCall(0)
+			Expect(storedDeployment.LastOperationState).To(Equal("failed"))
+			Expect(storedDeployment.LastOperationMessage).To(Equal("provision failed: marked as failed by an operator: the database is gone"))
+			Expect(fakeStore.FinishOperationLogCallCount()).To(Equal(1))
+		})
+
+		It("fails when no operation is in progress", func() {
+			fakeStore.GetTerraformDeploymentReturns(storage.TerraformDeployment{
+				ID:                 "deploymentID",
+				LastOperationType:  "provision",
+				LastOperationState: "succeeded",
+			}, nil)
+
+			err := deploymentManager.MarkOperationFailed("deploymentID", "")
+
+			Expect(err).To(MatchError(`no operation is in progress on "deploymentID": the last provision operation has succeeded`))
+			Expect(fakeStore.StoreTerraformDeploymentCallCount()).To(BeZero())
 		})
 	})
 
@@ -268,6 +511,22 @@ var _ = Describe("DeploymentManager", func() {
 				Expect(completed).To(BeTrue())
 				Expect(lastOpMessage).To(Equal("not so great update"))
 			})
+
+			It("reports a timed out operation as completed with an error", func() {
+				existingDeployment = storage.TerraformDeployment{
+					ID:                   existingDeploymentID,
+					LastOperationType:    "update",
+					LastOperationState:   "timed out",
+					LastOperationMessage: "update timed out: terraform was stopped",
+				}
+				fakeStore.GetTerraformDeploymentReturns(existingDeployment, nil)
+
+				completed, lastOpMessage, err := deploymentManager.OperationStatus(existingDeploymentID)
+
+				Expect(err).To(MatchError("update timed out: terraform was stopped"))
+				Expect(completed).To(BeTrue())
+				Expect(lastOpMessage).To(Equal("update timed out: terraform was stopped"))
+			})
 		})
 
 		When("last operation is in progress", func() {
@@ -425,6 +684,10 @@ var _ = Describe("DeploymentManager", func() {
 					State: []byte(terraformState),
 				}
 				Expect(actualTerraformDeployment.Workspace).To(Equal(expectedWorkspace))
+
+				By("checking that the lock on the deployment is released")
+				Expect(store.AcquireTerraformDeploymentLeaseCallCount()).To(Equal(1))
+				Expect(store.ReleaseTerraformDeploymentLeaseCallCount()).To(Equal(1))
 			})
 
 			When("getting deployment fails", func() {
@@ -432,9 +695,10 @@ var _ = Describe("DeploymentManager", func() {
 					store.GetTerraformDeploymentReturns(storage.TerraformDeployment{}, errors.New("boom"))
 				})
 
-				It("returns the error", func() {
+				It("returns the error and releases the lock on the deployment", func() {
 					err := deploymentManager.UpdateWorkspaceHCL(id, updatedProvisionSettings, templateVars)
 					Expect(err).To(MatchError("boom"))
+					Expect(store.ReleaseTerraformDeploymentLeaseCallCount()).To(Equal(1))
 				})
 			})
 
@@ -0,0 +1,65 @@
+package tf
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/invoker"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
+)
+
+// CheckDrift runs "terraform plan" for a deployment and reports the resources that
+// would be changed. The plan is not applied and the deployment is not modified.
+func (provider *TerraformProvider) CheckDrift(ctx context.Context, deploymentID string) (storage.TerraformDrift, error) {
+	provider.logger.Debug("check-drift", correlation.ID(ctx), lager.Data{"deploymentID": deploymentID})
+
+	deployment, err := provider.GetTerraformDeployment(deploymentID)
+	if err != nil {
+		return storage.TerraformDrift{}, err
+	}
+
+	tfInvoker, err := provider.stateVersionInvoker(deployment.Workspace)
+	if err != nil {
+		return storage.TerraformDrift{}, err
+	}
+
+	output, err := tfInvoker.Plan(ctx, deployment.Workspace)
+	if err != nil {
+		return storage.TerraformDrift{}, err
+	}
+
+	empty, changes, err := ParseTerraformPlanOutput(output)
+	if err != nil {
+		return storage.TerraformDrift{}, err
+	}
+
+	return storage.TerraformDrift{
+		DeploymentID:    deploymentID,
+		Drifted:         !empty,
+		ResourceChanges: changes,
+	}, nil
+}
+
+// stateVersionInvoker returns an invoker for the version of Terraform that wrote the state,
+// so that checking for drift does not depend on the state being upgraded first
+func (provider *TerraformProvider) stateVersionInvoker(workspace workspace.Workspace) (invoker.TerraformInvoker, error) {
+	currentTfVersion, err := workspace.StateTFVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	if currentTfVersion.Equal(provider.tfBinContext.DefaultTfVersion) {
+		return provider.DefaultInvoker(), nil
+	}
+	for _, targetTfVersion := range provider.tfBinContext.TfUpgradePath {
+		if currentTfVersion.Equal(targetTfVersion) {
+			return provider.VersionedInvoker(targetTfVersion), nil
+		}
+	}
+
+	return nil, fmt.Errorf("no Terraform binary available for state version %s, upgrade the service before checking for drift", currentTfVersion)
+}
@@ -0,0 +1,129 @@
+package tf_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/tffakes"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace/workspacefakes"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+)
+
+var _ = Describe("CheckDrift", func() {
+	const deploymentID = "tf:instance-id:"
+
+	var (
+		fakeDeploymentManager *tffakes.FakeDeploymentManagerInterface
+		fakeInvokerBuilder    *tffakes.FakeTerraformInvokerBuilder
+		fakeInvoker           *tffakes.FakeTerraformInvoker
+		fakeWorkspace         *workspacefakes.FakeWorkspace
+		provider              *tf.TerraformProvider
+	)
+
+	BeforeEach(func() {
+		fakeDeploymentManager = &tffakes.FakeDeploymentManagerInterface{}
+		fakeInvokerBuilder = &tffakes.FakeTerraformInvokerBuilder{}
+		fakeInvoker = &tffakes.FakeTerraformInvoker{}
+		fakeWorkspace = &workspacefakes.FakeWorkspace{}
+
+		fakeWorkspace.StateTFVersionReturns(version.Must(version.NewVersion("1.1.0")), nil)
+		fakeDeploymentManager.GetTerraformDeploymentReturns(storage.TerraformDeployment{ID: deploymentID, Workspace: fakeWorkspace}, nil)
+		fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeInvoker)
+		fakeInvoker.PlanReturns(executor.ExecutionOutput{StdOut: "No changes."}, nil)
+
+		provider = tf.NewTerraformProvider(
+			executor.TFBinariesContext{
+				DefaultTfVersion: version.Must(version.NewVersion("1.1.0")),
+				TfUpgradePath:    []*version.Version{version.Must(version.NewVersion("1.0.0")), version.Must(version.NewVersion("1.1.0"))},
+			},
+			fakeInvokerBuilder,
+			utils.NewLogger("test"),
+			tf.TfServiceDefinitionV1{},
+			fakeDeploymentManager,
+		)
+	})
+
+	It("reports no drift when the plan is empty", func() {
+		fakeInvoker.PlanReturns(executor.ExecutionOutput{StdOut: "No changes. Your infrastructure matches the configuration."}, nil)
+
+		drift, err := provider.CheckDrift(context.TODO(), deploymentID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).To(Equal(storage.TerraformDrift{DeploymentID: deploymentID}))
+
+		Expect(fakeDeploymentManager.GetTerraformDeploymentArgsForCall(0)).To(Equal(deploymentID))
+		Expect(fakeInvoker.PlanCallCount()).To(Equal(1))
+		_, ws := fakeInvoker.PlanArgsForCall(0)
+		Expect(ws).To(Equal(fakeWorkspace))
+	})
+
+	It("reports the changes when the plan is not empty", func() {
+		fakeInvoker.PlanReturns(executor.ExecutionOutput{StdOut: `
+  # random_password.password must be replaced
+-/+ resource "random_password" "password" {
+      ~ length = 10 -> 12 # forces replacement
+    }
+
+Plan: 1 to add, 0 to change, 1 to destroy.
+`}, nil)
+
+		drift, err := provider.CheckDrift(context.TODO(), deploymentID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift.Drifted).To(BeTrue())
+		Expect(drift.ResourceChanges).To(Equal([]storage.TerraformResourceChange{
+			{Address: "random_password.password", Action: "replace", Attributes: []string{"length"}},
+		}))
+	})
+
+	It("does not modify the deployment", func() {
+		_, err := provider.CheckDrift(context.TODO(), deploymentID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(BeZero())
+		Expect(fakeDeploymentManager.MarkOperationFinishedCallCount()).To(BeZero())
+		Expect(fakeInvoker.ApplyCallCount()).To(BeZero())
+	})
+
+	It("uses the Terraform version of the state", func() {
+		fakeWorkspace.StateTFVersionReturns(version.Must(version.NewVersion("1.0.0")), nil)
+
+		_, err := provider.CheckDrift(context.TODO(), deploymentID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeInvokerBuilder.VersionedTerraformInvokerCallCount()).To(Equal(1))
+		Expect(fakeInvokerBuilder.VersionedTerraformInvokerArgsForCall(0)).To(Equal(version.Must(version.NewVersion("1.0.0"))))
+	})
+
+	It("fails when there is no Terraform binary for the state version", func() {
+		fakeWorkspace.StateTFVersionReturns(version.Must(version.NewVersion("0.13.0")), nil)
+
+		_, err := provider.CheckDrift(context.TODO(), deploymentID)
+		Expect(err).To(MatchError("no Terraform binary available for state version 0.13.0, upgrade the service before checking for drift"))
+		Expect(fakeInvoker.PlanCallCount()).To(BeZero())
+	})
+
+	It("fails when the plan fails", func() {
+		fakeInvoker.PlanReturns(executor.ExecutionOutput{}, errors.New("plan failed"))
+
+		_, err := provider.CheckDrift(context.TODO(), deploymentID)
+		Expect(err).To(MatchError("plan failed"))
+	})
+
+	It("fails when the plan output is not recognised", func() {
+		fakeInvoker.PlanReturns(executor.ExecutionOutput{StdOut: "unexpected output"}, nil)
+
+		_, err := provider.CheckDrift(context.TODO(), deploymentID)
+		Expect(err).To(MatchError(ContainSubstring("unrecognised output from terraform plan")))
+	})
+
+	It("fails when the deployment cannot be read", func() {
+		fakeDeploymentManager.GetTerraformDeploymentReturns(storage.TerraformDeployment{}, errors.New("not found"))
+
+		_, err := provider.CheckDrift(context.TODO(), deploymentID)
+		Expect(err).To(MatchError("not found"))
+	})
+})
@@ -5,14 +5,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-version"
 
 	"code.cloudfoundry.org/lager/v3"
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics"
 	"github.com/cloudfoundry/cloud-service-broker/utils"
 	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -30,15 +34,38 @@ type ExecutionOutput struct {
 	StdErr string
 }
 
+// ExecutionError is returned when a tf cli execution fails. It keeps
+// the output from stdout and stderr so that it can be recorded.
+type ExecutionError struct {
+	StdOut string
+	StdErr string
+	err    error
+}
+
+func (e *ExecutionError) Error() string {
+	return fmt.Sprintf("%s %s", flatten([]byte(e.StdErr)), e.err)
+}
+
+func (e *ExecutionError) Unwrap() error {
+	return e.err
+}
+
+// interruptGracePeriod is how long Terraform has to stop after it is interrupted,
+// for instance to save the state, before it is killed
+const interruptGracePeriod = 2 * time.Minute
+
 // DefaultExecutor is the default executor that shells out to Terraform
-// and logs results to stdout.
+// and logs results to stdout. When the context is done, Terraform is
+// interrupted, and then killed if it does not stop within a grace period.
 func DefaultExecutor() TerraformExecutor {
-	return defaultExecutor{}
+	return defaultExecutor{gracePeriod: interruptGracePeriod}
 }
 
-type defaultExecutor struct{}
+type defaultExecutor struct {
+	gracePeriod time.Duration
+}
 
-func (defaultExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutput, error) {
+func (e defaultExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutput, error) {
 	logger := utils.NewLogger("terraform@" + c.Dir).WithData(correlation.ID(ctx))
 
 	logger.Info("starting process", lager.Data{
@@ -61,25 +88,39 @@ func (defaultExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutpu
 		return ExecutionOutput{}, fmt.Errorf("failed to execute terraform: %v", err)
 	}
 
+	exited := make(chan struct{})
+	interrupted := make(chan bool, 1)
+	go func() {
+		interrupted <- e.stopWhenDone(ctx, c, exited, logger)
+	}()
+
 	output, _ := io.ReadAll(stdout)
 	errors, _ := io.ReadAll(stderr)
 
 	err = c.Wait()
+	close(exited)
 
+	// A command that completed, or that failed before it was interrupted, keeps its own result
+	if <-interrupted && err != nil {
+		err = fmt.Errorf("terraform was stopped: %w", context.Cause(ctx))
+	}
+
+	// The output can contain the values of sensitive variables, which are not known to the log sink
+	redactor := redaction.NewReplacer(redaction.Values(ctx))
 	if err != nil ||
 		len(errors) > 0 {
 		logger.Error("terraform execution failed", err, lager.Data{
-			"errors": string(errors),
+			"errors": redactor.Replace(string(errors)),
 		})
 	}
 
 	logger.Info("finished process")
 	logger.Debug("terraform output", lager.Data{
-		"output": string(output),
+		"output": redactor.Replace(string(output)),
 	})
 
 	if err != nil {
-		return ExecutionOutput{}, fmt.Errorf("%s %w", flatten(errors), err)
+		return ExecutionOutput{}, &ExecutionError{StdOut: string(output), StdErr: string(errors), err: err}
 	}
 
 	return ExecutionOutput{
@@ -88,6 +129,34 @@ func (defaultExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutpu
 	}, nil
 }
 
+// stopWhenDone interrupts the process when the context is done, and kills it
+// if it has not exited after the grace period. It returns whether the process was interrupted.
+func (e defaultExecutor) stopWhenDone(ctx context.Context, c *exec.Cmd, exited <-chan struct{}, logger lager.Logger) bool {
+	select {
+	case <-exited:
+		return false
+	case <-ctx.Done():
+	}
+
+	logger.Info("interrupting process", lager.Data{"reason": context.Cause(ctx).Error()})
+	if err := c.Process.Signal(os.Interrupt); err != nil {
+		// The process has usually exited already
+		logger.Error("interrupt-failed", err)
+		return false
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(e.gracePeriod):
+		logger.Info("killing process", lager.Data{"grace-period": e.gracePeriod.String()})
+		if err := c.Process.Kill(); err != nil {
+			logger.Error("kill-failed", err)
+		}
+	}
+
+	return true
+}
+
 func flatten(input []byte) string {
 	var lines []string
 	for _, l := range strings.Split(string(input), "\n") {
@@ -98,6 +167,28 @@ func flatten(input []byte) string {
 	return strings.Join(lines, " ")
 }
 
+// InstrumentedExecutor records the duration and failures of each Terraform command
+// in the broker metrics
+func InstrumentedExecutor(wrapped TerraformExecutor) TerraformExecutor {
+	return instrumentedExecutor{wrapped: wrapped}
+}
+
+type instrumentedExecutor struct {
+	wrapped TerraformExecutor
+}
+
+func (e instrumentedExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutput, error) {
+	command := "unknown"
+	if len(c.Args) > 1 {
+		command = c.Args[1]
+	}
+
+	start := time.Now()
+	output, err := e.wrapped.Execute(ctx, c)
+	metrics.ObserveTerraformInvocation(command, time.Since(start), err)
+	return output, err
+}
+
 // CustomTerraformExecutor executes a custom Terraform binary that uses plugins
 // from a given plugin directory rather than the Terraform that's on the PATH
 // which will download provider binaries from the web.
@@ -147,3 +238,18 @@ func (executor customEnvironmentExecutor) Execute(ctx context.Context, c *exec.C
 
 	return executor.wrapped.Execute(ctx, c)
 }
+
+// RedactingExecutor masks the secrets, in addition to the values that are already masked in the
+// context, wherever the output of the Terraform commands is logged or recorded
+func RedactingExecutor(secrets []string, wrapped TerraformExecutor) TerraformExecutor {
+	return redactingExecutor{secrets: secrets, wrapped: wrapped}
+}
+
+type redactingExecutor struct {
+	secrets []string
+	wrapped TerraformExecutor
+}
+
+func (e redactingExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutput, error) {
+	return e.wrapped.Execute(redaction.WithValues(ctx, e.secrets...), c)
+}
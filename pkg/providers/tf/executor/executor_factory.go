@@ -16,6 +16,27 @@ type TFBinariesContext struct {
 
 	TfUpgradePath        []*version.Version
 	ProviderReplacements map[string]string
+
+	// OpenTofuVersions are the versions that run OpenTofu rather than HashiCorp Terraform
+	OpenTofuVersions []*version.Version
+
+	// Pool limits the Terraform commands and operations of the brokerpak.
+	// It may be shared with other brokerpaks, and is nil when there are no limits.
+	Pool *Pool
+
+	// PluginCache is the shared provider plugin cache for the brokerpak, or nil when it is not used
+	PluginCache *PluginCache
+
+	// TFLogLevel is the TF_LOG level of the Terraform logs that are recorded for each operation.
+	// Terraform does not log when it is empty.
+	TFLogLevel string
+
+	// MaxParallelBindingUpgrades limits the bindings of an instance that are upgraded
+	// at the same time. Bindings are upgraded one at a time when it is not set.
+	MaxParallelBindingUpgrades int
+
+	// RetryRules say which failed Terraform commands of the brokerpak are retried
+	RetryRules []RetryRule
 }
 
 func NewExecutorFactory(dir string, params map[string]string, envVars map[string]string) ExecutorBuilder {
@@ -29,8 +50,15 @@ func NewExecutorFactory(dir string, params map[string]string, envVars map[string
 type ExecutorFactory struct {
 	Dir              string
 	DefaultTfVersion *version.Version
+	OpenTofuVersions []*version.Version
 	Params           map[string]string
 	EnvVars          map[string]string
+	Pool             *Pool
+	PluginCache      *PluginCache
+	LogStore         LogStore
+	TFLogLevel       string
+	RetryRules       []RetryRule
+	RetryRecorder    RetryRecorder
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -41,15 +69,45 @@ type ExecutorBuilder interface {
 }
 
 func (executorFactory ExecutorFactory) VersionedExecutor(tfVersion *version.Version) TerraformExecutor {
-	return CustomEnvironmentExecutor(executorFactory.EnvVars,
-		CustomEnvironmentExecutor(
-			executorFactory.Params,
-			CustomTerraformExecutor(
-				filepath.Join(executorFactory.Dir, "versions", tfVersion.String(), "terraform"),
-				executorFactory.Dir,
-				tfVersion,
-				DefaultExecutor(),
+	return RedactingExecutor(executorFactory.secrets(),
+		CustomEnvironmentExecutor(executorFactory.EnvVars,
+			CustomEnvironmentExecutor(
+				executorFactory.Params,
+				CustomTerraformExecutor(
+					filepath.Join(executorFactory.Dir, "versions", tfVersion.String(), executorFactory.binaryName(tfVersion)),
+					executorFactory.Dir,
+					tfVersion,
+					RetryExecutor(executorFactory.RetryRules, executorFactory.RetryRecorder,
+						executorFactory.Pool.Executor(
+							LogCaptureExecutor(executorFactory.LogStore, executorFactory.TFLogLevel,
+								PluginCacheExecutor(executorFactory.PluginCache, InstrumentedExecutor(DefaultExecutor())),
+							),
+						),
+					),
+				),
 			),
 		),
 	)
 }
+
+// secrets returns the values of the environment variables that are set for Terraform,
+// such as the credentials in the brokerpak parameters, which must not be logged or recorded
+func (executorFactory ExecutorFactory) secrets() []string {
+	var secrets []string
+	for _, vars := range []map[string]string{executorFactory.Params, executorFactory.EnvVars} {
+		for _, v := range vars {
+			secrets = append(secrets, v)
+		}
+	}
+	return secrets
+}
+
+// binaryName returns the name of the OpenTofu or Terraform binary for a version
+func (executorFactory ExecutorFactory) binaryName(tfVersion *version.Version) string {
+	for _, v := range executorFactory.OpenTofuVersions {
+		if v.Equal(tfVersion) {
+			return "tofu"
+		}
+	}
+	return "terraform"
+}
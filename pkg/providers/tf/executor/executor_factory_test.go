@@ -0,0 +1,42 @@
+package executor_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+)
+
+var _ = Describe("ExecutorFactory", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		for _, binary := range []string{"1.5.7/terraform", "1.6.0/tofu"} {
+			path := filepath.Join(dir, "versions", binary)
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(os.WriteFile(path, []byte("#!/bin/sh\necho "+binary+" \"$@\"\n"), 0755)).To(Succeed())
+		}
+	})
+
+	It("runs the Terraform or OpenTofu binary for the version", func() {
+		factory := executor.ExecutorFactory{
+			Dir:              dir,
+			OpenTofuVersions: []*version.Version{version.Must(version.NewVersion("1.6.0"))},
+		}
+
+		output, err := factory.VersionedExecutor(version.Must(version.NewVersion("1.5.7"))).Execute(context.TODO(), exec.Command("terraform", "apply"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.StdOut).To(Equal("1.5.7/terraform apply\n"))
+
+		output, err = factory.VersionedExecutor(version.Must(version.NewVersion("1.6.0"))).Execute(context.TODO(), exec.Command("terraform", "apply"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.StdOut).To(Equal("1.6.0/tofu apply\n"))
+	})
+})
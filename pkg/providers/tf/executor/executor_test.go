@@ -0,0 +1,52 @@
+package executor_test
+
+import (
+	"context"
+	"os/exec"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+)
+
+var _ = Describe("DefaultExecutor", func() {
+	It("returns the output of the command", func() {
+		output, err := executor.DefaultExecutor().Execute(context.TODO(), exec.Command("sh", "-c", "echo out; echo err >&2"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.StdOut).To(Equal("out\n"))
+		Expect(output.StdErr).To(Equal("err\n"))
+	})
+
+	It("keeps the error output when the command fails", func() {
+		_, err := executor.DefaultExecutor().Execute(context.TODO(), exec.Command("sh", "-c", "echo boom >&2; exit 1"))
+
+		var executionError *executor.ExecutionError
+		Expect(err).To(BeAssignableToTypeOf(executionError))
+		Expect(err.(*executor.ExecutionError).StdErr).To(Equal("boom\n"))
+		Expect(err.(*executor.ExecutionError).StdOut).To(BeEmpty())
+	})
+
+	It("interrupts the command when the context is done", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := executor.DefaultExecutor().Execute(ctx, exec.Command("sh", "-c", `trap 'echo interrupted >&2; exit 130' INT; while true; do sleep 0.1; done`))
+
+		Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(err).To(MatchError(ContainSubstring("interrupted")))
+	})
+	It("keeps the result of a command that completes after it is interrupted", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		output, err := executor.DefaultExecutor().Execute(ctx, exec.Command("sh", "-c", `trap '' INT; sleep 0.5; echo done`))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.StdOut).To(Equal("done\n"))
+	})
+})
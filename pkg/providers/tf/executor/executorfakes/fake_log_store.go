@@ -0,0 +1,111 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package executorfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+)
+
+type FakeLogStore struct {
+	StoreCommandLogStub        func(executor.CommandLog) error
+	storeCommandLogMutex       sync.RWMutex
+	storeCommandLogArgsForCall []struct {
+		arg1 executor.CommandLog
+	}
+	storeCommandLogReturns struct {
+		result1 error
+	}
+	storeCommandLogReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeLogStore) StoreCommandLog(arg1 executor.CommandLog) error {
+	fake.storeCommandLogMutex.Lock()
+	ret, specificReturn := fake.storeCommandLogReturnsOnCall[len(fake.storeCommandLogArgsForCall)]
+	fake.storeCommandLogArgsForCall = append(fake.storeCommandLogArgsForCall, struct {
+		arg1 executor.CommandLog
+	}{arg1})
+	stub := fake.StoreCommandLogStub
+	fakeReturns := fake.storeCommandLogReturns
+	fake.recordInvocation("StoreCommandLog", []interface{}{arg1})
+	fake.storeCommandLogMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLogStore) StoreCommandLogCallCount() int {
+	fake.storeCommandLogMutex.RLock()
+	defer fake.storeCommandLogMutex.RUnlock()
+	return len(fake.storeCommandLogArgsForCall)
+}
+
+func (fake *FakeLogStore) StoreCommandLogCalls(stub func(executor.CommandLog) error) {
+	fake.storeCommandLogMutex.Lock()
+	defer fake.storeCommandLogMutex.Unlock()
+	fake.StoreCommandLogStub = stub
+}
+
+func (fake *FakeLogStore) StoreCommandLogArgsForCall(i int) executor.CommandLog {
+	fake.storeCommandLogMutex.RLock()
+	defer fake.storeCommandLogMutex.RUnlock()
+	argsForCall := fake.storeCommandLogArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLogStore) StoreCommandLogReturns(result1 error) {
+	fake.storeCommandLogMutex.Lock()
+	defer fake.storeCommandLogMutex.Unlock()
+	fake.StoreCommandLogStub = nil
+	fake.storeCommandLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLogStore) StoreCommandLogReturnsOnCall(i int, result1 error) {
+	fake.storeCommandLogMutex.Lock()
+	defer fake.storeCommandLogMutex.Unlock()
+	fake.StoreCommandLogStub = nil
+	if fake.storeCommandLogReturnsOnCall == nil {
+		fake.storeCommandLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeCommandLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLogStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.storeCommandLogMutex.RLock()
+	defer fake.storeCommandLogMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeLogStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ executor.LogStore = new(FakeLogStore)
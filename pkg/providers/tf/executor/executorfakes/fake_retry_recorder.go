@@ -0,0 +1,78 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package executorfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+)
+
+type FakeRetryRecorder struct {
+	RecordRetryStub        func(string, executor.Retry)
+	recordRetryMutex       sync.RWMutex
+	recordRetryArgsForCall []struct {
+		arg1 string
+		arg2 executor.Retry
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRetryRecorder) RecordRetry(arg1 string, arg2 executor.Retry) {
+	fake.recordRetryMutex.Lock()
+	fake.recordRetryArgsForCall = append(fake.recordRetryArgsForCall, struct {
+		arg1 string
+		arg2 executor.Retry
+	}{arg1, arg2})
+	stub := fake.RecordRetryStub
+	fake.recordInvocation("RecordRetry", []interface{}{arg1, arg2})
+	fake.recordRetryMutex.Unlock()
+	if stub != nil {
+		fake.RecordRetryStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeRetryRecorder) RecordRetryCallCount() int {
+	fake.recordRetryMutex.RLock()
+	defer fake.recordRetryMutex.RUnlock()
+	return len(fake.recordRetryArgsForCall)
+}
+
+func (fake *FakeRetryRecorder) RecordRetryCalls(stub func(string, executor.Retry)) {
+	fake.recordRetryMutex.Lock()
+	defer fake.recordRetryMutex.Unlock()
+	fake.RecordRetryStub = stub
+}
+
+func (fake *FakeRetryRecorder) RecordRetryArgsForCall(i int) (string, executor.Retry) {
+	fake.recordRetryMutex.RLock()
+	defer fake.recordRetryMutex.RUnlock()
+	argsForCall := fake.recordRetryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRetryRecorder) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.recordRetryMutex.RLock()
+	defer fake.recordRetryMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeRetryRecorder) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ executor.RetryRecorder = new(FakeRetryRecorder)
@@ -0,0 +1,284 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/sumdb/dirhash"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/brokerpak/platform"
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics"
+)
+
+// lockFileName is the name of the dependency lock file that Terraform reads from the workspace
+const lockFileName = ".terraform.lock.hcl"
+
+// PluginCache is a read-only Terraform provider plugin cache that is shared between workspaces.
+// Terraform links the providers from the cache into a workspace when they match the hashes in the
+// dependency lock file, rather than copying them from the plugin directory on every init.
+type PluginCache struct {
+	// Dir is the cache directory. It is named after the hash of the providers it contains.
+	Dir string
+
+	lockFile []byte
+}
+
+type providerPackage struct {
+	address string
+	version string
+	dir     string
+	hash    string
+}
+
+// NewPluginCache creates a plugin cache in the root directory for the providers in the plugin
+// directory of a brokerpak, or reuses one with the same providers. It returns nil when there are no
+// providers that can be cached, for instance because the brokerpak uses the Terraform 0.12 layout.
+// The cache is not used for a brokerpak with several versions of a provider, because the lock file
+// can only select one of them.
+func NewPluginCache(root, pluginDir string) (*PluginCache, error) {
+	packages, err := findProviderPackages(pluginDir)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(packages) == 0:
+		return nil, nil
+	}
+
+	versions := make(map[string]string)
+	for _, p := range packages {
+		if v, ok := versions[p.address]; ok && v != p.version {
+			return nil, nil
+		}
+		versions[p.address] = p.version
+	}
+
+	contentHash := sha256.New()
+	for _, p := range packages {
+		_, _ = fmt.Fprintf(contentHash, "%s %s %s\n", p.address, p.version, p.hash)
+	}
+
+	cache := PluginCache{
+		Dir:      filepath.Join(root, fmt.Sprintf("%x", contentHash.Sum(nil))),
+		lockFile: lockFile(packages),
+	}
+
+	switch _, err := os.Stat(cache.Dir); {
+	case err == nil:
+		return &cache, nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+
+	if err := populatePluginCache(root, cache.Dir, pluginDir, packages); err != nil {
+		return nil, fmt.Errorf("error creating plugin cache: %w", err)
+	}
+
+	return &cache, nil
+}
+
+// findProviderPackages finds the providers for the current platform, which are extracted
+// to HOSTNAME/NAMESPACE/TYPE/VERSION/OS_ARCH in the plugin directory
+func findProviderPackages(pluginDir string) ([]providerPackage, error) {
+	plat := platform.CurrentPlatform()
+	matches, err := filepath.Glob(filepath.Join(pluginDir, "*", "*", "*", "*", fmt.Sprintf("%s_%s", plat.Os, plat.Arch)))
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []providerPackage
+	for _, dir := range matches {
+		rel, err := filepath.Rel(pluginDir, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		hash, err := dirhash.HashDir(dir, "", dirhash.Hash1)
+		if err != nil {
+			return nil, err
+		}
+
+		packages = append(packages, providerPackage{
+			address: strings.Join(parts[0:3], "/"),
+			version: parts[3],
+			dir:     rel,
+			hash:    hash,
+		})
+	}
+
+	sort.Slice(packages, func(i, j int) bool { return packages[i].dir < packages[j].dir })
+	return packages, nil
+}
+
+// lockFile generates a dependency lock file that selects the providers in the cache
+func lockFile(packages []providerPackage) []byte {
+	var b strings.Builder
+	b.WriteString("# This file is maintained automatically by the Cloud Service Broker.\n")
+	for _, p := range packages {
+		fmt.Fprintf(&b, "\nprovider %q {\n  version = %q\n  hashes = [\n    %q,\n  ]\n}\n", p.address, p.version, p.hash)
+	}
+	return []byte(b.String())
+}
+
+// populatePluginCache copies the providers to a temporary directory which is renamed to the
+// cache directory when it is complete, so that a partial cache is never used
+func populatePluginCache(root, cacheDir, pluginDir string, packages []providerPackage) error {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp(root, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	// MkdirTemp creates the directory as 0700, so it is widened for the Terraform processes that read the cache
+	if err := os.Chmod(tmp, 0755); err != nil {
+		return err
+	}
+
+	for _, p := range packages {
+		if err := copyDir(filepath.Join(pluginDir, p.dir), filepath.Join(tmp, p.dir)); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmp, cacheDir); err != nil {
+		// Another broker sharing the root directory may have created the same cache
+		if _, statErr := os.Stat(cacheDir); statErr == nil {
+			return nil
+		}
+		return err
+	}
+
+	return makeReadOnly(cacheDir)
+}
+
+func copyDir(source, destination string) error {
+	return filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(source, destination string, perm fs.FileMode) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(destination, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// makeReadOnly removes the write permissions, so that Terraform cannot modify the shared providers
+func makeReadOnly(dir string) error {
+	var paths []string
+	if err := filepath.WalkDir(dir, func(path string, _ fs.DirEntry, err error) error {
+		paths = append(paths, path)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Directories are visited before their contents, so the permissions are removed in reverse order
+	for i := len(paths) - 1; i >= 0; i-- {
+		info, err := os.Lstat(paths[i])
+		if err != nil {
+			return err
+		}
+		if err := os.Chmod(paths[i], info.Mode().Perm()&^0222); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PluginCacheExecutor makes Terraform use the providers in the plugin cache, by setting
+// TF_PLUGIN_CACHE_DIR and adding the lock file to the workspace before init. It records the
+// duration of init in the broker metrics, so that the time saved by the cache can be measured.
+// A nil cache is not used, but the duration of init is still recorded.
+func PluginCacheExecutor(cache *PluginCache, wrapped TerraformExecutor) TerraformExecutor {
+	return pluginCacheExecutor{cache: cache, wrapped: wrapped}
+}
+
+type pluginCacheExecutor struct {
+	cache   *PluginCache
+	wrapped TerraformExecutor
+}
+
+func (e pluginCacheExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutput, error) {
+	isInit := len(c.Args) > 1 && c.Args[1] == "init"
+
+	if e.cache != nil {
+		c.Env = append(c.Env, fmt.Sprintf("TF_PLUGIN_CACHE_DIR=%s", e.cache.Dir))
+		if isInit {
+			if err := e.cache.writeLockFile(c.Dir); err != nil {
+				return ExecutionOutput{}, err
+			}
+		}
+	}
+
+	if !isInit {
+		return e.wrapped.Execute(ctx, c)
+	}
+
+	start := time.Now()
+	output, err := e.wrapped.Execute(ctx, c)
+	metrics.ObserveTerraformInit(e.cache != nil, time.Since(start))
+	return output, err
+}
+
+// writeLockFile adds the lock file to a workspace, unless the workspace already has one
+func (p *PluginCache) writeLockFile(dir string) error {
+	f, err := os.OpenFile(filepath.Join(dir, lockFileName), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	switch {
+	case errors.Is(err, fs.ErrExist):
+		return nil
+	case err != nil:
+		return fmt.Errorf("error writing dependency lock file: %w", err)
+	}
+
+	if _, err := f.Write(p.lockFile); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error writing dependency lock file: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,181 @@
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/mod/sumdb/dirhash"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor/executorfakes"
+)
+
+var _ = Describe("PluginCache", func() {
+	var (
+		root      string
+		pluginDir string
+	)
+
+	providerDir := func(provider, version string) string {
+		return filepath.Join(pluginDir, provider, version, fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH))
+	}
+
+	addProvider := func(provider, version, contents string) {
+		dir := providerDir(provider, version)
+		Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "terraform-provider_v"+version), []byte(contents), 0755)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		root = filepath.Join(GinkgoT().TempDir(), "cache")
+		pluginDir = GinkgoT().TempDir()
+
+		addProvider("registry.terraform.io/hashicorp/random", "3.1.0", "fake-random")
+		Expect(os.MkdirAll(filepath.Join(pluginDir, "versions", "1.5.7"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(pluginDir, "versions", "1.5.7", "terraform"), []byte("fake-terraform"), 0755)).To(Succeed())
+
+		DeferCleanup(func() {
+			// The cache is read-only, so it must be made writable before it can be removed
+			_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+				if err == nil {
+					_ = os.Chmod(path, 0755)
+				}
+				return nil
+			})
+		})
+	})
+
+	Describe("NewPluginCache", func() {
+		It("copies the providers to a read-only directory named after their hash", func() {
+			cache, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Dir(cache.Dir)).To(Equal(root))
+			Expect(filepath.Base(cache.Dir)).To(MatchRegexp(`^[0-9a-f]{64}$`))
+
+			cached := filepath.Join(cache.Dir, "registry.terraform.io/hashicorp/random/3.1.0", fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH), "terraform-provider_v3.1.0")
+			Expect(os.ReadFile(cached)).To(Equal([]byte("fake-random")))
+			Expect(filepath.Join(cache.Dir, "versions")).NotTo(BeADirectory())
+
+			info, err := os.Stat(cached)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0555)))
+			info, err = os.Stat(cache.Dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0555)))
+		})
+
+		It("reuses the cache for the same providers", func() {
+			first, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			second, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Dir).To(Equal(first.Dir))
+		})
+
+		It("uses a different cache for different providers", func() {
+			first, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			addProvider("registry.terraform.io/hashicorp/null", "3.2.1", "fake-null")
+			second, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Dir).NotTo(Equal(first.Dir))
+		})
+
+		It("does not create a cache when there are no providers for the platform", func() {
+			Expect(os.RemoveAll(filepath.Join(pluginDir, "registry.terraform.io"))).To(Succeed())
+
+			cache, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cache).To(BeNil())
+		})
+
+		It("does not create a cache when there are several versions of a provider", func() {
+			addProvider("registry.terraform.io/hashicorp/random", "3.2.0", "fake-newer-random")
+
+			cache, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cache).To(BeNil())
+		})
+	})
+
+	Describe("PluginCacheExecutor", func() {
+		var (
+			fakeExecutor *executorfakes.FakeTerraformExecutor
+			workspaceDir string
+		)
+
+		BeforeEach(func() {
+			fakeExecutor = &executorfakes.FakeTerraformExecutor{}
+			workspaceDir = GinkgoT().TempDir()
+		})
+
+		It("sets the cache directory and writes the lock file before init", func() {
+			cache, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			c := exec.Command("terraform", "init", "-no-color")
+			c.Dir = workspaceDir
+			_, err = executor.PluginCacheExecutor(cache, fakeExecutor).Execute(context.TODO(), c)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeExecutor.ExecuteCallCount()).To(Equal(1))
+			_, actualCmd := fakeExecutor.ExecuteArgsForCall(0)
+			Expect(actualCmd.Env).To(ContainElement("TF_PLUGIN_CACHE_DIR=" + cache.Dir))
+
+			hash, err := dirhash.HashDir(providerDir("registry.terraform.io/hashicorp/random", "3.1.0"), "", dirhash.Hash1)
+			Expect(err).NotTo(HaveOccurred())
+			lockFile, err := os.ReadFile(filepath.Join(workspaceDir, ".terraform.lock.hcl"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(lockFile)).To(ContainSubstring(`provider "registry.terraform.io/hashicorp/random" {`))
+			Expect(string(lockFile)).To(ContainSubstring(`version = "3.1.0"`))
+			Expect(string(lockFile)).To(ContainSubstring(fmt.Sprintf("%q,", hash)))
+		})
+
+		It("does not replace the lock file of the workspace", func() {
+			cache, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(workspaceDir, ".terraform.lock.hcl"), []byte("existing"), 0644)).To(Succeed())
+
+			c := exec.Command("terraform", "init")
+			c.Dir = workspaceDir
+			_, err = executor.PluginCacheExecutor(cache, fakeExecutor).Execute(context.TODO(), c)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.ReadFile(filepath.Join(workspaceDir, ".terraform.lock.hcl"))).To(Equal([]byte("existing")))
+		})
+
+		It("only writes the lock file for init", func() {
+			cache, err := executor.NewPluginCache(root, pluginDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			c := exec.Command("terraform", "apply")
+			c.Dir = workspaceDir
+			_, err = executor.PluginCacheExecutor(cache, fakeExecutor).Execute(context.TODO(), c)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, actualCmd := fakeExecutor.ExecuteArgsForCall(0)
+			Expect(actualCmd.Env).To(ContainElement("TF_PLUGIN_CACHE_DIR=" + cache.Dir))
+			Expect(filepath.Join(workspaceDir, ".terraform.lock.hcl")).NotTo(BeAnExistingFile())
+		})
+
+		It("runs the command unchanged without a cache", func() {
+			c := exec.Command("terraform", "init")
+			c.Dir = workspaceDir
+			_, err := executor.PluginCacheExecutor(nil, fakeExecutor).Execute(context.TODO(), c)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, actualCmd := fakeExecutor.ExecuteArgsForCall(0)
+			Expect(actualCmd.Env).To(BeEmpty())
+			Expect(filepath.Join(workspaceDir, ".terraform.lock.hcl")).NotTo(BeAnExistingFile())
+		})
+	})
+})
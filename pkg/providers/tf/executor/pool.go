@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/metrics"
+)
+
+// ErrQueueFull is returned when an operation is not admitted because the
+// Terraform execution pool already has as many pending operations as it allows
+var ErrQueueFull = errors.New("too many terraform operations are queued, try again later")
+
+// Pool limits the number of Terraform commands that run at the same time, and the
+// number of operations that can wait to run them. A nil Pool has no limits.
+type Pool struct {
+	name    string
+	pending chan struct{}
+	workers chan struct{}
+
+	lock   sync.Mutex
+	queued int
+}
+
+// NewPool creates a Pool that runs at most the specified number of Terraform commands
+// at the same time, and admits up to queueSize operations in addition to that.
+// It returns nil, which has no limits, when the number of workers is not positive.
+func NewPool(name string, workers, queueSize int) *Pool {
+	if workers <= 0 {
+		return nil
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	return &Pool{
+		name:    name,
+		pending: make(chan struct{}, workers+queueSize),
+		workers: make(chan struct{}, workers),
+	}
+}
+
+// Admit reserves a place in the Pool for an operation, or returns ErrQueueFull
+// when there is none. The returned function must be called when the operation
+// has finished, and can safely be called more than once.
+func (p *Pool) Admit() (release func(), err error) {
+	if p == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case p.pending <- struct{}{}:
+	default:
+		metrics.ObserveTerraformRejection(p.name)
+		return nil, ErrQueueFull
+	}
+	p.report()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-p.pending
+			p.report()
+		})
+	}, nil
+}
+
+// Executor returns a TerraformExecutor that waits for a worker in the Pool before
+// running each command
+func (p *Pool) Executor(wrapped TerraformExecutor) TerraformExecutor {
+	if p == nil {
+		return wrapped
+	}
+
+	return pooledExecutor{pool: p, wrapped: wrapped}
+}
+
+type pooledExecutor struct {
+	pool    *Pool
+	wrapped TerraformExecutor
+}
+
+func (e pooledExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutput, error) {
+	e.pool.changeQueued(1)
+	select {
+	case e.pool.workers <- struct{}{}:
+		e.pool.changeQueued(-1)
+	case <-ctx.Done():
+		e.pool.changeQueued(-1)
+		return ExecutionOutput{}, fmt.Errorf("stopped waiting for a terraform worker: %w", context.Cause(ctx))
+	}
+
+	defer func() {
+		<-e.pool.workers
+		e.pool.report()
+	}()
+
+	return e.wrapped.Execute(ctx, c)
+}
+
+func (p *Pool) changeQueued(delta int) {
+	p.lock.Lock()
+	p.queued += delta
+	p.lock.Unlock()
+	p.report()
+}
+
+func (p *Pool) report() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	metrics.SetTerraformPool(p.name, len(p.pending), p.queued, len(p.workers))
+}
@@ -0,0 +1,119 @@
+package executor_test
+
+import (
+	"context"
+	"os/exec"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor/executorfakes"
+)
+
+var _ = Describe("Pool", func() {
+	Describe("Admit", func() {
+		It("admits operations up to the workers and the queue size", func() {
+			pool := executor.NewPool("fake-pool", 2, 1)
+
+			var releases []func()
+			for i := 0; i < 3; i++ {
+				release, err := pool.Admit()
+				Expect(err).NotTo(HaveOccurred())
+				releases = append(releases, release)
+			}
+
+			_, err := pool.Admit()
+			Expect(err).To(MatchError(executor.ErrQueueFull))
+
+			releases[0]()
+			releases[0]()
+
+			_, err = pool.Admit()
+			Expect(err).NotTo(HaveOccurred())
+			_, err = pool.Admit()
+			Expect(err).To(MatchError(executor.ErrQueueFull))
+		})
+
+		It("admits everything when there is no limit", func() {
+			pool := executor.NewPool("fake-pool", 0, 0)
+			Expect(pool).To(BeNil())
+
+			for i := 0; i < 100; i++ {
+				release, err := pool.Admit()
+				Expect(err).NotTo(HaveOccurred())
+				release()
+			}
+		})
+	})
+
+	Describe("Executor", func() {
+		It("runs at most one command per worker", func() {
+			pool := executor.NewPool("fake-pool", 1, 10)
+
+			running := make(chan struct{})
+			unblock := make(chan struct{})
+			fakeExecutor := &executorfakes.FakeTerraformExecutor{}
+			fakeExecutor.ExecuteCalls(func(context.Context, *exec.Cmd) (executor.ExecutionOutput, error) {
+				running <- struct{}{}
+				<-unblock
+				return executor.ExecutionOutput{}, nil
+			})
+			pooled := pool.Executor(fakeExecutor)
+
+			done := make(chan struct{})
+			for i := 0; i < 2; i++ {
+				go func() {
+					defer GinkgoRecover()
+					_, err := pooled.Execute(context.TODO(), exec.Command("terraform", "apply"))
+					Expect(err).NotTo(HaveOccurred())
+					done <- struct{}{}
+				}()
+			}
+
+			Eventually(running).Should(Receive())
+			Consistently(running).ShouldNot(Receive())
+
+			unblock <- struct{}{}
+			Eventually(done).Should(Receive())
+			Eventually(running).Should(Receive())
+
+			unblock <- struct{}{}
+			Eventually(done).Should(Receive())
+			Expect(fakeExecutor.ExecuteCallCount()).To(Equal(2))
+		})
+
+		It("stops waiting for a worker when the context is done", func() {
+			pool := executor.NewPool("fake-pool", 1, 10)
+
+			unblock := make(chan struct{})
+			defer close(unblock)
+			fakeExecutor := &executorfakes.FakeTerraformExecutor{}
+			fakeExecutor.ExecuteCalls(func(context.Context, *exec.Cmd) (executor.ExecutionOutput, error) {
+				<-unblock
+				return executor.ExecutionOutput{}, nil
+			})
+			pooled := pool.Executor(fakeExecutor)
+
+			go func() {
+				_, _ = pooled.Execute(context.TODO(), exec.Command("terraform", "apply"))
+			}()
+			Eventually(fakeExecutor.ExecuteCallCount).Should(Equal(1))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			_, err := pooled.Execute(ctx, exec.Command("terraform", "apply"))
+
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(fakeExecutor.ExecuteCallCount()).To(Equal(1))
+		})
+
+		It("returns the wrapped executor when there is no limit", func() {
+			var pool *executor.Pool
+			fakeExecutor := &executorfakes.FakeTerraformExecutor{}
+
+			Expect(pool.Executor(fakeExecutor)).To(BeIdenticalTo(fakeExecutor))
+		})
+	})
+})
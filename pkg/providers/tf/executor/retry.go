@@ -0,0 +1,208 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/validation"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
+)
+
+const (
+	// defaultInitialBackoff is how long to wait before the first retry when a rule does not say
+	defaultInitialBackoff = 10 * time.Second
+
+	// maxRetryReasonLength limits the error output that is kept as the reason for a retry
+	maxRetryReasonLength = 200
+)
+
+// RetryRule says when a failed Terraform command is retried. A command is retried when its
+// error output matches the pattern, until it has run the maximum number of attempts. The wait
+// before each retry starts with the initial backoff, and doubles up to the maximum backoff.
+type RetryRule struct {
+	// Pattern is the regular expression that is matched against the error output of Terraform
+	Pattern string `yaml:"pattern"`
+	// MaxAttempts is the maximum number of times that the command runs, including the first time
+	MaxAttempts int `yaml:"max_attempts"`
+	// InitialBackoff is the wait before the first retry. It defaults to 10s.
+	InitialBackoff string `yaml:"initial_backoff,omitempty"`
+	// MaxBackoff is the longest wait between retries. There is no limit when it is empty.
+	MaxBackoff string `yaml:"max_backoff,omitempty"`
+}
+
+var _ validation.Validatable = (*RetryRule)(nil)
+
+// Validate implements validation.Validatable.
+func (r *RetryRule) Validate() (errs *validation.FieldError) {
+	errs = errs.Also(validation.ErrIfBlank(r.Pattern, "pattern"))
+	if _, err := regexp.Compile(r.Pattern); err != nil {
+		errs = errs.Also(&validation.FieldError{
+			Message: fmt.Sprintf("invalid regular expression: %s", err),
+			Paths:   []string{"pattern"},
+		})
+	}
+
+	if r.MaxAttempts < 2 {
+		errs = errs.Also(validation.ErrInvalidValue(r.MaxAttempts, "max_attempts"))
+	}
+
+	return errs.Also(
+		validateBackoff(r.InitialBackoff, "initial_backoff"),
+		validateBackoff(r.MaxBackoff, "max_backoff"),
+	)
+}
+
+func validateBackoff(backoff, field string) *validation.FieldError {
+	if backoff == "" {
+		return nil
+	}
+
+	if d, err := time.ParseDuration(backoff); err != nil || d <= 0 {
+		return validation.ErrInvalidValue(backoff, field)
+	}
+
+	return nil
+}
+
+// Retry is a failed attempt to run a Terraform command that was retried
+type Retry struct {
+	Command string
+	Attempt int
+	// Reason is the error output that matched the rule
+	Reason string
+}
+
+func (r Retry) String() string {
+	return fmt.Sprintf("%s attempt %d failed with %q", r.Command, r.Attempt, r.Reason)
+}
+
+//counterfeiter:generate . RetryRecorder
+
+// RetryRecorder records the retries of the Terraform commands that run for an operation on a deployment
+type RetryRecorder interface {
+	RecordRetry(deploymentID string, retry Retry)
+}
+
+type retryRule struct {
+	pattern        *regexp.Regexp
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// backoff returns the wait after the attempt has failed
+func (r retryRule) backoff(attempt int) time.Duration {
+	backoff := r.initialBackoff
+	for i := 1; i < attempt && (r.maxBackoff == 0 || backoff < r.maxBackoff); i++ {
+		backoff *= 2
+	}
+
+	if r.maxBackoff > 0 && backoff > r.maxBackoff {
+		return r.maxBackoff
+	}
+	return backoff
+}
+
+// RetryExecutor runs a Terraform command again when it fails with error output that matches one
+// of the rules. The first rule that matches applies. The retries of the commands that run for an
+// operation on a deployment are reported to the recorder, which may be nil. The rules must have
+// been validated.
+func RetryExecutor(rules []RetryRule, recorder RetryRecorder, wrapped TerraformExecutor) TerraformExecutor {
+	var compiled []retryRule
+	for _, r := range rules {
+		rule := retryRule{
+			pattern:        regexp.MustCompile(r.Pattern),
+			maxAttempts:    r.MaxAttempts,
+			initialBackoff: defaultInitialBackoff,
+		}
+		if r.InitialBackoff != "" {
+			rule.initialBackoff, _ = time.ParseDuration(r.InitialBackoff)
+		}
+		if r.MaxBackoff != "" {
+			rule.maxBackoff, _ = time.ParseDuration(r.MaxBackoff)
+		}
+		compiled = append(compiled, rule)
+	}
+
+	return retryExecutor{rules: compiled, recorder: recorder, wrapped: wrapped}
+}
+
+type retryExecutor struct {
+	rules    []retryRule
+	recorder RetryRecorder
+	wrapped  TerraformExecutor
+}
+
+func (e retryExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutput, error) {
+	if len(e.rules) == 0 {
+		return e.wrapped.Execute(ctx, c)
+	}
+
+	command := "unknown"
+	if len(c.Args) > 1 {
+		command = c.Args[1]
+	}
+	logger := utils.NewLogger("terraform-retry").WithData(correlation.ID(ctx))
+
+	for attempt := 1; ; attempt++ {
+		output, err := e.wrapped.Execute(ctx, cloneCommand(c))
+
+		var executionError *ExecutionError
+		if !errors.As(err, &executionError) {
+			return output, err
+		}
+
+		rule, reason, ok := e.match(executionError.StdErr)
+		if !ok || attempt >= rule.maxAttempts {
+			return output, err
+		}
+		// The reason is redacted before it is truncated, so that a truncated value is still redacted
+		reason = redaction.RedactValues(ctx, reason)
+		if len(reason) > maxRetryReasonLength {
+			reason = reason[:maxRetryReasonLength] + "..."
+		}
+
+		retry := Retry{Command: command, Attempt: attempt, Reason: reason}
+		backoff := rule.backoff(attempt)
+		logger.Info("retrying", lager.Data{"command": command, "attempt": attempt, "reason": reason, "backoff": backoff.String()})
+		if deploymentID := DeploymentID(ctx); e.recorder != nil && deploymentID != "" {
+			e.recorder.RecordRetry(deploymentID, retry)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ExecutionOutput{}, fmt.Errorf("stopped waiting to retry terraform %s: %w", command, context.Cause(ctx))
+		}
+	}
+}
+
+// match returns the first rule that matches the error output, and the output that it matched
+func (e retryExecutor) match(stderr string) (retryRule, string, bool) {
+	for _, r := range e.rules {
+		if loc := r.pattern.FindStringIndex(stderr); loc != nil {
+			return r, flatten([]byte(stderr[loc[0]:loc[1]])), true
+		}
+	}
+	return retryRule{}, "", false
+}
+
+// cloneCommand returns a copy of a command that has not been started, because a command can only run once
+func cloneCommand(c *exec.Cmd) *exec.Cmd {
+	return &exec.Cmd{
+		Path: c.Path,
+		Args: append([]string(nil), c.Args...),
+		Dir:  c.Dir,
+		Env:  append([]string(nil), c.Env...),
+	}
+}
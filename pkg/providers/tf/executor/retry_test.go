@@ -0,0 +1,163 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor/executorfakes"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
+)
+
+var _ = Describe("RetryExecutor", func() {
+	var (
+		fakeRecorder *executorfakes.FakeRetryRecorder
+		terraform    string
+		ctx          context.Context
+	)
+
+	// command runs the fake Terraform, which fails with the error output the specified number of times
+	command := func(failures, stderr string) *exec.Cmd {
+		c := exec.Command(terraform, "apply")
+		c.Env = append(os.Environ(), "FAILURES="+failures, "FAILURE_OUTPUT="+stderr)
+		return c
+	}
+
+	attempts := func() string {
+		count, err := os.ReadFile(terraform + ".count")
+		Expect(err).NotTo(HaveOccurred())
+		return string(count)
+	}
+
+	BeforeEach(func() {
+		fakeRecorder = &executorfakes.FakeRetryRecorder{}
+		ctx = executor.WithDeploymentID(context.TODO(), "fake-deployment-id")
+
+		terraform = filepath.Join(GinkgoT().TempDir(), "terraform")
+		Expect(os.WriteFile(terraform, []byte(`#!/bin/sh
+count=0
+if [ -f "$0.count" ]; then read count < "$0.count"; fi
+count=$((count+1))
+echo "$count" > "$0.count"
+if [ "$count" -le "$FAILURES" ]; then printf "Error: $FAILURE_OUTPUT\n\n  on main.tf line 1\n" >&2; exit 1; fi
+echo "applied"
+`), 0755)).To(Succeed())
+	})
+
+	It("retries a command that fails with error output matching a rule", func() {
+		rules := []executor.RetryRule{{Pattern: `409 Conflict: \w+`, MaxAttempts: 3, InitialBackoff: "1ms"}}
+
+		output, err := executor.RetryExecutor(rules, fakeRecorder, executor.DefaultExecutor()).Execute(ctx, command("2", "409 Conflict: busy"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.StdOut).To(Equal("applied\n"))
+		Expect(attempts()).To(Equal("3\n"))
+
+		Expect(fakeRecorder.RecordRetryCallCount()).To(Equal(2))
+		deploymentID, retry := fakeRecorder.RecordRetryArgsForCall(0)
+		Expect(deploymentID).To(Equal("fake-deployment-id"))
+		Expect(retry).To(Equal(executor.Retry{Command: "apply", Attempt: 1, Reason: "409 Conflict: busy"}))
+		_, retry = fakeRecorder.RecordRetryArgsForCall(1)
+		Expect(retry).To(Equal(executor.Retry{Command: "apply", Attempt: 2, Reason: "409 Conflict: busy"}))
+	})
+
+	It("redacts the reason for a retry before it is truncated", func() {
+		rules := []executor.RetryRule{{Pattern: `409 Conflict: .*`, MaxAttempts: 2, InitialBackoff: "1ms"}}
+		prefix := "409 Conflict: " + strings.Repeat("a", 180) + " "
+		ctx = redaction.WithValues(ctx, "fake-secret-value")
+
+		_, err := executor.RetryExecutor(rules, fakeRecorder, executor.DefaultExecutor()).Execute(ctx, command("1", prefix+"fake-secret-value"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeRecorder.RecordRetryCallCount()).To(Equal(1))
+		_, retry := fakeRecorder.RecordRetryArgsForCall(0)
+		Expect(retry.Reason).To(Equal(prefix + redaction.Mask[:200-len(prefix)] + "..."))
+	})
+
+	It("returns the error after the maximum number of attempts", func() {
+		rules := []executor.RetryRule{{Pattern: `409 Conflict`, MaxAttempts: 2, InitialBackoff: "1ms"}}
+
+		_, err := executor.RetryExecutor(rules, fakeRecorder, executor.DefaultExecutor()).Execute(ctx, command("5", "409 Conflict"))
+		var executionError *executor.ExecutionError
+		Expect(errors.As(err, &executionError)).To(BeTrue())
+		Expect(executionError.StdErr).To(ContainSubstring("Error: 409 Conflict"))
+		Expect(attempts()).To(Equal("2\n"))
+		Expect(fakeRecorder.RecordRetryCallCount()).To(Equal(1))
+	})
+
+	It("does not retry a command that fails with other error output", func() {
+		rules := []executor.RetryRule{{Pattern: `409 Conflict`, MaxAttempts: 3, InitialBackoff: "1ms"}}
+
+		_, err := executor.RetryExecutor(rules, fakeRecorder, executor.DefaultExecutor()).Execute(ctx, command("1", "invalid argument"))
+		Expect(err).To(HaveOccurred())
+		Expect(attempts()).To(Equal("1\n"))
+		Expect(fakeRecorder.RecordRetryCallCount()).To(BeZero())
+	})
+
+	It("applies the first rule that matches", func() {
+		rules := []executor.RetryRule{
+			{Pattern: `quota`, MaxAttempts: 2, InitialBackoff: "1ms"},
+			{Pattern: `.*`, MaxAttempts: 5, InitialBackoff: "1ms"},
+		}
+
+		_, err := executor.RetryExecutor(rules, fakeRecorder, executor.DefaultExecutor()).Execute(ctx, command("5", "quota exceeded"))
+		Expect(err).To(HaveOccurred())
+		Expect(attempts()).To(Equal("2\n"))
+	})
+
+	It("doubles the wait before each retry", func() {
+		rules := []executor.RetryRule{{Pattern: `409 Conflict`, MaxAttempts: 4, InitialBackoff: "20ms"}}
+
+		start := time.Now()
+		_, err := executor.RetryExecutor(rules, fakeRecorder, executor.DefaultExecutor()).Execute(ctx, command("3", "409 Conflict"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically(">=", 140*time.Millisecond))
+	})
+
+	It("stops waiting to retry when the context is done", func() {
+		rules := []executor.RetryRule{{Pattern: `409 Conflict`, MaxAttempts: 3, InitialBackoff: "1h"}}
+		ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		_, err := executor.RetryExecutor(rules, fakeRecorder, executor.DefaultExecutor()).Execute(ctx, command("1", "409 Conflict"))
+		Expect(err).To(MatchError("stopped waiting to retry terraform apply: context deadline exceeded"))
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(attempts()).To(Equal("1\n"))
+	})
+
+	It("does not record the retries of commands that are not part of an operation on a deployment", func() {
+		rules := []executor.RetryRule{{Pattern: `409 Conflict`, MaxAttempts: 3, InitialBackoff: "1ms"}}
+
+		_, err := executor.RetryExecutor(rules, fakeRecorder, executor.DefaultExecutor()).Execute(context.TODO(), command("1", "409 Conflict"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts()).To(Equal("2\n"))
+		Expect(fakeRecorder.RecordRetryCallCount()).To(BeZero())
+	})
+
+	Describe("RetryRule", func() {
+		It("validates a rule", func() {
+			rule := executor.RetryRule{Pattern: `409 Conflict`, MaxAttempts: 3, InitialBackoff: "10s", MaxBackoff: "2m"}
+			Expect(rule.Validate()).To(BeNil())
+		})
+
+		It("validates the fields", func() {
+			rule := executor.RetryRule{Pattern: `(`, MaxAttempts: 1, InitialBackoff: "soon", MaxBackoff: "-1m"}
+			Expect(rule.Validate()).To(MatchError(ContainSubstring("invalid regular expression: error parsing regexp: missing closing ): `(`: pattern")))
+			Expect(rule.Validate()).To(MatchError(ContainSubstring("invalid value: 1: max_attempts")))
+			Expect(rule.Validate()).To(MatchError(ContainSubstring("invalid value: soon: initial_backoff")))
+			Expect(rule.Validate()).To(MatchError(ContainSubstring("invalid value: -1m: max_backoff")))
+		})
+
+		It("requires a pattern", func() {
+			rule := executor.RetryRule{MaxAttempts: 3}
+			Expect(rule.Validate()).To(MatchError("missing field(s): pattern"))
+		})
+	})
+})
@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
+)
+
+// CommandLog is the output of a Terraform command that ran for an operation on a deployment
+type CommandLog struct {
+	DeploymentID string
+	Command      string
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	Error        string
+	Stdout       string
+	Stderr       string
+	TFLog        string
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+//counterfeiter:generate . LogStore
+
+// LogStore records the output of Terraform commands
+type LogStore interface {
+	StoreCommandLog(CommandLog) error
+}
+
+type deploymentIDKey struct{}
+
+// WithDeploymentID returns a context for the Terraform commands of an operation on a deployment,
+// so that their output is recorded for the deployment
+func WithDeploymentID(ctx context.Context, deploymentID string) context.Context {
+	return context.WithValue(ctx, deploymentIDKey{}, deploymentID)
+}
+
+// DeploymentID returns the deployment that the Terraform commands run for,
+// or an empty string when they are not part of an operation on a deployment
+func DeploymentID(ctx context.Context) string {
+	id, _ := ctx.Value(deploymentIDKey{}).(string)
+	return id
+}
+
+// LogCaptureExecutor records the complete output of the Terraform commands that run for an operation
+// on a deployment, with the values that are masked in the context replaced. When a log level is specified, Terraform is run with
+// TF_LOG at that level and its log is recorded too. Commands that are not part of an operation on a
+// deployment are not recorded, and a nil store records nothing.
+func LogCaptureExecutor(store LogStore, tfLogLevel string, wrapped TerraformExecutor) TerraformExecutor {
+	return logCaptureExecutor{
+		store:      store,
+		tfLogLevel: tfLogLevel,
+		wrapped:    wrapped,
+	}
+}
+
+type logCaptureExecutor struct {
+	store      LogStore
+	tfLogLevel string
+	wrapped    TerraformExecutor
+}
+
+func (e logCaptureExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutput, error) {
+	deploymentID := DeploymentID(ctx)
+	if e.store == nil || deploymentID == "" {
+		return e.wrapped.Execute(ctx, c)
+	}
+
+	logger := utils.NewLogger("terraform-logs").WithData(correlation.ID(ctx))
+
+	var tfLogPath string
+	if e.tfLogLevel != "" {
+		f, err := os.CreateTemp("", "tf-log")
+		if err != nil {
+			return ExecutionOutput{}, fmt.Errorf("error creating file for TF_LOG output: %w", err)
+		}
+		_ = f.Close()
+		tfLogPath = f.Name()
+		defer os.Remove(tfLogPath)
+
+		c.Env = append(c.Env, fmt.Sprintf("TF_LOG=%s", e.tfLogLevel), fmt.Sprintf("TF_LOG_PATH=%s", tfLogPath))
+	}
+
+	l := CommandLog{
+		DeploymentID: deploymentID,
+		Command:      "unknown",
+		StartedAt:    time.Now(),
+	}
+	if len(c.Args) > 1 {
+		l.Command = c.Args[1]
+	}
+
+	output, err := e.wrapped.Execute(ctx, c)
+	l.FinishedAt = time.Now()
+
+	var executionError *ExecutionError
+	switch {
+	case errors.As(err, &executionError):
+		l.Stdout, l.Stderr = executionError.StdOut, executionError.StdErr
+		l.Error = executionError.err.Error()
+	case err != nil:
+		l.Error = err.Error()
+	default:
+		l.Stdout, l.Stderr = output.StdOut, output.StdErr
+	}
+
+	if tfLogPath != "" {
+		if tfLog, readErr := os.ReadFile(tfLogPath); readErr == nil {
+			l.TFLog = string(tfLog)
+		} else {
+			logger.Error("read-tf-log-failed", readErr)
+		}
+	}
+
+	redactor := redaction.NewReplacer(redaction.Values(ctx))
+	l.Stdout = redactor.Replace(l.Stdout)
+	l.Stderr = redactor.Replace(l.Stderr)
+	l.TFLog = redactor.Replace(l.TFLog)
+	l.Error = redactor.Replace(l.Error)
+
+	// The output is only kept for troubleshooting, so the command does not fail when it cannot be stored
+	if storeErr := e.store.StoreCommandLog(l); storeErr != nil {
+		logger.Error("store-terraform-log-failed", storeErr, lager.Data{"deployment": deploymentID, "command": l.Command})
+	}
+
+	return output, err
+}
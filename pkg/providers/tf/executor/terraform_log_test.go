@@ -0,0 +1,102 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor/executorfakes"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
+)
+
+var _ = Describe("LogCaptureExecutor", func() {
+	var (
+		fakeStore *executorfakes.FakeLogStore
+		terraform string
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		fakeStore = &executorfakes.FakeLogStore{}
+		ctx = executor.WithDeploymentID(context.TODO(), "fake-deployment-id")
+
+		terraform = filepath.Join(GinkgoT().TempDir(), "terraform")
+		Expect(os.WriteFile(terraform, []byte(`#!/bin/sh
+echo "running $1 with fake-secret-value"
+echo "warning" >&2
+if [ -n "$TF_LOG_PATH" ]; then echo "log at $TF_LOG" > "$TF_LOG_PATH"; fi
+if [ "$1" = "destroy" ]; then echo "boom" >&2; exit 1; fi
+`), 0755)).To(Succeed())
+	})
+
+	It("records the output of the command for the deployment", func() {
+		output, err := executor.LogCaptureExecutor(fakeStore, "", executor.DefaultExecutor()).Execute(ctx, exec.Command(terraform, "apply"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.StdOut).To(Equal("running apply with fake-secret-value\n"))
+
+		Expect(fakeStore.StoreCommandLogCallCount()).To(Equal(1))
+		l := fakeStore.StoreCommandLogArgsForCall(0)
+		Expect(l.DeploymentID).To(Equal("fake-deployment-id"))
+		Expect(l.Command).To(Equal("apply"))
+		Expect(l.StartedAt).NotTo(BeZero())
+		Expect(l.FinishedAt).To(BeTemporally(">=", l.StartedAt))
+		Expect(l.Error).To(BeEmpty())
+		Expect(l.Stdout).To(Equal("running apply with fake-secret-value\n"))
+		Expect(l.Stderr).To(Equal("warning\n"))
+		Expect(l.TFLog).To(BeEmpty())
+	})
+
+	It("records the complete output when the command fails", func() {
+		_, err := executor.LogCaptureExecutor(fakeStore, "", executor.DefaultExecutor()).Execute(ctx, exec.Command(terraform, "destroy"))
+		Expect(err).To(HaveOccurred())
+
+		l := fakeStore.StoreCommandLogArgsForCall(0)
+		Expect(l.Command).To(Equal("destroy"))
+		Expect(l.Error).To(Equal("exit status 1"))
+		Expect(l.Stdout).To(Equal("running destroy with fake-secret-value\n"))
+		Expect(l.Stderr).To(Equal("warning\nboom\n"))
+	})
+
+	It("redacts the secrets", func() {
+		ctx = redaction.WithValues(ctx, "secret", "fake-secret-value", "true")
+		_, err := executor.LogCaptureExecutor(fakeStore, "", executor.DefaultExecutor()).Execute(ctx, exec.Command(terraform, "apply"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeStore.StoreCommandLogArgsForCall(0).Stdout).To(Equal("running apply with *REDACTED*\n"))
+	})
+
+	It("redacts the secrets of a redacting executor", func() {
+		e := executor.RedactingExecutor([]string{"fake-secret-value"}, executor.LogCaptureExecutor(fakeStore, "", executor.DefaultExecutor()))
+		_, err := e.Execute(ctx, exec.Command(terraform, "apply"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeStore.StoreCommandLogArgsForCall(0).Stdout).To(Equal("running apply with *REDACTED*\n"))
+	})
+
+	It("records the TF_LOG output when a log level is specified", func() {
+		_, err := executor.LogCaptureExecutor(fakeStore, "DEBUG", executor.DefaultExecutor()).Execute(ctx, exec.Command(terraform, "apply"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeStore.StoreCommandLogArgsForCall(0).TFLog).To(Equal("log at DEBUG\n"))
+	})
+
+	It("does not record commands that are not part of an operation on a deployment", func() {
+		_, err := executor.LogCaptureExecutor(fakeStore, "", executor.DefaultExecutor()).Execute(context.TODO(), exec.Command(terraform, "apply"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeStore.StoreCommandLogCallCount()).To(BeZero())
+	})
+
+	It("does not fail the command when the output cannot be recorded", func() {
+		fakeStore.StoreCommandLogReturns(errors.New("boom"))
+
+		_, err := executor.LogCaptureExecutor(fakeStore, "", executor.DefaultExecutor()).Execute(ctx, exec.Command(terraform, "apply"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
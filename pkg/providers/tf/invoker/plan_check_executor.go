@@ -0,0 +1,30 @@
+package invoker
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/command"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+)
+
+// planCheckExecutor checks the saved plan when it is shown in the Terraform JSON output format.
+// A plan that fails the check stops the commands that follow, so that it is not applied.
+type planCheckExecutor struct {
+	executor executor.TerraformExecutor
+	check    func(plan string) error
+}
+
+func (e planCheckExecutor) Execute(ctx context.Context, c *exec.Cmd) (executor.ExecutionOutput, error) {
+	output, err := e.executor.Execute(ctx, c)
+	if err != nil || !isCommand(c, command.NewShowPlanJSON(planFile)) {
+		return output, err
+	}
+
+	return output, e.check(output.StdOut)
+}
+
+func isCommand(c *exec.Cmd, cmd command.TerraformCommand) bool {
+	return len(c.Args) > 0 && strings.Join(c.Args[1:], " ") == strings.Join(cmd.Command(), " ")
+}
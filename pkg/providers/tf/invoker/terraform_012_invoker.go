@@ -45,6 +45,23 @@ func (cmd Terraform012Invoker) Plan(ctx context.Context, workspace workspace.Wor
 		command.NewPlan())
 }
 
+func (cmd Terraform012Invoker) PlanJSON(ctx context.Context, workspace workspace.Workspace) (string, error) {
+	output, err := workspace.Execute(ctx, cmd.executor,
+		command.NewInit012(cmd.pluginDirectory),
+		command.NewPlanToFile(planFile),
+		command.NewShowPlanJSON(planFile))
+	return output.StdOut, err
+}
+
+func (cmd Terraform012Invoker) ApplyCheckedPlan(ctx context.Context, workspace workspace.Workspace, check func(plan string) error) error {
+	_, err := workspace.Execute(ctx, planCheckExecutor{executor: cmd.executor, check: check},
+		command.NewInit012(cmd.pluginDirectory),
+		command.NewPlanToFile(planFile),
+		command.NewShowPlanJSON(planFile),
+		command.NewApplyPlan(planFile))
+	return err
+}
+
 func (cmd Terraform012Invoker) Import(ctx context.Context, workspace workspace.Workspace, resources map[string]string) error {
 	commands := []command.TerraformCommand{
 		command.NewInit012(cmd.pluginDirectory),
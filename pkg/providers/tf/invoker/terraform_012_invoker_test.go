@@ -3,6 +3,7 @@ package invoker_test
 import (
 	"context"
 
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor/executorfakes"
 
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/command"
@@ -41,4 +42,41 @@ var _ = Context("Terraform012Invoker", func() {
 			}))
 		})
 	})
+
+	Context("PlanJSON", func() {
+		It("initializes the workspace, saves the plan and shows it as JSON", func() {
+			fakeWorkspace.ExecuteReturns(executor.ExecutionOutput{StdOut: `{"format_version":"1.0"}`}, nil)
+
+			plan, err := invokerUnderTest.PlanJSON(expectedContext, fakeWorkspace)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan).To(Equal(`{"format_version":"1.0"}`))
+			Expect(fakeWorkspace.ExecuteCallCount()).To(Equal(1))
+			actualContext, actualExecutor, actualCommands := fakeWorkspace.ExecuteArgsForCall(0)
+			Expect(actualContext).To(Equal(expectedContext))
+			Expect(actualExecutor).To(Equal(fakeExecutor))
+			Expect(actualCommands).To(Equal([]command.TerraformCommand{
+				command.NewInit012(pluginDirectory),
+				command.NewPlanToFile("tfplan"),
+				command.NewShowPlanJSON("tfplan"),
+			}))
+		})
+	})
+	Context("ApplyCheckedPlan", func() {
+		It("initializes the workspace, saves the plan, shows it as JSON and applies it", func() {
+			Expect(
+				invokerUnderTest.ApplyCheckedPlan(expectedContext, fakeWorkspace, func(string) error { return nil }),
+			).To(Succeed())
+
+			Expect(fakeWorkspace.ExecuteCallCount()).To(Equal(1))
+			actualContext, _, actualCommands := fakeWorkspace.ExecuteArgsForCall(0)
+			Expect(actualContext).To(Equal(expectedContext))
+			Expect(actualCommands).To(Equal([]command.TerraformCommand{
+				command.NewInit012(pluginDirectory),
+				command.NewPlanToFile("tfplan"),
+				command.NewShowPlanJSON("tfplan"),
+				command.NewApplyPlan("tfplan"),
+			}))
+		})
+	})
 })
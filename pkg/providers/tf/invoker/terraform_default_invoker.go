@@ -56,6 +56,29 @@ func (cmd TerraformDefaultInvoker) Plan(ctx context.Context, workspace workspace
 		command.NewPlan())
 }
 
+func (cmd TerraformDefaultInvoker) PlanJSON(ctx context.Context, workspace workspace.Workspace) (string, error) {
+	output, err := workspace.Execute(ctx, cmd.executor,
+		command.NewInit(cmd.pluginDirectory),
+		command.NewPlanToFile(planFile),
+		command.NewShowPlanJSON(planFile))
+	return output.StdOut, err
+}
+
+func (cmd TerraformDefaultInvoker) ApplyCheckedPlan(ctx context.Context, workspace workspace.Workspace, check func(plan string) error) error {
+	var commands []command.TerraformCommand
+	if workspace.HasState() {
+		commands = cmd.ReplacementCommands()
+	}
+	commands = append(commands,
+		command.NewInit(cmd.pluginDirectory),
+		command.NewPlanToFile(planFile),
+		command.NewShowPlanJSON(planFile),
+		command.NewApplyPlan(planFile))
+
+	_, err := workspace.Execute(ctx, planCheckExecutor{executor: cmd.executor, check: check}, commands...)
+	return err
+}
+
 func (cmd TerraformDefaultInvoker) Import(ctx context.Context, workspace workspace.Workspace, resources map[string]string) error {
 	commands := []command.TerraformCommand{
 		command.NewInit(cmd.pluginDirectory),
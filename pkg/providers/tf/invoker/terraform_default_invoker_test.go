@@ -2,10 +2,13 @@ package invoker_test
 
 import (
 	"context"
+	"errors"
+	"os/exec"
 
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/command"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/invoker"
 
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor/executorfakes"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace/workspacefakes"
 	. "github.com/onsi/ginkgo/v2"
@@ -135,4 +138,74 @@ var _ = Context("TerraformDefaultInvoker", func() {
 			})
 		})
 	})
+
+	Context("PlanJSON", func() {
+		It("initializes the workspace, saves the plan and shows it as JSON", func() {
+			fakeWorkspace.ExecuteReturns(executor.ExecutionOutput{StdOut: `{"format_version":"1.0"}`}, nil)
+
+			plan, err := invokerUnderTest.PlanJSON(expectedContext, fakeWorkspace)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan).To(Equal(`{"format_version":"1.0"}`))
+			Expect(fakeWorkspace.ExecuteCallCount()).To(Equal(1))
+			actualContext, actualExecutor, actualCommands := fakeWorkspace.ExecuteArgsForCall(0)
+			Expect(actualContext).To(Equal(expectedContext))
+			Expect(actualExecutor).To(Equal(fakeExecutor))
+			Expect(actualCommands).To(Equal([]command.TerraformCommand{
+				command.NewInit(pluginDirectory),
+				command.NewPlanToFile("tfplan"),
+				command.NewShowPlanJSON("tfplan"),
+			}))
+		})
+	})
+	Context("ApplyCheckedPlan", func() {
+		var checkedPlans []string
+
+		BeforeEach(func() {
+			checkedPlans = nil
+			fakeWorkspace.HasStateReturns(true)
+		})
+
+		check := func(plan string) error {
+			checkedPlans = append(checkedPlans, plan)
+			if plan == "rejected" {
+				return errors.New("plan rejected")
+			}
+			return nil
+		}
+
+		It("renames providers, initializes the workspace, saves the plan, shows it as JSON and applies it", func() {
+			Expect(invokerUnderTest.ApplyCheckedPlan(expectedContext, fakeWorkspace, check)).To(Succeed())
+
+			Expect(fakeWorkspace.ExecuteCallCount()).To(Equal(1))
+			actualContext, _, actualCommands := fakeWorkspace.ExecuteArgsForCall(0)
+			Expect(actualContext).To(Equal(expectedContext))
+			Expect(actualCommands).To(Equal([]command.TerraformCommand{
+				command.NewRenameProvider("old_provider_1", "new_provider_1"),
+				command.NewInit(pluginDirectory),
+				command.NewPlanToFile("tfplan"),
+				command.NewShowPlanJSON("tfplan"),
+				command.NewApplyPlan("tfplan"),
+			}))
+		})
+
+		It("checks the plan when it is shown, and stops the commands when the check fails", func() {
+			Expect(invokerUnderTest.ApplyCheckedPlan(expectedContext, fakeWorkspace, check)).To(Succeed())
+			_, actualExecutor, _ := fakeWorkspace.ExecuteArgsForCall(0)
+
+			fakeExecutor.ExecuteReturns(executor.ExecutionOutput{StdOut: "accepted"}, nil)
+			_, err := actualExecutor.Execute(expectedContext, exec.Command("terraform", "plan", "-no-color", "-out=tfplan"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checkedPlans).To(BeEmpty())
+
+			_, err = actualExecutor.Execute(expectedContext, exec.Command("terraform", "show", "-json", "tfplan"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checkedPlans).To(Equal([]string{"accepted"}))
+
+			fakeExecutor.ExecuteReturns(executor.ExecutionOutput{StdOut: "rejected"}, nil)
+			_, err = actualExecutor.Execute(expectedContext, exec.Command("terraform", "show", "-json", "tfplan"))
+			Expect(err).To(MatchError("plan rejected"))
+			Expect(fakeExecutor.ExecuteCallCount()).To(Equal(3))
+		})
+	})
 })
@@ -10,6 +10,9 @@ import (
 	"github.com/hashicorp/go-version"
 )
 
+// planFile is the name of the file in the workspace to which a plan is saved
+const planFile = "tfplan"
+
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate . TerraformInvokerBuilder
 type TerraformInvokerBuilder interface {
@@ -23,5 +26,10 @@ type TerraformInvoker interface {
 	Apply(ctx context.Context, workspace workspace.Workspace) error
 	Show(ctx context.Context, workspace workspace.Workspace) (string, error)
 	Plan(ctx context.Context, workspace workspace.Workspace) (executor.ExecutionOutput, error)
+	// PlanJSON returns the plan in the Terraform JSON output format
+	PlanJSON(ctx context.Context, workspace workspace.Workspace) (string, error)
+	// ApplyCheckedPlan saves a plan and applies it, unless the check of the plan in the
+	// Terraform JSON output format fails. The plan that is applied is the plan that was checked.
+	ApplyCheckedPlan(ctx context.Context, workspace workspace.Workspace, check func(plan string) error) error
 	Import(ctx context.Context, workspace workspace.Workspace, resources map[string]string) error
 }
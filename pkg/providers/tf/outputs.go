@@ -19,6 +19,17 @@ func (provider *TerraformProvider) GetTerraformOutputs(_ context.Context, instan
 	return outs, nil
 }
 
+func (provider *TerraformProvider) GetBindingOutputs(_ context.Context, instanceGUID, bindingID string) (storage.JSONObject, error) {
+	tfID := generateTfID(instanceGUID, bindingID)
+
+	outs, err := provider.outputs(tfID, workspace.DefaultInstanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return outs, nil
+}
+
 // Outputs gets the output variables for the given module instance in the workspace.
 func (provider *TerraformProvider) outputs(deploymentID, instanceName string) (map[string]any, error) {
 	deployment, err := provider.GetTerraformDeployment(deploymentID)
@@ -72,4 +72,22 @@ var _ = Describe("Outputs", func() {
 			Expect(err).To(MatchError("cant get outputs"))
 		})
 	})
+	Describe("GetBindingOutputs", func() {
+		It("returns workspace outputs of the binding deployment", func() {
+			fakeDeploymentManager := &tffakes.FakeDeploymentManagerInterface{}
+			fakeWorkspace := &workspacefakes.FakeWorkspace{}
+			fakeDeploymentManager.GetTerraformDeploymentReturns(storage.TerraformDeployment{
+				Workspace: fakeWorkspace,
+			}, nil)
+			fakeWorkspace.OutputsReturns(map[string]any{"username": "foo"}, nil)
+
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, &tffakes.FakeTerraformInvokerBuilder{}, utils.NewLogger("test"), tf.TfServiceDefinitionV1{}, fakeDeploymentManager)
+
+			output, err := provider.GetBindingOutputs(context.TODO(), "instance-guid", "binding-guid")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal(storage.JSONObject{"username": "foo"}))
+			Expect(fakeDeploymentManager.GetTerraformDeploymentArgsForCall(0)).To(Equal("tf:instance-guid:binding-guid"))
+		})
+	})
 })
@@ -0,0 +1,77 @@
+package tf
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/featureflags"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/varcontext"
+	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
+)
+
+// PlanUpdate runs "terraform plan" with the configuration that an update would apply, and reports
+// the resources that the update would change. The plan is not applied and the deployment is not modified.
+func (provider *TerraformProvider) PlanUpdate(ctx context.Context, updateContext *varcontext.VarContext) ([]storage.TerraformResourceChange, error) {
+	provider.logger.Debug("plan-update", correlation.ID(ctx), lager.Data{
+		"context": updateContext.ToMap(),
+	})
+
+	if provider.serviceDefinition.ProvisionSettings.IsTfImport(updateContext) {
+		return nil, fmt.Errorf("cannot update to subsume plan")
+	}
+
+	tfID := updateContext.GetString("tf_id")
+	if err := updateContext.Error(); err != nil {
+		return nil, err
+	}
+
+	deployment, err := provider.GetTerraformDeployment(tfID)
+	if err != nil {
+		return nil, err
+	}
+	if deployment.LastOperationState == InProgress {
+		return nil, fmt.Errorf("cannot plan an update of %q while its %s operation is in progress", tfID, deployment.LastOperationType)
+	}
+
+	release, err := provider.tfBinContext.Pool.Admit()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// The workspace is changed in the same way as for an update, but it is never stored
+	workspace := deployment.Workspace
+	if featureflags.Enabled(featureflags.DynamicHCLEnabled) || featureflags.Enabled(featureflags.TfUpgradeEnabled) {
+		workspace, err = regenerateWorkspace(deployment.TFWorkspace(), provider.serviceDefinition.ProvisionSettings, updateContext.ToMap())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := workspace.UpdateInstanceConfiguration(updateContext.ToMap()); err != nil {
+		return nil, err
+	}
+
+	plan, err := provider.DefaultInvoker().PlanJSON(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := ParseTerraformJSONPlan(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]storage.TerraformResourceChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, storage.TerraformResourceChange{
+			Address: change.Address,
+			Action:  change.Action,
+		})
+	}
+
+	return result, nil
+}
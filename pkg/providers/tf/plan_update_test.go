@@ -0,0 +1,119 @@
+package tf_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/tffakes"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace/workspacefakes"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/varcontext"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+)
+
+var _ = Describe("PlanUpdate", func() {
+	const plan = `{"resource_changes": [
+		{"address": "random_password.password", "mode": "managed", "type": "random_password", "change": {"actions": ["delete", "create"]}},
+		{"address": "aws_db_instance.db", "mode": "managed", "type": "aws_db_instance", "change": {"actions": ["update"]}},
+		{"address": "aws_s3_bucket.bucket", "mode": "managed", "type": "aws_s3_bucket", "change": {"actions": ["no-op"]}}
+	]}`
+
+	var (
+		fakeDeploymentManager *tffakes.FakeDeploymentManagerInterface
+		fakeInvokerBuilder    *tffakes.FakeTerraformInvokerBuilder
+		fakeDefaultInvoker    *tffakes.FakeTerraformInvoker
+		fakeWorkspace         *workspacefakes.FakeWorkspace
+		provider              *tf.TerraformProvider
+		varContext            *varcontext.VarContext
+		templateVars          = map[string]any{"tf_id": "fake-deployment-id", "var": "value"}
+	)
+
+	BeforeEach(func() {
+		fakeWorkspace = &workspacefakes.FakeWorkspace{}
+		fakeDeploymentManager = &tffakes.FakeDeploymentManagerInterface{}
+		fakeDeploymentManager.GetTerraformDeploymentReturns(storage.TerraformDeployment{
+			ID:                 "fake-deployment-id",
+			Workspace:          fakeWorkspace,
+			LastOperationType:  "provision",
+			LastOperationState: tf.Succeeded,
+		}, nil)
+		fakeDefaultInvoker = &tffakes.FakeTerraformInvoker{}
+		fakeDefaultInvoker.PlanJSONReturns(plan, nil)
+		fakeInvokerBuilder = &tffakes.FakeTerraformInvokerBuilder{}
+		fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+
+		provider = tf.NewTerraformProvider(executor.TFBinariesContext{DefaultTfVersion: newVersion("1.1")}, fakeInvokerBuilder, utils.NewLogger("test"), tf.TfServiceDefinitionV1{}, fakeDeploymentManager)
+
+		var err error
+		varContext, err = varcontext.Builder().MergeMap(templateVars).Build()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("plans the update with the new configuration and reports the changes", func() {
+		changes, err := provider.PlanUpdate(context.TODO(), varContext)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(changes).To(Equal([]storage.TerraformResourceChange{
+			{Address: "random_password.password", Action: "replace"},
+			{Address: "aws_db_instance.db", Action: "update"},
+		}))
+		Expect(fakeDeploymentManager.GetTerraformDeploymentArgsForCall(0)).To(Equal("fake-deployment-id"))
+		Expect(fakeWorkspace.UpdateInstanceConfigurationArgsForCall(0)).To(Equal(templateVars))
+		_, actualWorkspace := fakeDefaultInvoker.PlanJSONArgsForCall(0)
+		Expect(actualWorkspace).To(Equal(fakeWorkspace))
+	})
+
+	It("does not modify the deployment", func() {
+		_, err := provider.PlanUpdate(context.TODO(), varContext)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeDeploymentManager.UpdateWorkspaceHCLCallCount()).To(BeZero())
+		Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(BeZero())
+		Expect(fakeDeploymentManager.MarkOperationFinishedCallCount()).To(BeZero())
+		Expect(fakeDefaultInvoker.ApplyCallCount()).To(BeZero())
+	})
+
+	When("an operation is in progress", func() {
+		BeforeEach(func() {
+			fakeDeploymentManager.GetTerraformDeploymentReturns(storage.TerraformDeployment{
+				ID:                 "fake-deployment-id",
+				Workspace:          fakeWorkspace,
+				LastOperationType:  "update",
+				LastOperationState: tf.InProgress,
+			}, nil)
+		})
+
+		It("returns an error", func() {
+			_, err := provider.PlanUpdate(context.TODO(), varContext)
+			Expect(err).To(MatchError(`cannot plan an update of "fake-deployment-id" while its update operation is in progress`))
+			Expect(fakeDefaultInvoker.PlanJSONCallCount()).To(BeZero())
+		})
+	})
+
+	When("getting the deployment fails", func() {
+		BeforeEach(func() {
+			fakeDeploymentManager.GetTerraformDeploymentReturns(storage.TerraformDeployment{}, errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			_, err := provider.PlanUpdate(context.TODO(), varContext)
+			Expect(err).To(MatchError("boom"))
+		})
+	})
+
+	When("terraform plan fails", func() {
+		BeforeEach(func() {
+			fakeDefaultInvoker.PlanJSONReturns("", errors.New("plan failed"))
+		})
+
+		It("returns the error", func() {
+			_, err := provider.PlanUpdate(context.TODO(), varContext)
+			Expect(err).To(MatchError("plan failed"))
+		})
+	})
+})
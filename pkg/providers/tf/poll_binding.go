@@ -0,0 +1,10 @@
+package tf
+
+import (
+	"context"
+)
+
+// PollBinding returns the binding status of the backing job.
+func (provider *TerraformProvider) PollBinding(_ context.Context, instanceGUID, bindingID string) (bool, string, error) {
+	return provider.OperationStatus(generateTfID(instanceGUID, bindingID))
+}
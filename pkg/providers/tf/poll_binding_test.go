@@ -0,0 +1,32 @@
+package tf_test
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/tffakes"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PollBinding", func() {
+	It("returns the binding operation status", func() {
+		fakeDeploymentManager := &tffakes.FakeDeploymentManagerInterface{}
+		fakeInvokerBuilder := &tffakes.FakeTerraformInvokerBuilder{}
+		fakeLogger := utils.NewLogger("test")
+
+		fakeDeploymentManager.OperationStatusReturns(false, "bind in progress", nil)
+		provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, tf.TfServiceDefinitionV1{}, fakeDeploymentManager)
+
+		finished, message, err := provider.PollBinding(context.TODO(), "instance-guid", "binding-guid")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(finished).To(BeFalse())
+		Expect(message).To(Equal("bind in progress"))
+
+		Expect(fakeDeploymentManager.OperationStatusCallCount()).To(Equal(1))
+		Expect(fakeDeploymentManager.OperationStatusArgsForCall(0)).To(Equal("tf:instance-guid:binding-guid"))
+	})
+})
@@ -33,6 +33,8 @@ const (
 	InProgress = "in progress"
 	Succeeded  = "succeeded"
 	Failed     = "failed"
+	// TimedOut is the state of an operation that failed because it ran for longer than its timeout
+	TimedOut = "timed out"
 )
 
 // NewTerraformProvider creates a new ServiceProvider backed by Terraform module definitions for provision and bind.
@@ -79,17 +81,28 @@ func (provider *TerraformProvider) create(ctx context.Context, vars *varcontext.
 		return tfID, fmt.Errorf("error creating workspace: %w", err)
 	}
 
+	release, err := provider.tfBinContext.Pool.Admit()
+	if err != nil {
+		return tfID, err
+	}
+
 	deployment, err := provider.CreateAndSaveDeployment(tfID, newWorkspace)
 	if err != nil {
+		release()
 		provider.logger.Error("terraform provider create failed", err)
 		return tfID, fmt.Errorf("terraform provider create failed: %w", err)
 	}
 
-	if err := provider.MarkOperationStarted(&deployment, operationType); err != nil {
+	if err := provider.MarkOperationStarted(ctx, &deployment, operationType); err != nil {
+		release()
 		return tfID, fmt.Errorf("error marking job started: %w", err)
 	}
 
 	go func() {
+		defer release()
+		ctx, cancel := operationContext(ctx, deployment.ID, provider.serviceDefinition.operationTimeout(operationType))
+		defer cancel()
+
 		err := provider.DefaultInvoker().Apply(ctx, newWorkspace)
 		_ = provider.MarkOperationFinished(&deployment, err)
 	}()
@@ -121,11 +134,21 @@ func (provider *TerraformProvider) destroy(ctx context.Context, deploymentID str
 
 	tfWorkspace.Instances[0].Configuration = limitedConfig
 
-	if err := provider.MarkOperationStarted(&deployment, operationType); err != nil {
+	release, err := provider.tfBinContext.Pool.Admit()
+	if err != nil {
+		return err
+	}
+
+	if err := provider.MarkOperationStarted(ctx, &deployment, operationType); err != nil {
+		release()
 		return err
 	}
 
 	go func() {
+		defer release()
+		ctx, cancel := operationContext(ctx, deployment.ID, provider.serviceDefinition.operationTimeout(operationType))
+		defer cancel()
+
 		err = provider.DefaultInvoker().Destroy(ctx, tfWorkspace)
 		_ = provider.MarkOperationFinished(&deployment, err)
 	}()
@@ -153,7 +176,7 @@ func (provider *TerraformProvider) Wait(ctx context.Context, id string) error {
 type DeploymentManagerInterface interface {
 	GetTerraformDeployment(deploymentID string) (storage.TerraformDeployment, error)
 	CreateAndSaveDeployment(deploymentID string, workspace *workspace.TerraformWorkspace) (storage.TerraformDeployment, error)
-	MarkOperationStarted(deployment *storage.TerraformDeployment, operationType string) error
+	MarkOperationStarted(ctx context.Context, deployment *storage.TerraformDeployment, operationType string) error
 	MarkOperationFinished(deployment *storage.TerraformDeployment, err error) error
 	OperationStatus(deploymentID string) (bool, string, error)
 	UpdateWorkspaceHCL(deploymentID string, serviceDefinitionAction TfServiceDefinitionV1Action, templateVars map[string]any) error
@@ -74,17 +74,28 @@ func (provider *TerraformProvider) importCreate(ctx context.Context, vars *varco
 		return tfID, fmt.Errorf("error creating workspace: %w", err)
 	}
 
+	release, err := provider.tfBinContext.Pool.Admit()
+	if err != nil {
+		return tfID, err
+	}
+
 	deployment, err := provider.CreateAndSaveDeployment(tfID, newWorkspace)
 	if err != nil {
+		release()
 		provider.logger.Error("terraform provider create failed", err)
 		return tfID, fmt.Errorf("terraform provider create failed: %w", err)
 	}
 
-	if err := provider.MarkOperationStarted(&deployment, models.ProvisionOperationType); err != nil {
+	if err := provider.MarkOperationStarted(ctx, &deployment, models.ProvisionOperationType); err != nil {
+		release()
 		return tfID, fmt.Errorf("error marking job started: %w", err)
 	}
 
 	go func() {
+		defer release()
+		ctx, cancel := operationContext(ctx, tfID, provider.serviceDefinition.operationTimeout(models.ProvisionOperationType))
+		defer cancel()
+
 		logger := utils.NewLogger("Import").WithData(correlation.ID(ctx))
 		resources := make(map[string]string)
 		for _, resource := range importParams {
@@ -172,9 +183,17 @@ func createTFMainDefinition(workspace *workspace.TerraformWorkspace, mainTf stri
 		mainTf = mainTf[:i]
 	}
 
-	var tf string
-	var parameterVals map[string]string
-	tf, parameterVals, err := workspace.Transformer.ReplaceParametersInTf(workspace.Transformer.AddParametersInTf(workspace.Transformer.CleanTf(mainTf)))
+	tf, err := workspace.Transformer.CleanTf(mainTf)
+	if err != nil {
+		return err
+	}
+
+	tf, err = workspace.Transformer.AddParametersInTf(tf)
+	if err != nil {
+		return err
+	}
+
+	tf, parameterVals, err := workspace.Transformer.ReplaceParametersInTf(tf)
 	if err != nil {
 		return err
 	}
@@ -193,10 +212,28 @@ func createTFMainDefinition(workspace *workspace.TerraformWorkspace, mainTf stri
 }
 
 func (provider *TerraformProvider) terraformPlanToCheckNoResourcesDeleted(invoker invoker.TerraformInvoker, ctx context.Context, workspace *workspace.TerraformWorkspace, logger lager.Logger) error {
-	planOutput, err := invoker.Plan(ctx, workspace)
+	plan, err := invoker.PlanJSON(ctx, workspace)
 	if err != nil {
 		return err
 	}
-	err = CheckTerraformPlanOutput(logger, planOutput)
-	return err
+
+	changes, err := ParseTerraformJSONPlan(plan)
+	if err != nil {
+		return err
+	}
+
+	var destroyed []string
+	for _, change := range changes {
+		if change.Destructive() {
+			destroyed = append(destroyed, change.String())
+		}
+	}
+
+	if len(destroyed) > 0 {
+		logger.Info("cancelling-destroy", lager.Data{"destroyed": destroyed})
+		return fmt.Errorf("terraform plan shows that resources would be destroyed - cancelling subsume")
+	}
+
+	logger.Info("no-destroyed")
+	return nil
 }
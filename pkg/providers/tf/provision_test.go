@@ -3,6 +3,7 @@ package tf_test
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
@@ -40,6 +41,7 @@ var _ = Describe("Provision", func() {
 		}
 		fakeDeploymentManager = &tffakes.FakeDeploymentManagerInterface{}
 		fakeDefaultInvoker = &tffakes.FakeTerraformInvoker{}
+		fakeDefaultInvoker.PlanJSONReturns(`{"format_version": "1.1"}`, nil)
 		deployment = storage.TerraformDeployment{
 			ID: expectedTfID,
 			Workspace: &workspace.TerraformWorkspace{
@@ -87,7 +89,7 @@ var _ = Describe("Provision", func() {
 
 			By("checking that provision is marked as started")
 			Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
-			actualDeployment, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+			_, actualDeployment, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
 			Expect(actualDeployment).To(Equal(&deployment))
 			Expect(actualOperationType).To(Equal("provision"))
 
@@ -95,6 +97,78 @@ var _ = Describe("Provision", func() {
 			Eventually(applyCallCount(fakeDefaultInvoker)).Should(Equal(1))
 			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
 			Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(BeNil())
+
+			By("checking the Terraform output is recorded for the deployment")
+			applyContext, _ := fakeDefaultInvoker.ApplyArgsForCall(0)
+			Expect(executor.DeploymentID(applyContext)).To(Equal(expectedTfID))
+		})
+
+		It("admits the operation to the terraform execution pool until it finishes", func() {
+			pool := executor.NewPool("fake-pool", 1, 0)
+			fakeDeploymentManager.CreateAndSaveDeploymentReturns(deployment, nil)
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			applied := make(chan struct{})
+			fakeDefaultInvoker.ApplyCalls(func(context.Context, workspace.Workspace) error {
+				<-applied
+				return nil
+			})
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{Pool: pool}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			_, err := provider.Provision(context.TODO(), provisionContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("rejecting another operation while the pool is full")
+			_, err = provider.Provision(context.TODO(), provisionContext)
+			Expect(err).To(MatchError(executor.ErrQueueFull))
+			Expect(fakeDeploymentManager.CreateAndSaveDeploymentCallCount()).To(Equal(1))
+
+			By("admitting another operation once the first has finished")
+			close(applied)
+			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
+			Eventually(func() error {
+				_, err := provider.Provision(context.TODO(), provisionContext)
+				return err
+			}).Should(Succeed())
+		})
+
+		It("stops terraform when the operation times out, but not when the request completes", func() {
+			fakeServiceDefinition.ProvisionSettings.Timeouts = map[string]string{"provision": "100ms"}
+			fakeDeploymentManager.CreateAndSaveDeploymentReturns(deployment, nil)
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			fakeDefaultInvoker.ApplyCalls(func(ctx context.Context, _ workspace.Workspace) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			requestContext, completeRequest := context.WithCancel(context.Background())
+			_, err := provider.Provision(requestContext, provisionContext)
+			Expect(err).NotTo(HaveOccurred())
+			completeRequest()
+
+			Eventually(applyCallCount(fakeDefaultInvoker)).Should(Equal(1))
+			Consistently(fakeDeploymentManager.MarkOperationFinishedCallCount, 50*time.Millisecond).Should(BeZero())
+			Eventually(operationWasFinishedWithError(fakeDeploymentManager)).Should(MatchError(context.DeadlineExceeded))
+		})
+
+		It("stops terraform when the operation is cancelled", func() {
+			fakeDeploymentManager.CreateAndSaveDeploymentReturns(deployment, nil)
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			fakeDefaultInvoker.ApplyCalls(func(ctx context.Context, _ workspace.Workspace) error {
+				<-ctx.Done()
+				return context.Cause(ctx)
+			})
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			_, err := provider.Provision(context.TODO(), provisionContext)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(applyCallCount(fakeDefaultInvoker)).Should(Equal(1))
+
+			Expect(tf.CancelOperation("not-running")).To(BeFalse())
+			Expect(tf.CancelOperation(deployment.ID)).To(BeTrue())
+
+			Eventually(operationWasFinishedWithError(fakeDeploymentManager)).Should(MatchError(tf.ErrOperationCancelled))
+			Eventually(func() bool { return tf.CancelOperation(deployment.ID) }).Should(BeFalse())
 		})
 
 		It("fails, when tfID is not provided", func() {
@@ -190,15 +264,16 @@ var _ = Describe("Provision", func() {
 					},
 					ImportParametersToAdd: []tf.ImportParameterMapping{
 						{
-							TfVariable:    "add_this_tf_param",
+							TfVariable:    "tf_import_resource.instance.add_this_tf_param",
 							ParameterName: "add_as_this_param",
 						},
 					},
-					ImportParametersToDelete: []string{"remove_this_param"},
+					ImportParametersToDelete: []string{"tf_import_resource.instance.remove_this_param"},
 					Template:                 template,
 					Templates:                map[string]string{"first": template},
 				},
 			}
+			fakeDefaultInvoker.ShowReturns("resource \"tf_import_resource\" \"instance\" {\n  remove_this_param = \"value\"\n}\n", nil)
 		})
 
 		It("creates a provision deployment", func() {
@@ -221,12 +296,12 @@ var _ = Describe("Provision", func() {
 			Expect(actualWorkspace.Modules[0].Definitions).To(Equal(fakeServiceDefinition.ProvisionSettings.Templates))
 			Expect(actualWorkspace.Instances[0].Configuration).To(Equal(map[string]any{"username": "some-user"}))
 			Expect(actualWorkspace.Transformer.ParameterMappings).To(Equal([]workspace.ParameterMapping{{TfVariable: "map_this_param", ParameterName: "map_to_this_param"}}))
-			Expect(actualWorkspace.Transformer.ParametersToRemove).To(Equal([]string{"remove_this_param"}))
-			Expect(actualWorkspace.Transformer.ParametersToAdd).To(Equal([]workspace.ParameterMapping{{TfVariable: "add_this_tf_param", ParameterName: "add_as_this_param"}}))
+			Expect(actualWorkspace.Transformer.ParametersToRemove).To(Equal([]string{"tf_import_resource.instance.remove_this_param"}))
+			Expect(actualWorkspace.Transformer.ParametersToAdd).To(Equal([]workspace.ParameterMapping{{TfVariable: "tf_import_resource.instance.add_this_tf_param", ParameterName: "add_as_this_param"}}))
 
 			By("checking that provision is marked as started")
 			Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
-			actualDeployment, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+			_, actualDeployment, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
 			Expect(actualDeployment).To(Equal(&deployment))
 			Expect(actualOperationType).To(Equal("provision"))
 
@@ -236,7 +311,7 @@ var _ = Describe("Provision", func() {
 			Expect(resources).To(Equal(map[string]string{"tf_import_input": "some_import_input"}))
 
 			Eventually(showCallCount(fakeDefaultInvoker)).Should(Equal(1))
-			Eventually(planCallCount(fakeDefaultInvoker)).Should(Equal(1))
+			Eventually(planJSONCallCount(fakeDefaultInvoker)).Should(Equal(1))
 			Eventually(applyCallCount(fakeDefaultInvoker)).Should(Equal(1))
 
 			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
@@ -287,11 +362,11 @@ var _ = Describe("Provision", func() {
 					},
 					ImportParametersToAdd: []tf.ImportParameterMapping{
 						{
-							TfVariable:    "add_this_tf_param",
+							TfVariable:    "tf_import_resource.instance.add_this_tf_param",
 							ParameterName: "add_as_this_param",
 						},
 					},
-					ImportParametersToDelete: []string{"remove_this_param"},
+					ImportParametersToDelete: []string{"tf_import_resource.instance.remove_this_param"},
 					Templates:                map[string]string{"first": "invalid template"},
 				},
 			}
@@ -335,7 +410,7 @@ var _ = Describe("Provision", func() {
 				By("checking TF import has been called")
 				Eventually(importCallCount(fakeDefaultInvoker)).Should(Equal(1))
 				Eventually(showCallCount(fakeDefaultInvoker)).Should(Equal(0))
-				Eventually(planCallCount(fakeDefaultInvoker)).Should(Equal(0))
+				Eventually(planJSONCallCount(fakeDefaultInvoker)).Should(Equal(0))
 				Eventually(applyCallCount(fakeDefaultInvoker)).Should(Equal(0))
 				Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
 				Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError("some TF import issue happened"))
@@ -352,7 +427,7 @@ var _ = Describe("Provision", func() {
 
 				By("checking TF show has been called")
 				Eventually(showCallCount(fakeDefaultInvoker)).Should(Equal(1))
-				Eventually(planCallCount(fakeDefaultInvoker)).Should(Equal(0))
+				Eventually(planJSONCallCount(fakeDefaultInvoker)).Should(Equal(0))
 				Eventually(applyCallCount(fakeDefaultInvoker)).Should(Equal(0))
 				Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
 				Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError("some TF show issue happened"))
@@ -361,19 +436,32 @@ var _ = Describe("Provision", func() {
 			It("return the error in last operation, if terraform plan fails", func() {
 				fakeDeploymentManager.CreateAndSaveDeploymentReturns(deployment, nil)
 				fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
-				fakeDefaultInvoker.PlanReturns(executor.ExecutionOutput{}, errors.New("some TF plan issue happened"))
+				fakeDefaultInvoker.PlanJSONReturns("", errors.New("some TF plan issue happened"))
 				provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
 
 				_, err := provider.Provision(context.TODO(), provisionContext)
 				Expect(err).NotTo(HaveOccurred())
 
 				By("checking TF plan has been called")
-				Eventually(planCallCount(fakeDefaultInvoker)).Should(Equal(1))
+				Eventually(planJSONCallCount(fakeDefaultInvoker)).Should(Equal(1))
 				Eventually(applyCallCount(fakeDefaultInvoker)).Should(Equal(0))
 				Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
 				Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError("some TF plan issue happened"))
 			})
 
+			It("return the error in last operation, if terraform plan shows that resources would be destroyed", func() {
+				fakeDeploymentManager.CreateAndSaveDeploymentReturns(deployment, nil)
+				fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+				fakeDefaultInvoker.PlanJSONReturns(`{"resource_changes": [{"address": "aws_db_instance.db", "mode": "managed", "type": "aws_db_instance", "change": {"actions": ["delete", "create"]}}]}`, nil)
+				provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+				_, err := provider.Provision(context.TODO(), provisionContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(operationWasFinishedWithError(fakeDeploymentManager)).Should(MatchError("terraform plan shows that resources would be destroyed - cancelling subsume"))
+				Expect(applyCallCount(fakeDefaultInvoker)()).To(BeZero())
+			})
+
 			It("return the error in last operation, if terraform apply fails", func() {
 				fakeDeploymentManager.CreateAndSaveDeploymentReturns(deployment, nil)
 				fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
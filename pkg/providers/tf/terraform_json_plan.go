@@ -0,0 +1,67 @@
+package tf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PlannedChange is a change to a managed resource in a Terraform plan
+type PlannedChange struct {
+	Address string
+	Type    string
+	// Action is one of "create", "update", "delete" or "replace"
+	Action string
+}
+
+// Destructive is true when the change destroys the resource, including when it is replaced
+func (c PlannedChange) Destructive() bool {
+	return c.Action == "delete" || c.Action == "replace"
+}
+
+func (c PlannedChange) String() string {
+	return fmt.Sprintf("%s (%s)", c.Address, c.Action)
+}
+
+type jsonPlan struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Mode    string `json:"mode"`
+		Type    string `json:"type"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// ParseTerraformJSONPlan reads the changes to managed resources from a plan in the Terraform JSON
+// output format, as printed by "terraform show -json". Resources that do not change are left out.
+func ParseTerraformJSONPlan(plan string) ([]PlannedChange, error) {
+	var receiver jsonPlan
+	if err := json.Unmarshal([]byte(plan), &receiver); err != nil {
+		return nil, fmt.Errorf("error parsing terraform plan: %w", err)
+	}
+
+	var changes []PlannedChange
+	for _, rc := range receiver.ResourceChanges {
+		if rc.Mode != "managed" {
+			continue
+		}
+
+		var action string
+		switch strings.Join(rc.Change.Actions, ",") {
+		case "no-op", "read":
+			continue
+		case "create", "update", "delete":
+			action = rc.Change.Actions[0]
+		case "delete,create", "create,delete":
+			action = "replace"
+		default:
+			return nil, fmt.Errorf("unexpected actions %q for %s in terraform plan", rc.Change.Actions, rc.Address)
+		}
+
+		changes = append(changes, PlannedChange{Address: rc.Address, Type: rc.Type, Action: action})
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,57 @@
+package tf_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
+)
+
+var _ = Describe("ParseTerraformJSONPlan", func() {
+	It("returns the changes to managed resources", func() {
+		changes, err := tf.ParseTerraformJSONPlan(`{
+			"format_version": "1.1",
+			"resource_changes": [
+				{"address": "random_password.password", "mode": "managed", "type": "random_password", "change": {"actions": ["create"]}},
+				{"address": "aws_s3_bucket.bucket", "mode": "managed", "type": "aws_s3_bucket", "change": {"actions": ["update"]}},
+				{"address": "aws_db_instance.db", "mode": "managed", "type": "aws_db_instance", "change": {"actions": ["delete", "create"]}},
+				{"address": "aws_iam_user.user", "mode": "managed", "type": "aws_iam_user", "change": {"actions": ["create", "delete"]}},
+				{"address": "module.m.aws_vpc.vpc", "mode": "managed", "type": "aws_vpc", "change": {"actions": ["delete"]}},
+				{"address": "aws_subnet.subnet", "mode": "managed", "type": "aws_subnet", "change": {"actions": ["no-op"]}},
+				{"address": "data.aws_region.current", "mode": "data", "type": "aws_region", "change": {"actions": ["read"]}}
+			]
+		}`)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal([]tf.PlannedChange{
+			{Address: "random_password.password", Type: "random_password", Action: "create"},
+			{Address: "aws_s3_bucket.bucket", Type: "aws_s3_bucket", Action: "update"},
+			{Address: "aws_db_instance.db", Type: "aws_db_instance", Action: "replace"},
+			{Address: "aws_iam_user.user", Type: "aws_iam_user", Action: "replace"},
+			{Address: "module.m.aws_vpc.vpc", Type: "aws_vpc", Action: "delete"},
+		}))
+		Expect(changes[0].Destructive()).To(BeFalse())
+		Expect(changes[1].Destructive()).To(BeFalse())
+		Expect(changes[2].Destructive()).To(BeTrue())
+		Expect(changes[4].Destructive()).To(BeTrue())
+	})
+
+	It("returns no changes for an empty plan", func() {
+		changes, err := tf.ParseTerraformJSONPlan(`{"format_version": "1.1"}`)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("fails when the plan is not JSON", func() {
+		_, err := tf.ParseTerraformJSONPlan("Plan: 0 to add, 0 to change, 0 to destroy.")
+
+		Expect(err).To(MatchError(ContainSubstring("error parsing terraform plan")))
+	})
+
+	It("fails when the actions are not recognised", func() {
+		_, err := tf.ParseTerraformJSONPlan(`{"resource_changes": [{"address": "a.b", "mode": "managed", "type": "a", "change": {"actions": ["forget"]}}]}`)
+
+		Expect(err).To(MatchError(`unexpected actions ["forget"] for a.b in terraform plan`))
+	})
+})
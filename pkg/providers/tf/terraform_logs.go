@@ -0,0 +1,25 @@
+package tf
+
+import (
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+)
+
+// terraformLogStore records the output of the Terraform commands of the operations on deployments
+type terraformLogStore struct {
+	store broker.ServiceProviderStorage
+}
+
+func (s terraformLogStore) StoreCommandLog(l executor.CommandLog) error {
+	return s.store.StoreTerraformLog(storage.TerraformLog{
+		DeploymentID: l.DeploymentID,
+		Command:      l.Command,
+		StartedAt:    l.StartedAt,
+		FinishedAt:   l.FinishedAt,
+		Error:        l.Error,
+		Stdout:       l.Stdout,
+		Stderr:       l.Stderr,
+		TFLog:        l.TFLog,
+	})
+}
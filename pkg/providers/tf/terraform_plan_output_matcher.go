@@ -1,26 +1,93 @@
 package tf
 
 import (
-	"fmt"
+	"errors"
 	"regexp"
+	"strings"
 
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+)
 
-	"code.cloudfoundry.org/lager/v3"
+var (
+	noChangesMatcher      = regexp.MustCompile(`No changes\.`)
+	planSummaryMatcher    = regexp.MustCompile(`Plan: (\d+) to add, (\d+) to change, (\d+) to destroy\.`)
+	resourceHeaderMatcher = regexp.MustCompile(`^\s*# (\S+) (will be created|will be destroyed|will be updated in-place|will be read during apply|(?:is tainted, so )?must be replaced|has changed|has been deleted)`)
+	attributeMatcher      = regexp.MustCompile(`^(?:[~+-]|-/\+|\+/-)\s+"?([^"\s=]+)"?`)
 )
 
-var planMessageMatcher = regexp.MustCompile(`Plan: \d+ to add, \d+ to change, (\d+) to destroy\.`)
-
-func CheckTerraformPlanOutput(logger lager.Logger, output executor.ExecutionOutput) error {
-	matches := planMessageMatcher.FindStringSubmatch(output.StdOut)
-	switch {
-	case len(matches) == 0: // presumably: "No changes. Infrastructure is up-to-date."
-		logger.Info("no-match")
-	case len(matches) == 2 && matches[1] == "0":
-		logger.Info("no-destroyed")
-	default:
-		logger.Info("cancelling-destroy", lager.Data{"stdout": output.StdOut, "stderr": output.StdErr})
-		return fmt.Errorf("terraform plan shows that resources would be destroyed - cancelling subsume")
+var resourceActions = map[string]string{
+	"will be created":                 "create",
+	"will be destroyed":               "delete",
+	"will be updated in-place":        "update",
+	"will be read during apply":       "read",
+	"must be replaced":                "replace",
+	"is tainted, so must be replaced": "replace",
+	"has changed":                     "changed outside of Terraform",
+	"has been deleted":                "deleted outside of Terraform",
+}
+
+// ParseTerraformPlanOutput reads the resources that would be changed, and for updated
+// or replaced resources the top level attributes that would change, from the output of
+// "terraform plan". The plan is empty when no resource changes are returned. Output that
+// has neither resource changes nor a summary of the plan is an error, so that it is not
+// mistaken for a plan without changes.
+func ParseTerraformPlanOutput(output executor.ExecutionOutput) (empty bool, changes []storage.TerraformResourceChange, err error) {
+	var (
+		current *storage.TerraformResourceChange
+		depth   int
+		heredoc string
+	)
+
+	for _, line := range strings.Split(output.StdOut, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case heredoc != "":
+			if trimmed == heredoc {
+				heredoc = ""
+			}
+			continue
+		case current == nil:
+			if matches := resourceHeaderMatcher.FindStringSubmatch(line); matches != nil {
+				changes = append(changes, storage.TerraformResourceChange{Address: matches[1], Action: resourceActions[matches[2]]})
+				current = &changes[len(changes)-1]
+				depth = 0
+			}
+			continue
+		}
+
+		if depth == 1 && (current.Action == "update" || current.Action == "replace" || current.Action == "changed outside of Terraform") {
+			if matches := attributeMatcher.FindStringSubmatch(trimmed); matches != nil {
+				current.Attributes = append(current.Attributes, matches[1])
+			}
+		}
+
+		switch {
+		case strings.HasSuffix(trimmed, "{"), strings.HasSuffix(trimmed, "["), strings.HasSuffix(trimmed, "("):
+			depth++
+		case strings.Contains(trimmed, "<<-"):
+			heredoc = trimmed[strings.Index(trimmed, "<<-")+3:]
+		case strings.HasPrefix(trimmed, "}"), strings.HasPrefix(trimmed, "]"), strings.HasPrefix(trimmed, ")"):
+			depth--
+			if depth <= 0 {
+				current = nil
+			}
+		}
 	}
-	return nil
+
+	if len(changes) > 0 {
+		return false, changes, nil
+	}
+	if noChangesMatcher.MatchString(output.StdOut) {
+		return true, nil, nil
+	}
+
+	matches := planSummaryMatcher.FindStringSubmatch(output.StdOut)
+	if matches == nil {
+		return false, nil, errUnrecognisedPlanOutput
+	}
+	return matches[1] == "0" && matches[2] == "0" && matches[3] == "0", nil, nil
 }
+
+var errUnrecognisedPlanOutput = errors.New("unrecognised output from terraform plan: no resource changes or plan summary found")
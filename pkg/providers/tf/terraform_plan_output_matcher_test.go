@@ -1,79 +1,116 @@
 package tf
 
 import (
-	"code.cloudfoundry.org/lager/v3"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
-var _ = Context("CheckTerraformPlanOutput", func() {
-	It("returns no errors if nothing is being changed", func() {
-		logger := lager.NewLogger("test")
-		output := CheckTerraformPlanOutput(logger, executor.ExecutionOutput{StdOut: `
-An execution plan has been generated and is shown below.
-Resource actions are indicated with the following symbols:
-
-Terraform will perform the following actions:
+var _ = Context("ParseTerraformPlanOutput", func() {
+	It("reports an empty plan when there are no changes", func() {
+		empty, changes, err := ParseTerraformPlanOutput(executor.ExecutionOutput{StdOut: `
+random_password.password: Refreshing state... [id=none]
 
-Plan: 0 to add, 0 to change, 0 to destroy.
+No changes. Your infrastructure matches the configuration.
 
-Changes to Outputs:
- + test = true
- - another_test = false
+Terraform has compared your real infrastructure against your configuration
+and found no differences, so no changes are needed.
 `})
-		Expect(output).NotTo(HaveOccurred())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(empty).To(BeTrue())
+		Expect(changes).To(BeEmpty())
 	})
 
-	It("returns no errors if resources are being added", func() {
-		logger := lager.NewLogger("test")
-		output := CheckTerraformPlanOutput(logger, executor.ExecutionOutput{StdOut: `
+	It("reports an empty plan for older versions of Terraform", func() {
+		empty, changes, err := ParseTerraformPlanOutput(executor.ExecutionOutput{StdOut: `
 An execution plan has been generated and is shown below.
-Resource actions are indicated with the following symbols:
 
-Terraform will perform the following actions:
-
-Plan: 5 to add, 0 to change, 0 to destroy.
-
-Changes to Outputs:
- + test = true
- - another_test = false
+Plan: 0 to add, 0 to change, 0 to destroy.
 `})
-		Expect(output).NotTo(HaveOccurred())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(empty).To(BeTrue())
+		Expect(changes).To(BeEmpty())
 	})
 
-	It("returns no errors if resources are being changed", func() {
-		logger := lager.NewLogger("test")
-		output := CheckTerraformPlanOutput(logger, executor.ExecutionOutput{StdOut: `
-An execution plan has been generated and is shown below.
-Resource actions are indicated with the following symbols:
+	It("reports the changed resources and attributes", func() {
+		empty, changes, err := ParseTerraformPlanOutput(executor.ExecutionOutput{StdOut: `
+Terraform used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  + create
+  ~ update in-place
+-/+ destroy and then create replacement
 
 Terraform will perform the following actions:
 
-Plan: 0 to add, 6 to change, 0 to destroy.
-
-Changes to Outputs:
- + test = true
- - another_test = false
+  # aws_db_instance.db will be updated in-place
+  ~ resource "aws_db_instance" "db" {
+      ~ allocated_storage = 10 -> 20
+        id                = "db-1"
+      ~ tags              = {
+          - "owner" = "someone" -> null
+        }
+        # (20 unchanged attributes hidden)
+    }
+
+  # random_string.name must be replaced
+-/+ resource "random_string" "name" {
+      ~ id      = "abcdef" -> (known after apply)
+      ~ length  = 6 -> 8 # forces replacement
+      ~ policy  = <<-EOT
+            { "Statement": [] }
+        EOT
+      ~ result  = "abcdef" -> (known after apply)
+    }
+
+  # random_pet.pet will be created
+  + resource "random_pet" "pet" {
+      + id     = (known after apply)
+      + length = 2
+    }
+
+Plan: 2 to add, 1 to change, 1 to destroy.
 `})
-		Expect(output).NotTo(HaveOccurred())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(empty).To(BeFalse())
+		Expect(changes).To(Equal([]storage.TerraformResourceChange{
+			{Address: "aws_db_instance.db", Action: "update", Attributes: []string{"allocated_storage", "tags"}},
+			{Address: "random_string.name", Action: "replace", Attributes: []string{"id", "length", "policy", "result"}},
+			{Address: "random_pet.pet", Action: "create"},
+		}))
 	})
 
-	It("fails if there are any deletes", func() {
-		logger := lager.NewLogger("test")
-		output := CheckTerraformPlanOutput(logger, executor.ExecutionOutput{StdOut: `
-An execution plan has been generated and is shown below.
-Resource actions are indicated with the following symbols:
+	It("reports resources that have changed outside of Terraform", func() {
+		empty, changes, err := ParseTerraformPlanOutput(executor.ExecutionOutput{StdOut: `
+Note: Objects have changed outside of Terraform
 
-Terraform will perform the following actions:
+Terraform detected the following changes made outside of Terraform since the
+last "terraform apply":
 
-Plan: 0 to add, 0 to change, 1 to destroy.
+  # aws_s3_bucket.bucket has changed
+  ~ resource "aws_s3_bucket" "bucket" {
+        id            = "bucket"
+      ~ force_destroy = false -> true
+    }
 
-Changes to Outputs:
- + test = true
- - another_test = false
+  # aws_s3_bucket_policy.policy has been deleted
+  - resource "aws_s3_bucket_policy" "policy" {
+      - bucket = "bucket" -> null
+    }
+
+No changes. Your infrastructure matches the configuration.
+`})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(empty).To(BeFalse())
+		Expect(changes).To(Equal([]storage.TerraformResourceChange{
+			{Address: "aws_s3_bucket.bucket", Action: "changed outside of Terraform", Attributes: []string{"force_destroy"}},
+			{Address: "aws_s3_bucket_policy.policy", Action: "deleted outside of Terraform"},
+		}))
+	})
+	It("fails for output that has neither resource changes nor a plan summary", func() {
+		_, _, err := ParseTerraformPlanOutput(executor.ExecutionOutput{StdOut: `
+Warning: the output of this version of Terraform is not known
 `})
-		Expect(output).To(HaveOccurred())
-		Expect(output).To(MatchError("terraform plan shows that resources would be destroyed - cancelling subsume"))
+		Expect(err).To(MatchError("unrecognised output from terraform plan: no resource changes or plan summary found"))
 	})
 })
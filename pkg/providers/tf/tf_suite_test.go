@@ -36,9 +36,9 @@ func showCallCount(fakeDefaultInvoker *tffakes.FakeTerraformInvoker) func() int
 	}
 }
 
-func planCallCount(fakeDefaultInvoker *tffakes.FakeTerraformInvoker) func() int {
+func planJSONCallCount(fakeDefaultInvoker *tffakes.FakeTerraformInvoker) func() int {
 	return func() int {
-		return fakeDefaultInvoker.PlanCallCount()
+		return fakeDefaultInvoker.PlanJSONCallCount()
 	}
 }
 
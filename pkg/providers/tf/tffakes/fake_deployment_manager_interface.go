@@ -2,6 +2,7 @@
 package tffakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
@@ -62,11 +63,12 @@ type FakeDeploymentManagerInterface struct {
 	markOperationFinishedReturnsOnCall map[int]struct {
 		result1 error
 	}
-	MarkOperationStartedStub        func(*storage.TerraformDeployment, string) error
+	MarkOperationStartedStub        func(context.Context, *storage.TerraformDeployment, string) error
 	markOperationStartedMutex       sync.RWMutex
 	markOperationStartedArgsForCall []struct {
-		arg1 *storage.TerraformDeployment
-		arg2 string
+		arg1 context.Context
+		arg2 *storage.TerraformDeployment
+		arg3 string
 	}
 	markOperationStartedReturns struct {
 		result1 error
@@ -361,19 +363,20 @@ func (fake *FakeDeploymentManagerInterface) MarkOperationFinishedReturnsOnCall(i
 	}{result1}
 }
 
-func (fake *FakeDeploymentManagerInterface) MarkOperationStarted(arg1 *storage.TerraformDeployment, arg2 string) error {
+func (fake *FakeDeploymentManagerInterface) MarkOperationStarted(arg1 context.Context, arg2 *storage.TerraformDeployment, arg3 string) error {
 	fake.markOperationStartedMutex.Lock()
 	ret, specificReturn := fake.markOperationStartedReturnsOnCall[len(fake.markOperationStartedArgsForCall)]
 	fake.markOperationStartedArgsForCall = append(fake.markOperationStartedArgsForCall, struct {
-		arg1 *storage.TerraformDeployment
-		arg2 string
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 *storage.TerraformDeployment
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.MarkOperationStartedStub
 	fakeReturns := fake.markOperationStartedReturns
-	fake.recordInvocation("MarkOperationStarted", []interface{}{arg1, arg2})
+	fake.recordInvocation("MarkOperationStarted", []interface{}{arg1, arg2, arg3})
 	fake.markOperationStartedMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -387,17 +390,17 @@ func (fake *FakeDeploymentManagerInterface) MarkOperationStartedCallCount() int
 	return len(fake.markOperationStartedArgsForCall)
 }
 
-func (fake *FakeDeploymentManagerInterface) MarkOperationStartedCalls(stub func(*storage.TerraformDeployment, string) error) {
+func (fake *FakeDeploymentManagerInterface) MarkOperationStartedCalls(stub func(context.Context, *storage.TerraformDeployment, string) error) {
 	fake.markOperationStartedMutex.Lock()
 	defer fake.markOperationStartedMutex.Unlock()
 	fake.MarkOperationStartedStub = stub
 }
 
-func (fake *FakeDeploymentManagerInterface) MarkOperationStartedArgsForCall(i int) (*storage.TerraformDeployment, string) {
+func (fake *FakeDeploymentManagerInterface) MarkOperationStartedArgsForCall(i int) (context.Context, *storage.TerraformDeployment, string) {
 	fake.markOperationStartedMutex.RLock()
 	defer fake.markOperationStartedMutex.RUnlock()
 	argsForCall := fake.markOperationStartedArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeDeploymentManagerInterface) MarkOperationStartedReturns(result1 error) {
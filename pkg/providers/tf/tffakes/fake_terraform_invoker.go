@@ -23,6 +23,19 @@ type FakeTerraformInvoker struct {
 	applyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ApplyCheckedPlanStub        func(context.Context, workspace.Workspace, func(plan string) error) error
+	applyCheckedPlanMutex       sync.RWMutex
+	applyCheckedPlanArgsForCall []struct {
+		arg1 context.Context
+		arg2 workspace.Workspace
+		arg3 func(plan string) error
+	}
+	applyCheckedPlanReturns struct {
+		result1 error
+	}
+	applyCheckedPlanReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DestroyStub        func(context.Context, workspace.Workspace) error
 	destroyMutex       sync.RWMutex
 	destroyArgsForCall []struct {
@@ -62,6 +75,20 @@ type FakeTerraformInvoker struct {
 		result1 executor.ExecutionOutput
 		result2 error
 	}
+	PlanJSONStub        func(context.Context, workspace.Workspace) (string, error)
+	planJSONMutex       sync.RWMutex
+	planJSONArgsForCall []struct {
+		arg1 context.Context
+		arg2 workspace.Workspace
+	}
+	planJSONReturns struct {
+		result1 string
+		result2 error
+	}
+	planJSONReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	ShowStub        func(context.Context, workspace.Workspace) (string, error)
 	showMutex       sync.RWMutex
 	showArgsForCall []struct {
@@ -142,6 +169,69 @@ func (fake *FakeTerraformInvoker) ApplyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeTerraformInvoker) ApplyCheckedPlan(arg1 context.Context, arg2 workspace.Workspace, arg3 func(plan string) error) error {
+	fake.applyCheckedPlanMutex.Lock()
+	ret, specificReturn := fake.applyCheckedPlanReturnsOnCall[len(fake.applyCheckedPlanArgsForCall)]
+	fake.applyCheckedPlanArgsForCall = append(fake.applyCheckedPlanArgsForCall, struct {
+		arg1 context.Context
+		arg2 workspace.Workspace
+		arg3 func(plan string) error
+	}{arg1, arg2, arg3})
+	stub := fake.ApplyCheckedPlanStub
+	fakeReturns := fake.applyCheckedPlanReturns
+	fake.recordInvocation("ApplyCheckedPlan", []any{arg1, arg2, arg3})
+	fake.applyCheckedPlanMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeTerraformInvoker) ApplyCheckedPlanCallCount() int {
+	fake.applyCheckedPlanMutex.RLock()
+	defer fake.applyCheckedPlanMutex.RUnlock()
+	return len(fake.applyCheckedPlanArgsForCall)
+}
+
+func (fake *FakeTerraformInvoker) ApplyCheckedPlanCalls(stub func(context.Context, workspace.Workspace, func(plan string) error) error) {
+	fake.applyCheckedPlanMutex.Lock()
+	defer fake.applyCheckedPlanMutex.Unlock()
+	fake.ApplyCheckedPlanStub = stub
+}
+
+func (fake *FakeTerraformInvoker) ApplyCheckedPlanArgsForCall(i int) (context.Context, workspace.Workspace, func(plan string) error) {
+	fake.applyCheckedPlanMutex.RLock()
+	defer fake.applyCheckedPlanMutex.RUnlock()
+	argsForCall := fake.applyCheckedPlanArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeTerraformInvoker) ApplyCheckedPlanReturns(result1 error) {
+	fake.applyCheckedPlanMutex.Lock()
+	defer fake.applyCheckedPlanMutex.Unlock()
+	fake.ApplyCheckedPlanStub = nil
+	fake.applyCheckedPlanReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTerraformInvoker) ApplyCheckedPlanReturnsOnCall(i int, result1 error) {
+	fake.applyCheckedPlanMutex.Lock()
+	defer fake.applyCheckedPlanMutex.Unlock()
+	fake.ApplyCheckedPlanStub = nil
+	if fake.applyCheckedPlanReturnsOnCall == nil {
+		fake.applyCheckedPlanReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.applyCheckedPlanReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeTerraformInvoker) Destroy(arg1 context.Context, arg2 workspace.Workspace) error {
 	fake.destroyMutex.Lock()
 	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
@@ -332,6 +422,71 @@ func (fake *FakeTerraformInvoker) PlanReturnsOnCall(i int, result1 executor.Exec
 	}{result1, result2}
 }
 
+func (fake *FakeTerraformInvoker) PlanJSON(arg1 context.Context, arg2 workspace.Workspace) (string, error) {
+	fake.planJSONMutex.Lock()
+	ret, specificReturn := fake.planJSONReturnsOnCall[len(fake.planJSONArgsForCall)]
+	fake.planJSONArgsForCall = append(fake.planJSONArgsForCall, struct {
+		arg1 context.Context
+		arg2 workspace.Workspace
+	}{arg1, arg2})
+	stub := fake.PlanJSONStub
+	fakeReturns := fake.planJSONReturns
+	fake.recordInvocation("PlanJSON", []any{arg1, arg2})
+	fake.planJSONMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONCallCount() int {
+	fake.planJSONMutex.RLock()
+	defer fake.planJSONMutex.RUnlock()
+	return len(fake.planJSONArgsForCall)
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONCalls(stub func(context.Context, workspace.Workspace) (string, error)) {
+	fake.planJSONMutex.Lock()
+	defer fake.planJSONMutex.Unlock()
+	fake.PlanJSONStub = stub
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONArgsForCall(i int) (context.Context, workspace.Workspace) {
+	fake.planJSONMutex.RLock()
+	defer fake.planJSONMutex.RUnlock()
+	argsForCall := fake.planJSONArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONReturns(result1 string, result2 error) {
+	fake.planJSONMutex.Lock()
+	defer fake.planJSONMutex.Unlock()
+	fake.PlanJSONStub = nil
+	fake.planJSONReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONReturnsOnCall(i int, result1 string, result2 error) {
+	fake.planJSONMutex.Lock()
+	defer fake.planJSONMutex.Unlock()
+	fake.PlanJSONStub = nil
+	if fake.planJSONReturnsOnCall == nil {
+		fake.planJSONReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.planJSONReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTerraformInvoker) Show(arg1 context.Context, arg2 workspace.Workspace) (string, error) {
 	fake.showMutex.Lock()
 	ret, specificReturn := fake.showReturnsOnCall[len(fake.showArgsForCall)]
@@ -402,12 +557,16 @@ func (fake *FakeTerraformInvoker) Invocations() map[string][][]any {
 	defer fake.invocationsMutex.RUnlock()
 	fake.applyMutex.RLock()
 	defer fake.applyMutex.RUnlock()
+	fake.applyCheckedPlanMutex.RLock()
+	defer fake.applyCheckedPlanMutex.RUnlock()
 	fake.destroyMutex.RLock()
 	defer fake.destroyMutex.RUnlock()
 	fake.importMutex.RLock()
 	defer fake.importMutex.RUnlock()
 	fake.planMutex.RLock()
 	defer fake.planMutex.RUnlock()
+	fake.planJSONMutex.RLock()
+	defer fake.planJSONMutex.RUnlock()
 	fake.showMutex.RLock()
 	defer fake.showMutex.RUnlock()
 	copiedInvocations := map[string][][]any{}
@@ -0,0 +1,119 @@
+package tf
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/validation"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+)
+
+var (
+	// provisionTimeoutOperations are the operations that can have a timeout in the provision action
+	provisionTimeoutOperations = []string{
+		models.ProvisionOperationType,
+		models.UpdateOperationType,
+		models.UpgradeOperationType,
+		models.DeprovisionOperationType,
+	}
+
+	// bindTimeoutOperations are the operations that can have a timeout in the bind action
+	bindTimeoutOperations = []string{
+		models.BindOperationType,
+		models.UnbindOperationType,
+	}
+)
+
+// operationTimeout returns how long an operation can run before Terraform is stopped.
+// A timeout for the operation type in the provision or bind action takes precedence
+// over the timeout of the service. Zero means that there is no timeout.
+func (tfb *TfServiceDefinitionV1) operationTimeout(operationType string) time.Duration {
+	timeout := tfb.Timeout
+	if t, ok := tfb.ProvisionSettings.Timeouts[operationType]; ok {
+		timeout = t
+	}
+	if t, ok := tfb.BindSettings.Timeouts[operationType]; ok {
+		timeout = t
+	}
+
+	// The timeouts have been validated, so they can be parsed
+	d, _ := time.ParseDuration(timeout)
+	return d
+}
+
+func validateTimeouts(timeouts map[string]string, operationTypes []string) (errs *validation.FieldError) {
+	for operationType, timeout := range timeouts {
+		if !utils.NewStringSet(operationTypes...).Contains(operationType) {
+			errs = errs.Also(validation.ErrInvalidKeyName(operationType, "timeouts", "expected one of the operations of the action"))
+			continue
+		}
+		errs = errs.Also(validateTimeout(timeout, "").ViaFieldKey("timeouts", operationType))
+	}
+
+	return errs
+}
+
+func validateTimeout(timeout, field string) *validation.FieldError {
+	if timeout == "" {
+		return nil
+	}
+
+	if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+		return validation.ErrInvalidValue(timeout, field)
+	}
+
+	return nil
+}
+
+// operationContext returns the context for an operation on a deployment that continues in the
+// background after the request that started it has completed. It keeps the values of the request
+// context, such as the correlation ID, but is only cancelled when the timeout expires or when
+// CancelOperation is called for the deployment. The output of the Terraform commands that run
+// with the context is recorded for the deployment.
+func operationContext(ctx context.Context, deploymentID string, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancelCause := context.WithCancelCause(detachedContext{Context: executor.WithDeploymentID(ctx, deploymentID)})
+	untrack := trackOperation(deploymentID, cancelCause)
+
+	cancelTimeout := func() {}
+	if timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+	}
+
+	return ctx, func() {
+		untrack()
+		cancelTimeout()
+		cancelCause(context.Canceled)
+	}
+}
+
+// suboperationContext returns the context for an operation on a deployment that is part of the
+// operation of the context, such as the upgrade of a binding during the upgrade of its instance.
+// It is cancelled with the operation of the context, or when CancelOperation is called for the
+// deployment. The output of the Terraform commands that run with the context is recorded for the deployment.
+func suboperationContext(ctx context.Context, deploymentID string) (context.Context, context.CancelFunc) {
+	ctx, cancelCause := context.WithCancelCause(executor.WithDeploymentID(ctx, deploymentID))
+	untrack := trackOperation(deploymentID, cancelCause)
+
+	return ctx, func() {
+		untrack()
+		cancelCause(context.Canceled)
+	}
+}
+
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
@@ -12,6 +12,16 @@ import (
 
 // Unbind performs a terraform destroy on the binding.
 func (provider *TerraformProvider) Unbind(ctx context.Context, instanceGUID, bindingID string, vc *varcontext.VarContext) error {
+	tfID, err := provider.UnbindAsync(ctx, instanceGUID, bindingID, vc)
+	if err != nil {
+		return err
+	}
+
+	return provider.Wait(ctx, tfID)
+}
+
+// UnbindAsync starts a terraform destroy on the binding and returns its ID without waiting on the result.
+func (provider *TerraformProvider) UnbindAsync(ctx context.Context, instanceGUID, bindingID string, vc *varcontext.VarContext) (string, error) {
 	tfID := generateTfID(instanceGUID, bindingID)
 	provider.logger.Debug("terraform-unbind", correlation.ID(ctx), lager.Data{
 		"instance": instanceGUID,
@@ -20,12 +30,12 @@ func (provider *TerraformProvider) Unbind(ctx context.Context, instanceGUID, bin
 	})
 
 	if err := provider.UpdateWorkspaceHCL(tfID, provider.serviceDefinition.BindSettings, vc.ToMap()); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := provider.destroy(ctx, tfID, vc.ToMap(), models.UnbindOperationType); err != nil {
-		return err
+		return "", err
 	}
 
-	return provider.Wait(ctx, tfID)
+	return tfID, nil
 }
@@ -97,7 +97,7 @@ var _ = Describe("Unbind", func() {
 
 		By("Checking that deprovision is marked as started")
 		Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
-		actualDeployment, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+		_, actualDeployment, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
 		Expect(actualDeployment).To(Equal(&deployment))
 		Expect(actualOperationType).To(Equal("unbind"))
 
@@ -147,4 +147,32 @@ var _ = Describe("Unbind", func() {
 		err := provider.Unbind(context.TODO(), instanceGUID, bindingGUID, unbindContext)
 		Expect(err).To(MatchError(expectedError))
 	})
+	Describe("UnbindAsync", func() {
+		It("starts the destroy and returns the operation ID without waiting", func() {
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			fakeDeploymentManager.GetTerraformDeploymentReturns(deployment, nil)
+
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{DefaultTfVersion: version.Must(version.NewVersion("1"))}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			operationID, err := provider.UnbindAsync(context.TODO(), instanceGUID, bindingGUID, unbindContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operationID).To(Equal(expectedTFID))
+
+			By("checking that it did not wait for the result")
+			Expect(fakeDeploymentManager.OperationStatusCallCount()).To(BeZero())
+
+			By("checking TF destroy has been called")
+			Eventually(destroyCallCount(fakeDefaultInvoker)).Should(Equal(1))
+			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
+		})
+
+		It("fails, when unable to update the workspace HCL", func() {
+			fakeDeploymentManager.UpdateWorkspaceHCLReturns(fmt.Errorf(expectedError))
+
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			_, err := provider.UnbindAsync(context.TODO(), instanceGUID, bindingGUID, unbindContext)
+			Expect(err).To(MatchError(expectedError))
+		})
+	})
 })
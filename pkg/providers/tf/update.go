@@ -7,6 +7,7 @@ import (
 	"code.cloudfoundry.org/lager/v3"
 
 	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/varcontext"
 	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
 )
@@ -26,29 +27,41 @@ func (provider *TerraformProvider) Update(ctx context.Context, updateContext *va
 		return models.ServiceInstanceDetails{}, err
 	}
 
+	release, err := provider.tfBinContext.Pool.Admit()
+	if err != nil {
+		return models.ServiceInstanceDetails{}, err
+	}
+
 	if err := provider.UpdateWorkspaceHCL(tfID, provider.serviceDefinition.ProvisionSettings, updateContext.ToMap()); err != nil {
+		release()
 		return models.ServiceInstanceDetails{}, err
 	}
 
 	deployment, err := provider.GetTerraformDeployment(tfID)
 	if err != nil {
+		release()
 		return models.ServiceInstanceDetails{}, err
 	}
 
 	workspace := deployment.Workspace
 
-	if err := provider.MarkOperationStarted(&deployment, models.UpdateOperationType); err != nil {
+	if err := provider.MarkOperationStarted(ctx, &deployment, models.UpdateOperationType); err != nil {
+		release()
 		return models.ServiceInstanceDetails{}, err
 	}
 
 	go func() {
+		defer release()
+		ctx, cancel := operationContext(ctx, tfID, provider.serviceDefinition.operationTimeout(models.UpdateOperationType))
+		defer cancel()
+
 		err = workspace.UpdateInstanceConfiguration(updateContext.ToMap())
 		if err != nil {
 			_ = provider.MarkOperationFinished(&deployment, err)
 			return
 		}
 
-		err = provider.DefaultInvoker().Apply(ctx, workspace)
+		err = provider.applyUpdate(ctx, workspace, updateContext)
 		_ = provider.MarkOperationFinished(&deployment, err)
 	}()
 
@@ -57,3 +70,22 @@ func (provider *TerraformProvider) Update(ctx context.Context, updateContext *va
 		OperationType: models.UpdateOperationType,
 	}, nil
 }
+
+// applyUpdate applies the update. For services with an update policy, the plan is checked
+// against the policy, and it is only applied if it complies.
+func (provider *TerraformProvider) applyUpdate(ctx context.Context, workspace workspace.Workspace, updateContext *varcontext.VarContext) error {
+	policy := provider.serviceDefinition.UpdatePolicy
+	if policy == nil {
+		return provider.DefaultInvoker().Apply(ctx, workspace)
+	}
+
+	return provider.DefaultInvoker().ApplyCheckedPlan(ctx, workspace, func(plan string) error {
+		changes, err := ParseTerraformJSONPlan(plan)
+		if err != nil {
+			return err
+		}
+
+		confirmed, _ := updateContext.ToMap()[policy.ConfirmationParameter].(bool)
+		return policy.check(changes, confirmed)
+	})
+}
@@ -0,0 +1,88 @@
+package tf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/validation"
+	"github.com/cloudfoundry/cloud-service-broker/utils"
+)
+
+// TfServiceDefinitionV1UpdatePolicy says which resources an update may destroy or replace.
+// When a service has a policy, updates are planned before they are applied, and an update that
+// would destroy or replace a resource of a type that the policy does not allow fails.
+type TfServiceDefinitionV1UpdatePolicy struct {
+	// AllowDestroy are the resource types that updates can destroy or replace
+	AllowDestroy []string `yaml:"allow_destroy,omitempty"`
+	// ConfirmDestroy are the resource types that updates can destroy or replace
+	// when the user sets the confirmation parameter to true
+	ConfirmDestroy []string `yaml:"confirm_destroy,omitempty"`
+	// ConfirmationParameter is the boolean user input with which users confirm an update.
+	// It only applies to the update request in which it is set.
+	ConfirmationParameter string `yaml:"confirmation_parameter,omitempty"`
+}
+
+// Validate checks the policy against the user inputs of the provision action
+func (p *TfServiceDefinitionV1UpdatePolicy) Validate(userInputs []broker.BrokerVariable) (errs *validation.FieldError) {
+	if p == nil {
+		return nil
+	}
+
+	switch {
+	case p.ConfirmationParameter == "" && len(p.ConfirmDestroy) > 0:
+		errs = errs.Also(validation.ErrMissingField("confirmation_parameter"))
+	case p.ConfirmationParameter != "":
+		var input *broker.BrokerVariable
+		for i := range userInputs {
+			if userInputs[i].FieldName == p.ConfirmationParameter {
+				input = &userInputs[i]
+			}
+		}
+
+		switch {
+		case input == nil:
+			errs = errs.Also(&validation.FieldError{
+				Message: fmt.Sprintf("confirmation parameter %q is not a user input of the provision action", p.ConfirmationParameter),
+				Paths:   []string{"confirmation_parameter"},
+			})
+		case input.Type != broker.JSONTypeBoolean:
+			errs = errs.Also(&validation.FieldError{
+				Message: fmt.Sprintf("confirmation parameter %q must have type boolean", p.ConfirmationParameter),
+				Paths:   []string{"confirmation_parameter"},
+			})
+		}
+	}
+
+	return errs
+}
+
+// check returns an error when the changes would destroy or replace resources that the policy
+// does not allow, taking into account whether the user has confirmed the update
+func (p *TfServiceDefinitionV1UpdatePolicy) check(changes []PlannedChange, confirmed bool) error {
+	allowed := utils.NewStringSet(p.AllowDestroy...)
+	if confirmed {
+		allowed.Add(p.ConfirmDestroy...)
+	}
+	needConfirmation := utils.NewStringSet(p.ConfirmDestroy...)
+
+	var forbidden, unconfirmed []string
+	for _, change := range changes {
+		switch {
+		case !change.Destructive(), allowed.Contains(change.Type):
+		case needConfirmation.Contains(change.Type):
+			unconfirmed = append(unconfirmed, change.String())
+		default:
+			forbidden = append(forbidden, change.String())
+		}
+	}
+
+	switch {
+	case len(forbidden) > 0:
+		return fmt.Errorf("update cancelled because it would destroy or replace resources that cannot be destroyed: %s", strings.Join(forbidden, ", "))
+	case len(unconfirmed) > 0:
+		return fmt.Errorf("update cancelled because it would destroy or replace resources: %s; set the %q parameter to true to confirm the update", strings.Join(unconfirmed, ", "), p.ConfirmationParameter)
+	}
+
+	return nil
+}
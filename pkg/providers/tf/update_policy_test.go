@@ -0,0 +1,77 @@
+package tf
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker"
+)
+
+var _ = Describe("TfServiceDefinitionV1UpdatePolicy", func() {
+	policy := TfServiceDefinitionV1UpdatePolicy{
+		AllowDestroy:          []string{"random_password"},
+		ConfirmDestroy:        []string{"aws_db_instance"},
+		ConfirmationParameter: "confirm",
+	}
+
+	Describe("check", func() {
+		It("allows changes that do not destroy resources", func() {
+			Expect(policy.check([]PlannedChange{
+				{Address: "aws_s3_bucket.b", Type: "aws_s3_bucket", Action: "create"},
+				{Address: "aws_db_instance.db", Type: "aws_db_instance", Action: "update"},
+			}, false)).To(Succeed())
+		})
+
+		It("allows the resource types that can be destroyed", func() {
+			Expect(policy.check([]PlannedChange{{Address: "random_password.p", Type: "random_password", Action: "replace"}}, false)).To(Succeed())
+		})
+
+		It("allows the resource types that need confirmation when the update is confirmed", func() {
+			changes := []PlannedChange{{Address: "aws_db_instance.db", Type: "aws_db_instance", Action: "delete"}}
+
+			Expect(policy.check(changes, false)).To(MatchError(`update cancelled because it would destroy or replace resources: aws_db_instance.db (delete); set the "confirm" parameter to true to confirm the update`))
+			Expect(policy.check(changes, true)).To(Succeed())
+		})
+
+		It("does not allow other resource types to be destroyed, even when the update is confirmed", func() {
+			changes := []PlannedChange{
+				{Address: "aws_db_instance.db", Type: "aws_db_instance", Action: "delete"},
+				{Address: "aws_s3_bucket.b", Type: "aws_s3_bucket", Action: "replace"},
+			}
+
+			Expect(policy.check(changes, true)).To(MatchError("update cancelled because it would destroy or replace resources that cannot be destroyed: aws_s3_bucket.b (replace)"))
+		})
+	})
+
+	Describe("Validate", func() {
+		userInputs := []broker.BrokerVariable{
+			{FieldName: "confirm", Type: broker.JSONTypeBoolean},
+			{FieldName: "name", Type: broker.JSONTypeString},
+		}
+
+		It("accepts a boolean user input as the confirmation parameter", func() {
+			Expect(policy.Validate(userInputs)).To(BeNil())
+		})
+
+		It("accepts a policy without a confirmation parameter when nothing needs confirmation", func() {
+			p := TfServiceDefinitionV1UpdatePolicy{AllowDestroy: []string{"random_password"}}
+
+			Expect(p.Validate(nil)).To(BeNil())
+		})
+
+		It("requires a confirmation parameter when resources need confirmation", func() {
+			p := TfServiceDefinitionV1UpdatePolicy{ConfirmDestroy: []string{"aws_db_instance"}}
+
+			Expect(p.Validate(userInputs)).To(MatchError("missing field(s): confirmation_parameter"))
+		})
+
+		It("requires the confirmation parameter to be a boolean user input", func() {
+			p := policy
+			p.ConfirmationParameter = "name"
+			Expect(p.Validate(userInputs)).To(MatchError(`confirmation parameter "name" must have type boolean: confirmation_parameter`))
+
+			p.ConfirmationParameter = "missing"
+			Expect(p.Validate(userInputs)).To(MatchError(`confirmation parameter "missing" is not a user input of the provision action: confirmation_parameter`))
+		})
+	})
+})
@@ -103,6 +103,64 @@ var _ = Describe("Update", func() {
 		Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError(genericError))
 	})
 
+	When("the service has an update policy", func() {
+		const plan = `{"resource_changes": [
+			{"address": "random_password.password", "mode": "managed", "type": "random_password", "change": {"actions": ["delete", "create"]}},
+			{"address": "aws_db_instance.db", "mode": "managed", "type": "aws_db_instance", "change": {"actions": ["update"]}}
+		]}`
+
+		var provider *tf.TerraformProvider
+
+		BeforeEach(func() {
+			deployment.Workspace = fakeWorkspace
+			fakeDeploymentManager.GetTerraformDeploymentReturns(deployment, nil)
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			fakeDefaultInvoker.ApplyCheckedPlanCalls(func(_ context.Context, _ workspace.Workspace, check func(string) error) error {
+				return check(plan)
+			})
+
+			serviceDefinition := fakeServiceDefinition
+			serviceDefinition.UpdatePolicy = &tf.TfServiceDefinitionV1UpdatePolicy{
+				ConfirmDestroy:        []string{"random_password"},
+				ConfirmationParameter: "confirm",
+			}
+			provider = tf.NewTerraformProvider(executor.TFBinariesContext{DefaultTfVersion: newVersion("1.1")}, fakeInvokerBuilder, fakeLogger, serviceDefinition, fakeDeploymentManager)
+		})
+
+		It("does not apply an update that would replace a resource without confirmation", func() {
+			_, err := provider.Update(context.TODO(), varContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(operationWasFinishedWithError(fakeDeploymentManager)).Should(MatchError(`update cancelled because it would destroy or replace resources: random_password.password (replace); set the "confirm" parameter to true to confirm the update`))
+			Expect(fakeDefaultInvoker.ApplyCheckedPlanCallCount()).To(Equal(1))
+			Expect(fakeDefaultInvoker.ApplyCallCount()).To(BeZero())
+		})
+
+		It("applies an update that would replace a resource when it is confirmed", func() {
+			varContext, err := varcontext.Builder().MergeMap(templateVars).MergeMap(map[string]any{"confirm": true}).Build()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = provider.Update(context.TODO(), varContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
+			Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(BeNil())
+			Expect(fakeDefaultInvoker.ApplyCheckedPlanCallCount()).To(Equal(1))
+			_, actualWorkspace, _ := fakeDefaultInvoker.ApplyCheckedPlanArgsForCall(0)
+			Expect(actualWorkspace).To(Equal(fakeWorkspace))
+		})
+
+		It("returns the error in last operation, if terraform plan fails", func() {
+			fakeDefaultInvoker.ApplyCheckedPlanReturns(genericError)
+
+			_, err := provider.Update(context.TODO(), varContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(operationWasFinishedWithError(fakeDeploymentManager)).Should(MatchError(genericError))
+			Expect(fakeDefaultInvoker.ApplyCallCount()).To(BeZero())
+		})
+	})
+
 	When("update called on subsume plan", func() {
 		It("fails", func() {
 			varContext, err := varcontext.Builder().MergeMap(map[string]any{"tf_id": "567c6af0-d68a-11ec-a5b6-367dda7ea869", "var": "value", "subsume": true}).Build()
@@ -3,12 +3,16 @@ package tf
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/hashicorp/go-version"
 
 	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/varcontext"
 	"github.com/cloudfoundry/cloud-service-broker/utils/correlation"
@@ -24,16 +28,25 @@ func (provider *TerraformProvider) UpgradeInstance(ctx context.Context, instance
 		return nil, err
 	}
 
+	// The upgrade of the bindings is admitted separately by UpgradeBindings, once the instance is upgraded
+	release, err := provider.tfBinContext.Pool.Admit()
+	if err != nil {
+		return nil, err
+	}
+
 	if err := provider.UpdateWorkspaceHCL(instanceDeploymentID, provider.serviceDefinition.ProvisionSettings, instanceContext.ToMap()); err != nil {
+		release()
 		return nil, err
 	}
 
 	instanceDeployment, err := provider.GetTerraformDeployment(instanceDeploymentID)
 	if err != nil {
+		release()
 		return nil, err
 	}
 
-	if err := provider.MarkOperationStarted(&instanceDeployment, models.UpgradeOperationType); err != nil {
+	if err := provider.MarkOperationStarted(ctx, &instanceDeployment, models.UpgradeOperationType); err != nil {
+		release()
 		return nil, err
 	}
 
@@ -41,16 +54,19 @@ func (provider *TerraformProvider) UpgradeInstance(ctx context.Context, instance
 	finished.Add(1)
 
 	go func() {
-		err = provider.performTerraformUpgrade(ctx, instanceDeployment.Workspace)
+		// The waiter is released whatever the outcome, and checks whether the operation is still in progress
+		defer finished.Done()
+		defer release()
+		ctx, cancel := operationContext(ctx, instanceDeploymentID, provider.serviceDefinition.operationTimeout(models.UpgradeOperationType))
+		defer cancel()
+
+		err := provider.performTerraformUpgrade(ctx, instanceDeployment.Workspace)
+		if err == nil {
+			err = provider.MarkOperationStarted(ctx, &instanceDeployment, models.UpgradeOperationType)
+		}
 		if err != nil {
 			_ = provider.MarkOperationFinished(&instanceDeployment, err)
-			return
-		}
-
-		if err := provider.MarkOperationStarted(&instanceDeployment, models.UpgradeOperationType); err != nil {
-			panic(err)
 		}
-		finished.Done()
 	}()
 
 	return &finished, nil
@@ -66,38 +82,171 @@ func (provider *TerraformProvider) UpgradeBindings(ctx context.Context, instance
 		return err
 	}
 
+	release, err := provider.tfBinContext.Pool.Admit()
+	if err != nil {
+		return err
+	}
+
 	instanceDeployment, err := provider.GetTerraformDeployment(instanceDeploymentID)
 	if err != nil {
+		release()
 		return err
 	}
 
+	bindingDeployments, err := provider.GetBindingDeployments(instanceDeploymentID)
+	if err != nil {
+		release()
+		return err
+	}
+
+	// The bindings that are already on the default Terraform version are skipped before their
+	// workspaces are updated, so that a failed upgrade can be triggered again without repeating
+	// the bindings that succeeded
+	upgrades := make(map[string]struct{})
+	for _, bindingDeployment := range bindingDeployments {
+		if provider.bindingNeedsUpgrade(ctx, bindingDeployment) {
+			upgrades[bindingDeployment.ID] = struct{}{}
+		}
+	}
+
 	for _, bindingContext := range bindingContexts {
 		bindingDeploymentID := bindingContext.GetString("tf_id")
+		if _, ok := upgrades[bindingDeploymentID]; !ok {
+			continue
+		}
 		if err := provider.UpdateWorkspaceHCL(bindingDeploymentID, provider.serviceDefinition.BindSettings, bindingContext.ToMap()); err != nil {
+			release()
 			return err
 		}
 	}
-	bindingDeployments, err := provider.GetBindingDeployments(instanceDeploymentID)
+
+	// The binding deployments are read again to get the updated workspaces
+	bindingDeployments, err = provider.GetBindingDeployments(instanceDeploymentID)
 	if err != nil {
+		release()
 		return err
 	}
 
-	go func() {
-		for i := range bindingDeployments {
-			err = provider.performTerraformUpgrade(ctx, bindingDeployments[i].Workspace)
-			_ = provider.MarkOperationFinished(&bindingDeployments[i], err)
-			if err != nil {
-				_ = provider.MarkOperationFinished(&instanceDeployment, err)
-				return
-			}
+	var upgradeDeployments []storage.TerraformDeployment
+	for _, bindingDeployment := range bindingDeployments {
+		if _, ok := upgrades[bindingDeployment.ID]; ok {
+			upgradeDeployments = append(upgradeDeployments, bindingDeployment)
 		}
+	}
+
+	go func() {
+		defer release()
 
-		_ = provider.MarkOperationFinished(&instanceDeployment, err)
+		// The bindings are upgraded after the request has completed,
+		// with their own timeout for the upgrade operation
+		ctx, cancel := operationContext(ctx, instanceDeploymentID, provider.serviceDefinition.operationTimeout(models.UpgradeOperationType))
+		defer cancel()
+
+		_ = provider.MarkOperationFinished(&instanceDeployment, provider.upgradeBindingDeployments(ctx, upgradeDeployments))
 	}()
 
 	return nil
 }
 
+// upgradeBindingDeployments upgrades the bindings in parallel, up to the configured limit. The
+// outcome of each upgrade is recorded in the binding deployment. A failed upgrade does not stop the others.
+func (provider *TerraformProvider) upgradeBindingDeployments(ctx context.Context, bindingDeployments []storage.TerraformDeployment) error {
+	parallelism := provider.tfBinContext.MaxParallelBindingUpgrades
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		failures []bindingUpgradeFailure
+		slots    = make(chan struct{}, parallelism)
+	)
+
+	for i := range bindingDeployments {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(bindingDeployment *storage.TerraformDeployment) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			if err := provider.upgradeBindingDeployment(ctx, bindingDeployment); err != nil {
+				lock.Lock()
+				defer lock.Unlock()
+				failures = append(failures, bindingUpgradeFailure{deploymentID: bindingDeployment.ID, err: err})
+			}
+		}(&bindingDeployments[i])
+	}
+	wg.Wait()
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	// The failures are sorted so that the summary does not depend on the order the upgrades finished
+	sort.Slice(failures, func(i, j int) bool { return failures[i].deploymentID < failures[j].deploymentID })
+	return &bindingUpgradeError{failures: failures, total: len(bindingDeployments)}
+}
+
+// bindingNeedsUpgrade reports whether a binding is not on the default Terraform version yet. A binding
+// whose version cannot be read is upgraded, so that the upgrade reports the error for the binding.
+func (provider *TerraformProvider) bindingNeedsUpgrade(ctx context.Context, bindingDeployment storage.TerraformDeployment) bool {
+	currentTfVersion, err := bindingDeployment.Workspace.StateTFVersion()
+	if err != nil || currentTfVersion.LessThan(provider.tfBinContext.DefaultTfVersion) {
+		return true
+	}
+
+	provider.logger.Debug("skip-binding-upgrade", correlation.ID(ctx), lager.Data{
+		"deployment": bindingDeployment.ID,
+		"version":    currentTfVersion.String(),
+	})
+	return false
+}
+
+func (provider *TerraformProvider) upgradeBindingDeployment(ctx context.Context, bindingDeployment *storage.TerraformDeployment) error {
+	if err := provider.MarkOperationStarted(ctx, bindingDeployment, models.UpgradeOperationType); err != nil {
+		return err
+	}
+
+	// The context is for the operation on the instance, so the binding has its own operation
+	// that can be cancelled, and that the output is recorded for
+	ctx, cancel := suboperationContext(ctx, bindingDeployment.ID)
+	defer cancel()
+
+	err := provider.performTerraformUpgrade(ctx, bindingDeployment.Workspace)
+	_ = provider.MarkOperationFinished(bindingDeployment, err)
+	return err
+}
+
+type bindingUpgradeFailure struct {
+	deploymentID string
+	err          error
+}
+
+// bindingUpgradeError summarises the bindings of an instance that failed to upgrade
+type bindingUpgradeError struct {
+	failures []bindingUpgradeFailure
+	total    int
+}
+
+func (e *bindingUpgradeError) Error() string {
+	summaries := make([]string, 0, len(e.failures))
+	for _, f := range e.failures {
+		summaries = append(summaries, fmt.Sprintf("%s: %s", f.deploymentID, f.err))
+	}
+	return fmt.Sprintf("failed to upgrade %d of %d bindings: %s", len(e.failures), e.total, strings.Join(summaries, "; "))
+}
+
+func (e *bindingUpgradeError) Unwrap() []error {
+	errs := make([]error, 0, len(e.failures))
+	for _, f := range e.failures {
+		errs = append(errs, f.err)
+	}
+	return errs
+}
+
 func (provider *TerraformProvider) performTerraformUpgrade(ctx context.Context, workspace workspace.Workspace) error {
 	currentTfVersion, err := workspace.StateTFVersion()
 	if err != nil {
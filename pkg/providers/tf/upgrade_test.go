@@ -3,8 +3,11 @@ package tf_test
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"github.com/cloudfoundry/cloud-service-broker/dbservice/models"
 	"github.com/cloudfoundry/cloud-service-broker/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/pkg/broker/brokerfakes"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/tffakes"
@@ -115,6 +118,53 @@ var _ = Describe("Upgrade", func() {
 				Expect(actualAction).To(Equal(provisionAction))
 				Expect(actualUpgradeContext).To(Equal(instanceTemplateVars))
 			})
+
+			It("fails the upgrade, and releases the waiter, if the upgraded instance cannot be stored", func() {
+				tfBinContext := executor.TFBinariesContext{
+					DefaultTfVersion: newVersion("4.0.0"),
+					TfUpgradePath: []*version.Version{
+						newVersion("2.0.0"),
+						newVersion("3.0.0"),
+						newVersion("4.0.0"),
+					},
+				}
+				fakeDeploymentManager.MarkOperationStartedReturnsOnCall(1, genericError)
+
+				provider := tf.NewTerraformProvider(tfBinContext, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+				finished, err := provider.UpgradeInstance(context.TODO(), instanceVarContext)
+				Expect(err).NotTo(HaveOccurred())
+				finished.Wait()
+
+				Expect(fakeDeploymentManager.MarkOperationFinishedCallCount()).To(Equal(1))
+				Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError(genericError))
+			})
+
+			It("records one operation log entry for the upgrade, which is finished", func() {
+				tfBinContext := executor.TFBinariesContext{
+					DefaultTfVersion: newVersion("4.0.0"),
+					TfUpgradePath: []*version.Version{
+						newVersion("2.0.0"),
+						newVersion("3.0.0"),
+						newVersion("4.0.0"),
+					},
+				}
+				fakeStore := &brokerfakes.FakeServiceProviderStorage{}
+				fakeStore.GetTerraformDeploymentReturns(instanceTFDeployment, nil)
+
+				provider := tf.NewTerraformProvider(tfBinContext, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, tf.NewDeploymentManager(fakeStore))
+				finished, err := provider.UpgradeInstance(context.TODO(), instanceVarContext)
+				Expect(err).NotTo(HaveOccurred())
+				finished.Wait()
+
+				Expect(provider.UpgradeBindings(context.TODO(), instanceVarContext, nil)).To(Succeed())
+				Eventually(fakeStore.FinishOperationLogCallCount).Should(Equal(1))
+
+				Expect(fakeStore.StartOperationLogCallCount()).To(Equal(1))
+				Expect(fakeStore.StartOperationLogArgsForCall(0).DeploymentID).To(Equal(instanceDeploymentID))
+				actualDeploymentID, actualState, _, _ := fakeStore.FinishOperationLogArgsForCall(0)
+				Expect(actualDeploymentID).To(Equal(instanceDeploymentID))
+				Expect(actualState).To(Equal(tf.Succeeded))
+			})
 		})
 
 		It("fails if the instance TF version is < 0.12.0", func() {
@@ -253,24 +303,43 @@ var _ = Describe("Upgrade", func() {
 		var (
 			firstBindingID          = "firstBindingID"
 			secondBindingID         = "secondBindingID"
-			firstBindingWorkspace   = &workspacefakes.FakeWorkspace{}
-			firstBindingDeployment  = storage.TerraformDeployment{ID: instanceDeploymentID + firstBindingID, Workspace: firstBindingWorkspace}
-			secondBindingWorkspace  = &workspacefakes.FakeWorkspace{}
-			secondBindingDeployment = storage.TerraformDeployment{ID: instanceDeploymentID + secondBindingID, Workspace: secondBindingWorkspace}
+			firstBindingWorkspace   *workspacefakes.FakeWorkspace
+			firstBindingDeployment  storage.TerraformDeployment
+			secondBindingWorkspace  *workspacefakes.FakeWorkspace
+			secondBindingDeployment storage.TerraformDeployment
 
-			bindingDeployments = []storage.TerraformDeployment{
-				firstBindingDeployment,
-				secondBindingDeployment,
-			}
 			firstBindingVars    = map[string]any{"tf_id": instanceDeploymentID + firstBindingID, "first-binding-var": "first-binding-value"}
 			secondBindingVars   = map[string]any{"tf_id": instanceDeploymentID + secondBindingID, "second-binding-var": "second-binding-value"}
 			bindingsVarContexts []*varcontext.VarContext
 
+			fakeInvoker1 *tffakes.FakeTerraformInvoker
+			fakeInvoker2 *tffakes.FakeTerraformInvoker
+			fakeInvoker3 *tffakes.FakeTerraformInvoker
+			fakeInvoker4 *tffakes.FakeTerraformInvoker
+
+			tfBinContext executor.TFBinariesContext
+		)
+
+		BeforeEach(func() {
+			firstBindingWorkspace = &workspacefakes.FakeWorkspace{}
+			firstBindingDeployment = storage.TerraformDeployment{ID: instanceDeploymentID + firstBindingID, Workspace: firstBindingWorkspace}
+			secondBindingWorkspace = &workspacefakes.FakeWorkspace{}
+			secondBindingDeployment = storage.TerraformDeployment{ID: instanceDeploymentID + secondBindingID, Workspace: secondBindingWorkspace}
+
 			fakeInvoker1 = &tffakes.FakeTerraformInvoker{}
 			fakeInvoker2 = &tffakes.FakeTerraformInvoker{}
 			fakeInvoker3 = &tffakes.FakeTerraformInvoker{}
 			fakeInvoker4 = &tffakes.FakeTerraformInvoker{}
-		)
+
+			tfBinContext = executor.TFBinariesContext{
+				DefaultTfVersion: newVersion("4.0.0"),
+				TfUpgradePath: []*version.Version{
+					newVersion("2.0.0"),
+					newVersion("3.0.0"),
+					newVersion("4.0.0"),
+				},
+			}
+		})
 
 		BeforeEach(func() {
 			instanceTFDeployment.Workspace = fakeWorkspace
@@ -279,7 +348,7 @@ var _ = Describe("Upgrade", func() {
 			fakeWorkspace.StateTFVersionReturns(newVersion("2.0.0"), nil)
 			fakeWorkspace.ModuleInstancesReturns([]workspace.ModuleInstance{{ModuleName: "instance-moduleName"}})
 
-			fakeDeploymentManager.GetBindingDeploymentsReturns(bindingDeployments, nil)
+			fakeDeploymentManager.GetBindingDeploymentsReturns([]storage.TerraformDeployment{firstBindingDeployment, secondBindingDeployment}, nil)
 			fakeInvokerBuilder.VersionedTerraformInvokerReturnsOnCall(0, fakeInvoker1)
 			fakeInvokerBuilder.VersionedTerraformInvokerReturnsOnCall(1, fakeInvoker2)
 			fakeInvokerBuilder.VersionedTerraformInvokerReturnsOnCall(2, fakeInvoker3)
@@ -303,14 +372,6 @@ var _ = Describe("Upgrade", func() {
 		})
 
 		It("upgrades all the available bindings to latest version", func() {
-			tfBinContext := executor.TFBinariesContext{
-				DefaultTfVersion: newVersion("4.0.0"),
-				TfUpgradePath: []*version.Version{
-					newVersion("2.0.0"),
-					newVersion("3.0.0"),
-					newVersion("4.0.0"),
-				},
-			}
 			instanceTemplateVars = map[string]any{"tf_id": instanceDeploymentID, "var": "value"}
 
 			provider := tf.NewTerraformProvider(tfBinContext, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
@@ -321,6 +382,10 @@ var _ = Describe("Upgrade", func() {
 			Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(BeNil())
 
 			By("checking the binding operations were also updated")
+			Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(2))
+			_, startedFirstBindingDeployment, startedOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+			Expect(startedFirstBindingDeployment.ID).To(Equal(firstBindingDeployment.ID))
+			Expect(startedOperationType).To(Equal(models.UpgradeOperationType))
 			Expect(fakeDeploymentManager.MarkOperationFinishedCallCount()).To(Equal(3))
 			actualFirstBindingDeployment, _ := fakeDeploymentManager.MarkOperationFinishedArgsForCall(0)
 			Expect(actualFirstBindingDeployment.ID).To(Equal(firstBindingDeployment.ID))
@@ -342,6 +407,12 @@ var _ = Describe("Upgrade", func() {
 			Expect(fakeInvoker4.ApplyCallCount()).To(Equal(1))
 			Expect(getWorkspace(fakeInvoker4, 0)).To(Equal(secondBindingWorkspace))
 
+			By("checking the Terraform output is recorded for the bindings")
+			applyContext, _ := fakeInvoker1.ApplyArgsForCall(0)
+			Expect(executor.DeploymentID(applyContext)).To(Equal(firstBindingDeployment.ID))
+			applyContext, _ = fakeInvoker3.ApplyArgsForCall(0)
+			Expect(executor.DeploymentID(applyContext)).To(Equal(secondBindingDeployment.ID))
+
 			Expect(fakeInvokerBuilder.VersionedTerraformInvokerCallCount()).To(Equal(4))
 			Expect(fakeInvokerBuilder.VersionedTerraformInvokerArgsForCall(0)).To(Equal(newVersion("3.0.0")))
 			Expect(fakeInvokerBuilder.VersionedTerraformInvokerArgsForCall(1)).To(Equal(newVersion("4.0.0")))
@@ -362,6 +433,106 @@ var _ = Describe("Upgrade", func() {
 			Expect(actualSecondBindingUpgradeContext).To(Equal(secondBindingVars))
 		})
 
+		It("admits the upgrade of the bindings to the terraform execution pool until it finishes", func() {
+			tfBinContext.Pool = executor.NewPool("fake-pool", 1, 0)
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			applied := make(chan struct{})
+			fakeInvoker1.ApplyCalls(func(context.Context, workspace.Workspace) error {
+				<-applied
+				return nil
+			})
+
+			provider := tf.NewTerraformProvider(tfBinContext, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+			Expect(provider.UpgradeBindings(context.TODO(), instanceVarContext, bindingsVarContexts)).To(Succeed())
+
+			By("rejecting another operation while the pool is full")
+			_, err := provider.UpgradeInstance(context.TODO(), instanceVarContext)
+			Expect(err).To(MatchError(executor.ErrQueueFull))
+
+			By("admitting another operation once the upgrade of the bindings has finished")
+			close(applied)
+			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(instanceTFDeployment))
+			Eventually(func() error {
+				_, err := provider.UpgradeInstance(context.TODO(), instanceVarContext)
+				return err
+			}).Should(Succeed())
+		})
+
+		It("skips the bindings that are already on the latest version", func() {
+			secondBindingWorkspace.StateTFVersionReturns(newVersion("4.0.0"), nil)
+
+			provider := tf.NewTerraformProvider(tfBinContext, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+			err := provider.UpgradeBindings(context.TODO(), instanceVarContext, bindingsVarContexts)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(instanceTFDeployment))
+			Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(BeNil())
+
+			By("checking only the first binding was upgraded")
+			Expect(fakeInvokerBuilder.VersionedTerraformInvokerCallCount()).To(Equal(2))
+			Expect(getWorkspace(fakeInvoker1, 0)).To(Equal(firstBindingWorkspace))
+			Expect(getWorkspace(fakeInvoker2, 0)).To(Equal(firstBindingWorkspace))
+
+			By("checking the workspace of the skipped binding was not updated")
+			Expect(fakeDeploymentManager.UpdateWorkspaceHCLCallCount()).To(Equal(1))
+			actualDeploymentID, _, _ := fakeDeploymentManager.UpdateWorkspaceHCLArgsForCall(0)
+			Expect(actualDeploymentID).To(Equal(firstBindingDeployment.ID))
+
+			By("checking the operation of the skipped binding was not changed")
+			Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
+			Expect(fakeDeploymentManager.MarkOperationFinishedCallCount()).To(Equal(2))
+			actualBindingDeployment, _ := fakeDeploymentManager.MarkOperationFinishedArgsForCall(0)
+			Expect(actualBindingDeployment.ID).To(Equal(firstBindingDeployment.ID))
+		})
+
+		It("stops the upgrade of a binding when its operation is cancelled", func() {
+			fakeInvoker1.ApplyCalls(func(ctx context.Context, _ workspace.Workspace) error {
+				<-ctx.Done()
+				return context.Cause(ctx)
+			})
+
+			provider := tf.NewTerraformProvider(tfBinContext, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+			err := provider.UpgradeBindings(context.TODO(), instanceVarContext, bindingsVarContexts)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() bool { return tf.CancelOperation(firstBindingDeployment.ID) }).Should(BeTrue())
+
+			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(instanceTFDeployment))
+			Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError(tf.ErrOperationCancelled))
+
+			By("checking the other binding was upgraded")
+			actualFirstBindingDeployment, err := fakeDeploymentManager.MarkOperationFinishedArgsForCall(0)
+			Expect(actualFirstBindingDeployment.ID).To(Equal(firstBindingDeployment.ID))
+			Expect(err).To(MatchError(tf.ErrOperationCancelled))
+			actualSecondBindingDeployment, err := fakeDeploymentManager.MarkOperationFinishedArgsForCall(1)
+			Expect(actualSecondBindingDeployment.ID).To(Equal(secondBindingDeployment.ID))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("upgrades the bindings in parallel up to the limit", func() {
+			tfBinContext.MaxParallelBindingUpgrades = 2
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			fakeInvokerBuilder.VersionedTerraformInvokerReturnsOnCall(0, fakeDefaultInvoker)
+			fakeInvokerBuilder.VersionedTerraformInvokerReturnsOnCall(1, fakeDefaultInvoker)
+
+			var running sync.WaitGroup
+			running.Add(2)
+			fakeDefaultInvoker.ApplyStub = func(context.Context, workspace.Workspace) error {
+				running.Done()
+				running.Wait()
+				return nil
+			}
+			tfBinContext.TfUpgradePath = []*version.Version{newVersion("4.0.0")}
+
+			provider := tf.NewTerraformProvider(tfBinContext, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+			err := provider.UpgradeBindings(context.TODO(), instanceVarContext, bindingsVarContexts)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(instanceTFDeployment))
+			Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(BeNil())
+			Expect(fakeDefaultInvoker.ApplyCallCount()).To(Equal(2))
+		})
+
 		When("an apply fails for a binding", func() {
 			var fakeInvokerBind *tffakes.FakeTerraformInvoker
 
@@ -371,15 +542,7 @@ var _ = Describe("Upgrade", func() {
 				fakeInvokerBuilder.VersionedTerraformInvokerReturnsOnCall(0, fakeInvokerBind)
 			})
 
-			It("fails the upgrade, if an apply fails for a binding", func() {
-				tfBinContext := executor.TFBinariesContext{
-					DefaultTfVersion: newVersion("4.0.0"),
-					TfUpgradePath: []*version.Version{
-						newVersion("2.0.0"),
-						newVersion("3.0.0"),
-						newVersion("4.0.0"),
-					},
-				}
+			It("upgrades the other bindings and summarises the failure in the instance operation", func() {
 				provider := tf.NewTerraformProvider(tfBinContext, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
 
 				err := provider.UpgradeBindings(context.TODO(), instanceVarContext, bindingsVarContexts)
@@ -387,12 +550,22 @@ var _ = Describe("Upgrade", func() {
 
 				Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(instanceTFDeployment))
 				Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError(genericError))
+				Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError("failed to upgrade 1 of 2 bindings: tf:instance-ID:firstBindingID: genericError"))
 
-				By("checking the binding operations were also finished with error")
-				Expect(fakeDeploymentManager.MarkOperationFinishedCallCount()).To(Equal(2))
+				By("checking the binding operations were finished with their own result")
+				Expect(fakeDeploymentManager.MarkOperationFinishedCallCount()).To(Equal(3))
 				actualFirstBindingDeployment, err := fakeDeploymentManager.MarkOperationFinishedArgsForCall(0)
 				Expect(actualFirstBindingDeployment.ID).To(Equal(firstBindingDeployment.ID))
 				Expect(err).To(MatchError(genericError))
+				actualSecondBindingDeployment, err := fakeDeploymentManager.MarkOperationFinishedArgsForCall(1)
+				Expect(actualSecondBindingDeployment.ID).To(Equal(secondBindingDeployment.ID))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the second binding was upgraded")
+				Expect(fakeInvoker2.ApplyCallCount()).To(Equal(1))
+				Expect(getWorkspace(fakeInvoker2, 0)).To(Equal(secondBindingWorkspace))
+				Expect(fakeInvoker3.ApplyCallCount()).To(Equal(1))
+				Expect(getWorkspace(fakeInvoker3, 0)).To(Equal(secondBindingWorkspace))
 			})
 		})
 	})
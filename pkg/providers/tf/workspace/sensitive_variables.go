@@ -0,0 +1,128 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	variableIdentifier  = "variable"
+	outputIdentifier    = "output"
+	sensitiveIdentifier = "sensitive"
+)
+
+// MarkSensitiveVariables declares the named variables of a Terraform definition as sensitive,
+// so that Terraform does not show their values in its output
+func MarkSensitiveVariables(input string, names []string) (string, error) {
+	if len(names) == 0 {
+		return input, nil
+	}
+
+	file, diags := hclwrite.ParseConfig([]byte(input), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return "", diags
+	}
+
+	sensitive := make(map[string]struct{})
+	for _, n := range names {
+		sensitive[n] = struct{}{}
+	}
+
+	changed := false
+	for _, block := range file.Body().Blocks() {
+		if block.Type() != variableIdentifier || len(block.Labels()) != 1 {
+			continue
+		}
+		if _, ok := sensitive[block.Labels()[0]]; ok {
+			setSensitive(block.Body())
+			changed = true
+		}
+	}
+
+	if !changed {
+		return input, nil
+	}
+	return string(hclwrite.Format(file.Bytes())), nil
+}
+
+func setSensitive(body *hclwrite.Body) {
+	if body.GetAttribute(sensitiveIdentifier) == nil {
+		// An attribute can only be added on a new line, so a block that is written on one line is split
+		tokens := body.BuildTokens(nil)
+		if len(tokens) == 0 || tokens[0].Type != hclsyntax.TokenNewline {
+			body.Clear()
+			body.AppendNewline()
+			body.AppendUnstructuredTokens(tokens)
+			if len(tokens) > 0 && tokens[len(tokens)-1].Type != hclsyntax.TokenNewline {
+				body.AppendNewline()
+			}
+		}
+	}
+
+	body.SetAttributeValue(sensitiveIdentifier, cty.True)
+}
+
+// CheckSensitiveOutputs returns an error for the outputs of a Terraform definition that use the
+// named variables without being declared sensitive, as Terraform does not apply such a definition
+// once the variables are sensitive. Only outputs that refer to the variables directly are found.
+func CheckSensitiveOutputs(input string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	file, diags := hclsyntax.ParseConfig([]byte(input), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return diags
+	}
+
+	sensitive := make(map[string]struct{})
+	for _, n := range names {
+		sensitive[n] = struct{}{}
+	}
+
+	var outputs []string
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != outputIdentifier || len(block.Labels) != 1 || isSensitive(block.Body) {
+			continue
+		}
+		if usesVariables(block.Body, sensitive) {
+			outputs = append(outputs, fmt.Sprintf("%q", block.Labels[0]))
+		}
+	}
+
+	if len(outputs) > 0 {
+		return fmt.Errorf("outputs %s use sensitive variables, so they must be declared sensitive", strings.Join(outputs, ", "))
+	}
+	return nil
+}
+
+func isSensitive(body *hclsyntax.Body) bool {
+	attr, ok := body.Attributes[sensitiveIdentifier]
+	if !ok {
+		return false
+	}
+
+	value, diags := attr.Expr.Value(nil)
+	return !diags.HasErrors() && value.Type() == cty.Bool && value.IsKnown() && !value.IsNull() && value.True()
+}
+
+func usesVariables(body *hclsyntax.Body, names map[string]struct{}) bool {
+	for _, attr := range body.Attributes {
+		for _, traversal := range attr.Expr.Variables() {
+			if traversal.RootName() != "var" || len(traversal) < 2 {
+				continue
+			}
+			if step, ok := traversal[1].(hcl.TraverseAttr); ok {
+				if _, ok := names[step.Name]; ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package workspace_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/workspace"
+)
+
+var _ = Describe("MarkSensitiveVariables", func() {
+	It("declares the named variables as sensitive", func() {
+		output, err := workspace.MarkSensitiveVariables(`
+variable "admin_password" { type = string }
+variable "name" { type = string }
+variable "api_key" {
+  type      = string
+  sensitive = false
+}
+
+resource "random_string" "admin_password" {
+  length = 10
+}`, []string{"admin_password", "api_key"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output).To(Equal(`
+variable "admin_password" {
+  type      = string
+  sensitive = true
+}
+variable "name" { type = string }
+variable "api_key" {
+  type      = string
+  sensitive = true
+}
+
+resource "random_string" "admin_password" {
+  length = 10
+}`))
+	})
+
+	It("does not change a definition without the named variables", func() {
+		const input = `variable "name" {type=string}`
+
+		Expect(workspace.MarkSensitiveVariables(input, []string{"admin_password"})).To(Equal(input))
+		Expect(workspace.MarkSensitiveVariables(input, nil)).To(Equal(input))
+	})
+
+	It("fails for invalid HCL", func() {
+		_, err := workspace.MarkSensitiveVariables(`variable "name" {`, []string{"name"})
+		Expect(err).To(MatchError(ContainSubstring("Unclosed configuration block")))
+	})
+})
+
+var _ = Describe("CheckSensitiveOutputs", func() {
+	It("accepts outputs that use the named variables when they are declared sensitive", func() {
+		Expect(workspace.CheckSensitiveOutputs(`
+output "password" {
+  value     = var.admin_password
+  sensitive = true
+}
+output "name" { value = var.name }`, []string{"admin_password"})).To(Succeed())
+	})
+
+	It("fails for outputs that use the named variables without being declared sensitive", func() {
+		err := workspace.CheckSensitiveOutputs(`
+output "password" { value = var.admin_password }
+output "uri" {
+  value     = "mysql://admin:${var.admin_password}@${var.name}"
+  sensitive = false
+}
+output "name" { value = var.name }`, []string{"admin_password"})
+		Expect(err).To(MatchError(`outputs "password", "uri" use sensitive variables, so they must be declared sensitive`))
+	})
+
+	It("does not check a definition without named variables", func() {
+		Expect(workspace.CheckSensitiveOutputs(`output "password" {`, nil)).To(Succeed())
+	})
+
+	It("fails for invalid HCL", func() {
+		err := workspace.CheckSensitiveOutputs(`output "password" {`, []string{"admin_password"})
+		Expect(err).To(MatchError(ContainSubstring("Unclosed configuration block")))
+	})
+})
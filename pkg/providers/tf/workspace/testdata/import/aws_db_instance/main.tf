@@ -0,0 +1,42 @@
+# aws_db_instance.db_instance:
+resource "aws_db_instance" "db_instance" {
+  password                              = random_password.password.result
+  count                                 = 1
+  allocated_storage                     = var.storage_gb
+  auto_minor_version_upgrade            = true
+  availability_zone                     = "us-west-2b"
+  backup_retention_period               = 7
+  backup_window                         = "08:44-09:14"
+  ca_cert_identifier                    = "rds-ca-2019"
+  copy_tags_to_snapshot                 = false
+  db_name                               = "vsbdb"
+  db_subnet_group_name                  = "csb-postgresql-subsume-p-sn"
+  delete_automated_backups              = true
+  deletion_protection                   = false
+  enabled_cloudwatch_logs_exports       = []
+  engine                                = "postgres"
+  engine_version                        = "14.7"
+  iam_database_authentication_enabled   = false
+  identifier                            = "csb-postgresql-subsume"
+  instance_class                        = var.instance_class
+  iops                                  = 0
+  license_model                         = "postgresql-license"
+  maintenance_window                    = "sun:10:06-sun:10:36"
+  max_allocated_storage                 = 0
+  monitoring_interval                   = 0
+  multi_az                              = false
+  network_type                          = "IPV4"
+  option_group_name                     = "default:postgres-14"
+  parameter_group_name                  = "csb-postgresql-subsume-pg"
+  performance_insights_enabled          = false
+  performance_insights_retention_period = 0
+  port                                  = 5432
+  publicly_accessible                   = false
+  skip_final_snapshot                   = true
+  storage_encrypted                     = false
+  storage_throughput                    = 0
+  storage_type                          = "gp2"
+  tags                                  = var.labels
+  username                              = "admin_user"
+  vpc_security_group_ids                = [aws_security_group.rds_sg.id]
+}
@@ -0,0 +1,57 @@
+# aws_db_instance.db_instance:
+resource "aws_db_instance" "db_instance" {
+    address                               = "csb-postgresql-subsume.c1xvnzhqfqhd.us-west-2.rds.amazonaws.com"
+    allocated_storage                     = 20
+    arn                                   = "arn:aws:rds:us-west-2:123456789012:db:csb-postgresql-subsume"
+    auto_minor_version_upgrade            = true
+    availability_zone                     = "us-west-2b"
+    backup_retention_period               = 7
+    backup_window                         = "08:44-09:14"
+    ca_cert_identifier                    = "rds-ca-2019"
+    copy_tags_to_snapshot                 = false
+    db_name                               = "vsbdb"
+    db_subnet_group_name                  = "csb-postgresql-subsume-p-sn"
+    delete_automated_backups              = true
+    deletion_protection                   = false
+    enabled_cloudwatch_logs_exports       = []
+    endpoint                              = "csb-postgresql-subsume.c1xvnzhqfqhd.us-west-2.rds.amazonaws.com:5432"
+    engine                                = "postgres"
+    engine_version                        = "14.7"
+    engine_version_actual                 = "14.7"
+    hosted_zone_id                        = "Z1PVIF0B656C1W"
+    iam_database_authentication_enabled   = false
+    id                                    = "csb-postgresql-subsume"
+    identifier                            = "csb-postgresql-subsume"
+    instance_class                        = "db.t3.micro"
+    iops                                  = 0
+    license_model                         = "postgresql-license"
+    maintenance_window                    = "sun:10:06-sun:10:36"
+    max_allocated_storage                 = 0
+    monitoring_interval                   = 0
+    multi_az                              = false
+    network_type                          = "IPV4"
+    option_group_name                     = "default:postgres-14"
+    parameter_group_name                  = "csb-postgresql-subsume-pg"
+    password                              = (sensitive value)
+    performance_insights_enabled          = false
+    performance_insights_retention_period = 0
+    port                                  = 5432
+    publicly_accessible                   = false
+    replicas                              = []
+    resource_id                           = "db-N6GZ3XWTQCJ3HCK7AMTQUMKFKY"
+    skip_final_snapshot                   = true
+    status                                = "available"
+    storage_encrypted                     = false
+    storage_throughput                    = 0
+    storage_type                          = "gp2"
+    tags                                  = {
+        "pcf-instance-id" = "2d1dc3f5-8a0b-4a5b-b0a8-5d6a3f5cbd4e"
+    }
+    tags_all                              = {
+        "pcf-instance-id" = "2d1dc3f5-8a0b-4a5b-b0a8-5d6a3f5cbd4e"
+    }
+    username                              = "admin_user"
+    vpc_security_group_ids                = [
+        "sg-0a1b2c3d4e5f67890",
+    ]
+}
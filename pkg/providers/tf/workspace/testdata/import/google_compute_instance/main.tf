@@ -0,0 +1,51 @@
+# google_compute_instance.vm:
+resource "google_compute_instance" "vm" {
+  can_ip_forward          = false
+  deletion_protection     = false
+  enable_display          = false
+  guest_accelerator       = []
+  labels                  = {}
+  machine_type            = var.machine_type
+  metadata                = {}
+  metadata_startup_script = var.startup_script
+  name                    = "csb-vm"
+  project                 = "fake-project"
+  tags                    = var.network_tags
+  zone                    = "us-central1-a"
+
+  boot_disk {
+    auto_delete = true
+    device_name = "persistent-disk-0"
+    mode        = "READ_WRITE"
+
+    initialize_params {
+      image  = "https://www.googleapis.com/compute/v1/projects/debian-cloud/global/images/debian-11-bullseye-v20230615"
+      labels = {}
+      size   = local.disk_size
+      type   = "pd-standard"
+    }
+  }
+
+  network_interface {
+    name               = "nic0"
+    network            = "https://www.googleapis.com/compute/v1/projects/fake-project/global/networks/default"
+    network_ip         = "10.128.0.2"
+    stack_type         = "IPV4_ONLY"
+    subnetwork         = "https://www.googleapis.com/compute/v1/projects/fake-project/regions/us-central1/subnetworks/default"
+    subnetwork_project = "fake-project"
+  }
+
+  scheduling {
+    automatic_restart   = true
+    min_node_cpus       = 0
+    on_host_maintenance = "MIGRATE"
+    preemptible         = false
+    provisioning_model  = "STANDARD"
+  }
+
+  shielded_instance_config {
+    enable_integrity_monitoring = true
+    enable_secure_boot          = false
+    enable_vtpm                 = true
+  }
+}
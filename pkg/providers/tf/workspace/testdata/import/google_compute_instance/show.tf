@@ -0,0 +1,73 @@
+# google_compute_instance.vm:
+resource "google_compute_instance" "vm" {
+    can_ip_forward          = false
+    cpu_platform            = "Intel Broadwell"
+    current_status          = "RUNNING"
+    deletion_protection     = false
+    enable_display          = false
+    guest_accelerator       = []
+    id                      = "projects/fake-project/zones/us-central1-a/instances/csb-vm"
+    instance_id             = "5236411409237483052"
+    label_fingerprint       = "42WmSpB8rSM="
+    labels                  = {}
+    machine_type            = "e2-medium"
+    metadata                = {}
+    metadata_fingerprint    = "y0HDeOTmS2U="
+    metadata_startup_script = <<-EOT
+        #!/bin/bash
+        apt-get update
+        apt-get install -y nginx
+        echo "server { listen 8080; }" > /etc/nginx/conf.d/app.conf
+    EOT
+    name                    = "csb-vm"
+    project                 = "fake-project"
+    self_link               = "https://www.googleapis.com/compute/v1/projects/fake-project/zones/us-central1-a/instances/csb-vm"
+    tags                    = [
+        "http-server",
+        "https-server",
+    ]
+    tags_fingerprint        = "6smc4R4d39I="
+    zone                    = "us-central1-a"
+
+    boot_disk {
+        auto_delete = true
+        device_name = "persistent-disk-0"
+        mode        = "READ_WRITE"
+        source      = "https://www.googleapis.com/compute/v1/projects/fake-project/zones/us-central1-a/disks/csb-vm"
+
+        initialize_params {
+            image  = "https://www.googleapis.com/compute/v1/projects/debian-cloud/global/images/debian-11-bullseye-v20230615"
+            labels = {}
+            size   = 10
+            type   = "pd-standard"
+        }
+    }
+
+    network_interface {
+        name               = "nic0"
+        network            = "https://www.googleapis.com/compute/v1/projects/fake-project/global/networks/default"
+        network_ip         = "10.128.0.2"
+        stack_type         = "IPV4_ONLY"
+        subnetwork         = "https://www.googleapis.com/compute/v1/projects/fake-project/regions/us-central1/subnetworks/default"
+        subnetwork_project = "fake-project"
+
+        access_config {
+            nat_ip       = "35.222.11.7"
+            network_tier = "PREMIUM"
+        }
+    }
+
+    scheduling {
+        automatic_restart   = true
+        min_node_cpus       = 0
+        on_host_maintenance = "MIGRATE"
+        preemptible         = false
+        provisioning_model  = "STANDARD"
+    }
+
+    shielded_instance_config {
+        enable_integrity_monitoring = true
+        enable_secure_boot          = false
+        enable_vtpm                 = true
+    }
+}
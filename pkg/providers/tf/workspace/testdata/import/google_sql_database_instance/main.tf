@@ -0,0 +1,45 @@
+# google_sql_database_instance.instance:
+resource "google_sql_database_instance" "instance" {
+  database_version    = var.database_version
+  deletion_protection = false
+  name                = "csb-mysql-subsume"
+  project             = "fake-project"
+  region              = "us-central1"
+
+  settings {
+    activation_policy     = "ALWAYS"
+    availability_type     = "ZONAL"
+    connector_enforcement = "NOT_REQUIRED"
+    disk_autoresize       = true
+    disk_autoresize_limit = 0
+    disk_size             = 10
+    disk_type             = "PD_SSD"
+    pricing_plan          = "PER_USE"
+    tier                  = var.tier
+    user_labels           = var.labels
+
+    backup_configuration {
+      binary_log_enabled             = true
+      enabled                        = true
+      start_time                     = "04:00"
+      transaction_log_retention_days = 7
+    }
+
+    ip_configuration {
+      ipv4_enabled = true
+      require_ssl  = false
+
+      authorized_networks {
+        name  = "office"
+        value = var.authorized_network
+      }
+    }
+
+    location_preference {
+      zone = "us-central1-a"
+    }
+  }
+
+  timeouts {}
+}
+
@@ -0,0 +1,81 @@
+# google_sql_database_instance.instance:
+resource "google_sql_database_instance" "instance" {
+    connection_name               = "fake-project:us-central1:csb-mysql-subsume"
+    database_version              = "MYSQL_8_0"
+    deletion_protection           = true
+    first_ip_address              = "34.70.22.141"
+    id                            = "csb-mysql-subsume"
+    ip_address                    = [
+        {
+            ip_address     = "34.70.22.141"
+            time_to_retire = ""
+            type           = "PRIMARY"
+        },
+    ]
+    maintenance_version           = "MYSQL_8_0_31.R20230516.01_00"
+    name                          = "csb-mysql-subsume"
+    project                       = "fake-project"
+    public_ip_address             = "34.70.22.141"
+    region                        = "us-central1"
+    self_link                     = "https://sqladmin.googleapis.com/sql/v1beta4/projects/fake-project/instances/csb-mysql-subsume"
+    server_ca_cert                = [
+        {
+            cert             = <<-EOT
+                -----BEGIN CERTIFICATE-----
+                MIIDfzCCAmegAwIBAgIBADANBgkqhkiG9w0BAQsFADB3MS0wKwYDVQQuEyQ2YjFl
+                -----END CERTIFICATE-----
+            EOT
+            common_name      = "C=US,O=Google\\, Inc,CN=Google Cloud SQL Server CA,dnQualifier=6b1e"
+            create_time      = "2023-06-14T10:13:45.251Z"
+            expiration_time  = "2033-06-11T10:14:45.251Z"
+            sha1_fingerprint = "d2b1e8c2ad0fa50cc37a6ac6b2f1b9c2d0e0f6a1"
+        },
+    ]
+    service_account_email_address = "p123456789-abcdef@gcp-sa-cloud-sql.iam.gserviceaccount.com"
+
+    settings {
+        activation_policy           = "ALWAYS"
+        availability_type           = "ZONAL"
+        connector_enforcement       = "NOT_REQUIRED"
+        disk_autoresize             = true
+        disk_autoresize_limit       = 0
+        disk_size                   = 10
+        disk_type                   = "PD_SSD"
+        pricing_plan                = "PER_USE"
+        tier                        = "db-n1-standard-2"
+        user_labels                 = {
+            "pcf-instance-id" = "8f0d2a1c-3cb6-4f3d-9b3c-1c1d5cb4f0e6"
+            "team"            = "data"
+        }
+        version                     = 12
+
+        backup_configuration {
+            binary_log_enabled             = true
+            enabled                        = true
+            start_time                     = "04:00"
+            transaction_log_retention_days = 7
+
+            backup_retention_settings {
+                retained_backups = 7
+                retention_unit   = "COUNT"
+            }
+        }
+
+        ip_configuration {
+            ipv4_enabled = true
+            require_ssl  = false
+
+            authorized_networks {
+                name  = "office"
+                value = "203.0.113.0/24"
+            }
+        }
+
+        location_preference {
+            zone = "us-central1-a"
+        }
+    }
+
+    timeouts {}
+}
+
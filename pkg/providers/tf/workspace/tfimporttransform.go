@@ -15,11 +15,16 @@
 package workspace
 
 import (
-	"bufio"
-	"bytes"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 // ParameterMapping mapping for tf variable to service parameter
@@ -28,132 +33,385 @@ type ParameterMapping struct {
 	ParameterName string `yaml:"parameter_name"`
 }
 
-// TfTransformer terraform transformation
+// TfTransformer turns the output of `terraform show` for imported resources into a
+// Terraform definition that can be managed by the broker.
+//
+// Resources, attributes and nested blocks are addressed by paths such as
+// "azurerm_mssql_database.azure_sql_db.threat_detection_policy.state". The TfVariable of a
+// parameter mapping is either such a path, or the name of an attribute, which matches the
+// attribute wherever it appears.
 type TfTransformer struct {
 	ParameterMappings  []ParameterMapping `json:"parameter_mappings"`
 	ParametersToRemove []string           `json:"parameters_to_remove"`
 	ParametersToAdd    []ParameterMapping `json:"parameters_to_add"`
 }
 
-func braceCount(str string, count int) int {
-	return count + strings.Count(str, "{") - strings.Count(str, "}")
-}
+// CleanTf removes the attributes and blocks in ttf.ParametersToRemove from the tf. It fails when
+// the path of an entry does not match a resource or block, as the entry would have no effect.
+// An attribute or block that is missing from the resource is not an error, as `terraform show`
+// only lists the attributes and blocks that have values.
+func (ttf *TfTransformer) CleanTf(tf string) (string, error) {
+	file, err := parseTf(tf)
+	if err != nil {
+		return "", err
+	}
+
+	toRemove := make(map[string]struct{})
+	for _, p := range ttf.ParametersToRemove {
+		toRemove[p] = struct{}{}
+	}
+
+	paths := make(map[string]struct{})
+	walkResources(file, func(path string, body *hclwrite.Body) {
+		paths[path] = struct{}{}
+		for name := range body.Attributes() {
+			if _, ok := toRemove[path+"."+name]; ok {
+				body.RemoveAttribute(name)
+			}
+		}
+		for _, block := range body.Blocks() {
+			if _, ok := toRemove[path+"."+block.Type()]; ok {
+				body.RemoveBlock(block)
+			}
+		}
+	})
+
+	if err := checkPathsMatched("import_parameters_to_delete", ttf.ParametersToRemove, paths); err != nil {
+		return "", err
+	}
 
-// CleanTf removes ttf.ParametersToRemove from tf string
-func (ttf *TfTransformer) CleanTf(tf string) string {
-	resource := regexp.MustCompile(`resource "(.*)" "(.*)"`)
-	value := regexp.MustCompile(`^\s*(\S*)\s*=\s*(.*)\s*$`)
-	block := regexp.MustCompile(`^\s*(\S*)\s*{\s*$`)
-	depth := 0
-	blockStack := make([]string, 64)
-	scanner := bufio.NewScanner(strings.NewReader(tf))
-	buffer := bytes.Buffer{}
-	skipBlockDepth := 0
+	return formatTf(file), nil
+}
 
-	for scanner.Scan() {
-		skipLine := !(skipBlockDepth == 0 || depth < skipBlockDepth)
-		line := scanner.Text()
-		depth = braceCount(line, depth)
+// AddParametersInTf sets the attributes in ttf.ParametersToAdd in their resources, or in the
+// blocks nested in them, in the tf. Attributes that the bodies do not have yet are added at the
+// start of the bodies, where meta-arguments such as count belong. It fails when the path of an
+// entry does not match a resource or block.
+func (ttf *TfTransformer) AddParametersInTf(tf string) (string, error) {
+	file, err := parseTf(tf)
+	if err != nil {
+		return "", err
+	}
 
-		if depth < skipBlockDepth {
-			skipBlockDepth = 0
+	tokens := make([]hclwrite.Tokens, len(ttf.ParametersToAdd))
+	names := make([]string, len(ttf.ParametersToAdd))
+	for i, addition := range ttf.ParametersToAdd {
+		tokens[i], err = expressionTokens(addition.ParameterName)
+		if err != nil {
+			return "", fmt.Errorf("invalid value for parameter %q: %w", addition.TfVariable, err)
 		}
+		names[i] = addition.TfVariable
+	}
 
-		if res := resource.FindStringSubmatch(line); res != nil {
-			blockStack[depth] = fmt.Sprintf("%s.%s", res[1], res[2])
-		} else if res = value.FindStringSubmatch(line); res != nil {
-			for _, removal := range ttf.ParametersToRemove {
-				if fmt.Sprintf("%s.%s", blockStack[depth], res[1]) == removal {
-					skipLine = true
-					break
-				}
+	type pathBody struct {
+		path string
+		body *hclwrite.Body
+	}
+	var bodies []pathBody
+	paths := make(map[string]struct{})
+	walkResources(file, func(path string, body *hclwrite.Body) {
+		bodies = append(bodies, pathBody{path: path, body: body})
+		paths[path] = struct{}{}
+	})
+
+	if err := checkPathsMatched("import_parameters_to_add", names, paths); err != nil {
+		return "", err
+	}
+
+	// Nested blocks are changed before the bodies that enclose them, because the enclosing
+	// bodies are rebuilt from the tokens of their nested blocks when attributes are added
+	for i := len(bodies) - 1; i >= 0; i-- {
+		body := bodies[i].body
+		var additions []string
+		values := make(map[string]hclwrite.Tokens)
+		for j, addition := range ttf.ParametersToAdd {
+			path, name := splitPath(addition.TfVariable)
+			if path != bodies[i].path {
+				continue
 			}
-		} else if res := block.FindStringSubmatch(line); res != nil {
-			blockStack[depth] = fmt.Sprintf("%s.%s", blockStack[depth-1], res[1])
-			for _, removal := range ttf.ParametersToRemove {
-				if blockStack[depth] == removal {
-					skipBlockDepth = depth
-					skipLine = true
-					break
-				}
+
+			if body.GetAttribute(name) != nil {
+				body.SetAttributeRaw(name, tokens[j])
+			} else {
+				additions = append(additions, name)
+				values[name] = tokens[j]
 			}
 		}
-		if !skipLine {
-			buffer.WriteString(fmt.Sprintf("%s\n", line))
+		if len(additions) == 0 {
+			continue
+		}
+
+		existing := body.BuildTokens(nil)
+		if len(existing) > 0 && existing[0].Type == hclsyntax.TokenNewline {
+			existing = existing[1:]
+		}
+
+		body.Clear()
+		body.AppendNewline()
+		for _, name := range additions {
+			body.SetAttributeRaw(name, values[name])
+		}
+		body.AppendUnstructuredTokens(existing)
+	}
+
+	return formatTf(file), nil
+}
+
+// checkPathsMatched returns an error that lists the entries of a field whose paths do not
+// match the path of a resource or block, such as entries with a mistyped resource name
+func checkPathsMatched(field string, entries []string, paths map[string]struct{}) error {
+	var unmatched []string
+	for _, entry := range entries {
+		path, _ := splitPath(entry)
+		if _, ok := paths[path]; !ok {
+			unmatched = append(unmatched, entry)
 		}
 	}
-	return buffer.String()
+
+	if len(unmatched) > 0 {
+		return fmt.Errorf("the %s entries do not match any imported resource or block: %s", field, strings.Join(unmatched, ", "))
+	}
+	return nil
+}
+
+// ReplaceParametersInTf replaces the values of the attributes in ttf.ParameterMappings with
+// their parameters in the tf. It returns the values that were replaced for the parameters that
+// are variables or locals, so that the imported resources keep them. As it is the last step of
+// the transformation, it fails when sensitive values have not been removed or replaced.
+func (ttf *TfTransformer) ReplaceParametersInTf(tf string) (string, map[string]any, error) {
+	file, err := parseTf(tf)
+	if err != nil {
+		return "", nil, err
+	}
+
+	parameterValues, err := ttf.captureParameterValues(file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := ttf.replaceParameters(file); err != nil {
+		return "", nil, err
+	}
+
+	if err := checkNoSensitiveValues(file); err != nil {
+		return "", nil, err
+	}
+
+	return formatTf(file), parameterValues, nil
 }
 
-func (ttf *TfTransformer) captureParameterValues(tf string) (map[string]string, error) {
-	parameterValues := make(map[string]string)
+// captureParameterValues returns the first value of each mapped attribute, keyed by the name of
+// the variable or local that it is mapped to. Attributes with sensitive values are not captured.
+func (ttf *TfTransformer) captureParameterValues(file *hclwrite.File) (map[string]any, error) {
+	parameterValues := make(map[string]any)
 
 	for _, mapping := range ttf.ParameterMappings {
-		reBlock := regexp.MustCompile(fmt.Sprintf(`(?m)%s[\s]*=[\s]+({[\s\S.]*?})`, mapping.TfVariable))
-		reSimple := regexp.MustCompile(fmt.Sprintf(`(?m)%s[\s]*=[\s"]*(.*[^"\s])`, mapping.TfVariable))
-
-		if res := reBlock.FindAllStringSubmatch(tf, -1); len(res) > 0 {
-			//parameterValues[mapping.ParameterName] = res[0][1]
-		} else if res := reSimple.FindAllStringSubmatch(tf, -1); len(res) > 0 {
-			if strings.HasPrefix(mapping.ParameterName, "var.") {
-				parameterValues[strings.TrimPrefix(mapping.ParameterName, "var.")] = res[0][1]
-			} else if strings.HasPrefix(mapping.ParameterName, "local.") {
-				parameterValues[strings.TrimPrefix(mapping.ParameterName, "local.")] = res[0][1]
+		name, ok := parameterVariableName(mapping.ParameterName)
+		if !ok {
+			continue
+		}
+
+		var (
+			value    any
+			found    bool
+			captured bool
+			valueErr error
+		)
+		walkResources(file, func(path string, body *hclwrite.Body) {
+			if found {
+				return
+			}
+			for attrName, attr := range body.Attributes() {
+				if matchesMapping(mapping.TfVariable, path, attrName) {
+					// Sensitive values are not known, so the parameter keeps the value it was given
+					if !hasSensitiveValue(attr) {
+						value, valueErr = attributeValue(attr)
+						captured = true
+					}
+					found = true
+					return
+				}
 			}
+		})
+
+		if valueErr != nil {
+			return nil, fmt.Errorf("cannot read value of %q: %w", mapping.TfVariable, valueErr)
+		}
+		if captured {
+			parameterValues[name] = value
 		}
 	}
 
 	return parameterValues, nil
 }
 
-func (ttf *TfTransformer) replaceParameters(tf string) string {
+func (ttf *TfTransformer) replaceParameters(file *hclwrite.File) error {
 	for _, mapping := range ttf.ParameterMappings {
-		reBlock := regexp.MustCompile(fmt.Sprintf(`(?m)%s[\s]*=[\s]+{[\s\S.]*?}`, mapping.TfVariable))
-		tf = reBlock.ReplaceAllString(tf, fmt.Sprintf("%s = %s", mapping.TfVariable, mapping.ParameterName))
-		reArray := regexp.MustCompile(fmt.Sprintf(`(?m)%s[\s]*=[\s]+\[[\s\S.]*?\]`, mapping.TfVariable))
-		tf = reArray.ReplaceAllString(tf, fmt.Sprintf("%s = %s", mapping.TfVariable, mapping.ParameterName))
-		reSimple := regexp.MustCompile(fmt.Sprintf(`(?m)%s[\s]*=.*$`, mapping.TfVariable))
-		tf = reSimple.ReplaceAllString(tf, fmt.Sprintf("%s = %s", mapping.TfVariable, mapping.ParameterName))
+		tokens, err := expressionTokens(mapping.ParameterName)
+		if err != nil {
+			return fmt.Errorf("invalid parameter for %q: %w", mapping.TfVariable, err)
+		}
+
+		walkResources(file, func(path string, body *hclwrite.Body) {
+			for attrName := range body.Attributes() {
+				if matchesMapping(mapping.TfVariable, path, attrName) {
+					body.SetAttributeRaw(attrName, tokens)
+				}
+			}
+		})
 	}
 
-	return tf
+	return nil
 }
 
-// ReplaceParametersInTf replaces ttf.ParameterMappings in tf
-func (ttf *TfTransformer) ReplaceParametersInTf(tf string) (string, map[string]string, error) {
-	parameterValues, err := ttf.captureParameterValues(tf)
+const (
+	// sensitiveValue is how `terraform show` displays the values of sensitive attributes
+	sensitiveValue = "(sensitive value)"
+	// sensitiveValuePlaceholder stands for sensitive values while the tf is parsed, as they are not valid HCL
+	sensitiveValuePlaceholder = "__sensitive_value__"
+)
+
+var (
+	sensitiveValueMatcher            = regexp.MustCompile(`(=\s*)` + regexp.QuoteMeta(sensitiveValue))
+	sensitiveValuePlaceholderMatcher = regexp.MustCompile(`(=\s*)` + sensitiveValuePlaceholder + `\b`)
+)
 
-	if err == nil {
-		tf = ttf.replaceParameters(tf)
+// parseTf parses the output of `terraform show`. The attributes that have sensitive values must
+// be removed or mapped to parameters, as the values are not known.
+func parseTf(tf string) (*hclwrite.File, error) {
+	tf = sensitiveValueMatcher.ReplaceAllString(tf, "${1}"+sensitiveValuePlaceholder)
+	file, diags := hclwrite.ParseConfig([]byte(tf), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("error parsing imported HCL: %w", diags)
 	}
-	return tf, parameterValues, err
+	return file, nil
 }
 
-func (ttf *TfTransformer) AddParametersInTf(tf string) string {
-	resource := regexp.MustCompile(`resource "(.*)" "(.*)"`)
-	scanner := bufio.NewScanner(strings.NewReader(tf))
-	buffer := bytes.Buffer{}
-	depth := 0
-	blockStack := make([]string, 64)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		depth = braceCount(line, depth)
-		if res := resource.FindStringSubmatch(line); res != nil {
-			blockStack[depth] = fmt.Sprintf("%s.%s", res[1], res[2])
-			buffer.WriteString(fmt.Sprintf("%s\n", line))
+// formatTf returns the formatted tf, without the blank lines that removed blocks leave at the
+// end of their enclosing blocks, and with the sensitive values that remain as `terraform show` displays them
+func formatTf(file *hclwrite.File) string {
+	tokens := file.BuildTokens(nil)
+	var result hclwrite.Tokens
+	for i, token := range tokens {
+		if token.Type == hclsyntax.TokenNewline && i+2 < len(tokens) &&
+			tokens[i+1].Type == hclsyntax.TokenNewline && tokens[i+2].Type == hclsyntax.TokenCBrace {
+			continue
+		}
+		result = append(result, token)
+	}
+	return sensitiveValuePlaceholderMatcher.ReplaceAllString(string(hclwrite.Format(result.Bytes())), "${1}"+sensitiveValue)
+}
 
-			for _, addition := range ttf.ParametersToAdd {
-				additionPrefix := addition.TfVariable[0:strings.LastIndex(addition.TfVariable, ".")]
+// hasSensitiveValue reports whether the value of an attribute is, or contains, a sensitive value
+func hasSensitiveValue(attr *hclwrite.Attribute) bool {
+	for _, token := range attr.Expr().BuildTokens(nil) {
+		if token.Type == hclsyntax.TokenIdent && string(token.Bytes) == sensitiveValuePlaceholder {
+			return true
+		}
+	}
+	return false
+}
 
-				if additionPrefix == blockStack[depth] {
-					buffer.WriteString(fmt.Sprintf("%s = %s\n", addition.TfVariable[strings.LastIndex(addition.TfVariable, ".")+1:], addition.ParameterName))
-				}
+// checkNoSensitiveValues returns an error that lists the attributes that still have sensitive values
+func checkNoSensitiveValues(file *hclwrite.File) error {
+	var paths []string
+	walkResources(file, func(path string, body *hclwrite.Body) {
+		for name, attr := range body.Attributes() {
+			if hasSensitiveValue(attr) {
+				paths = append(paths, path+"."+name)
 			}
-		} else {
-			buffer.WriteString(fmt.Sprintf("%s\n", line))
 		}
+	})
+
+	if len(paths) > 0 {
+		sort.Strings(paths)
+		return fmt.Errorf("the imported resources have sensitive values that must be removed or mapped to parameters: %s", strings.Join(paths, ", "))
+	}
+	return nil
+}
+
+// walkResources calls the function for the body of each resource, and of each block nested
+// in a resource, with the path of the body
+func walkResources(file *hclwrite.File, fn func(path string, body *hclwrite.Body)) {
+	var walk func(path string, body *hclwrite.Body)
+	walk = func(path string, body *hclwrite.Body) {
+		fn(path, body)
+		for _, block := range body.Blocks() {
+			walk(path+"."+block.Type(), block.Body())
+		}
+	}
+
+	for _, block := range file.Body().Blocks() {
+		if block.Type() == resourceIdentifier {
+			walk(strings.Join(block.Labels(), "."), block.Body())
+		}
+	}
+}
+
+// matchesMapping reports whether the attribute of the body at the path is the TfVariable of a mapping
+func matchesMapping(tfVariable, path, attrName string) bool {
+	if strings.Contains(tfVariable, ".") {
+		return tfVariable == path+"."+attrName
+	}
+	return tfVariable == attrName
+}
+
+// parameterVariableName returns the name of the variable or local that a parameter refers to
+func parameterVariableName(parameterName string) (string, bool) {
+	for _, prefix := range []string{"var.", "local."} {
+		if name, ok := strings.CutPrefix(parameterName, prefix); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// attributeValue evaluates the expression of an attribute, which in the output of
+// `terraform show` is always a literal value
+func attributeValue(attr *hclwrite.Attribute) (any, error) {
+	// The newline that ends a heredoc belongs to the attribute rather than to the expression
+	src := append(attr.Expr().BuildTokens(nil).Bytes(), '\n')
+	expr, diags := hclsyntax.ParseExpression(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	raw, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var result any
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// expressionTokens returns the tokens of an HCL expression, such as a reference to a variable
+func expressionTokens(expression string) (hclwrite.Tokens, error) {
+	file, diags := hclwrite.ParseConfig([]byte(fmt.Sprintf("value = %s\n", expression)), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	attr := file.Body().GetAttribute("value")
+	if attr == nil || len(file.Body().Attributes()) != 1 || len(file.Body().Blocks()) != 0 {
+		return nil, fmt.Errorf("%q is not an expression", expression)
 	}
+	return attr.Expr().BuildTokens(nil), nil
+}
 
-	return buffer.String()
+// splitPath splits the path of an attribute into the path of its block and its name
+func splitPath(path string) (string, string) {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return "", path
+	}
+	return path[:i], path[i+1:]
 }
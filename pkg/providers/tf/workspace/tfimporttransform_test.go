@@ -15,6 +15,8 @@
 package workspace
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -463,7 +465,10 @@ resource "azurerm_sql_failover_group" "failover_group" {
 
 	for tn, tc := range cases {
 		t.Run(tn, func(t *testing.T) {
-			output := tc.transformer.CleanTf(tc.input)
+			output, err := tc.transformer.CleanTf(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if !compareIgnoreWhiteSpace(output, tc.expected) {
 				t.Fatalf("Expected %s, actual %s", tc.expected, output)
 			}
@@ -476,13 +481,13 @@ func TestTfImportTransform_ReplaceParametersInTf(t *testing.T) {
 		transformer        TfTransformer
 		input              string
 		expected           string
-		expectedParameters map[string]string
+		expectedParameters map[string]any
 	}{
 		"none": {
 			transformer: TfTransformer{
 				ParameterMappings: []ParameterMapping{},
 			},
-			expectedParameters: map[string]string{},
+			expectedParameters: map[string]any{},
 			input: `resource "azurerm_mssql_database" "azure_sql_db" {
     collation                        = "SQL_Latin1_General_CP1_CI_AS"
     creation_date                    = "2020-08-26T18:15:12.057Z"
@@ -553,7 +558,7 @@ func TestTfImportTransform_ReplaceParametersInTf(t *testing.T) {
 					},
 				},
 			},
-			expectedParameters: map[string]string{
+			expectedParameters: map[string]any{
 				"edition": "Basic",
 			},
 			input: `resource "azurerm_mssql_database" "azure_sql_db" {
@@ -626,10 +631,10 @@ func TestTfImportTransform_ReplaceParametersInTf(t *testing.T) {
 					},
 				},
 			},
-			expectedParameters: map[string]string{
-				//             "labels": `{
-				//     "user-agent" = "meta-azure-service-broker"
-				// }`,
+			expectedParameters: map[string]any{
+				"labels": map[string]any{
+					"user-agent": "meta-azure-service-broker",
+				},
 			},
 			input: `resource "azurerm_mssql_database" "azure_sql_db" {
     collation                        = "SQL_Latin1_General_CP1_CI_AS"
@@ -699,7 +704,7 @@ func TestTfImportTransform_ReplaceParametersInTf(t *testing.T) {
 					},
 				},
 			},
-			expectedParameters: map[string]string{
+			expectedParameters: map[string]any{
 				"sku_name": "GP_Gen5_4",
 			},
 			input: `resource "azurerm_mssql_database" "azure_sql_db" {
@@ -763,6 +768,23 @@ func TestTfImportTransform_ReplaceParametersInTf(t *testing.T) {
 
 	timeouts {}
 	sku_name = local.sku_name
+}`,
+		},
+		"sensitive": {
+			transformer: TfTransformer{
+				ParameterMappings: []ParameterMapping{
+					{TfVariable: "password", ParameterName: "var.admin_password"},
+					{TfVariable: "username", ParameterName: "var.admin_username"},
+				},
+			},
+			expectedParameters: map[string]any{"admin_username": "admin"},
+			input: `resource "aws_db_instance" "db_instance" {
+    password = (sensitive value)
+    username = "admin"
+}`,
+			expected: `resource "aws_db_instance" "db_instance" {
+    password = var.admin_password
+    username = var.admin_username
 }`,
 		},
 		"array": {
@@ -774,9 +796,7 @@ func TestTfImportTransform_ReplaceParametersInTf(t *testing.T) {
 					},
 				},
 			},
-			expectedParameters: map[string]string{
-				//"sku_name": "GP_Gen5_4",
-			},
+			expectedParameters: map[string]any{},
 			input: `resource "azurerm_mssql_database" "azure_sql_db" {
     an_array = [
 
@@ -804,6 +824,47 @@ func TestTfImportTransform_ReplaceParametersInTf(t *testing.T) {
 	}
 }
 
+func TestTfImportTransform_ReplaceParametersInTfFailsForSensitiveValues(t *testing.T) {
+	transformer := TfTransformer{
+		ParameterMappings: []ParameterMapping{{TfVariable: "username", ParameterName: "var.admin_username"}},
+	}
+
+	_, _, err := transformer.ReplaceParametersInTf(`resource "aws_db_instance" "db_instance" {
+    password = (sensitive value)
+    username = "admin"
+
+    master_user_secret {
+        secret_arn = (sensitive value)
+    }
+}`)
+
+	const expected = "the imported resources have sensitive values that must be removed or mapped to parameters: aws_db_instance.db_instance.master_user_secret.secret_arn, aws_db_instance.db_instance.password"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error %q, actual %v", expected, err)
+	}
+}
+
+func TestTfImportTransform_CleanTfKeepsSensitiveValues(t *testing.T) {
+	transformer := TfTransformer{
+		ParametersToRemove: []string{"aws_db_instance.db_instance.id"},
+	}
+
+	output, err := transformer.CleanTf(`resource "aws_db_instance" "db_instance" {
+    id       = "db"
+    password = (sensitive value)
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expected = `resource "aws_db_instance" "db_instance" {
+    password = (sensitive value)
+}`
+	if !compareIgnoreWhiteSpace(output, expected) {
+		t.Fatalf("Expected %s, actual %s", expected, output)
+	}
+}
+
 func TestTfImportTransform_AddTf(t *testing.T) {
 	cases := map[string]struct {
 		transformer TfTransformer
@@ -944,16 +1005,221 @@ func TestTfImportTransform_AddTf(t *testing.T) {
     }
 
     timeouts {}
+}`,
+		},
+		"nested": {
+			transformer: TfTransformer{
+				ParametersToAdd: []ParameterMapping{
+					{
+						TfVariable:    "google_sql_database_instance.instance.settings.tier",
+						ParameterName: "var.tier",
+					},
+					{
+						TfVariable:    "google_sql_database_instance.instance.settings.disk_autoresize",
+						ParameterName: "true",
+					},
+					{
+						TfVariable:    "google_sql_database_instance.instance.deletion_protection",
+						ParameterName: "false",
+					},
+				},
+			},
+			input: `resource "google_sql_database_instance" "instance" {
+    name = "instance"
+
+    settings {
+        tier = "db-f1-micro"
+    }
+}`,
+			expected: `resource "google_sql_database_instance" "instance" {
+    deletion_protection = false
+    name = "instance"
+
+    settings {
+        disk_autoresize = true
+        tier = var.tier
+    }
 }`,
 		},
 	}
 
 	for tn, tc := range cases {
 		t.Run(tn, func(t *testing.T) {
-			output := tc.transformer.AddParametersInTf(tc.input)
+			output, err := tc.transformer.AddParametersInTf(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if !compareIgnoreWhiteSpace(output, tc.expected) {
 				t.Fatalf("Expected %s, actual %s", tc.expected, output)
 			}
 		})
 	}
 }
+
+func TestTfImportTransform_UnmatchedPaths(t *testing.T) {
+	const input = `resource "google_sql_database_instance" "instance" {
+    name = "instance"
+
+    settings {
+        tier = "db-f1-micro"
+    }
+}`
+
+	transformer := TfTransformer{
+		ParametersToRemove: []string{
+			"google_sql_database_instance.instance.settings.version",
+			"google_sql_database_instance.instnace.id",
+		},
+		ParametersToAdd: []ParameterMapping{
+			{TfVariable: "google_sql_database_instance.instance.settings.tier", ParameterName: "var.tier"},
+			{TfVariable: "google_sql_database_instance.instance.backup_configuration.enabled", ParameterName: "true"},
+		},
+	}
+
+	_, err := transformer.CleanTf(input)
+	const expectedCleanError = "the import_parameters_to_delete entries do not match any imported resource or block: google_sql_database_instance.instnace.id"
+	if err == nil || err.Error() != expectedCleanError {
+		t.Fatalf("Expected error %q, actual %v", expectedCleanError, err)
+	}
+
+	_, err = transformer.AddParametersInTf(input)
+	const expectedAddError = "the import_parameters_to_add entries do not match any imported resource or block: google_sql_database_instance.instance.backup_configuration.enabled"
+	if err == nil || err.Error() != expectedAddError {
+		t.Fatalf("Expected error %q, actual %v", expectedAddError, err)
+	}
+}
+
+// TestTfImportTransform_Golden transforms the output of `terraform show` for resources of real
+// providers, as the subsume of a service instance does, and compares it with the golden files
+func TestTfImportTransform_Golden(t *testing.T) {
+	cases := map[string]struct {
+		transformer        TfTransformer
+		expectedParameters map[string]any
+	}{
+		"google_sql_database_instance": {
+			transformer: TfTransformer{
+				ParametersToRemove: []string{
+					"google_sql_database_instance.instance.connection_name",
+					"google_sql_database_instance.instance.first_ip_address",
+					"google_sql_database_instance.instance.id",
+					"google_sql_database_instance.instance.ip_address",
+					"google_sql_database_instance.instance.maintenance_version",
+					"google_sql_database_instance.instance.public_ip_address",
+					"google_sql_database_instance.instance.self_link",
+					"google_sql_database_instance.instance.server_ca_cert",
+					"google_sql_database_instance.instance.service_account_email_address",
+					"google_sql_database_instance.instance.settings.version",
+					"google_sql_database_instance.instance.settings.backup_configuration.backup_retention_settings",
+				},
+				ParameterMappings: []ParameterMapping{
+					{TfVariable: "database_version", ParameterName: "var.database_version"},
+					{TfVariable: "google_sql_database_instance.instance.settings.tier", ParameterName: "var.tier"},
+					{TfVariable: "google_sql_database_instance.instance.settings.user_labels", ParameterName: "var.labels"},
+					{TfVariable: "google_sql_database_instance.instance.settings.ip_configuration.authorized_networks.value", ParameterName: "var.authorized_network"},
+				},
+				ParametersToAdd: []ParameterMapping{
+					{TfVariable: "google_sql_database_instance.instance.deletion_protection", ParameterName: "false"},
+				},
+			},
+			expectedParameters: map[string]any{
+				"database_version":   "MYSQL_8_0",
+				"tier":               "db-n1-standard-2",
+				"labels":             map[string]any{"pcf-instance-id": "8f0d2a1c-3cb6-4f3d-9b3c-1c1d5cb4f0e6", "team": "data"},
+				"authorized_network": "203.0.113.0/24",
+			},
+		},
+		"google_compute_instance": {
+			transformer: TfTransformer{
+				ParametersToRemove: []string{
+					"google_compute_instance.vm.cpu_platform",
+					"google_compute_instance.vm.current_status",
+					"google_compute_instance.vm.id",
+					"google_compute_instance.vm.instance_id",
+					"google_compute_instance.vm.label_fingerprint",
+					"google_compute_instance.vm.metadata_fingerprint",
+					"google_compute_instance.vm.self_link",
+					"google_compute_instance.vm.tags_fingerprint",
+					"google_compute_instance.vm.boot_disk.source",
+					"google_compute_instance.vm.network_interface.access_config",
+				},
+				ParameterMappings: []ParameterMapping{
+					{TfVariable: "machine_type", ParameterName: "var.machine_type"},
+					{TfVariable: "metadata_startup_script", ParameterName: "var.startup_script"},
+					{TfVariable: "google_compute_instance.vm.tags", ParameterName: "var.network_tags"},
+					{TfVariable: "google_compute_instance.vm.boot_disk.initialize_params.size", ParameterName: "local.disk_size"},
+				},
+			},
+			expectedParameters: map[string]any{
+				"machine_type":   "e2-medium",
+				"startup_script": "#!/bin/bash\napt-get update\napt-get install -y nginx\necho \"server { listen 8080; }\" > /etc/nginx/conf.d/app.conf\n",
+				"network_tags":   []any{"http-server", "https-server"},
+				"disk_size":      float64(10),
+			},
+		},
+		"aws_db_instance": {
+			transformer: TfTransformer{
+				ParametersToRemove: []string{
+					"aws_db_instance.db_instance.address",
+					"aws_db_instance.db_instance.arn",
+					"aws_db_instance.db_instance.endpoint",
+					"aws_db_instance.db_instance.engine_version_actual",
+					"aws_db_instance.db_instance.hosted_zone_id",
+					"aws_db_instance.db_instance.id",
+					"aws_db_instance.db_instance.password",
+					"aws_db_instance.db_instance.replicas",
+					"aws_db_instance.db_instance.resource_id",
+					"aws_db_instance.db_instance.status",
+					"aws_db_instance.db_instance.tags_all",
+				},
+				ParameterMappings: []ParameterMapping{
+					{TfVariable: "instance_class", ParameterName: "var.instance_class"},
+					{TfVariable: "allocated_storage", ParameterName: "var.storage_gb"},
+					{TfVariable: "tags", ParameterName: "var.labels"},
+					{TfVariable: "vpc_security_group_ids", ParameterName: "[aws_security_group.rds_sg.id]"},
+				},
+				ParametersToAdd: []ParameterMapping{
+					{TfVariable: "aws_db_instance.db_instance.password", ParameterName: "random_password.password.result"},
+					{TfVariable: "aws_db_instance.db_instance.count", ParameterName: "1"},
+				},
+			},
+			expectedParameters: map[string]any{
+				"instance_class": "db.t3.micro",
+				"storage_gb":     float64(20),
+				"labels":         map[string]any{"pcf-instance-id": "2d1dc3f5-8a0b-4a5b-b0a8-5d6a3f5cbd4e"},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", "import", tn, "show.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := os.ReadFile(filepath.Join("testdata", "import", tn, "main.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			output, err := tc.transformer.CleanTf(string(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			output, err = tc.transformer.AddParametersInTf(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			output, parameters, err := tc.transformer.ReplaceParametersInTf(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if output != string(expected) {
+				t.Fatalf("Expected %s, actual %s", expected, output)
+			}
+			if !reflect.DeepEqual(parameters, tc.expectedParameters) {
+				t.Fatalf("Expected %v, actual %v", tc.expectedParameters, parameters)
+			}
+		})
+	}
+}
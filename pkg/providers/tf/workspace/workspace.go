@@ -27,6 +27,7 @@ import (
 
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/command"
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
 
 	"github.com/hashicorp/go-version"
 )
@@ -320,6 +321,11 @@ func (workspace *TerraformWorkspace) Execute(ctx context.Context, terraformExecu
 	}
 	var lastExecutionOutput executor.ExecutionOutput
 
+	// Terraform can output the values of sensitive variables, which must not be logged
+	for _, instance := range workspace.Instances {
+		ctx = redaction.WithValues(ctx, redaction.SensitiveValues(instance.Configuration)...)
+	}
+
 	for _, cmd := range commands {
 		c := exec.Command("terraform", cmd.Command()...)
 		c.Env = os.Environ()
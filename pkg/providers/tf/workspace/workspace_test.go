@@ -25,6 +25,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/executor"
 
 	"github.com/cloudfoundry/cloud-service-broker/pkg/providers/tf/command"
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
 
 	"github.com/hashicorp/go-version"
 )
@@ -282,6 +283,27 @@ func TestCustomTerraformExecutor013(t *testing.T) {
 	}
 }
 
+func TestTerraformWorkspace_ExecuteMasksSensitiveValues(t *testing.T) {
+	ws, err := NewWorkspace(map[string]any{"admin_password": "fake-password", "name": "fake-name"}, "variable admin_password { type = string }\nvariable name { type = string }", map[string]string{}, []ParameterMapping{}, []string{}, []ParameterMapping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var values []string
+	tExecutor := newTestExecutor(func(ctx context.Context, cmd *exec.Cmd) (executor.ExecutionOutput, error) {
+		values = redaction.Values(ctx)
+		return executor.ExecutionOutput{}, nil
+	})
+
+	if _, err := ws.Execute(context.TODO(), tExecutor, command.NewApply()); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"fake-password"}; !reflect.DeepEqual(expected, values) {
+		t.Fatalf("Expected %v actual %v", expected, values)
+	}
+}
+
 func TestCustomEnvironmentExecutor(t *testing.T) {
 	c := exec.Command("/path/to/terraform", "apply")
 	c.Env = []string{"ORIGINAL=value"}
@@ -39,6 +39,7 @@ const (
 	KeyProhibitUpdate   = "prohibitUpdate"
 	KeyTFAttribute      = "tf_attribute"
 	KeyTFAttributeSkip  = "tf_attribute_skip"
+	KeyWriteOnly        = "writeOnly"
 )
 
 // NewConstraintBuilder creates a builder for JSON Schema compliant constraint
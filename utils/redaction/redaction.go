@@ -0,0 +1,208 @@
+// Package redaction masks secrets before they are written to the broker logs
+package redaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/lager/v3"
+)
+
+const (
+	// Mask replaces the secrets. It is the same as the mask of the lager redacting sink.
+	Mask = "*REDACTED*"
+
+	// minValueLength avoids replacing short values, such as "true", that are unlikely to be secrets
+	minValueLength = 6
+)
+
+// DefaultKeyPatterns match the keys of log data whose values are masked, unless other patterns are configured
+var DefaultKeyPatterns = []string{`(?i)passw(or)?d`, `(?i)passphrase`, `(?i)secret`, `(?i)private_?key`, `(?i)token`}
+
+var (
+	lock        sync.RWMutex
+	keyMatchers = mustCompile(DefaultKeyPatterns)
+	fields      = make(map[string]struct{})
+	redacter    = newJSONRedacter()
+)
+
+// SetKeyPatterns sets the regular expressions that match the keys of log data whose values are masked.
+// They replace the default patterns, so no keys are matched when there are no patterns.
+func SetKeyPatterns(patterns []string) error {
+	matchers, err := compile(patterns)
+	if err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	keyMatchers = matchers
+	redacter = newJSONRedacter()
+	return nil
+}
+
+// AddSensitiveFields adds the names of fields whose values are masked wherever they appear in log data,
+// such as the service parameters that are marked as sensitive
+func AddSensitiveFields(names ...string) {
+	lock.Lock()
+	defer lock.Unlock()
+	for _, n := range names {
+		fields[n] = struct{}{}
+	}
+	redacter = newJSONRedacter()
+}
+
+// IsSensitive reports whether the values of a field or key are masked
+func IsSensitive(key string) bool {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	if _, ok := fields[key]; ok {
+		return true
+	}
+	for _, m := range keyMatchers {
+		if m.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// SensitiveValues returns the string values of the variables that are sensitive
+func SensitiveValues(vars map[string]any) []string {
+	var values []string
+	for k, v := range vars {
+		if s, ok := v.(string); ok && IsSensitive(k) {
+			values = append(values, s)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+type valuesKey struct{}
+
+// WithValues returns a context in which the values are masked, in addition to the values that
+// are already masked in the context, for instance in the output of Terraform
+func WithValues(ctx context.Context, values ...string) context.Context {
+	if len(values) == 0 {
+		return ctx
+	}
+
+	existing := Values(ctx)
+	combined := make([]string, 0, len(existing)+len(values))
+	combined = append(combined, existing...)
+	combined = append(combined, values...)
+	return context.WithValue(ctx, valuesKey{}, combined)
+}
+
+// Values returns the values that are masked in the context
+func Values(ctx context.Context) []string {
+	values, _ := ctx.Value(valuesKey{}).([]string)
+	return values
+}
+
+// RedactValues masks the values of the context in the text
+func RedactValues(ctx context.Context, text string) string {
+	return NewReplacer(Values(ctx)).Replace(text)
+}
+
+// NewReplacer masks the values in a text. Longer values are replaced first, so that a value
+// which contains another is not partly revealed.
+func NewReplacer(values []string) *strings.Replacer {
+	var sorted []string
+	for _, v := range values {
+		if len(v) >= minValueLength {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	var oldnew []string
+	for _, v := range sorted {
+		oldnew = append(oldnew, v, Mask)
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// NewSink returns a sink that masks the values of the sensitive fields, and of the keys matching the
+// key patterns, in the log data before it is written to the wrapped sink. Values that look like well-known
+// credentials, such as private keys, are masked too.
+func NewSink(wrapped lager.Sink) lager.Sink {
+	return sink{wrapped: wrapped}
+}
+
+type sink struct {
+	wrapped lager.Sink
+}
+
+func (s sink) Log(log lager.LogFormat) {
+	if len(log.Data) > 0 {
+		log.Data = redactData(log.Data)
+	}
+	s.wrapped.Log(log)
+}
+
+func redactData(data lager.Data) lager.Data {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		// The wrapped sink reports data that cannot be serialized
+		return data
+	}
+
+	lock.RLock()
+	r := redacter
+	lock.RUnlock()
+
+	var redacted lager.Data
+	if err := json.Unmarshal(r.Redact(raw), &redacted); err != nil {
+		return lager.Data{"redaction-error": err.Error()}
+	}
+	return redacted
+}
+
+// newJSONRedacter must be called with the lock held
+func newJSONRedacter() *lager.JSONRedacter {
+	keyPatterns := make([]string, 0, len(keyMatchers)+1)
+	for _, m := range keyMatchers {
+		keyPatterns = append(keyPatterns, m.String())
+	}
+
+	if len(fields) > 0 {
+		var names []string
+		for n := range fields {
+			names = append(names, regexp.QuoteMeta(n))
+		}
+		sort.Strings(names)
+		keyPatterns = append(keyPatterns, fmt.Sprintf("^(?:%s)$", strings.Join(names, "|")))
+	}
+
+	// The patterns have been compiled already, so they are valid
+	r, _ := lager.NewJSONRedacter(keyPatterns, lager.DefaultValuePatterns())
+	return r
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	var matchers []*regexp.Regexp
+	for _, p := range patterns {
+		m, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction key pattern %q: %w", p, err)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+func mustCompile(patterns []string) []*regexp.Regexp {
+	matchers, err := compile(patterns)
+	if err != nil {
+		panic(err)
+	}
+	return matchers
+}
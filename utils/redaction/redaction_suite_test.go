@@ -0,0 +1,13 @@
+package redaction_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRedaction(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Utils Redaction Suite")
+}
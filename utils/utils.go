@@ -26,6 +26,8 @@ import (
 	"strings"
 
 	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/utils/redaction"
 )
 
 const EnvironmentVarPrefix = "gsb"
@@ -134,7 +136,7 @@ func SingleLineErrorFormatter(es []error) string {
 }
 
 // NewLogger creates a new lager.Logger with the given name that has correct
-// writing settings.
+// writing settings. Secrets in the log data are masked.
 func NewLogger(name string) lager.Logger {
 	logger := lager.NewLogger(name)
 
@@ -143,8 +145,8 @@ func NewLogger(name string) lager.Logger {
 		logLevel = lager.DEBUG
 	}
 
-	logger.RegisterSink(lager.NewWriterSink(os.Stderr, lager.ERROR))
-	logger.RegisterSink(lager.NewWriterSink(os.Stdout, logLevel))
+	logger.RegisterSink(redaction.NewSink(lager.NewWriterSink(os.Stderr, lager.ERROR)))
+	logger.RegisterSink(redaction.NewSink(lager.NewWriterSink(os.Stdout, logLevel)))
 
 	return logger
 }